@@ -17,11 +17,13 @@ package cmd
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
 	"regexp"
+	"runtime/debug"
 	"strings"
 	"syscall"
 	"time"
@@ -32,6 +34,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/log/global"
 
 	// Import tool packages for side effect of registration
 	_ "github.com/googleapis/genai-toolbox/internal/tools/alloydbainl"
@@ -45,6 +48,9 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/couchbase"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/dgraph"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/http"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/inmemorylookup"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodbaggregate"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodbfind"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mssqlexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mssqlsql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mysqlexecutesql"
@@ -52,6 +58,7 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/neo4j"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgresexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgressql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/redis"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/spanner"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/spannerexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/sqlitesql"
@@ -67,10 +74,13 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/sources/couchbase"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/dgraph"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/http"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/inmemory"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/mongodb"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/mssql"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/mysql"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/neo4j"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/redis"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/spanner"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/sqlite"
 )
@@ -81,6 +91,10 @@ var (
 	versionString string
 	// metadataString indicates additional build or distribution metadata.
 	metadataString string
+	// commitString is the commit the binary was built from.
+	commitString string
+	// buildDateString is when the binary was built.
+	buildDateString string
 )
 
 func init() {
@@ -96,6 +110,26 @@ func semanticVersion() string {
 	return v
 }
 
+// buildInfo returns the commit and date of the build. If they were not set
+// at compile-time, they are read from the version control information
+// stamped by the Go toolchain, if any.
+func buildInfo() (commit, date string) {
+	commit, date = commitString, buildDateString
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return commit, date
+	}
+	for _, s := range info.Settings {
+		switch {
+		case s.Key == "vcs.revision" && commit == "":
+			commit = s.Value
+		case s.Key == "vcs.time" && date == "":
+			date = s.Value
+		}
+	}
+	return commit, date
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -142,6 +176,7 @@ func NewCommand(opts ...Option) *Command {
 
 	// Set server version
 	cmd.cfg.Version = versionString
+	cmd.cfg.BuildCommit, cmd.cfg.BuildDate = buildInfo()
 
 	// set baseCmd in, out and err the same as cmd.
 	baseCmd.SetIn(cmd.inStream)
@@ -161,8 +196,45 @@ func NewCommand(opts ...Option) *Command {
 	flags.BoolVar(&cmd.cfg.TelemetryGCP, "telemetry-gcp", false, "Enable exporting directly to Google Cloud Monitoring.")
 	flags.StringVar(&cmd.cfg.TelemetryOTLP, "telemetry-otlp", "", "Enable exporting using OpenTelemetry Protocol (OTLP) to the specified endpoint (e.g. 'http://127.0.0.1:4318')")
 	flags.StringVar(&cmd.cfg.TelemetryServiceName, "telemetry-service-name", "toolbox", "Sets the value of the service.name resource attribute for telemetry data.")
+	flags.BoolVar(&cmd.cfg.TelemetryPrometheus, "telemetry-prometheus", false, "Enable serving metrics in the Prometheus format at the /metrics endpoint.")
+	flags.BoolVar(&cmd.cfg.TelemetryOTLPLogs, "telemetry-otlp-logs", false, "Enable exporting logs using OpenTelemetry Protocol (OTLP) to the endpoint set by --telemetry-otlp.")
 	flags.StringVar(&cmd.prebuiltConfig, "prebuilt", "", "Use a prebuilt tool configuration by source type. Cannot be used with --tools-file. Allowed: 'alloydb-postgres', 'bigquery', 'cloud-sql-mysql', 'cloud-sql-postgres', 'cloud-sql-mssql', 'postgres', 'spanner', 'spanner-postgres'.")
 	flags.BoolVar(&cmd.cfg.Stdio, "stdio", false, "Listens via MCP STDIO instead of acting as a remote HTTP server.")
+	flags.Var(&cmd.cfg.StdioFraming, "stdio-framing", "Specify how MCP messages are delimited with --stdio. Allowed: 'newline' or 'content-length'.")
+	flags.IntVar(&cmd.cfg.MaxConcurrentInvocations, "max-concurrent-invocations", 0, "Maximum number of tool invocations that run at once. 0 means unlimited.")
+	flags.IntVar(&cmd.cfg.InvocationQueueDepth, "invocation-queue-depth", 0, "Number of invocations that may wait for a slot once --max-concurrent-invocations is reached. Excess invocations are rejected.")
+	flags.DurationVar(&cmd.cfg.InvocationQueueTimeout, "invocation-queue-timeout", time.Second, "Maximum time a queued invocation waits for a slot before being rejected.")
+	flags.Int64Var(&cmd.cfg.MaxUploadSize, "max-upload-size", 10<<20, "Maximum size in bytes of invoke requests that upload files.")
+	flags.Int64Var(&cmd.cfg.MaxRequestBytes, "max-request-bytes", server.DefaultMaxRequestBytes, "Maximum size in bytes of the bodies of other invoke requests and of MCP requests. A negative value disables the limit.")
+	flags.IntVar(&cmd.cfg.CompressionMinBytes, "compression-min-bytes", server.DefaultCompressionMinBytes, "Minimum size in bytes of the /api and /mcp responses that are compressed with gzip for clients that accept it. A negative value disables compression.")
+	flags.Float64Var(&cmd.cfg.RateLimit, "rate-limit", 0, "Number of requests per second that each client may send to the /api and /mcp endpoints. 0 means unlimited.")
+	flags.IntVar(&cmd.cfg.RateLimitBurst, "rate-limit-burst", 0, "Number of requests that each client may send at once before --rate-limit applies. Defaults to --rate-limit, rounded up.")
+	flags.StringVar(&cmd.cfg.RateLimitHeader, "rate-limit-header", "", "Header that identifies clients for --rate-limit, such as one set by an authenticating proxy. Clients are identified by their IP address by default.")
+	flags.Int64Var(&cmd.cfg.MaxSourceResultBytes, "max-source-result-bytes", 0, "Maximum size in bytes of the results of each source's tools that may be in flight at once. Invocations past the limit are rejected. 0 means unlimited.")
+	flags.IntVar(&cmd.cfg.CircuitBreakerThreshold, "circuit-breaker-threshold", 0, "Number of consecutive invocations of a source's tools that must fail to reach it for them to fail fast for --circuit-breaker-cooldown. 0 disables circuit breakers.")
+	flags.DurationVar(&cmd.cfg.CircuitBreakerCooldown, "circuit-breaker-cooldown", 30*time.Second, "How long the tools of a source fail fast once its circuit breaker opened, before an invocation tests whether the source recovered.")
+	flags.BoolVar(&cmd.cfg.SourceMeta, "source-meta", false, "Include the name and kind of the source that served each invocation in the '_meta.source' field of its result.")
+	flags.BoolVar(&cmd.cfg.ExecutionMetadata, "execution-metadata", false, "Include the duration, row count and truncation of each invocation in the '_meta' field of its result.")
+	flags.DurationVar(&cmd.cfg.ResourcePollInterval, "resource-poll-interval", 30*time.Second, "How often resources with MCP subscriptions are polled for changes.")
+	flags.DurationVar(&cmd.cfg.HealthCheckInterval, "health-check-interval", 30*time.Second, "How often the health of the sources is checked in the background and reported by '/api/health'. 0 checks the sources on each request instead.")
+	flags.Var(&cmd.cfg.TimeZone, "time-zone", "Time zone that time values in results are converted to, e.g. 'UTC' or 'America/New_York'. By default, time values are returned in the zone set by the source.")
+	flags.Var(&cmd.cfg.ToolNameMode, "tool-name-mode", "Specify how tool names that are not URL-safe are handled. Allowed: 'strict' to reject them, or 'escape' to URL-escape them.")
+	flags.DurationVar(&cmd.cfg.ShutdownGracePeriod, "shutdown-grace-period", 10*time.Second, "Maximum time to wait for open sessions and connections to close on shutdown before forcing them closed.")
+	flags.StringVar(&cmd.cfg.AdminAuth.AuthService, "admin-auth-service", "", "Auth service that verifies the callers of the admin endpoints under '/api/admin'. Requires --admin-auth-claim.")
+	flags.StringVar(&cmd.cfg.AdminAuth.Claim, "admin-auth-claim", "", "Claim of --admin-auth-service that callers of the admin endpoints need, e.g. 'groups'.")
+	flags.StringSliceVar(&cmd.cfg.AdminAuth.Values, "admin-auth-values", nil, "Values of --admin-auth-claim that grant access to the admin endpoints. By default, any value other than false or empty grants access.")
+	flags.StringVar(&cmd.cfg.AdminTokenFile, "admin-token-file", "", "File with a bearer token that also grants access to the admin endpoints. The admin endpoints are disabled if neither --admin-auth-service nor --admin-token-file are set.")
+	flags.DurationVar(&cmd.cfg.McpToolCallTimeout, "mcp-tool-call-timeout", 0, "Default timeout of MCP 'tools/call' requests, for tools that don't declare a 'timeout' of their own. 0 means no timeout.")
+	flags.IntVar(&cmd.cfg.ToolsListPageSize, "tools-list-page-size", 0, "Maximum number of tools returned by each MCP 'tools/list' request. 0 means all tools are returned at once.")
+	flags.StringVar(&cmd.cfg.ArgumentsKey, "arguments-key", "", "Key of the object that invoke arguments are nested under, e.g. 'args'. By default, arguments are read from the top level of the request.")
+	flags.StringSliceVar(&cmd.cfg.CorsAllowedOrigins, "cors-allowed-origins", nil, "Origins allowed to make cross-origin requests, e.g. 'https://example.com', or '*' for all origins. No CORS headers are sent if not set.")
+	flags.StringSliceVar(&cmd.cfg.CorsAllowedMethods, "cors-allowed-methods", nil, "Methods allowed in cross-origin requests. Defaults to 'GET', 'POST' and 'DELETE'.")
+	flags.StringSliceVar(&cmd.cfg.CorsAllowedHeaders, "cors-allowed-headers", nil, "Headers allowed in cross-origin requests. Defaults to the headers used by Toolbox clients.")
+	flags.BoolVar(&cmd.cfg.CorsAllowCredentials, "cors-allow-credentials", false, "Allow cross-origin requests to include credentials, such as cookies.")
+	flags.IntVar(&cmd.cfg.MaxSSESessions, "max-sse-sessions", 0, "Maximum number of concurrent MCP SSE sessions. New sessions are rejected once the limit is reached. 0 means unlimited.")
+	flags.StringVar(&cmd.cfg.TLSCertFile, "tls-cert-file", "", "File with the PEM encoded certificate to serve HTTPS with. Requires --tls-key-file.")
+	flags.StringVar(&cmd.cfg.TLSKeyFile, "tls-key-file", "", "File with the PEM encoded private key of --tls-cert-file.")
+	flags.StringVar(&cmd.cfg.TLSClientCAFile, "tls-client-ca-file", "", "File with the PEM encoded CA certificates that client certificates must be signed by. Enables mutual TLS.")
 
 	// wrap RunE command so that we have access to original Command object
 	cmd.RunE = func(*cobra.Command, []string) error { return run(cmd) }
@@ -176,6 +248,7 @@ type ToolsFile struct {
 	AuthServices server.AuthServiceConfigs `yaml:"authServices"`
 	Tools        server.ToolConfigs        `yaml:"tools"`
 	Toolsets     server.ToolsetConfigs     `yaml:"toolsets"`
+	Prompts      server.PromptConfigs      `yaml:"prompts"`
 }
 
 // parseEnv replaces environment variables ${ENV_NAME} with their values.
@@ -225,6 +298,58 @@ func updateLogLevel(stdio bool, logLevel string) bool {
 	return false
 }
 
+// loadToolsFile reads and parses the tools file, or the prebuilt
+// configuration, that cmd is configured with.
+func loadToolsFile(ctx context.Context, cmd *Command) (ToolsFile, error) {
+	var buf []byte
+	var err error
+	if cmd.prebuiltConfig != "" {
+		// Make sure --prebuilt and --tools-file flags are mutually exclusive
+		if cmd.tools_file != "" {
+			return ToolsFile{}, fmt.Errorf("--prebuilt and --tools-file flags cannot be used simultaneously")
+		}
+		// Use prebuilt tools
+		buf, err = prebuiltconfigs.Get(cmd.prebuiltConfig)
+		if err != nil {
+			return ToolsFile{}, err
+		}
+		logMsg := fmt.Sprint("Using prebuilt tool configuration for ", cmd.prebuiltConfig)
+		cmd.logger.InfoContext(ctx, logMsg)
+	} else {
+		// Set default value of tools-file flag to tools.yaml
+		if cmd.tools_file == "" {
+			cmd.tools_file = "tools.yaml"
+		}
+		// Read tool file contents
+		buf, err = os.ReadFile(cmd.tools_file)
+		if err != nil {
+			return ToolsFile{}, fmt.Errorf("unable to read tool file at %q: %w", cmd.tools_file, err)
+		}
+	}
+
+	toolsFile, err := parseToolsFile(ctx, buf)
+	if err != nil {
+		return ToolsFile{}, fmt.Errorf("unable to parse tool file at %q: %w", cmd.tools_file, err)
+	}
+	if toolsFile.AuthSources != nil {
+		cmd.logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` instead")
+		toolsFile.AuthServices = toolsFile.AuthSources
+	}
+	return toolsFile, nil
+}
+
+// reload reloads the tools file and replaces the resources served by s.
+func reload(ctx context.Context, cmd *Command, s *server.Server) error {
+	toolsFile, err := loadToolsFile(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	cfg := cmd.cfg
+	cfg.SourceConfigs, cfg.AuthServiceConfigs, cfg.ToolConfigs, cfg.ToolsetConfigs = toolsFile.Sources, toolsFile.AuthServices, toolsFile.Tools, toolsFile.Toolsets
+	cfg.PromptConfigs = toolsFile.Prompts
+	return s.Reload(ctx, cfg)
+}
+
 func run(cmd *Command) error {
 	if updateLogLevel(cmd.cfg.Stdio, cmd.cfg.LogLevel.String()) {
 		cmd.cfg.LogLevel = server.StringLevel(log.Warn)
@@ -274,7 +399,7 @@ func run(cmd *Command) error {
 	ctx = util.WithLogger(ctx, cmd.logger)
 
 	// Set up OpenTelemetry
-	otelShutdown, err := telemetry.SetupOTel(ctx, cmd.Version, cmd.cfg.TelemetryOTLP, cmd.cfg.TelemetryGCP, cmd.cfg.TelemetryServiceName)
+	otelShutdown, err := telemetry.SetupOTel(ctx, cmd.Version, cmd.cfg.TelemetryOTLP, cmd.cfg.TelemetryGCP, cmd.cfg.TelemetryServiceName, cmd.cfg.TelemetryPrometheus, cmd.cfg.TelemetryOTLPLogs)
 	if err != nil {
 		errMsg := fmt.Errorf("error setting up OpenTelemetry: %w", err)
 		cmd.logger.ErrorContext(ctx, errMsg.Error())
@@ -288,49 +413,22 @@ func run(cmd *Command) error {
 		}
 	}()
 
-	var buf []byte
-
-	if cmd.prebuiltConfig != "" {
-		// Make sure --prebuilt and --tools-file flags are mutually exclusive
-		if cmd.tools_file != "" {
-			errMsg := fmt.Errorf("--prebuilt and --tools-file flags cannot be used simultaneously")
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-		// Use prebuilt tools
-		buf, err = prebuiltconfigs.Get(cmd.prebuiltConfig)
-		if err != nil {
-			cmd.logger.ErrorContext(ctx, err.Error())
-			return err
-		}
-		logMsg := fmt.Sprint("Using prebuilt tool configuration for ", cmd.prebuiltConfig)
-		cmd.logger.InfoContext(ctx, logMsg)
-	} else {
-		// Set default value of tools-file flag to tools.yaml
-		if cmd.tools_file == "" {
-			cmd.tools_file = "tools.yaml"
-		}
-		// Read tool file contents
-		buf, err = os.ReadFile(cmd.tools_file)
+	if cmd.cfg.TelemetryOTLPLogs {
+		logger, err := log.NewOTelLogger(cmd.logger, global.GetLoggerProvider(), cmd.cfg.LogLevel.String())
 		if err != nil {
-			errMsg := fmt.Errorf("unable to read tool file at %q: %w", cmd.tools_file, err)
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
+			return fmt.Errorf("unable to initialize logger: %w", err)
 		}
+		cmd.logger = logger
+		ctx = util.WithLogger(ctx, cmd.logger)
 	}
 
-	toolsFile, err := parseToolsFile(ctx, buf)
-	cmd.cfg.SourceConfigs, cmd.cfg.AuthServiceConfigs, cmd.cfg.ToolConfigs, cmd.cfg.ToolsetConfigs = toolsFile.Sources, toolsFile.AuthServices, toolsFile.Tools, toolsFile.Toolsets
-	authSourceConfigs := toolsFile.AuthSources
-	if authSourceConfigs != nil {
-		cmd.logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` instead")
-		cmd.cfg.AuthServiceConfigs = authSourceConfigs
-	}
+	toolsFile, err := loadToolsFile(ctx, cmd)
 	if err != nil {
-		errMsg := fmt.Errorf("unable to parse tool file at %q: %w", cmd.tools_file, err)
-		cmd.logger.ErrorContext(ctx, errMsg.Error())
-		return errMsg
+		cmd.logger.ErrorContext(ctx, err.Error())
+		return err
 	}
+	cmd.cfg.SourceConfigs, cmd.cfg.AuthServiceConfigs, cmd.cfg.ToolConfigs, cmd.cfg.ToolsetConfigs = toolsFile.Sources, toolsFile.AuthServices, toolsFile.Tools, toolsFile.Toolsets
+	cmd.cfg.PromptConfigs = toolsFile.Prompts
 
 	// start server
 	s, err := server.NewServer(ctx, cmd.cfg, cmd.logger)
@@ -348,6 +446,26 @@ func run(cmd *Command) error {
 	}
 	cmd.logger.InfoContext(ctx, "Server ready to serve!")
 
+	// reload the tools file on sighup
+	reloadSignals := make(chan os.Signal, 1)
+	signal.Notify(reloadSignals, syscall.SIGHUP)
+	defer signal.Stop(reloadSignals)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reloadSignals:
+			}
+			cmd.logger.InfoContext(ctx, "Received SIGHUP signal to reload the tools file.")
+			if err := reload(ctx, cmd, s); err != nil {
+				cmd.logger.ErrorContext(ctx, fmt.Sprintf("unable to reload, keeping the current configuration: %s", err))
+				continue
+			}
+			cmd.logger.InfoContext(ctx, "Reloaded the tools file.")
+		}
+	}()
+
 	// run server in background
 	srvErr := make(chan error)
 	go func() {
@@ -374,12 +492,11 @@ func run(cmd *Command) error {
 			return errMsg
 		}
 	case <-ctx.Done():
-		shutdownContext, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
+		shutdownContext := context.Background()
 		cmd.logger.WarnContext(shutdownContext, "Shutting down gracefully...")
 		err := s.Shutdown(shutdownContext)
-		if err == context.DeadlineExceeded {
-			return fmt.Errorf("graceful shutdown timed out... forcing exit")
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("graceful shutdown timed out... forcing exit: %w", err)
 		}
 	}
 
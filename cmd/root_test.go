@@ -20,6 +20,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -38,6 +39,7 @@ import (
 func withDefaults(c server.ServerConfig) server.ServerConfig {
 	data, _ := os.ReadFile("version.txt")
 	c.Version = strings.TrimSpace(string(data))
+	c.BuildCommit, c.BuildDate = buildInfo()
 	if c.Address == "" {
 		c.Address = "127.0.0.1"
 	}
@@ -47,6 +49,30 @@ func withDefaults(c server.ServerConfig) server.ServerConfig {
 	if c.TelemetryServiceName == "" {
 		c.TelemetryServiceName = "toolbox"
 	}
+	if c.InvocationQueueTimeout == 0 {
+		c.InvocationQueueTimeout = time.Second
+	}
+	if c.MaxUploadSize == 0 {
+		c.MaxUploadSize = 10 << 20
+	}
+	if c.MaxRequestBytes == 0 {
+		c.MaxRequestBytes = server.DefaultMaxRequestBytes
+	}
+	if c.CompressionMinBytes == 0 {
+		c.CompressionMinBytes = server.DefaultCompressionMinBytes
+	}
+	if c.ResourcePollInterval == 0 {
+		c.ResourcePollInterval = 30 * time.Second
+	}
+	if c.HealthCheckInterval == 0 {
+		c.HealthCheckInterval = 30 * time.Second
+	}
+	if c.CircuitBreakerCooldown == 0 {
+		c.CircuitBreakerCooldown = 30 * time.Second
+	}
+	if c.ShutdownGracePeriod == 0 {
+		c.ShutdownGracePeriod = 10 * time.Second
+	}
 	return c
 }
 
@@ -164,6 +190,20 @@ func TestServerConfigFlags(t *testing.T) {
 				TelemetryServiceName: "toolbox-custom",
 			}),
 		},
+		{
+			desc: "telemetry prometheus",
+			args: []string{"--telemetry-prometheus"},
+			want: withDefaults(server.ServerConfig{
+				TelemetryPrometheus: true,
+			}),
+		},
+		{
+			desc: "telemetry otlp logs",
+			args: []string{"--telemetry-otlp-logs"},
+			want: withDefaults(server.ServerConfig{
+				TelemetryOTLPLogs: true,
+			}),
+		},
 		{
 			desc: "stdio",
 			args: []string{"--stdio"},
@@ -171,6 +211,175 @@ func TestServerConfigFlags(t *testing.T) {
 				Stdio: true,
 			}),
 		},
+		{
+			desc: "tls",
+			args: []string{"--tls-cert-file", "server.crt", "--tls-key-file", "server.key", "--tls-client-ca-file", "ca.crt"},
+			want: withDefaults(server.ServerConfig{
+				TLSCertFile:     "server.crt",
+				TLSKeyFile:      "server.key",
+				TLSClientCAFile: "ca.crt",
+			}),
+		},
+		{
+			desc: "max sse sessions",
+			args: []string{"--max-sse-sessions", "10"},
+			want: withDefaults(server.ServerConfig{
+				MaxSSESessions: 10,
+			}),
+		},
+		{
+			desc: "max upload size",
+			args: []string{"--max-upload-size", "1024"},
+			want: withDefaults(server.ServerConfig{
+				MaxUploadSize: 1024,
+			}),
+		},
+		{
+			desc: "max request bytes",
+			args: []string{"--max-request-bytes", "1024"},
+			want: withDefaults(server.ServerConfig{
+				MaxRequestBytes: 1024,
+			}),
+		},
+		{
+			desc: "compression min bytes",
+			args: []string{"--compression-min-bytes", "-1"},
+			want: withDefaults(server.ServerConfig{
+				CompressionMinBytes: -1,
+			}),
+		},
+		{
+			desc: "rate limit",
+			args: []string{"--rate-limit", "2.5", "--rate-limit-burst", "10", "--rate-limit-header", "X-Client-Id"},
+			want: withDefaults(server.ServerConfig{
+				RateLimit:       2.5,
+				RateLimitBurst:  10,
+				RateLimitHeader: "X-Client-Id",
+			}),
+		},
+		{
+			desc: "max source result bytes",
+			args: []string{"--max-source-result-bytes", "1048576"},
+			want: withDefaults(server.ServerConfig{
+				MaxSourceResultBytes: 1 << 20,
+			}),
+		},
+		{
+			desc: "circuit breaker",
+			args: []string{"--circuit-breaker-threshold", "5", "--circuit-breaker-cooldown", "1m"},
+			want: withDefaults(server.ServerConfig{
+				CircuitBreakerThreshold: 5,
+				CircuitBreakerCooldown:  time.Minute,
+			}),
+		},
+		{
+			desc: "source meta",
+			args: []string{"--source-meta"},
+			want: withDefaults(server.ServerConfig{
+				SourceMeta: true,
+			}),
+		},
+		{
+			desc: "resource poll interval",
+			args: []string{"--resource-poll-interval", "5s"},
+			want: withDefaults(server.ServerConfig{
+				ResourcePollInterval: 5 * time.Second,
+			}),
+		},
+		{
+			desc: "health check interval",
+			args: []string{"--health-check-interval", "1m"},
+			want: withDefaults(server.ServerConfig{
+				HealthCheckInterval: time.Minute,
+			}),
+		},
+		{
+			desc: "shutdown grace period",
+			args: []string{"--shutdown-grace-period", "30s"},
+			want: withDefaults(server.ServerConfig{
+				ShutdownGracePeriod: 30 * time.Second,
+			}),
+		},
+		{
+			desc: "admin auth",
+			args: []string{"--admin-auth-service", "my-google-auth", "--admin-auth-claim", "groups", "--admin-auth-values", "admins,operators", "--admin-token-file", "admin-token.txt"},
+			want: withDefaults(server.ServerConfig{
+				AdminAuth: tools.ColumnAuth{
+					AuthService: "my-google-auth",
+					Claim:       "groups",
+					Values:      []string{"admins", "operators"},
+				},
+				AdminTokenFile: "admin-token.txt",
+			}),
+		},
+		{
+			desc: "cors",
+			args: []string{"--cors-allowed-origins", "https://a.example.com,https://b.example.com", "--cors-allowed-methods", "GET,POST", "--cors-allowed-headers", "Content-Type", "--cors-allow-credentials"},
+			want: withDefaults(server.ServerConfig{
+				CorsAllowedOrigins:   []string{"https://a.example.com", "https://b.example.com"},
+				CorsAllowedMethods:   []string{"GET", "POST"},
+				CorsAllowedHeaders:   []string{"Content-Type"},
+				CorsAllowCredentials: true,
+			}),
+		},
+		{
+			desc: "mcp tool call timeout",
+			args: []string{"--mcp-tool-call-timeout", "30s"},
+			want: withDefaults(server.ServerConfig{
+				McpToolCallTimeout: 30 * time.Second,
+			}),
+		},
+		{
+			desc: "tools list page size",
+			args: []string{"--tools-list-page-size", "50"},
+			want: withDefaults(server.ServerConfig{
+				ToolsListPageSize: 50,
+			}),
+		},
+		{
+			desc: "execution metadata",
+			args: []string{"--execution-metadata"},
+			want: withDefaults(server.ServerConfig{
+				ExecutionMetadata: true,
+			}),
+		},
+		{
+			desc: "arguments key",
+			args: []string{"--arguments-key", "args"},
+			want: withDefaults(server.ServerConfig{
+				ArgumentsKey: "args",
+			}),
+		},
+		{
+			desc: "tool name mode",
+			args: []string{"--tool-name-mode", "escape"},
+			want: withDefaults(server.ServerConfig{
+				ToolNameMode: "escape",
+			}),
+		},
+		{
+			desc: "stdio framing",
+			args: []string{"--stdio-framing", "content-length"},
+			want: withDefaults(server.ServerConfig{
+				StdioFraming: "content-length",
+			}),
+		},
+		{
+			desc: "time zone",
+			args: []string{"--time-zone", "UTC"},
+			want: withDefaults(server.ServerConfig{
+				TimeZone: "UTC",
+			}),
+		},
+		{
+			desc: "invocation queue",
+			args: []string{"--max-concurrent-invocations", "4", "--invocation-queue-depth", "8", "--invocation-queue-timeout", "250ms"},
+			want: withDefaults(server.ServerConfig{
+				MaxConcurrentInvocations: 4,
+				InvocationQueueDepth:     8,
+				InvocationQueueTimeout:   250 * time.Millisecond,
+			}),
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -269,6 +478,18 @@ func TestFailServerConfigFlags(t *testing.T) {
 			desc: "debug logs",
 			args: []string{"--log-level", "fail"},
 		},
+		{
+			desc: "tool name mode",
+			args: []string{"--tool-name-mode", "fail"},
+		},
+		{
+			desc: "time zone",
+			args: []string{"--time-zone", "Not/AZone"},
+		},
+		{
+			desc: "stdio framing",
+			args: []string{"--stdio-framing", "lsp"},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -376,6 +597,31 @@ func TestParseToolFile(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "with prompts",
+			in: `
+			prompts:
+				plan_trip:
+					description: Plan a trip to a city.
+					arguments:
+						- name: city
+							type: string
+							description: the city to visit
+					message: Plan a trip to {{.city}}.
+			`,
+			wantToolsFile: ToolsFile{
+				Prompts: server.PromptConfigs{
+					"plan_trip": tools.PromptConfig{
+						Name:        "plan_trip",
+						Description: "Plan a trip to a city.",
+						Arguments: tools.Parameters{
+							tools.NewStringParameter("city", "the city to visit"),
+						},
+						Message: "Plan a trip to {{.city}}.",
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.description, func(t *testing.T) {
@@ -395,6 +641,9 @@ func TestParseToolFile(t *testing.T) {
 			if diff := cmp.Diff(tc.wantToolsFile.Toolsets, toolsFile.Toolsets); diff != "" {
 				t.Fatalf("incorrect tools parse: diff %v", diff)
 			}
+			if diff := cmp.Diff(tc.wantToolsFile.Prompts, toolsFile.Prompts); diff != "" {
+				t.Fatalf("incorrect prompts parse: diff %v", diff)
+			}
 		})
 	}
 
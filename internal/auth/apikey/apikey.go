@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+)
+
+const AuthServiceKind string = "api-key"
+
+// HeaderName is the header that API keys are read from.
+const HeaderName = "X-API-Key"
+
+// validate interface
+var _ auth.AuthServiceConfig = Config{}
+
+// Auth service configuration
+type Config struct {
+	Name string   `yaml:"name" validate:"required"`
+	Kind string   `yaml:"kind" validate:"required"`
+	Key  string   `yaml:"key" validate:"required_without=Keys"`
+	Keys []string `yaml:"keys" validate:"required_without=Key"`
+}
+
+// Returns the auth service kind
+func (cfg Config) AuthServiceConfigKind() string {
+	return AuthServiceKind
+}
+
+// Initialize an API key auth service
+func (cfg Config) Initialize() (auth.AuthService, error) {
+	keys := slices.Clone(cfg.Keys)
+	if cfg.Key != "" {
+		keys = append(keys, cfg.Key)
+	}
+	if slices.Contains(keys, "") {
+		return nil, fmt.Errorf("API keys must not be empty")
+	}
+	subjects := make([]string, len(keys))
+	for i, k := range keys {
+		subjects[i] = keySubject(cfg.Name, k)
+	}
+	a := &AuthService{
+		Name:     cfg.Name,
+		Kind:     AuthServiceKind,
+		keys:     keys,
+		subjects: subjects,
+	}
+	return a, nil
+}
+
+// keySubject returns the subject of the callers of the named auth service with
+// key, which tells keys apart without revealing them.
+func keySubject(name, key string) string {
+	mac := hmac.New(sha256.New, []byte(name))
+	mac.Write([]byte(key))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+var _ auth.AuthService = AuthService{}
+
+// struct used to store auth service info
+type AuthService struct {
+	Name string `yaml:"name"`
+	Kind string `yaml:"kind"`
+	keys []string
+	// subjects are the `sub` claims of the keys, by their index in keys
+	subjects []string
+}
+
+// Returns the auth service kind
+func (a AuthService) AuthServiceKind() string {
+	return AuthServiceKind
+}
+
+// Returns the name of the auth service
+func (a AuthService) GetName() string {
+	return a.Name
+}
+
+// Verifies the API key in the X-API-Key header. A valid key has no claims
+// other than the name of the auth service and a `sub` that identifies the key.
+func (a AuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
+	key := h.Get(HeaderName)
+	if key == "" {
+		return nil, nil
+	}
+	for i, k := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			return map[string]any{"authService": a.Name, "sub": a.subjects[i]}, nil
+		}
+	}
+	return nil, fmt.Errorf("API key verification failure: invalid key")
+}
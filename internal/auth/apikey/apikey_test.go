@@ -0,0 +1,201 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth/apikey"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlAPIKey(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.AuthServiceConfigs
+	}{
+		{
+			desc: "single key",
+			in: `
+			authServices:
+				my-api-key:
+					kind: api-key
+					key: secret
+			`,
+			want: server.AuthServiceConfigs{
+				"my-api-key": apikey.Config{
+					Name: "my-api-key",
+					Kind: apikey.AuthServiceKind,
+					Key:  "secret",
+				},
+			},
+		},
+		{
+			desc: "list of keys",
+			in: `
+			authServices:
+				my-api-key:
+					kind: api-key
+					keys:
+						- secret-1
+						- secret-2
+			`,
+			want: server.AuthServiceConfigs{
+				"my-api-key": apikey.Config{
+					Name: "my-api-key",
+					Kind: apikey.AuthServiceKind,
+					Keys: []string{"secret-1", "secret-2"},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				AuthServices server.AuthServiceConfigs `yaml:"authServices"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.AuthServices); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlAPIKey(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{
+			desc: "missing key",
+			in: `
+			authServices:
+				my-api-key:
+					kind: api-key
+			`,
+			err: `unable to parse as "api-key"`,
+		},
+		{
+			desc: "extra field",
+			in: `
+			authServices:
+				my-api-key:
+					kind: api-key
+					key: secret
+					clientId: my-client-id
+			`,
+			err: `unknown field "clientId"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				AuthServices server.AuthServiceConfigs `yaml:"authServices"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err == nil {
+				t.Fatalf("expect parsing to fail")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %q, want substring %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestGetClaimsFromHeader(t *testing.T) {
+	a, err := apikey.Config{Name: "my-api-key", Kind: apikey.AuthServiceKind, Keys: []string{"secret-1", "secret-2"}}.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	tcs := []struct {
+		desc       string
+		key        string
+		wantClaims bool
+		wantErr    bool
+	}{
+		{desc: "valid key", key: "secret-2", wantClaims: true},
+		{desc: "missing key"},
+		{desc: "wrong key", key: "wrong", wantErr: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			h := http.Header{}
+			if tc.key != "" {
+				h.Set(apikey.HeaderName, tc.key)
+			}
+			claims, err := a.GetClaimsFromHeader(context.Background(), h)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (claims != nil) != tc.wantClaims {
+				t.Fatalf("unexpected claims: %v", claims)
+			}
+		})
+	}
+}
+
+func TestGetClaimsFromHeaderSubject(t *testing.T) {
+	a, err := apikey.Config{Name: "my-api-key", Kind: apikey.AuthServiceKind, Keys: []string{"secret-1", "secret-2"}}.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	subject := func(key string) string {
+		t.Helper()
+		h := http.Header{}
+		h.Set(apikey.HeaderName, key)
+		claims, err := a.GetClaimsFromHeader(context.Background(), h)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		sub, ok := claims["sub"].(string)
+		if !ok || sub == "" {
+			t.Fatalf("missing sub claim: %v", claims)
+		}
+		return sub
+	}
+	sub1, sub2 := subject("secret-1"), subject("secret-2")
+	if sub1 == sub2 {
+		t.Fatalf("keys have the same sub %q", sub1)
+	}
+	if sub1 != subject("secret-1") {
+		t.Fatalf("sub of a key changed between requests")
+	}
+	if strings.Contains(sub1, "secret-1") {
+		t.Fatalf("sub %q reveals the key", sub1)
+	}
+}
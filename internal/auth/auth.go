@@ -17,6 +17,7 @@ package auth
 import (
 	"context"
 	"net/http"
+	"strings"
 )
 
 // AuthServiceConfig is the interface for configuring authentication services.
@@ -31,3 +32,40 @@ type AuthService interface {
 	GetName() string
 	GetClaimsFromHeader(context.Context, http.Header) (map[string]any, error)
 }
+
+// TokenHeaderService is implemented by the auth services whose tokens can be
+// read from a configured header instead of the "<name>_token" header.
+type TokenHeaderService interface {
+	TokenHeader() string
+}
+
+// DefaultTokenHeader returns the header that the tokens of the named auth
+// service are read from when it doesn't configure one.
+func DefaultTokenHeader(name string) string {
+	return name + "_token"
+}
+
+// TokenFromHeader returns the raw token sent for the named auth service,
+// which is read from the "<name>_token" header.
+func TokenFromHeader(h http.Header, name string) string {
+	return Token(h, DefaultTokenHeader(name))
+}
+
+// TokenFromServiceHeader returns the raw token sent for a, which is read from
+// its configured header if it has one.
+func TokenFromServiceHeader(h http.Header, a AuthService) string {
+	if t, ok := a.(TokenHeaderService); ok {
+		return Token(h, t.TokenHeader())
+	}
+	return TokenFromHeader(h, a.GetName())
+}
+
+// Token returns the token sent in the given header, without its "Bearer "
+// prefix if it has one, as in an Authorization header.
+func Token(h http.Header, header string) string {
+	v := strings.TrimSpace(h.Get(header))
+	if len(v) > len("Bearer ") && strings.EqualFold(v[:len("Bearer ")], "Bearer ") {
+		v = strings.TrimSpace(v[len("Bearer "):])
+	}
+	return v
+}
@@ -33,6 +33,9 @@ type Config struct {
 	Name     string `yaml:"name" validate:"required"`
 	Kind     string `yaml:"kind" validate:"required"`
 	ClientID string `yaml:"clientId" validate:"required"`
+	// HeaderName is the header that tokens are read from, such as
+	// Authorization. Defaults to "<name>_token".
+	HeaderName string `yaml:"headerName"`
 }
 
 // Returns the auth service kind
@@ -43,20 +46,23 @@ func (cfg Config) AuthServiceConfigKind() string {
 // Initialize a Google auth service
 func (cfg Config) Initialize() (auth.AuthService, error) {
 	a := &AuthService{
-		Name:     cfg.Name,
-		Kind:     AuthServiceKind,
-		ClientID: cfg.ClientID,
+		Name:       cfg.Name,
+		Kind:       AuthServiceKind,
+		ClientID:   cfg.ClientID,
+		HeaderName: cfg.HeaderName,
 	}
 	return a, nil
 }
 
 var _ auth.AuthService = AuthService{}
+var _ auth.TokenHeaderService = AuthService{}
 
 // struct used to store auth service info
 type AuthService struct {
-	Name     string `yaml:"name"`
-	Kind     string `yaml:"kind"`
-	ClientID string `yaml:"clientId"`
+	Name       string `yaml:"name"`
+	Kind       string `yaml:"kind"`
+	ClientID   string `yaml:"clientId"`
+	HeaderName string `yaml:"headerName"`
 }
 
 // Returns the auth service kind
@@ -69,9 +75,17 @@ func (a AuthService) GetName() string {
 	return a.Name
 }
 
+// Returns the header that tokens are read from
+func (a AuthService) TokenHeader() string {
+	if a.HeaderName != "" {
+		return a.HeaderName
+	}
+	return auth.DefaultTokenHeader(a.Name)
+}
+
 // Verifies Google ID token and return claims
 func (a AuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
-	if token := h.Get(a.Name + "_token"); token != "" {
+	if token := auth.Token(h, a.TokenHeader()); token != "" {
 		payload, err := idtoken.Validate(ctx, token, a.ClientID)
 		if err != nil {
 			return nil, fmt.Errorf("Google ID token verification failure: %w", err) //nolint:staticcheck
@@ -0,0 +1,175 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// keysTTL is how long fetched keys are used before they are refreshed.
+	keysTTL = time.Hour
+	// minRefreshInterval limits how often an unknown key ID triggers a
+	// refresh, so that bad tokens can't be used to flood the issuer.
+	minRefreshInterval = time.Minute
+)
+
+// jwk is a JSON Web Key, as served by the jwks_uri endpoint.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA keys
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC keys
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keySet caches the signing keys of an issuer by key ID. Keys are refetched
+// once they are older than keysTTL, or when a token is signed with a key ID
+// that isn't known yet, which is how issuers rotate keys.
+type keySet struct {
+	client  *http.Client
+	jwksURI string
+	// now is replaced in tests.
+	now func() time.Time
+
+	mu      sync.Mutex
+	keys    map[string]any
+	fetched time.Time
+}
+
+func newKeySet(client *http.Client, jwksURI string) *keySet {
+	return &keySet{client: client, jwksURI: jwksURI, now: time.Now}
+}
+
+// key returns the public key with the given key ID. An empty key ID matches
+// the only key of a set with a single key.
+func (s *keySet) key(ctx context.Context, kid string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	age := s.now().Sub(s.fetched)
+	if s.keys == nil || age > keysTTL {
+		if err := s.refresh(ctx); err != nil {
+			return nil, err
+		}
+	} else if _, ok := s.lookup(kid); !ok && age > minRefreshInterval {
+		if err := s.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+	k, ok := s.lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("no signing key found for key ID %q", kid)
+	}
+	return k, nil
+}
+
+func (s *keySet) lookup(kid string) (any, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, k := range s.keys {
+			return k, true
+		}
+	}
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+// refresh fetches the keys from the jwks_uri. Keys that can't be parsed, or
+// that aren't meant for signatures, are skipped.
+func (s *keySet) refresh(ctx context.Context) error {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(ctx, s.client, s.jwksURI, &set); err != nil {
+		return fmt.Errorf("unable to fetch OIDC signing keys: %w", err)
+	}
+	keys := make(map[string]any)
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	s.keys = keys
+	s.fetched = s.now()
+	return nil
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
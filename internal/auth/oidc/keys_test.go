@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeySetRotation(t *testing.T) {
+	// the served key ID changes on every fetch of the JWKS
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := fetches.Add(1)
+		fmt.Fprintf(w, `{"keys": [{"kty": "RSA", "kid": "key-%d", "use": "sig", "n": "AQAB", "e": "AQAB"}]}`, n)
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	s := newKeySet(srv.Client(), srv.URL)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := s.key(ctx, "key-1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// an unknown key ID right after a fetch doesn't refetch
+	if _, err := s.key(ctx, "key-2"); err == nil {
+		t.Fatalf("expected an error for an unknown key ID")
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Fatalf("unexpected number of fetches: got %d, want 1", got)
+	}
+
+	// once the refresh interval has passed, an unknown key ID refetches
+	now = now.Add(2 * minRefreshInterval)
+	if _, err := s.key(ctx, "key-2"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Fatalf("unexpected number of fetches: got %d, want 2", got)
+	}
+
+	// known keys are refetched once they expire
+	now = now.Add(2 * keysTTL)
+	if _, err := s.key(ctx, "key-2"); err == nil {
+		t.Fatalf("expected the rotated out key to be gone")
+	}
+	if got := fetches.Load(); got != 3 {
+		t.Fatalf("unexpected number of fetches: got %d, want 3", got)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+)
+
+const AuthServiceKind string = "oidc"
+
+// discoveryTimeout bounds the requests made to the issuer.
+const discoveryTimeout = 10 * time.Second
+
+// defaultSigningAlgs are accepted if the issuer doesn't advertise any.
+var defaultSigningAlgs = []string{"RS256"}
+
+// validate interface
+var _ auth.AuthServiceConfig = Config{}
+
+// Auth service configuration
+type Config struct {
+	Name     string `yaml:"name" validate:"required"`
+	Kind     string `yaml:"kind" validate:"required"`
+	Issuer   string `yaml:"issuer" validate:"required"`
+	ClientID string `yaml:"clientId" validate:"required"`
+	// HeaderName is the header that tokens are read from, such as
+	// Authorization. Defaults to "<name>_token".
+	HeaderName string `yaml:"headerName"`
+}
+
+// Returns the auth service kind
+func (cfg Config) AuthServiceConfigKind() string {
+	return AuthServiceKind
+}
+
+// discovery is the subset of the OpenID Provider metadata that is used.
+type discovery struct {
+	Issuer      string   `json:"issuer"`
+	JWKSURI     string   `json:"jwks_uri"`
+	SigningAlgs []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// Initialize an OIDC auth service. The issuer's discovery document is fetched
+// once; its signing keys are fetched on first use and refreshed as they
+// rotate.
+func (cfg Config) Initialize() (auth.AuthService, error) {
+	client := &http.Client{Timeout: discoveryTimeout}
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+
+	issuer := strings.TrimSuffix(cfg.Issuer, "/")
+	var d discovery
+	if err := getJSON(ctx, client, issuer+"/.well-known/openid-configuration", &d); err != nil {
+		return nil, fmt.Errorf("unable to fetch OIDC discovery document: %w", err)
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("OIDC discovery document issuer %q does not match %q", d.Issuer, cfg.Issuer)
+	}
+	if d.JWKSURI == "" {
+		return nil, fmt.Errorf("OIDC discovery document is missing jwks_uri")
+	}
+	algs := d.SigningAlgs
+	if len(algs) == 0 {
+		algs = defaultSigningAlgs
+	}
+
+	a := &AuthService{
+		Name:       cfg.Name,
+		Kind:       AuthServiceKind,
+		Issuer:     d.Issuer,
+		ClientID:   cfg.ClientID,
+		HeaderName: cfg.HeaderName,
+		keys:       newKeySet(client, d.JWKSURI),
+		algs:       algs,
+	}
+	return a, nil
+}
+
+var _ auth.AuthService = AuthService{}
+var _ auth.TokenHeaderService = AuthService{}
+
+// struct used to store auth service info
+type AuthService struct {
+	Name       string `yaml:"name"`
+	Kind       string `yaml:"kind"`
+	Issuer     string `yaml:"issuer"`
+	ClientID   string `yaml:"clientId"`
+	HeaderName string `yaml:"headerName"`
+	keys       *keySet
+	algs       []string
+}
+
+// Returns the auth service kind
+func (a AuthService) AuthServiceKind() string {
+	return AuthServiceKind
+}
+
+// Returns the name of the auth service
+func (a AuthService) GetName() string {
+	return a.Name
+}
+
+// Returns the header that tokens are read from
+func (a AuthService) TokenHeader() string {
+	if a.HeaderName != "" {
+		return a.HeaderName
+	}
+	return auth.DefaultTokenHeader(a.Name)
+}
+
+// Verifies the OIDC ID token and returns its claims
+func (a AuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
+	token := auth.Token(h, a.TokenHeader())
+	if token == "" {
+		return nil, nil
+	}
+	keyFunc := func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.keys.key(ctx, kid)
+	}
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, keyFunc,
+		jwt.WithValidMethods(a.algs),
+		jwt.WithIssuer(a.Issuer),
+		jwt.WithAudience(a.ClientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("OIDC ID token verification failure: %w", err)
+	}
+	return claims, nil
+}
+
+// getJSON fetches url and decodes its JSON body into v.
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
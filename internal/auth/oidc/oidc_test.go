@@ -0,0 +1,349 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/auth/oidc"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlOIDC(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.AuthServiceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			authServices:
+				my-okta-auth:
+					kind: oidc
+					issuer: https://example.okta.com
+					clientId: my-client-id
+			`,
+			want: server.AuthServiceConfigs{
+				"my-okta-auth": oidc.Config{
+					Name:     "my-okta-auth",
+					Kind:     oidc.AuthServiceKind,
+					Issuer:   "https://example.okta.com",
+					ClientID: "my-client-id",
+				},
+			},
+		},
+		{
+			desc: "custom header",
+			in: `
+			authServices:
+				my-okta-auth:
+					kind: oidc
+					issuer: https://example.okta.com
+					clientId: my-client-id
+					headerName: Authorization
+			`,
+			want: server.AuthServiceConfigs{
+				"my-okta-auth": oidc.Config{
+					Name:       "my-okta-auth",
+					Kind:       oidc.AuthServiceKind,
+					Issuer:     "https://example.okta.com",
+					ClientID:   "my-client-id",
+					HeaderName: "Authorization",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				AuthServices server.AuthServiceConfigs `yaml:"authServices"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.AuthServices); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+// mockIssuer serves a discovery document and a JWKS with the keys in keys.
+type mockIssuer struct {
+	*httptest.Server
+	keys map[string]*rsa.PrivateKey
+	// issuer overrides the issuer advertised by the discovery document.
+	issuer string
+}
+
+func newMockIssuer(t *testing.T, kids ...string) *mockIssuer {
+	m := &mockIssuer{keys: make(map[string]*rsa.PrivateKey)}
+	for _, kid := range kids {
+		k, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("unable to generate key: %s", err)
+		}
+		m.keys[kid] = k
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		issuer := m.issuer
+		if issuer == "" {
+			issuer = m.URL
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                issuer,
+			"jwks_uri":                              m.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		var keys []map[string]string
+		for kid, k := range m.keys {
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"kid": kid,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	})
+	m.Server = httptest.NewServer(mux)
+	return m
+}
+
+func (m *mockIssuer) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	return m.signWithKid(t, kid, kid, claims)
+}
+
+// signWithKid signs claims with the key kid, but sets headerKid as the key ID
+// of the token.
+func (m *mockIssuer) signWithKid(t *testing.T, kid, headerKid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = headerKid
+	s, err := token.SignedString(m.keys[kid])
+	if err != nil {
+		t.Fatalf("unable to sign token: %s", err)
+	}
+	return s
+}
+
+func TestGetClaimsFromHeader(t *testing.T) {
+	issuer := newMockIssuer(t, "key-1")
+	defer issuer.Close()
+	other := newMockIssuer(t, "key-1")
+	defer other.Close()
+
+	cfg := oidc.Config{
+		Name:     "my-okta-auth",
+		Kind:     oidc.AuthServiceKind,
+		Issuer:   issuer.URL,
+		ClientID: "my-client-id",
+	}
+	a, err := cfg.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+	tcs := []struct {
+		desc    string
+		token   string
+		wantSub string
+		wantErr string
+	}{
+		{
+			desc:    "valid token",
+			token:   issuer.sign(t, "key-1", jwt.MapClaims{"iss": issuer.URL, "aud": "my-client-id", "sub": "alice", "exp": exp}),
+			wantSub: "alice",
+		},
+		{
+			desc:    "wrong audience",
+			token:   issuer.sign(t, "key-1", jwt.MapClaims{"iss": issuer.URL, "aud": "other-client", "sub": "alice", "exp": exp}),
+			wantErr: "token has invalid audience",
+		},
+		{
+			desc:    "wrong issuer",
+			token:   issuer.sign(t, "key-1", jwt.MapClaims{"iss": "https://evil.example.com", "aud": "my-client-id", "sub": "alice", "exp": exp}),
+			wantErr: "token has invalid issuer",
+		},
+		{
+			desc:    "expired",
+			token:   issuer.sign(t, "key-1", jwt.MapClaims{"iss": issuer.URL, "aud": "my-client-id", "sub": "alice", "exp": time.Now().Add(-time.Hour).Unix()}),
+			wantErr: "token is expired",
+		},
+		{
+			desc:    "missing expiry",
+			token:   issuer.sign(t, "key-1", jwt.MapClaims{"iss": issuer.URL, "aud": "my-client-id", "sub": "alice"}),
+			wantErr: "token is missing required claim",
+		},
+		{
+			desc:    "signed by another key",
+			token:   other.sign(t, "key-1", jwt.MapClaims{"iss": issuer.URL, "aud": "my-client-id", "sub": "alice", "exp": exp}),
+			wantErr: "token signature is invalid",
+		},
+		{
+			desc:    "unknown key ID",
+			token:   issuer.signWithKid(t, "key-1", "key-2", jwt.MapClaims{"iss": issuer.URL, "aud": "my-client-id", "sub": "alice", "exp": exp}),
+			wantErr: `no signing key found for key ID "key-2"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			h := http.Header{}
+			h.Set("my-okta-auth_token", tc.token)
+			claims, err := a.GetClaimsFromHeader(context.Background(), h)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if claims["sub"] != tc.wantSub {
+				t.Fatalf("unexpected sub claim: got %v, want %q", claims["sub"], tc.wantSub)
+			}
+		})
+	}
+
+	// no token is not an error
+	claims, err := a.GetClaimsFromHeader(context.Background(), http.Header{})
+	if claims != nil || err != nil {
+		t.Fatalf("expected no claims and no error without a token, got %v, %v", claims, err)
+	}
+}
+
+func TestGetClaimsFromTokenHeader(t *testing.T) {
+	issuer := newMockIssuer(t, "key-1")
+	defer issuer.Close()
+	token := issuer.sign(t, "key-1", jwt.MapClaims{"iss": issuer.URL, "aud": "my-client-id", "sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+
+	tcs := []struct {
+		desc       string
+		headerName string
+		header     string
+		value      string
+		wantClaims bool
+	}{
+		{
+			desc:       "derived header",
+			header:     "my-okta-auth_token",
+			value:      token,
+			wantClaims: true,
+		},
+		{
+			desc:       "derived header with bearer prefix",
+			header:     "my-okta-auth_token",
+			value:      "Bearer " + token,
+			wantClaims: true,
+		},
+		{
+			desc:       "authorization header",
+			headerName: "Authorization",
+			header:     "Authorization",
+			value:      "Bearer " + token,
+			wantClaims: true,
+		},
+		{
+			desc:       "authorization header with lowercase prefix",
+			headerName: "Authorization",
+			header:     "Authorization",
+			value:      "bearer " + token,
+			wantClaims: true,
+		},
+		{
+			desc:       "derived header ignored with a custom header",
+			headerName: "Authorization",
+			header:     "my-okta-auth_token",
+			value:      token,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := oidc.Config{
+				Name:       "my-okta-auth",
+				Kind:       oidc.AuthServiceKind,
+				Issuer:     issuer.URL,
+				ClientID:   "my-client-id",
+				HeaderName: tc.headerName,
+			}
+			a, err := cfg.Initialize()
+			if err != nil {
+				t.Fatalf("unable to initialize auth service: %s", err)
+			}
+			h := http.Header{}
+			h.Set(tc.header, tc.value)
+			claims, err := a.GetClaimsFromHeader(context.Background(), h)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !tc.wantClaims {
+				if claims != nil {
+					t.Fatalf("expected no claims, got %v", claims)
+				}
+				return
+			}
+			if claims["sub"] != "alice" {
+				t.Fatalf("unexpected sub claim: got %v, want %q", claims["sub"], "alice")
+			}
+			if got := auth.TokenFromServiceHeader(h, a); got != token {
+				t.Fatalf("unexpected raw token: got %q, want %q", got, token)
+			}
+		})
+	}
+}
+
+func TestInitializeIssuerMismatch(t *testing.T) {
+	issuer := newMockIssuer(t, "key-1")
+	defer issuer.Close()
+	issuer.issuer = "https://other.example.com"
+
+	cfg := oidc.Config{
+		Name:     "my-okta-auth",
+		Kind:     oidc.AuthServiceKind,
+		Issuer:   issuer.URL,
+		ClientID: "my-client-id",
+	}
+	_, err := cfg.Initialize()
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("unexpected error: got %v, want an issuer mismatch", err)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	otellog "go.opentelemetry.io/otel/log"
+)
+
+// OTelLoggerName is the instrumentation scope of the emitted log records.
+const OTelLoggerName = "github.com/googleapis/genai-toolbox/internal/log"
+
+// OTelLogger is a Logger that also emits its messages as OpenTelemetry log
+// records. Records are correlated with the span of the context they are
+// logged with, if any.
+type OTelLogger struct {
+	Logger
+	otelLogger otellog.Logger
+	level      slog.Level
+}
+
+// NewOTelLogger creates a Logger that logs messages with next, and emits the
+// messages at logLevel or above to provider.
+func NewOTelLogger(next Logger, provider otellog.LoggerProvider, logLevel string) (Logger, error) {
+	level, err := SeverityToLevel(logLevel)
+	if err != nil {
+		return nil, err
+	}
+	return &OTelLogger{Logger: next, otelLogger: provider.Logger(OTelLoggerName), level: level}, nil
+}
+
+// DebugContext logs debug messages
+func (l *OTelLogger) DebugContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.Logger.DebugContext(ctx, msg, keysAndValues...)
+	l.emit(ctx, slog.LevelDebug, otellog.SeverityDebug, msg, keysAndValues)
+}
+
+// InfoContext logs info messages
+func (l *OTelLogger) InfoContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.Logger.InfoContext(ctx, msg, keysAndValues...)
+	l.emit(ctx, slog.LevelInfo, otellog.SeverityInfo, msg, keysAndValues)
+}
+
+// WarnContext logs warning messages
+func (l *OTelLogger) WarnContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.Logger.WarnContext(ctx, msg, keysAndValues...)
+	l.emit(ctx, slog.LevelWarn, otellog.SeverityWarn, msg, keysAndValues)
+}
+
+// ErrorContext logs error messages
+func (l *OTelLogger) ErrorContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.Logger.ErrorContext(ctx, msg, keysAndValues...)
+	l.emit(ctx, slog.LevelError, otellog.SeverityError, msg, keysAndValues)
+}
+
+// emit emits msg as a log record. keysAndValues are added as attributes, and
+// are interpreted the same way as by slog.
+func (l *OTelLogger) emit(ctx context.Context, level slog.Level, severity otellog.Severity, msg string, keysAndValues []interface{}) {
+	if level < l.level {
+		return
+	}
+	var r otellog.Record
+	r.SetTimestamp(time.Now())
+	r.SetSeverity(severity)
+	r.SetSeverityText(level.String())
+	r.SetBody(otellog.StringValue(msg))
+
+	sr := slog.NewRecord(time.Time{}, level, msg, 0)
+	sr.Add(keysAndValues...)
+	sr.Attrs(func(a slog.Attr) bool {
+		r.AddAttributes(otellog.KeyValue{Key: a.Key, Value: otelValue(a.Value)})
+		return true
+	})
+	// the span context of ctx is added to the record by the SDK
+	l.otelLogger.Emit(ctx, r)
+}
+
+// otelValue converts v to an OpenTelemetry log value.
+func otelValue(v slog.Value) otellog.Value {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindBool:
+		return otellog.BoolValue(v.Bool())
+	case slog.KindInt64:
+		return otellog.Int64Value(v.Int64())
+	case slog.KindFloat64:
+		return otellog.Float64Value(v.Float64())
+	default:
+		return otellog.StringValue(v.String())
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// authenticate returns the claims of the auth tokens in h, along with the raw
+// tokens, by the name of the auth service that verified them. Tokens that
+// can't be verified are ignored.
+func (s *Server) authenticate(ctx context.Context, h http.Header) (map[string]map[string]any, map[string]string) {
+	claimsFromAuth := make(map[string]map[string]any)
+	authTokens := make(map[string]string)
+	for _, aS := range s.getAuthServices() {
+		claims, err := aS.GetClaimsFromHeader(ctx, h)
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			continue
+		}
+		if claims == nil {
+			// authService not present in header
+			continue
+		}
+		claimsFromAuth[aS.GetName()] = claims
+		authTokens[aS.GetName()] = auth.TokenFromServiceHeader(h, aS)
+	}
+	return claimsFromAuth, authTokens
+}
+
+// verifiedAuthServices returns the names of the auth services whose claims
+// are in ctx.
+func verifiedAuthServices(ctx context.Context) []string {
+	return slices.Sorted(maps.Keys(tools.AllClaimsFromContext(ctx)))
+}
+
+// listableManifest returns a copy of m without the tools that the caller,
+// whose claims are in ctx, can't list.
+func (s *Server) listableManifest(ctx context.Context, m tools.ToolsetManifest) tools.ToolsetManifest {
+	filtered := tools.ToolsetManifest{
+		ServerVersion: m.ServerVersion,
+		ToolsManifest: make(map[string]tools.Manifest, len(m.ToolsManifest)),
+	}
+	for name, tm := range m.ToolsManifest {
+		if t, ok := s.getTool(name); !ok || tools.CanList(ctx, t) {
+			filtered.ToolsManifest[name] = tm
+		}
+	}
+	return filtered
+}
+
+// listableMcpManifest returns a copy of m without the tools that the caller,
+// whose claims are in ctx, can't list.
+func (s *Server) listableMcpManifest(ctx context.Context, m []tools.McpManifest) []tools.McpManifest {
+	filtered := make([]tools.McpManifest, 0, len(m))
+	for _, tm := range m {
+		if t, ok := s.getTool(tm.Name); !ok || tools.CanList(ctx, t) {
+			filtered = append(filtered, tm)
+		}
+	}
+	return filtered
+}
+
+// toolForbiddenError returns the error reported when the caller doesn't
+// satisfy the invokeRequired rules of a tool.
+func toolForbiddenError(name string) error {
+	return fmt.Errorf("caller is not allowed to invoke tool %q", name)
+}
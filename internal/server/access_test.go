@@ -0,0 +1,132 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestToolAccessRules(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"payroll": tools.ToolWithOptions{
+			Tool: MockTool{Name: "payroll"},
+			Options: tools.Options{
+				// visible to managers, but only invocable by hr
+				ListRequired:   []tools.AccessRule{{AuthService: "my-role-auth", Claim: "roles", Values: []string{"manager", "hr"}}},
+				InvokeRequired: []tools.AccessRule{{AuthService: "my-role-auth", Claim: "roles", Values: []string{"hr"}}},
+			},
+		},
+		"public": MockTool{Name: "public"},
+	}
+	toolset, err := tools.ToolsetConfig{Name: "", ToolNames: []string{"payroll", "public"}}.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, map[string]tools.Toolset{"": toolset}, func(s *Server) {
+		s.authServices = map[string]auth.AuthService{"my-role-auth": roleAuthService{}}
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	send := func(t *testing.T, method, path, role string) (*http.Response, []byte) {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+path, bytes.NewBufferString(`{}`))
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if role != "" {
+			req.Header.Set("my-role-auth_token", role)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		defer resp.Body.Close()
+		var body bytes.Buffer
+		if _, err := body.ReadFrom(resp.Body); err != nil {
+			t.Fatalf("unable to read body: %s", err)
+		}
+		return resp, body.Bytes()
+	}
+
+	tcs := []struct {
+		name       string
+		role       string
+		wantListed bool
+		wantInvoke int
+	}{
+		{
+			name:       "privileged caller",
+			role:       "hr",
+			wantListed: true,
+			wantInvoke: http.StatusOK,
+		},
+		{
+			name:       "caller who can only list",
+			role:       "manager",
+			wantListed: true,
+			wantInvoke: http.StatusForbidden,
+		},
+		{
+			name:       "unprivileged caller",
+			role:       "engineering",
+			wantInvoke: http.StatusForbidden,
+		},
+		{
+			name:       "anonymous caller",
+			wantInvoke: http.StatusForbidden,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body := send(t, http.MethodGet, "/toolset", tc.role)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, body)
+			}
+			var m tools.ToolsetManifest
+			if err := json.Unmarshal(body, &m); err != nil {
+				t.Fatalf("unable to parse toolset manifest: %s", err)
+			}
+			if _, ok := m.ToolsManifest["public"]; !ok {
+				t.Fatalf("expected the public tool to be listed")
+			}
+			if _, ok := m.ToolsManifest["payroll"]; ok != tc.wantListed {
+				t.Fatalf("unexpected listing of the payroll tool: got %t, want %t", ok, tc.wantListed)
+			}
+
+			resp, body = send(t, http.MethodGet, "/tool/payroll", tc.role)
+			wantGet := http.StatusNotFound
+			if tc.wantListed {
+				wantGet = http.StatusOK
+			}
+			if resp.StatusCode != wantGet {
+				t.Fatalf("unexpected status code for the manifest: got %d, want %d: %s", resp.StatusCode, wantGet, body)
+			}
+
+			resp, body = send(t, http.MethodPost, "/tool/payroll/invoke", tc.role)
+			if resp.StatusCode != tc.wantInvoke {
+				t.Fatalf("unexpected status code for the invocation: got %d, want %d: %s", resp.StatusCode, tc.wantInvoke, body)
+			}
+		})
+	}
+}
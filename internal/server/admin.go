@@ -0,0 +1,244 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// codeToolDisabled is the error code used when a disabled tool is invoked.
+const codeToolDisabled = "TOOL_DISABLED"
+
+// yamlContentTypes are the Content-Types accepted for tool configs. JSON is
+// accepted too, since it is a subset of YAML.
+var yamlContentTypes = []string{"application/yaml", "application/x-yaml", "text/yaml", "application/json"}
+
+// errToolNotConfigured is returned when reloading a tool that isn't configured.
+var errToolNotConfigured = errors.New("no tool configured with name")
+
+// disabledTools is the set of tools that have been disabled at runtime. The
+// set isn't persisted, so all tools are enabled again on restart.
+type disabledTools struct {
+	mu    sync.RWMutex
+	names map[string]bool
+}
+
+// set disables the named tool if disabled is true, and enables it otherwise.
+func (d *disabledTools) set(name string, disabled bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !disabled {
+		delete(d.names, name)
+		return
+	}
+	if d.names == nil {
+		d.names = make(map[string]bool)
+	}
+	d.names[name] = true
+}
+
+// has reports whether the named tool is disabled.
+func (d *disabledTools) has(name string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.names[name]
+}
+
+// filterManifest returns a copy of m without the disabled tools.
+func (d *disabledTools) filterManifest(m tools.ToolsetManifest) tools.ToolsetManifest {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if len(d.names) == 0 {
+		return m
+	}
+	filtered := tools.ToolsetManifest{
+		ServerVersion: m.ServerVersion,
+		ToolsManifest: make(map[string]tools.Manifest, len(m.ToolsManifest)),
+	}
+	for name, tm := range m.ToolsManifest {
+		if !d.names[name] {
+			filtered.ToolsManifest[name] = tm
+		}
+	}
+	return filtered
+}
+
+// filterMcpManifest returns a copy of m without the disabled tools.
+func (d *disabledTools) filterMcpManifest(m []tools.McpManifest) []tools.McpManifest {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if len(d.names) == 0 {
+		return m
+	}
+	filtered := make([]tools.McpManifest, 0, len(m))
+	for _, tm := range m {
+		if !d.names[tm.Name] {
+			filtered = append(filtered, tm)
+		}
+	}
+	return filtered
+}
+
+// toolDisabledError returns the error reported when a disabled tool is used.
+func toolDisabledError(name string) error {
+	return fmt.Errorf("tool %q is disabled", name)
+}
+
+// readAdminToken validates the admin auth of cfg and returns the admin token
+// read from cfg.AdminTokenFile, or "" if it is not set. The file is read once,
+// so that invalid files are reported on startup.
+func readAdminToken(cfg ServerConfig, authServices map[string]auth.AuthService) (string, error) {
+	if a := cfg.AdminAuth; a.AuthService != "" || a.Claim != "" || len(a.Values) > 0 {
+		if _, ok := authServices[a.AuthService]; !ok {
+			return "", fmt.Errorf("admin auth requires a configured auth service, got %q", a.AuthService)
+		}
+		if a.Claim == "" {
+			return "", fmt.Errorf("admin auth requires a claim")
+		}
+	}
+	if cfg.AdminTokenFile == "" {
+		return "", nil
+	}
+	b, err := os.ReadFile(cfg.AdminTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("unable to read admin token: %w", err)
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("admin token file %q is empty", cfg.AdminTokenFile)
+	}
+	return token, nil
+}
+
+// adminEnabled reports whether the admin endpoints are served.
+func (s *Server) adminEnabled() bool {
+	return s.adminAuth.AuthService != "" || s.adminToken != ""
+}
+
+// adminRouter creates a router that represents the routes under /api/admin.
+func adminRouter(s *Server) chi.Router {
+	r := chi.NewRouter()
+	r.Use(requireAdmin(s))
+	r.Group(func(r chi.Router) {
+		r.Use(allowContentType("application/json"))
+		r.Post("/tools/{toolName}/disable", func(w http.ResponseWriter, r *http.Request) { toolDisableHandler(s, w, r, true) })
+		r.Post("/tools/{toolName}/enable", func(w http.ResponseWriter, r *http.Request) { toolDisableHandler(s, w, r, false) })
+	})
+	r.With(allowContentType(yamlContentTypes...)).Post("/tool/{toolName}/reload", func(w http.ResponseWriter, r *http.Request) { toolReloadHandler(s, w, r) })
+	return r
+}
+
+// requireAdmin is a middleware that rejects requests whose caller neither has
+// the admin claim of s's admin auth service nor carries the admin token as a
+// bearer token in their Authorization header.
+func requireAdmin(s *Server) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !s.isAdmin(r) {
+				err := fmt.Errorf("admin request not authorized. Please make sure you specify the admin auth token or the admin token")
+				_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isAdmin reports whether the caller of r may use the admin endpoints.
+func (s *Server) isAdmin(r *http.Request) bool {
+	if s.adminToken != "" {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(got), []byte(s.adminToken)) == 1 {
+			return true
+		}
+	}
+	if s.adminAuth.AuthService == "" {
+		return false
+	}
+	claims, _ := s.authenticate(r.Context(), r.Header)
+	return s.adminAuth.Allowed(tools.WithClaims(r.Context(), claims))
+}
+
+// toolDisableHandler handles the admin requests to disable or enable a Tool.
+func toolDisableHandler(s *Server, w http.ResponseWriter, r *http.Request, disable bool) {
+	toolName := chi.URLParam(r, "toolName")
+	if _, ok := s.getTool(toolName); !ok {
+		err := fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
+		s.logger.DebugContext(r.Context(), err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+	s.disabledTools.set(toolName, disable)
+	if disable {
+		s.logger.InfoContext(r.Context(), fmt.Sprintf("disabled tool %q", toolName))
+	} else {
+		s.logger.InfoContext(r.Context(), fmt.Sprintf("enabled tool %q", toolName))
+	}
+	render.JSON(w, r, map[string]any{"tool": toolName, "disabled": disable})
+}
+
+// toolReloadHandler handles the admin requests to reload a Tool from the YAML
+// configuration in the request body.
+func toolReloadHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx := util.WithLogger(r.Context(), s.logger)
+	toolName := chi.URLParam(r, "toolName")
+
+	body, err := io.ReadAll(limitBody(w, r, s.maxRequestBytes))
+	if err != nil {
+		err = fmt.Errorf("unable to read request body: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	var v map[string]any
+	if err := yaml.UnmarshalContext(ctx, body, &v); err != nil || v == nil {
+		if err == nil {
+			err = fmt.Errorf("request body must be a YAML mapping")
+		}
+		err = fmt.Errorf("unable to parse tool config: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	tc, err := decodeTool(ctx, toolName, v)
+	if err == nil {
+		err = s.reloadTool(ctx, toolName, tc)
+	}
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, errToolNotConfigured) {
+			status = http.StatusNotFound
+		}
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, status))
+		return
+	}
+	s.logger.InfoContext(ctx, fmt.Sprintf("reloaded tool %q", toolName))
+	render.JSON(w, r, map[string]any{"tool": toolName, "reloaded": true})
+}
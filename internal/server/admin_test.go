@@ -0,0 +1,345 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const fakeAdminToken = "admin-secret"
+
+// runAdminRequest sends a POST request to an admin endpoint with token as its
+// bearer token.
+func runAdminRequest(t *testing.T, ts *httptest.Server, path, token string) int {
+	req, err := http.NewRequest(http.MethodPost, ts.URL+path, nil)
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestAdminDisableTool(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets, func(s *Server) { s.adminToken = fakeAdminToken })
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	invoke := func() (int, errResponse) {
+		resp, body, err := runRequest(ts, http.MethodPost, "/tool/no_params/invoke", bytes.NewBufferString(`{}`))
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got errResponse
+		if resp.StatusCode != http.StatusOK {
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+		}
+		return resp.StatusCode, got
+	}
+	listed := func() bool {
+		_, body, err := runRequest(ts, http.MethodGet, "/toolset", nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var m tools.ToolsetManifest
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatalf("unable to parse toolset manifest: %s", err)
+		}
+		_, ok := m.ToolsManifest["no_params"]
+		return ok
+	}
+
+	if status := runAdminRequest(t, ts, "/admin/tools/no_params/disable", ""); status != http.StatusUnauthorized {
+		t.Fatalf("unexpected status code without token: want %d, got %d", http.StatusUnauthorized, status)
+	}
+	if status := runAdminRequest(t, ts, "/admin/tools/no_params/disable", "wrong"); status != http.StatusUnauthorized {
+		t.Fatalf("unexpected status code with wrong token: want %d, got %d", http.StatusUnauthorized, status)
+	}
+	if status := runAdminRequest(t, ts, "/admin/tools/missing/disable", fakeAdminToken); status != http.StatusNotFound {
+		t.Fatalf("unexpected status code for missing tool: want %d, got %d", http.StatusNotFound, status)
+	}
+
+	if status := runAdminRequest(t, ts, "/admin/tools/no_params/disable", fakeAdminToken); status != http.StatusOK {
+		t.Fatalf("unexpected status code disabling tool: want %d, got %d", http.StatusOK, status)
+	}
+	status, got := invoke()
+	if status != http.StatusForbidden {
+		t.Fatalf("unexpected status code invoking disabled tool: want %d, got %d", http.StatusForbidden, status)
+	}
+	if got.Code != codeToolDisabled {
+		t.Fatalf("unexpected error code: want %q, got %q", codeToolDisabled, got.Code)
+	}
+	if listed() {
+		t.Fatalf("disabled tool is listed in toolset")
+	}
+	// other tools are unaffected
+	resp, _, err := runRequest(ts, http.MethodPost, "/tool/some_params/invoke", bytes.NewBufferString(`{"param1": 1, "param2": 2}`))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code invoking enabled tool: want %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	if status := runAdminRequest(t, ts, "/admin/tools/no_params/enable", fakeAdminToken); status != http.StatusOK {
+		t.Fatalf("unexpected status code enabling tool: want %d, got %d", http.StatusOK, status)
+	}
+	if status, _ := invoke(); status != http.StatusOK {
+		t.Fatalf("unexpected status code invoking re-enabled tool: want %d, got %d", http.StatusOK, status)
+	}
+	if !listed() {
+		t.Fatalf("re-enabled tool is not listed in toolset")
+	}
+}
+
+func TestAdminEndpointsDisabledWithoutToken(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	if status := runAdminRequest(t, ts, "/admin/tools/no_params/disable", ""); status != http.StatusNotFound {
+		t.Fatalf("unexpected status code: want %d, got %d", http.StatusNotFound, status)
+	}
+}
+
+func TestAdminAuthService(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets, func(s *Server) {
+		s.authServices = map[string]auth.AuthService{"my-role-auth": roleAuthService{}}
+		s.adminAuth = tools.ColumnAuth{AuthService: "my-role-auth", Claim: "roles", Values: []string{"admin"}}
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		role string
+		want int
+	}{
+		{role: "", want: http.StatusUnauthorized},
+		{role: "manager", want: http.StatusUnauthorized},
+		{role: "admin", want: http.StatusOK},
+	}
+	for _, tc := range tcs {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/admin/tools/no_params/disable", nil)
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		if tc.role != "" {
+			req.Header.Set("my-role-auth_token", tc.role)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Fatalf("role %q: unexpected status code: want %d, got %d", tc.role, tc.want, resp.StatusCode)
+		}
+	}
+}
+
+func TestReadAdminToken(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte(fakeAdminToken+"\n"), 0o600); err != nil {
+		t.Fatalf("unable to write token file: %s", err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, nil, 0o600); err != nil {
+		t.Fatalf("unable to write token file: %s", err)
+	}
+	authServices := map[string]auth.AuthService{"my-role-auth": roleAuthService{}}
+
+	tcs := []struct {
+		desc    string
+		cfg     ServerConfig
+		want    string
+		wantErr bool
+	}{
+		{desc: "no admin auth", cfg: ServerConfig{}},
+		{desc: "token file", cfg: ServerConfig{AdminTokenFile: tokenFile}, want: fakeAdminToken},
+		{desc: "missing token file", cfg: ServerConfig{AdminTokenFile: filepath.Join(dir, "missing")}, wantErr: true},
+		{desc: "empty token file", cfg: ServerConfig{AdminTokenFile: emptyFile}, wantErr: true},
+		{desc: "auth service", cfg: ServerConfig{AdminAuth: tools.ColumnAuth{AuthService: "my-role-auth", Claim: "roles"}}},
+		{desc: "unknown auth service", cfg: ServerConfig{AdminAuth: tools.ColumnAuth{AuthService: "other-auth", Claim: "roles"}}, wantErr: true},
+		{desc: "no claim", cfg: ServerConfig{AdminAuth: tools.ColumnAuth{AuthService: "my-role-auth"}}, wantErr: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := readAdminToken(tc.cfg, authServices)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: want error %t, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected token: want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestMcpDisabledTool(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets, func(s *Server) { s.disabledTools.set("no_params", true) })
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	send := func(method string, params any) []byte {
+		reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+			Jsonrpc: jsonrpcVersion,
+			Id:      method,
+			Request: mcp.Request{Method: method},
+			Params:  params,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		return body
+	}
+
+	var list struct {
+		Result mcp.ListToolsResult `json:"result"`
+	}
+	if err := json.Unmarshal(send("tools/list", nil), &list); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	for _, m := range list.Result.Tools {
+		if m.Name == "no_params" {
+			t.Fatalf("disabled tool is listed in tools/list")
+		}
+	}
+
+	var call struct {
+		Error struct {
+			Message string         `json:"message"`
+			Data    map[string]any `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(send("tools/call", map[string]any{"name": "no_params"}), &call); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if call.Error.Data["code"] != codeToolDisabled {
+		t.Fatalf("unexpected error: want code %q, got %+v", codeToolDisabled, call.Error)
+	}
+}
+
+// countingToolConfig initializes to a countingTool that uses Source.
+type countingToolConfig struct {
+	Source string
+	count  *int
+}
+
+func (countingToolConfig) ToolConfigKind() string {
+	return "fake-counting"
+}
+
+func (c countingToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	params := tools.Parameters{tools.NewStringParameter("q", "the query")}
+	return countingTool{MockTool: MockTool{Name: "counting", Params: params}, count: c.count}, nil
+}
+
+func TestReloadToolKeepsOtherTools(t *testing.T) {
+	ctx := context.Background()
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	s := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      &sseManager{sseSessions: make(map[string]*sseSession)},
+		sourceUsers:     &sourceUsers{},
+	}
+	cached := func(count *int) tools.ToolConfig {
+		return tools.ConfigWithOptions{
+			ToolConfig: countingToolConfig{Source: "my-source", count: count},
+			Options:    tools.Options{CacheTTL: time.Hour},
+		}
+	}
+	var countA, countB int
+	cfg := ServerConfig{
+		CircuitBreakerThreshold: 3,
+		CircuitBreakerCooldown:  time.Minute,
+		ToolConfigs:             ToolConfigs{"a": cached(&countA), "b": cached(&countB)},
+	}
+	if err := s.Reload(ctx, cfg); err != nil {
+		t.Fatalf("unexpected error loading the config: %s", err)
+	}
+	invoke := func(name string) {
+		t.Helper()
+		tool, ok := s.getTool(name)
+		if !ok {
+			t.Fatalf("tool %q not found", name)
+		}
+		if _, err := tool.Invoke(ctx, tools.ParamValues{{Name: "q", Value: "x"}}); err != nil {
+			t.Fatalf("unexpected error invoking %q: %s", name, err)
+		}
+	}
+	invoke("a")
+	invoke("b")
+	breaker := circuitBreakers(s.getTools())["my-source"]
+
+	var reloadedA int
+	if err := s.reloadTool(ctx, "a", cached(&reloadedA)); err != nil {
+		t.Fatalf("unexpected error reloading: %s", err)
+	}
+	invoke("a")
+	invoke("b")
+	if reloadedA != 1 {
+		t.Fatalf("reloaded tool served a result cached by the previous tool")
+	}
+	if countB != 1 {
+		t.Fatalf("results cached by the other tools dropped by the reload")
+	}
+	if got := circuitBreakers(s.getTools())["my-source"]; got != breaker {
+		t.Fatalf("circuit breaker of the source replaced by the reload")
+	}
+}
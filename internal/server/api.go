@@ -16,13 +16,22 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"mime"
 	"net/http"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -33,21 +42,62 @@ import (
 func apiRouter(s *Server) (chi.Router, error) {
 	r := chi.NewRouter()
 
-	r.Use(middleware.AllowContentType("application/json"))
 	r.Use(middleware.StripSlashes)
+	r.Use(compressResponses(s))
 	r.Use(render.SetContentType(render.ContentTypeJSON))
 
-	r.Get("/toolset", func(w http.ResponseWriter, r *http.Request) { toolsetHandler(s, w, r) })
-	r.Get("/toolset/{toolsetName}", func(w http.ResponseWriter, r *http.Request) { toolsetHandler(s, w, r) })
-
-	r.Route("/tool/{toolName}", func(r chi.Router) {
-		r.Get("/", func(w http.ResponseWriter, r *http.Request) { toolGetHandler(s, w, r) })
-		r.Post("/invoke", func(w http.ResponseWriter, r *http.Request) { toolInvokeHandler(s, w, r) })
+	r.Group(func(r chi.Router) {
+		r.Use(allowContentType("application/json", "multipart/form-data"))
+
+		// health checks aren't rate limited
+		r.Get("/health", func(w http.ResponseWriter, r *http.Request) { healthHandler(s, w, r) })
+
+		r.Group(func(r chi.Router) {
+			r.Use(limitRate(s))
+			r.Get("/version", func(w http.ResponseWriter, r *http.Request) { versionHandler(s, w, r) })
+			r.Get("/tools", func(w http.ResponseWriter, r *http.Request) { toolsListHandler(s, w, r) })
+			r.Get("/toolset", func(w http.ResponseWriter, r *http.Request) { toolsetHandler(s, w, r) })
+			r.Get("/toolset/{toolsetName}", func(w http.ResponseWriter, r *http.Request) { toolsetHandler(s, w, r) })
+
+			r.Route("/tool/{toolName}", func(r chi.Router) {
+				r.Get("/", func(w http.ResponseWriter, r *http.Request) { toolGetHandler(s, w, r) })
+				r.With(limitInvocations(s)).Post("/invoke", func(w http.ResponseWriter, r *http.Request) { toolInvokeHandler(s, w, r) })
+				r.With(limitInvocations(s)).Get("/invoke", func(w http.ResponseWriter, r *http.Request) { toolInvokeHandler(s, w, r) })
+			})
+			r.Post("/invocations/{invocationId}/cancel", func(w http.ResponseWriter, r *http.Request) { invocationCancelHandler(s, w, r) })
+		})
 	})
 
+	// the admin endpoints accept YAML bodies, so they check the Content-Type
+	// of each route themselves
+	if s.adminEnabled() {
+		r.Group(func(r chi.Router) {
+			r.Use(limitRate(s))
+			r.Mount("/admin", adminRouter(s))
+		})
+	}
+
 	return r, nil
 }
 
+// versionResponse is the response sent back for the version of the server.
+type versionResponse struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"buildDate,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// versionHandler handles the request for the version of the server.
+func versionHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, versionResponse{
+		Version:   s.version,
+		Commit:    s.buildCommit,
+		BuildDate: s.buildDate,
+		GoVersion: runtime.Version(),
+	})
+}
+
 // toolsetHandler handles the request for information about a Toolset.
 func toolsetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/toolset/get")
@@ -75,14 +125,82 @@ func toolsetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		)
 	}()
 
-	toolset, ok := s.toolsets[toolsetName]
+	toolset, ok := s.getToolset(toolsetName)
 	if !ok {
 		err = fmt.Errorf("toolset %q does not exist", toolsetName)
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
 	}
-	render.JSON(w, r, toolset.Manifest)
+	claims, _ := s.authenticate(ctx, r.Header)
+	m := s.listableManifest(tools.WithClaims(ctx, claims), toolset.Manifest)
+	render.JSON(w, r, s.disabledTools.filterManifest(m))
+}
+
+// toolSummary describes a tool in the list of tools.
+type toolSummary struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind,omitempty"`
+	Description string `json:"description"`
+}
+
+// toolsListResponse is the response sent back for the list of tools.
+type toolsListResponse struct {
+	ServerVersion string        `json:"serverVersion"`
+	Tools         []toolSummary `json:"tools"`
+}
+
+// toolsListHandler handles requests for the names of all the tools, or of
+// the tools of the toolset in the `toolset` query parameter.
+func toolsListHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/tools/list")
+	r = r.WithContext(ctx)
+
+	toolsetName := r.URL.Query().Get("toolset")
+	s.logger.DebugContext(ctx, fmt.Sprintf("toolset name: %s", toolsetName))
+	span.SetAttributes(attribute.String("toolset_name", toolsetName))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		s.instrumentation.ToolsList.Add(
+			r.Context(),
+			1,
+			metric.WithAttributes(attribute.String("toolbox.name", toolsetName)),
+			metric.WithAttributes(attribute.String("toolbox.operation.status", status)),
+		)
+	}()
+
+	// the default toolset has all the tools
+	toolset, ok := s.getToolset(toolsetName)
+	if !ok {
+		err = fmt.Errorf("toolset %q does not exist", toolsetName)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+	claims, _ := s.authenticate(ctx, r.Header)
+	m := s.disabledTools.filterManifest(s.listableManifest(tools.WithClaims(ctx, claims), toolset.Manifest))
+
+	res := toolsListResponse{
+		ServerVersion: m.ServerVersion,
+		Tools:         make([]toolSummary, 0, len(m.ToolsManifest)),
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.ToolsManifest)) {
+		summary := toolSummary{Name: name, Description: m.ToolsManifest[name].Description}
+		if t, ok := s.getTool(name); ok {
+			summary.Kind = tools.Kind(t)
+		}
+		res.Tools = append(res.Tools, summary)
+	}
+	render.JSON(w, r, res)
 }
 
 // toolGetHandler handles requests for a single Tool.
@@ -111,18 +229,40 @@ func toolGetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 			metric.WithAttributes(attribute.String("toolbox.operation.status", status)),
 		)
 	}()
-	tool, ok := s.tools[toolName]
+	name, tool, ok := s.resolveTool(toolName, r.Header.Get(toolVersionHeader))
+	if ok {
+		// tools the caller can't list are reported as missing
+		claims, _ := s.authenticate(ctx, r.Header)
+		ok = tools.CanList(tools.WithClaims(ctx, claims), tool)
+	}
 	if !ok {
 		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
 	}
+	toolName = name
+	if s.disabledTools.has(toolName) {
+		err = toolDisabledError(toolName)
+		s.logger.DebugContext(ctx, err.Error())
+		errResp := newErrResponse(err, http.StatusForbidden)
+		errResp.Code = codeToolDisabled
+		_ = render.Render(w, r, errResp)
+		return
+	}
+	etag := toolETag(tool)
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 	// TODO: this can be optimized later with some caching
+	manifest := tool.Manifest()
+	manifest.ETag = etag
 	m := tools.ToolsetManifest{
 		ServerVersion: s.version,
 		ToolsManifest: map[string]tools.Manifest{
-			toolName: tool.Manifest(),
+			toolName: manifest,
 		},
 	}
 
@@ -137,6 +277,7 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	toolName := chi.URLParam(r, "toolName")
 	s.logger.DebugContext(ctx, fmt.Sprintf("tool name: %s", toolName))
 	span.SetAttributes(attribute.String("tool_name", toolName))
+	start := time.Now()
 	var err error
 	defer func() {
 		if err != nil {
@@ -144,42 +285,51 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		}
 		span.End()
 
-		status := "success"
-		if err != nil {
-			status = "error"
-		}
-		s.instrumentation.ToolInvoke.Add(
-			r.Context(),
-			1,
-			metric.WithAttributes(attribute.String("toolbox.name", toolName)),
-			metric.WithAttributes(attribute.String("toolbox.operation.status", status)),
-		)
+		s.instrumentation.recordToolInvoke(r.Context(), toolName, start, err != nil)
 	}()
 
-	tool, ok := s.tools[toolName]
+	name, tool, ok := s.resolveTool(toolName, r.Header.Get(toolVersionHeader))
 	if !ok {
 		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
 	}
+	toolName = name
+	if s.disabledTools.has(toolName) {
+		err = toolDisabledError(toolName)
+		s.logger.DebugContext(ctx, err.Error())
+		errResp := newErrResponse(err, http.StatusForbidden)
+		errResp.Code = codeToolDisabled
+		_ = render.Render(w, r, errResp)
+		return
+	}
 
-	// Tool authentication
-	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
-	claimsFromAuth := make(map[string]map[string]any)
-	for _, aS := range s.authServices {
-		claims, err := aS.GetClaimsFromHeader(ctx, r.Header)
-		if err != nil {
+	if allowed := allowedMethods(tool); !slices.Contains(allowed, r.Method) {
+		err = fmt.Errorf("tool %q can't be invoked with %s, allowed methods are %s", toolName, r.Method, strings.Join(allowed, ", "))
+		s.logger.DebugContext(ctx, err.Error())
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		errResp := newErrResponse(err, http.StatusMethodNotAllowed)
+		errResp.Details = map[string]any{"allowedMethods": allowed}
+		_ = render.Render(w, r, errResp)
+		return
+	}
+
+	dryRun := false
+	if v := r.URL.Query().Get("dryRun"); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			err = fmt.Errorf("invalid value %q for dryRun: must be a boolean", v)
 			s.logger.DebugContext(ctx, err.Error())
-			continue
+			_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+			return
 		}
-		if claims == nil {
-			// authService not present in header
-			continue
-		}
-		claimsFromAuth[aS.GetName()] = claims
 	}
 
+	// Tool authentication
+	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
+	// authTokens maps the name of the authservice to the raw token verified for it.
+	claimsFromAuth, authTokens := s.authenticate(ctx, r.Header)
+
 	// Tool authorization check
 	verifiedAuthServices := make([]string, len(claimsFromAuth))
 	i := 0
@@ -196,50 +346,284 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
 		return
 	}
+	if !tools.CanInvoke(tools.WithClaims(ctx, claimsFromAuth), tool) {
+		err = toolForbiddenError(toolName)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusForbidden))
+		return
+	}
 	s.logger.DebugContext(ctx, "tool invocation authorized")
 
 	var data map[string]any
-	if err = decodeJSON(r.Body, &data); err != nil {
-		render.Status(r, http.StatusBadRequest)
-		err = fmt.Errorf("request body was invalid JSON: %w", err)
+	if r.Method == http.MethodGet {
+		data = queryArguments(r.URL.Query(), tool)
+	} else if isMultipart(r) {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxUploadSize)
+		if data, err = decodeMultipart(r, tool, s.maxUploadSize); err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			status := http.StatusBadRequest
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			_ = render.Render(w, r, newErrResponse(err, status))
+			return
+		}
+		// an empty body invokes the tool without arguments
+	} else if err = decodeJSON(limitBody(w, r, s.maxRequestBytes), &data); err != nil && !errors.Is(err, io.EOF) {
 		s.logger.DebugContext(ctx, err.Error())
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = fmt.Errorf("request body is larger than %d bytes", maxBytesErr.Limit)
+			errResp := newErrResponse(err, http.StatusRequestEntityTooLarge)
+			errResp.Details = map[string]any{"limit": maxBytesErr.Limit}
+			_ = render.Render(w, r, errResp)
+			return
+		}
+		err = fmt.Errorf("request body was invalid JSON: %w", err)
 		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
 		return
+	} else if data, err = unwrapArguments(data, s.argumentsKey); err != nil {
+		err = fmt.Errorf("provided parameters were invalid: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newInvalidParametersResponse(err))
+		return
 	}
 
-	params, err := tool.ParseParams(data, claimsFromAuth)
+	previewRows, err := splitPreview(data)
 	if err != nil {
 		err = fmt.Errorf("provided parameters were invalid: %w", err)
 		s.logger.DebugContext(ctx, err.Error())
-		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		_ = render.Render(w, r, newInvalidParametersResponse(err))
+		return
+	}
+	rowFilter, err := splitRowFilter(data, tool)
+	if err != nil {
+		err = fmt.Errorf("provided parameters were invalid: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newInvalidParametersResponse(err))
+		return
+	}
+	var preview *previewTool
+	if previewRows > 0 {
+		preview = &previewTool{Tool: tool, rows: previewRows}
+		tool = preview
+	}
+
+	params, err := tool.ParseParams(ctx, data, claimsFromAuth)
+	if err != nil {
+		if errors.Is(err, tools.ErrUnauthenticated) {
+			err = fmt.Errorf("tool invocation not authorized: %w", err)
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+			return
+		}
+		err = fmt.Errorf("provided parameters were invalid: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newInvalidParametersResponse(err))
 		return
 	}
 	s.logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
-	res, err := tool.Invoke(ctx, params)
+	if dryRun {
+		dr, ok := tools.GetDryRunner(tool)
+		if !ok {
+			err = fmt.Errorf("tool %q does not support dry runs", toolName)
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+			return
+		}
+		var res tools.DryRunResult
+		if res, err = dr.DryRun(ctx, params); err != nil {
+			err = fmt.Errorf("error while rendering tool: %w", err)
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newInvalidParametersResponse(err))
+			return
+		}
+		render.JSON(w, r, res)
+		return
+	}
+
+	id, err := invocationID(r)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	key, err := newInvocationKey(claimsFromAuth, id)
 	if err != nil {
-		err = fmt.Errorf("error while invoking tool: %w", err)
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
 		return
 	}
+	invokeCtx, done, err := s.invocations.start(ctx, key)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusConflict))
+		return
+	}
+	defer done()
+	ctx = invokeCtx
+	w.Header().Set(invocationIDHeader, id)
+	if r.ProtoAtLeast(1, 1) && r.Header.Get(earlyInvocationIDHeader) == "true" {
+		// the ID is sent in an informational response, so that the invocation
+		// can be canceled before its result is sent
+		w.WriteHeader(http.StatusEarlyHints)
+	}
+
+	ctx = withInvokeRowFilter(tools.WithToolName(ctx, toolName), rowFilter)
+	ctx = tools.WithMeta(tools.WithClaims(tools.WithAuthTokens(ctx, authTokens), claimsFromAuth))
+	if preview == nil && acceptsNDJSON(r) {
+		if streamer, ok := tools.GetStreamer(tool); ok {
+			var streamed bool
+			streamed, err = streamResult(ctx, w, streamer, params)
+			if err != nil && !streamed {
+				err = fmt.Errorf("error while invoking tool: %w", canceledError(ctx, err))
+				s.logger.DebugContext(ctx, err.Error())
+				renderInvokeError(w, r, err)
+			} else if err != nil {
+				s.logger.DebugContext(ctx, fmt.Sprintf("error while streaming tool results: %s", err))
+			}
+			return
+		}
+	}
+
+	invokeStart := time.Now()
+	res, err := tool.Invoke(ctx, params)
+	duration := time.Since(invokeStart)
+	if err != nil {
+		err = fmt.Errorf("error while invoking tool: %w", canceledError(ctx, err))
+		s.logger.DebugContext(ctx, err.Error())
+		renderInvokeError(w, r, err)
+		return
+	}
 
-	resMarshal, err := json.Marshal(res)
+	var out any = res
+	if tools.GetOptions(tool).ResultMode == tools.ResultModeScalar && len(res) == 1 {
+		// scalar results are returned as the value itself rather than a list
+		out = res[0]
+	}
+	resMarshal, err := json.Marshal(out)
 	if err != nil {
 		err = fmt.Errorf("unable to marshal result: %w", err)
 		s.logger.DebugContext(ctx, err.Error())
-		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		errResp := newErrResponse(err, http.StatusInternalServerError)
+		errResp.Code = codeInvocationFailed
+		_ = render.Render(w, r, errResp)
+		return
+	}
+
+	meta := tools.MetaFromContext(ctx)
+	truncated := resultTruncated(meta, preview)
+	if s.executionMetadata {
+		meta = withExecutionMeta(meta, duration, len(res), truncated)
+	}
+	_ = render.Render(w, r, &resultResponse{Result: string(resMarshal), Preview: previewRows > 0, Truncated: truncated, Meta: meta})
+}
+
+// renderInvokeError renders the response to an invocation that failed with
+// err.
+func renderInvokeError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, errInvocationCanceled) {
+		errResp := newErrResponse(err, statusClientClosedRequest)
+		errResp.Code = codeInvocationCanceled
+		_ = render.Render(w, r, errResp)
+		return
+	}
+	if errors.Is(err, tools.ErrTimeout) {
+		errResp := newErrResponse(err, http.StatusGatewayTimeout)
+		errResp.Code = codeToolTimeout
+		_ = render.Render(w, r, errResp)
+		return
+	}
+	if sources.IsLimitExceeded(err) {
+		// retrying won't help, the query must be narrowed
+		errResp := newErrResponse(err, http.StatusUnprocessableEntity)
+		errResp.Code = codeSourceLimitExceeded
+		_ = render.Render(w, r, errResp)
+		return
+	}
+	if sources.IsUnavailable(err) {
+		// the source may recover, so clients are invited to retry
+		w.Header().Set("Retry-After", strconv.Itoa(sourceUnavailableRetryAfter))
+		errResp := newErrResponse(err, http.StatusServiceUnavailable)
+		errResp.Code = codeSourceUnavailable
+		_ = render.Render(w, r, errResp)
 		return
 	}
+	if errors.Is(err, errResultMemoryExceeded) {
+		errResp := newErrResponse(err, http.StatusServiceUnavailable)
+		errResp.Code = codeSourceAtCapacity
+		_ = render.Render(w, r, errResp)
+		return
+	}
+	errResp := newErrResponse(err, http.StatusInternalServerError)
+	errResp.Code = codeInvocationFailed
+	_ = render.Render(w, r, errResp)
+}
+
+// newInvalidParametersResponse returns the response to an invocation whose
+// arguments failed validation. The invalid parameter is included in the
+// details of the error, when known.
+func newInvalidParametersResponse(err error) *errResponse {
+	errResp := newErrResponse(err, http.StatusBadRequest)
+	errResp.Code = codeInvalidParameters
+	var fieldErr *tools.ObjectFieldError
+	var paramErr *tools.InvalidParameterError
+	switch {
+	case errors.As(err, &fieldErr):
+		errResp.Details = map[string]any{"parameter": fieldErr.Path}
+	case errors.As(err, &paramErr):
+		errResp.Details = map[string]any{"parameter": paramErr.Name}
+	}
+	return errResp
+}
 
-	_ = render.Render(w, r, &resultResponse{Result: string(resMarshal)})
+// allowContentType responds with a 415 to requests with a body whose
+// Content-Type is not one of contentTypes. Requests without a body are
+// allowed, so that tools without parameters can be invoked with an empty body.
+func allowContentType(contentTypes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			contentType := r.Header.Get("Content-Type")
+			mediaType, _, err := mime.ParseMediaType(contentType)
+			if err == nil && slices.Contains(contentTypes, mediaType) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if contentType == "" {
+				err = fmt.Errorf("request body has no Content-Type, supported types are %s", strings.Join(contentTypes, ", "))
+			} else {
+				err = fmt.Errorf("unsupported Content-Type %q, supported types are %s", contentType, strings.Join(contentTypes, ", "))
+			}
+			errResp := newErrResponse(err, http.StatusUnsupportedMediaType)
+			errResp.Details = map[string]any{"allowedContentTypes": contentTypes}
+			_ = render.Render(w, r, errResp)
+		})
+	}
+}
+
+// limitBody returns the body of r, limited to n bytes if n is positive.
+// Reading past the limit returns an *http.MaxBytesError.
+func limitBody(w http.ResponseWriter, r *http.Request, n int64) io.ReadCloser {
+	if n <= 0 {
+		return r.Body
+	}
+	return http.MaxBytesReader(w, r.Body, n)
 }
 
 var _ render.Renderer = &resultResponse{} // Renderer interface for managing response payloads.
 
 // resultResponse is the response sent back when the tool was invocated successfully.
 type resultResponse struct {
-	Result string `json:"result"` // result of tool invocation
+	Result    string         `json:"result"`              // result of tool invocation
+	Preview   bool           `json:"preview,omitempty"`   // whether the result was capped for a preview
+	Truncated bool           `json:"truncated,omitempty"` // whether rows were dropped from the result
+	Meta      map[string]any `json:"_meta,omitempty"`     // metadata about the result, if any
 }
 
 // Render renders a single payload and respond to the client request.
@@ -250,13 +634,17 @@ func (rr resultResponse) Render(w http.ResponseWriter, r *http.Request) error {
 
 var _ render.Renderer = &errResponse{} // Renderer interface for managing response payloads.
 
-// newErrResponse is a helper function initializing an ErrResponse
+// newErrResponse is a helper function initializing an ErrResponse. Its code
+// is the default code of the status, which callers can replace with a more
+// specific one.
 func newErrResponse(err error, code int) *errResponse {
 	return &errResponse{
 		Err:            err,
 		HTTPStatusCode: code,
 
 		StatusText: http.StatusText(code),
+		Code:       statusErrorCodes[code],
+		Message:    err.Error(),
 		ErrorText:  err.Error(),
 	}
 }
@@ -266,10 +654,55 @@ type errResponse struct {
 	Err            error `json:"-"` // low-level runtime error
 	HTTPStatusCode int   `json:"-"` // http response status code
 
-	StatusText string `json:"status"`          // user-level status message
-	ErrorText  string `json:"error,omitempty"` // application-level error message, for debugging
+	StatusText string         `json:"status"`            // user-level status message
+	Code       string         `json:"code,omitempty"`    // machine-readable error code
+	Message    string         `json:"message,omitempty"` // application-level error message
+	Details    map[string]any `json:"details,omitempty"` // machine-readable details of the error, if any
+	ErrorText  string         `json:"error,omitempty"`   // same as message, kept for older clients
 }
 
+// statusErrorCodes are the default error codes of the HTTP statuses.
+var statusErrorCodes = map[int]string{
+	http.StatusBadRequest:            "INVALID_REQUEST",
+	http.StatusUnauthorized:          codeUnauthorized,
+	http.StatusForbidden:             "FORBIDDEN",
+	http.StatusNotFound:              "NOT_FOUND",
+	http.StatusMethodNotAllowed:      "METHOD_NOT_ALLOWED",
+	http.StatusConflict:              "CONFLICT",
+	http.StatusRequestEntityTooLarge: "REQUEST_TOO_LARGE",
+	http.StatusUnsupportedMediaType:  "UNSUPPORTED_MEDIA_TYPE",
+	http.StatusTooManyRequests:       "RATE_LIMITED",
+	http.StatusInternalServerError:   "INTERNAL",
+	http.StatusServiceUnavailable:    "UNAVAILABLE",
+	http.StatusGatewayTimeout:        "TIMEOUT",
+}
+
+const (
+	// codeInvalidParameters is the error code used when the arguments of an
+	// invocation fail validation.
+	codeInvalidParameters = "INVALID_PARAMETERS"
+	// codeUnauthorized is the error code used when the caller isn't
+	// authenticated with the auth services a tool or parameter requires.
+	codeUnauthorized = "UNAUTHORIZED"
+	// codeInvocationFailed is the error code used when a tool fails while
+	// running.
+	codeInvocationFailed = "INVOCATION_FAILED"
+	// codeSourceUnavailable is the error code used when a tool's source can't be reached.
+	codeSourceUnavailable = "SOURCE_UNAVAILABLE"
+	// codeSourceAtCapacity is the error code used when a tool's source has too
+	// many result bytes in flight.
+	codeSourceAtCapacity = "SOURCE_AT_CAPACITY"
+	// codeSourceLimitExceeded is the error code used when a query exceeds a
+	// resource limit enforced by the database of its source.
+	codeSourceLimitExceeded = "SOURCE_LIMIT_EXCEEDED"
+	// codeToolTimeout is the error code used when an invocation runs past the
+	// tool's timeout.
+	codeToolTimeout = "TOOL_TIMEOUT"
+	// sourceUnavailableRetryAfter is the number of seconds clients should wait
+	// before retrying when a source can't be reached.
+	sourceUnavailableRetryAfter = 5
+)
+
 func (e *errResponse) Render(w http.ResponseWriter, r *http.Request) error {
 	render.Status(r, e.HTTPStatusCode)
 	return nil
@@ -284,3 +717,20 @@ func decodeJSON(r io.Reader, v interface{}) error {
 	d.UseNumber()
 	return d.Decode(v)
 }
+
+// unwrapArguments returns the invoke arguments nested under key in data. If
+// key is empty, the arguments are at the top level of data.
+func unwrapArguments(data map[string]any, key string) (map[string]any, error) {
+	if key == "" {
+		return data, nil
+	}
+	v, ok := data[key]
+	if !ok || v == nil {
+		return map[string]any{}, nil
+	}
+	args, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%q must be an object", key)
+	}
+	return args, nil
+}
@@ -16,14 +16,23 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"reflect"
+	"runtime"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/auth/apikey"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 func TestToolsetEndpoint(t *testing.T) {
@@ -123,6 +132,105 @@ func TestToolsetEndpoint(t *testing.T) {
 	}
 }
 
+// kindedTool is a MockTool with a kind.
+type kindedTool struct {
+	MockTool
+	Kind string
+}
+
+func TestToolsListEndpoint(t *testing.T) {
+	described := tool2
+	described.Description = "Some parameters."
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, described})
+	toolsMap[tool1.Name] = kindedTool{MockTool: toolsMap[tool1.Name].(MockTool), Kind: "mock-kind"}
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name       string
+		query      string
+		statusCode int
+		want       []toolSummary
+	}{
+		{
+			name:       "all tools",
+			statusCode: http.StatusOK,
+			want: []toolSummary{
+				{Name: tool1.Name, Kind: "mock-kind"},
+				{Name: tool2.Name, Description: "Some parameters."},
+			},
+		},
+		{
+			name:       "toolset filter",
+			query:      "?toolset=tool2_only",
+			statusCode: http.StatusOK,
+			want:       []toolSummary{{Name: tool2.Name, Description: "Some parameters."}},
+		},
+		{
+			name:       "unknown toolset",
+			query:      "?toolset=some_imaginary_toolset",
+			statusCode: http.StatusNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodGet, "/tools"+tc.query, nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.statusCode {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.statusCode, resp.StatusCode, body)
+			}
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+			var got toolsListResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response: %s", err)
+			}
+			if got.ServerVersion != fakeVersionString {
+				t.Fatalf("unexpected ServerVersion: want %q, got %q", fakeVersionString, got.ServerVersion)
+			}
+			if !reflect.DeepEqual(got.Tools, tc.want) {
+				t.Fatalf("unexpected tools: want %v, got %v", tc.want, got.Tools)
+			}
+		})
+	}
+}
+
+func TestVersionEndpoint(t *testing.T) {
+	r, shutdown := setUpServer(t, "api", nil, nil, func(s *Server) {
+		s.buildCommit = "abc123"
+		s.buildDate = "2025-01-02T03:04:05Z"
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodGet, "/version", nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusOK, resp.StatusCode, string(body))
+	}
+	var got versionResponse
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unable to parse version response: %s", err)
+	}
+	want := versionResponse{
+		Version:   fakeVersionString,
+		Commit:    "abc123",
+		BuildDate: "2025-01-02T03:04:05Z",
+		GoVersion: runtime.Version(),
+	}
+	if got != want {
+		t.Fatalf("unexpected version response: want %+v, got %+v", want, got)
+	}
+}
+
 func TestToolGetEndpoint(t *testing.T) {
 	mockTools := []MockTool{tool1, tool2}
 	toolsMap, toolsets := setUpResources(t, mockTools)
@@ -281,3 +389,993 @@ func TestToolInvokeEndpoint(t *testing.T) {
 		})
 	}
 }
+
+// failingTool returns err on every invocation.
+type failingTool struct {
+	MockTool
+	err error
+}
+
+func (t failingTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+	return nil, t.err
+}
+
+func TestToolInvokeSourceUnavailable(t *testing.T) {
+	// simulate a source that went down after startup
+	connErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	toolsMap := map[string]tools.Tool{
+		"down_source": failingTool{MockTool: MockTool{Name: "down_source"}, err: fmt.Errorf("unable to execute query: %w", connErr)},
+		"bad_query":   failingTool{MockTool: MockTool{Name: "bad_query"}, err: fmt.Errorf("unable to execute query: syntax error")},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name           string
+		toolName       string
+		wantStatusCode int
+		wantCode       string
+		wantRetryAfter string
+	}{
+		{
+			name:           "source unavailable",
+			toolName:       "down_source",
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantCode:       "SOURCE_UNAVAILABLE",
+			wantRetryAfter: "5",
+		},
+		{
+			name:           "query error",
+			toolName:       "bad_query",
+			wantStatusCode: http.StatusInternalServerError,
+			wantCode:       "INVOCATION_FAILED",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.toolName), bytes.NewBuffer([]byte(`{}`)))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatusCode {
+				t.Fatalf("unexpected status code: want %d, got %d", tc.wantStatusCode, resp.StatusCode)
+			}
+			if got := resp.Header.Get("Retry-After"); got != tc.wantRetryAfter {
+				t.Fatalf("unexpected Retry-After header: want %q, got %q", tc.wantRetryAfter, got)
+			}
+			var got errResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			if got.Code != tc.wantCode {
+				t.Fatalf("unexpected error code: want %q, got %q", tc.wantCode, got.Code)
+			}
+		})
+	}
+}
+
+func TestToolInvokeErrorCodes(t *testing.T) {
+	authParam := tools.NewStringParameterWithAuth("email", "the email of the user", []tools.ParamAuthService{{Name: "my-google-auth", Field: "email"}})
+	toolsMap := map[string]tools.Tool{
+		"flag": MockTool{
+			Name:   "flag",
+			Params: tools.Parameters{tools.NewBooleanParameter("active", "whether the user is active")},
+		},
+		"whoami":  MockTool{Name: "whoami", Params: tools.Parameters{authParam}},
+		"failing": failingTool{MockTool: MockTool{Name: "failing"}, err: fmt.Errorf("unable to execute query: syntax error")},
+		"expensive": failingTool{
+			MockTool: MockTool{Name: "expensive"},
+			err:      fmt.Errorf("unable to read rows: %w", &pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"}),
+		},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name           string
+		toolName       string
+		requestBody    string
+		wantStatusCode int
+		wantCode       string
+		wantDetails    map[string]any
+	}{
+		{
+			name:           "invalid parameter",
+			toolName:       "flag",
+			requestBody:    `{"active": "yes"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "INVALID_PARAMETERS",
+			wantDetails:    map[string]any{"parameter": "active"},
+		},
+		{
+			name:           "missing parameter",
+			toolName:       "flag",
+			requestBody:    `{}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "INVALID_PARAMETERS",
+		},
+		{
+			name:           "invalid JSON",
+			toolName:       "flag",
+			requestBody:    `{`,
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "INVALID_REQUEST",
+		},
+		{
+			name:           "missing authentication",
+			toolName:       "whoami",
+			requestBody:    `{}`,
+			wantStatusCode: http.StatusUnauthorized,
+			wantCode:       "UNAUTHORIZED",
+		},
+		{
+			name:           "failed invocation",
+			toolName:       "failing",
+			requestBody:    `{}`,
+			wantStatusCode: http.StatusInternalServerError,
+			wantCode:       "INVOCATION_FAILED",
+		},
+		{
+			name:           "source limit exceeded",
+			toolName:       "expensive",
+			requestBody:    `{}`,
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantCode:       "SOURCE_LIMIT_EXCEEDED",
+		},
+		{
+			name:           "unknown tool",
+			toolName:       "some_imaginary_tool",
+			requestBody:    `{}`,
+			wantStatusCode: http.StatusNotFound,
+			wantCode:       "NOT_FOUND",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.toolName), bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatusCode {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.wantStatusCode, resp.StatusCode, string(body))
+			}
+			var got errResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			if got.Code != tc.wantCode {
+				t.Fatalf("unexpected error code: want %q, got %q", tc.wantCode, got.Code)
+			}
+			if got.Message == "" || got.Message != got.ErrorText {
+				t.Fatalf("unexpected error message: got message %q and error %q", got.Message, got.ErrorText)
+			}
+			if !reflect.DeepEqual(got.Details, tc.wantDetails) {
+				t.Fatalf("unexpected error details: want %v, got %v", tc.wantDetails, got.Details)
+			}
+		})
+	}
+}
+
+func TestToolInvokeContentType(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"no_params": MockTool{Name: "no_params"},
+		"flag": MockTool{
+			Name:   "flag",
+			Params: tools.Parameters{tools.NewBooleanParameter("active", "whether the user is active")},
+		},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name           string
+		toolName       string
+		contentType    string
+		requestBody    string
+		wantStatusCode int
+		wantCode       string
+	}{
+		{
+			name:           "missing Content-Type",
+			toolName:       "flag",
+			requestBody:    `{"active": true}`,
+			wantStatusCode: http.StatusUnsupportedMediaType,
+			wantCode:       "UNSUPPORTED_MEDIA_TYPE",
+		},
+		{
+			name:           "unsupported Content-Type",
+			toolName:       "flag",
+			contentType:    "text/plain",
+			requestBody:    `{"active": true}`,
+			wantStatusCode: http.StatusUnsupportedMediaType,
+			wantCode:       "UNSUPPORTED_MEDIA_TYPE",
+		},
+		{
+			name:           "Content-Type with parameters",
+			toolName:       "flag",
+			contentType:    "application/json; charset=utf-8",
+			requestBody:    `{"active": true}`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "empty body without parameters",
+			toolName:       "no_params",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "empty body with parameters",
+			toolName:       "flag",
+			wantStatusCode: http.StatusBadRequest,
+			wantCode:       "INVALID_PARAMETERS",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/tool/%s/invoke", ts.URL, tc.toolName), strings.NewReader(tc.requestBody))
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatusCode {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.wantStatusCode, resp.StatusCode, string(body))
+			}
+			if tc.wantCode == "" {
+				return
+			}
+			var got errResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			if got.Code != tc.wantCode {
+				t.Fatalf("unexpected error code: want %q, got %q", tc.wantCode, got.Code)
+			}
+		})
+	}
+}
+
+func TestToolInvokeStrictArguments(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"lenient": tool2,
+		"strict": tools.ToolWithOptions{
+			Tool:    tool2,
+			Options: tools.Options{StrictArguments: true},
+		},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name           string
+		toolName       string
+		requestBody    string
+		wantStatusCode int
+		wantErr        string
+	}{
+		{
+			name:           "extra argument ignored by default",
+			toolName:       "lenient",
+			requestBody:    `{"param1": 1, "param2": 2, "parma3": 3}`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "declared arguments under strict mode",
+			toolName:       "strict",
+			requestBody:    `{"param1": 1, "param2": 2}`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "extra argument rejected under strict mode",
+			toolName:       "strict",
+			requestBody:    `{"param1": 1, "param2": 2, "parma3": 3}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErr:        `provided parameters were invalid: unexpected argument "parma3": tool has no parameter with that name`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.toolName), bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatusCode {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.wantStatusCode, resp.StatusCode, string(body))
+			}
+			if tc.wantErr == "" {
+				return
+			}
+			var got errResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			if got.ErrorText != tc.wantErr {
+				t.Fatalf("unexpected error: want %q, got %q", tc.wantErr, got.ErrorText)
+			}
+		})
+	}
+}
+
+func TestToolInvokeArgumentsKey(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"flag": MockTool{
+			Name:   "flag",
+			Params: tools.Parameters{tools.NewBooleanParameter("active", "whether the user is active")},
+		},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil, func(s *Server) { s.argumentsKey = "args" })
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name           string
+		requestBody    string
+		wantStatusCode int
+		wantErr        string
+	}{
+		{
+			name:           "nested arguments",
+			requestBody:    `{"args": {"active": true}}`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "top level arguments",
+			requestBody:    `{"active": true}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErr:        `provided parameters were invalid: parameter "active" is required`,
+		},
+		{
+			name:           "arguments not an object",
+			requestBody:    `{"args": [true]}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErr:        `provided parameters were invalid: "args" must be an object`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, "/tool/flag/invoke", bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatusCode {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.wantStatusCode, resp.StatusCode, string(body))
+			}
+			if tc.wantErr == "" {
+				return
+			}
+			var got errResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			if got.ErrorText != tc.wantErr {
+				t.Fatalf("unexpected error: want %q, got %q", tc.wantErr, got.ErrorText)
+			}
+		})
+	}
+}
+
+func TestToolInvokeBooleanParameter(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"flag": MockTool{
+			Name:   "flag",
+			Params: tools.Parameters{tools.NewBooleanParameter("active", "whether the user is active")},
+		},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name           string
+		requestBody    string
+		wantStatusCode int
+		wantErr        string
+	}{
+		{
+			name:           "boolean",
+			requestBody:    `{"active": true}`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "string",
+			requestBody:    `{"active": "true"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErr:        `provided parameters were invalid: unable to parse value for "active": parameter "active" must be a boolean (true or false)`,
+		},
+		{
+			name:           "number",
+			requestBody:    `{"active": 1}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErr:        `provided parameters were invalid: unable to parse value for "active": parameter "active" must be a boolean (true or false)`,
+		},
+		{
+			name:           "missing",
+			requestBody:    `{}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErr:        `provided parameters were invalid: parameter "active" is required`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, "/tool/flag/invoke", bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatusCode {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.wantStatusCode, resp.StatusCode, string(body))
+			}
+			if tc.wantErr == "" {
+				return
+			}
+			var got errResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			if got.ErrorText != tc.wantErr {
+				t.Fatalf("unexpected error: want %q, got %q", tc.wantErr, got.ErrorText)
+			}
+		})
+	}
+}
+
+// slowTool blocks until its invocation is canceled.
+// authRequiredTool is a MockTool that requires the "my-api-key" auth service.
+type authRequiredTool struct {
+	MockTool
+}
+
+func (t authRequiredTool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized([]string{"my-api-key"}, verifiedAuthServices)
+}
+
+func TestToolInvokeAPIKeyAuth(t *testing.T) {
+	a, err := apikey.Config{Name: "my-api-key", Kind: apikey.AuthServiceKind, Key: "secret"}.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	toolsMap := map[string]tools.Tool{
+		"my-auth-required-tool": authRequiredTool{MockTool: MockTool{Name: "my-auth-required-tool"}},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil, func(s *Server) {
+		s.authServices = map[string]auth.AuthService{"my-api-key": a}
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name           string
+		key            string
+		wantStatusCode int
+	}{
+		{name: "valid key", key: "secret", wantStatusCode: http.StatusOK},
+		{name: "missing key", wantStatusCode: http.StatusUnauthorized},
+		{name: "wrong key", key: "wrong", wantStatusCode: http.StatusUnauthorized},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, ts.URL+"/tool/my-auth-required-tool/invoke", bytes.NewBufferString(`{}`))
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			if tc.key != "" {
+				req.Header.Set(apikey.HeaderName, tc.key)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tc.wantStatusCode {
+				t.Fatalf("unexpected status code: want %d, got %d", tc.wantStatusCode, resp.StatusCode)
+			}
+		})
+	}
+}
+
+// countTool is a MockTool that returns a count as a single-column row.
+type countTool struct {
+	MockTool
+}
+
+func (t countTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+	return []any{map[string]any{"count": 5}}, nil
+}
+
+func TestToolInvokeScalarResult(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"count": tools.ToolWithOptions{
+			Tool:    countTool{MockTool: MockTool{Name: "count"}},
+			Options: tools.Options{ResultMode: tools.ResultModeScalar},
+		},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, "/tool/count/invoke", bytes.NewBufferString(`{}`))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusOK, resp.StatusCode, string(body))
+	}
+	var got resultResponse
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unable to parse response body: %s", err)
+	}
+	if got.Result != "5" {
+		t.Fatalf("unexpected result: want %q, got %q", "5", got.Result)
+	}
+}
+
+func TestToolInvokeIntRange(t *testing.T) {
+	id := tools.NewIntParameter("id", "id of the user")
+	min := 1
+	id.Min = &min
+	toolsMap := map[string]tools.Tool{
+		"get_user": MockTool{Name: "get_user", Params: tools.Parameters{id}},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, "/tool/get_user/invoke", bytes.NewBufferString(`{"id": 0}`))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusBadRequest, resp.StatusCode, string(body))
+	}
+	var got errResponse
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unable to parse response body: %s", err)
+	}
+	if want := `parameter "id" must be >= 1`; !strings.Contains(got.ErrorText, want) {
+		t.Fatalf("unexpected error: want %q in %q", want, got.ErrorText)
+	}
+
+	resp, body, err = runRequest(ts, http.MethodPost, "/tool/get_user/invoke", bytes.NewBufferString(`{"id": 1}`))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusOK, resp.StatusCode, string(body))
+	}
+}
+
+func TestMaxRequestBytes(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"get_user": MockTool{Name: "get_user", Params: tools.Parameters{tools.NewStringParameter("name", "name of the user")}},
+	}
+	large := fmt.Sprintf(`{"name": %q}`, strings.Repeat("a", 256))
+	tcs := []struct {
+		name       string
+		router     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{name: "invoke within limit", router: "api", path: "/tool/get_user/invoke", body: `{"name": "Alice"}`, wantStatus: http.StatusOK},
+		{name: "invoke past limit", router: "api", path: "/tool/get_user/invoke", body: large, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "mcp past limit", router: "mcp", path: "/", body: large, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r, shutdown := setUpServer(t, tc.router, toolsMap, nil, func(s *Server) {
+				s.maxRequestBytes = 128
+			})
+			defer shutdown()
+			ts := runServer(r, false)
+			defer ts.Close()
+
+			resp, body, err := runRequest(ts, http.MethodPost, tc.path, bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.wantStatus, resp.StatusCode, string(body))
+			}
+			if tc.wantStatus == http.StatusOK {
+				return
+			}
+			if want := "request body is larger than 128 bytes"; !strings.Contains(string(body), want) {
+				t.Fatalf("unexpected response: want %q in %s", want, string(body))
+			}
+		})
+	}
+}
+
+// dryRunTool is an authRequiredTool that renders a statement for its "id"
+// parameter, and fails if it is invoked.
+type dryRunTool struct {
+	authRequiredTool
+}
+
+func (t dryRunTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+	return nil, fmt.Errorf("dry runs must not invoke the tool")
+}
+
+func (t dryRunTool) DryRun(_ context.Context, params tools.ParamValues) (tools.DryRunResult, error) {
+	return tools.DryRunResult{
+		Statement:  "SELECT * FROM users WHERE id = $1",
+		Parameters: params.AsMap(),
+		Binds:      params.AsSlice(),
+	}, nil
+}
+
+func TestToolInvokeDryRun(t *testing.T) {
+	a, err := apikey.Config{Name: "my-api-key", Kind: apikey.AuthServiceKind, Key: "secret"}.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	params := tools.Parameters{tools.NewIntParameter("id", "id of the user")}
+	toolsMap := map[string]tools.Tool{
+		"get_user":  dryRunTool{authRequiredTool{MockTool{Name: "get_user", Params: params}}},
+		"no_render": authRequiredTool{MockTool{Name: "no_render", Params: params}},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil, func(s *Server) {
+		s.authServices = map[string]auth.AuthService{"my-api-key": a}
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name           string
+		path           string
+		key            string
+		wantStatusCode int
+		want           tools.DryRunResult
+	}{
+		{
+			name:           "renders without invoking",
+			path:           "/tool/get_user/invoke?dryRun=true",
+			key:            "secret",
+			wantStatusCode: http.StatusOK,
+			want: tools.DryRunResult{
+				Statement:  "SELECT * FROM users WHERE id = $1",
+				Parameters: map[string]any{"id": float64(1)},
+				Binds:      []any{float64(1)},
+			},
+		},
+		{name: "missing key", path: "/tool/get_user/invoke?dryRun=true", wantStatusCode: http.StatusUnauthorized},
+		{name: "invalid dryRun", path: "/tool/get_user/invoke?dryRun=maybe", key: "secret", wantStatusCode: http.StatusBadRequest},
+		{name: "unsupported tool", path: "/tool/no_render/invoke?dryRun=true", key: "secret", wantStatusCode: http.StatusBadRequest},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, ts.URL+tc.path, bytes.NewBufferString(`{"id": 1}`))
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			if tc.key != "" {
+				req.Header.Set(apikey.HeaderName, tc.key)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatusCode {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.wantStatusCode, resp.StatusCode, string(body))
+			}
+			if tc.wantStatusCode != http.StatusOK {
+				return
+			}
+			var got tools.DryRunResult
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("unexpected dry run: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+type slowTool struct {
+	MockTool
+}
+
+func (t slowTool) Invoke(ctx context.Context, _ tools.ParamValues) ([]any, error) {
+	<-ctx.Done()
+	return nil, fmt.Errorf("unable to execute query: %w", ctx.Err())
+}
+
+func TestToolInvokeTimeout(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"slow": tools.ToolWithOptions{
+			Tool:    slowTool{MockTool: MockTool{Name: "slow"}},
+			Options: tools.Options{Timeout: 10 * time.Millisecond},
+		},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, "/tool/slow/invoke", bytes.NewBufferString(`{}`))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusGatewayTimeout {
+		t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusGatewayTimeout, resp.StatusCode, string(body))
+	}
+	var got errResponse
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unable to parse response body: %s", err)
+	}
+	if got.Code != codeToolTimeout {
+		t.Fatalf("unexpected code: want %q, got %q", codeToolTimeout, got.Code)
+	}
+	if !strings.Contains(got.ErrorText, "tool invocation timed out after 10ms") {
+		t.Fatalf("unexpected error: %q", got.ErrorText)
+	}
+}
+
+// streamTool is a MockTool that streams its rows, and fails after them if
+// err is set.
+type streamTool struct {
+	MockTool
+	rows []any
+	err  error
+}
+
+func (t streamTool) InvokeStream(_ context.Context, _ tools.ParamValues, yield func(row any) error) error {
+	for _, row := range t.rows {
+		if err := yield(row); err != nil {
+			return err
+		}
+	}
+	return t.err
+}
+
+func TestToolInvokeNDJSON(t *testing.T) {
+	rows := []any{map[string]any{"id": 1}, map[string]any{"id": 2}}
+	toolsMap := map[string]tools.Tool{
+		"stream":        streamTool{MockTool: MockTool{Name: "stream"}, rows: rows},
+		"empty":         streamTool{MockTool: MockTool{Name: "empty"}},
+		"fail_midway":   streamTool{MockTool: MockTool{Name: "fail_midway"}, rows: rows, err: fmt.Errorf("connection lost")},
+		"fail_at_start": streamTool{MockTool: MockTool{Name: "fail_at_start"}, err: fmt.Errorf("syntax error")},
+		"no_stream":     MockTool{Name: "no_stream"},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name            string
+		tool            string
+		accept          string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "rows as lines",
+			tool:            "stream",
+			accept:          "application/x-ndjson",
+			wantStatus:      http.StatusOK,
+			wantContentType: ndjsonContentType,
+			wantBody:        "{\"id\":1}\n{\"id\":2}\n",
+		},
+		{
+			name:            "no rows",
+			tool:            "empty",
+			accept:          "application/json, application/x-ndjson",
+			wantStatus:      http.StatusOK,
+			wantContentType: ndjsonContentType,
+			wantBody:        "",
+		},
+		{
+			name:            "error after rows",
+			tool:            "fail_midway",
+			accept:          "application/x-ndjson",
+			wantStatus:      http.StatusOK,
+			wantContentType: ndjsonContentType,
+			wantBody:        "{\"id\":1}\n{\"id\":2}\n{\"error\":\"connection lost\"}\n",
+		},
+		{
+			name:            "error before rows",
+			tool:            "fail_at_start",
+			accept:          "application/x-ndjson",
+			wantStatus:      http.StatusInternalServerError,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "tool without streaming",
+			tool:            "no_stream",
+			accept:          "application/x-ndjson",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        "{\"result\":\"[\\\"no_stream\\\"]\"}\n",
+		},
+		{
+			name:            "json by default",
+			tool:            "stream",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        "{\"result\":\"[\\\"stream\\\"]\"}\n",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, ts.URL+"/tool/"+tc.tool+"/invoke", bytes.NewBufferString(`{}`))
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.wantStatus, resp.StatusCode, string(body))
+			}
+			if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, tc.wantContentType) {
+				t.Fatalf("unexpected content type: want %q, got %q", tc.wantContentType, got)
+			}
+			if tc.wantBody != "" && string(body) != tc.wantBody {
+				t.Fatalf("unexpected body: want %q, got %q", tc.wantBody, string(body))
+			}
+		})
+	}
+}
+
+// paramsTool returns the values of its parameters.
+type paramsTool struct {
+	MockTool
+}
+
+func (t paramsTool) Invoke(_ context.Context, params tools.ParamValues) ([]any, error) {
+	return []any{params.AsMap()}, nil
+}
+
+func TestInvokeNullableParameters(t *testing.T) {
+	middleName := tools.NewStringParameter("middle_name", "middle name of the user")
+	middleName.Nullable = true
+	toolsMap := map[string]tools.Tool{
+		"add_user": paramsTool{MockTool: MockTool{
+			Name:   "add_user",
+			Params: tools.Parameters{tools.NewStringParameter("name", "name of the user"), middleName},
+		}},
+	}
+
+	tcs := []struct {
+		name       string
+		args       string
+		wantResult string
+		wantErr    string
+	}{
+		{
+			name:       "value",
+			args:       `{"name": "Alice", "middle_name": "Jane"}`,
+			wantResult: `{"middle_name":"Jane","name":"Alice"}`,
+		},
+		{
+			name:       "null",
+			args:       `{"name": "Alice", "middle_name": null}`,
+			wantResult: `{"middle_name":null,"name":"Alice"}`,
+		},
+		{
+			name:    "omitted",
+			args:    `{"name": "Alice"}`,
+			wantErr: `parameter "middle_name" is required`,
+		},
+		{
+			name:    "null for a parameter that isn't nullable",
+			args:    `{"name": null, "middle_name": "Jane"}`,
+			wantErr: `value can't be null`,
+		},
+	}
+
+	t.Run("api", func(t *testing.T) {
+		r, shutdown := setUpServer(t, "api", toolsMap, nil)
+		defer shutdown()
+		ts := runServer(r, false)
+		defer ts.Close()
+		for _, tc := range tcs {
+			t.Run(tc.name, func(t *testing.T) {
+				resp, body, err := runRequest(ts, http.MethodPost, "/tool/add_user/invoke", bytes.NewBufferString(tc.args))
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				if tc.wantErr != "" {
+					var got errResponse
+					if err := json.Unmarshal(body, &got); err != nil {
+						t.Fatalf("unable to parse response body: %s", err)
+					}
+					if resp.StatusCode != http.StatusBadRequest || !strings.Contains(got.Message, tc.wantErr) {
+						t.Fatalf("unexpected response: want 400 with %q, got %d: %s", tc.wantErr, resp.StatusCode, body)
+					}
+					return
+				}
+				if resp.StatusCode != http.StatusOK {
+					t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusOK, resp.StatusCode, body)
+				}
+				var got map[string]string
+				if err := json.Unmarshal(body, &got); err != nil {
+					t.Fatalf("unable to parse response body: %s", err)
+				}
+				if want := "[" + tc.wantResult + "]"; got["result"] != want {
+					t.Fatalf("unexpected result: want %s, got %s", want, got["result"])
+				}
+			})
+		}
+	})
+
+	t.Run("mcp", func(t *testing.T) {
+		r, shutdown := setUpServer(t, "mcp", toolsMap, nil)
+		defer shutdown()
+		ts := runServer(r, false)
+		defer ts.Close()
+		for _, tc := range tcs {
+			t.Run(tc.name, func(t *testing.T) {
+				reqBody := fmt.Sprintf(`{"jsonrpc": "2.0", "id": "add-user", "method": "tools/call", "params": {"name": "add_user", "arguments": %s}}`, tc.args)
+				_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBufferString(reqBody))
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				var got struct {
+					Result struct {
+						Content []struct {
+							Text string `json:"text"`
+						} `json:"content"`
+					} `json:"result"`
+					Error *struct {
+						Message string `json:"message"`
+					} `json:"error"`
+				}
+				if err := json.Unmarshal(body, &got); err != nil {
+					t.Fatalf("unable to parse response body: %s", err)
+				}
+				if tc.wantErr != "" {
+					if got.Error == nil || !strings.Contains(got.Error.Message, tc.wantErr) {
+						t.Fatalf("unexpected response: want an error with %q, got %s", tc.wantErr, body)
+					}
+					return
+				}
+				if got.Error != nil || len(got.Result.Content) != 1 || got.Result.Content[0].Text != tc.wantResult {
+					t.Fatalf("unexpected result: want %s, got %s", tc.wantResult, body)
+				}
+			})
+		}
+	})
+}
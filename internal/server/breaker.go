@@ -0,0 +1,215 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// States of a circuit breaker.
+const (
+	breakerClosed   = "closed"
+	breakerOpen     = "open"
+	breakerHalfOpen = "half-open"
+)
+
+// circuitBreaker stops the invocations of a source's tools after threshold
+// consecutive invocations failed to reach it, so that they fail fast instead
+// of waiting for the source to time out. Once cooldown has passed, the
+// breaker is half-open: a single invocation is let through to test whether
+// the source recovered, which closes the breaker if it succeeds and opens it
+// again otherwise.
+type circuitBreaker struct {
+	source    string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	// openedAt is when the breaker was last opened, zero if it is closed
+	openedAt time.Time
+	// probing is true while the invocation testing the source is running
+	probing bool
+}
+
+// breakerHealth is the state of a circuit breaker, as reported by the health
+// endpoint.
+type breakerHealth struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	RetryAt             *time.Time `json:"retryAt,omitempty"`
+}
+
+// acquire returns an error wrapping sources.ErrUnavailable if an invocation
+// can't run at now. probe is true if the invocation tests whether the source
+// recovered. On success, the outcome of the invocation must be recorded with
+// record.
+func (b *circuitBreaker) acquire(now time.Time) (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openedAt.IsZero() {
+		return false, nil
+	}
+	if wait := b.openedAt.Add(b.cooldown).Sub(now); wait > 0 {
+		return false, fmt.Errorf("%w: circuit breaker of source %q is open after %d consecutive failures, retry in %s", sources.ErrUnavailable, b.source, b.failures, wait.Round(time.Millisecond))
+	}
+	if b.probing {
+		return false, fmt.Errorf("%w: circuit breaker of source %q is half-open, another invocation is testing the source", sources.ErrUnavailable, b.source)
+	}
+	b.probing = true
+	return true, nil
+}
+
+// record records the outcome of an invocation at now. Only failures to reach
+// the source count as failures. Errors that show the source answered, such as
+// a query error, count as successes, and invocations that were canceled or
+// timed out are ignored, as a slow query of one tool doesn't show that the
+// source is down for the others.
+func (b *circuitBreaker) record(probe bool, err error, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probe {
+		b.probing = false
+	}
+	switch {
+	case sources.IsUnavailable(err):
+		b.failures++
+		if probe || b.failures >= b.threshold {
+			b.openedAt = now
+		}
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded), errors.Is(err, tools.ErrTimeout):
+		return
+	default:
+		b.failures = 0
+		b.openedAt = time.Time{}
+	}
+}
+
+// health returns the state of the breaker at now.
+func (b *circuitBreaker) health(now time.Time) breakerHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	h := breakerHealth{State: breakerClosed, ConsecutiveFailures: b.failures}
+	if b.openedAt.IsZero() {
+		return h
+	}
+	h.State = breakerHalfOpen
+	if retryAt := b.openedAt.Add(b.cooldown); now.Before(retryAt) {
+		h.State = breakerOpen
+		retryAt = retryAt.UTC()
+		h.RetryAt = &retryAt
+	}
+	return h
+}
+
+// validate interface
+var _ tools.Tool = circuitBreakerTool{}
+
+// circuitBreakerTool is a Tool whose invocations go through the circuit
+// breaker of its source.
+type circuitBreakerTool struct {
+	tools.Tool
+	breaker *circuitBreaker
+}
+
+func (t circuitBreakerTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t circuitBreakerTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	probe, err := t.breaker.acquire(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	res, err := t.Tool.Invoke(ctx, params)
+	t.breaker.record(probe, err, time.Now())
+	return res, err
+}
+
+// Stream streams the rows through the circuit breaker. Errors returned by
+// yield, such as a failure to write to the client, don't count as failures
+// of the source.
+func (t circuitBreakerTool) Stream() (tools.Streamer, bool) {
+	s, ok := tools.GetStreamer(t.Tool)
+	if !ok {
+		return nil, false
+	}
+	return tools.StreamerFunc(func(ctx context.Context, params tools.ParamValues, yield func(row any) error) error {
+		probe, err := t.breaker.acquire(time.Now())
+		if err != nil {
+			return err
+		}
+		var yieldErr error
+		err = s.InvokeStream(ctx, params, func(row any) error {
+			yieldErr = yield(row)
+			return yieldErr
+		})
+		if yieldErr != nil {
+			t.breaker.record(probe, nil, time.Now())
+		} else {
+			t.breaker.record(probe, err, time.Now())
+		}
+		return err
+	}), true
+}
+
+// withCircuitBreakers wraps each tool that uses a source so that the
+// invocations of each source's tools go through a circuit breaker that opens
+// after threshold consecutive failures, for cooldown. toolSources maps tool
+// names to the name of their source, and breakers holds the existing circuit
+// breakers by source name, which are reused. The tools are returned unchanged
+// if threshold is not positive.
+func withCircuitBreakers(toolsMap map[string]tools.Tool, toolSources map[string]string, breakers map[string]*circuitBreaker, threshold int, cooldown time.Duration) map[string]tools.Tool {
+	if threshold <= 0 {
+		return toolsMap
+	}
+	breakers = maps.Clone(breakers)
+	if breakers == nil {
+		breakers = make(map[string]*circuitBreaker)
+	}
+	for name, t := range toolsMap {
+		src := toolSources[name]
+		if src == "" {
+			continue
+		}
+		b, ok := breakers[src]
+		if !ok {
+			b = &circuitBreaker{source: src, threshold: threshold, cooldown: cooldown}
+			breakers[src] = b
+		}
+		toolsMap[name] = circuitBreakerTool{Tool: t, breaker: b}
+	}
+	return toolsMap
+}
+
+// circuitBreakers returns the circuit breakers of the tools in toolsMap, by
+// the name of their source.
+func circuitBreakers(toolsMap map[string]tools.Tool) map[string]*circuitBreaker {
+	breakers := make(map[string]*circuitBreaker)
+	for _, t := range toolsMap {
+		if b, ok := findWrapper[circuitBreakerTool](t); ok {
+			breakers[b.breaker.source] = b.breaker
+		}
+	}
+	return breakers
+}
@@ -0,0 +1,194 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// downSourceTool fails to reach its source while down is true, and counts the
+// invocations that reached it.
+type downSourceTool struct {
+	MockTool
+	down  *atomic.Bool
+	calls *atomic.Int32
+}
+
+func (t downSourceTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	t.calls.Add(1)
+	if t.down.Load() {
+		return nil, fmt.Errorf("unable to execute query: %w", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED})
+	}
+	return t.MockTool.Invoke(ctx, params)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	connErr := fmt.Errorf("unable to execute query: %w", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED})
+	b := &circuitBreaker{source: "my-pg", threshold: 3, cooldown: time.Minute}
+	now := time.Now()
+
+	// query errors show the source answered, and reset the count
+	for _, err := range []error{connErr, connErr, errors.New("syntax error"), connErr, connErr} {
+		if _, err := b.acquire(now); err != nil {
+			t.Fatalf("unexpected error while the breaker is closed: %s", err)
+		}
+		b.record(false, err, now)
+	}
+	if h := b.health(now); h.State != breakerClosed || h.ConsecutiveFailures != 2 {
+		t.Fatalf("unexpected health: got %+v, want closed with 2 failures", h)
+	}
+
+	// the threshold opens the breaker
+	b.record(false, connErr, now)
+	if _, err := b.acquire(now.Add(time.Second)); !errors.Is(err, sources.ErrUnavailable) {
+		t.Fatalf("expected the open breaker to fail fast, got %v", err)
+	}
+	if h := b.health(now); h.State != breakerOpen || h.RetryAt == nil || !h.RetryAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("unexpected health: got %+v, want open until the end of the cooldown", h)
+	}
+
+	// once half-open, a single invocation tests the source
+	now = now.Add(time.Minute)
+	if h := b.health(now); h.State != breakerHalfOpen {
+		t.Fatalf("unexpected state after the cooldown: got %q, want %q", h.State, breakerHalfOpen)
+	}
+	probe, err := b.acquire(now)
+	if err != nil || !probe {
+		t.Fatalf("expected an invocation to test the source, got %t and %v", probe, err)
+	}
+	if _, err := b.acquire(now); !errors.Is(err, sources.ErrUnavailable) {
+		t.Fatalf("expected other invocations to fail fast while testing the source, got %v", err)
+	}
+
+	// a failed test opens the breaker again
+	b.record(true, connErr, now)
+	if _, err := b.acquire(now.Add(time.Second)); err == nil {
+		t.Fatalf("expected the breaker to open again")
+	}
+
+	// canceled and timed out tests are ignored
+	now = now.Add(time.Minute)
+	for _, err := range []error{context.Canceled, context.DeadlineExceeded, tools.ErrTimeout} {
+		probe, _ = b.acquire(now)
+		b.record(probe, err, now)
+		if h := b.health(now); h.State != breakerHalfOpen || h.ConsecutiveFailures != 4 {
+			t.Fatalf("unexpected health after a test failed with %v: got %+v, want half-open with 4 failures", err, h)
+		}
+	}
+
+	// a successful test closes the breaker
+	probe, _ = b.acquire(now)
+	b.record(probe, nil, now)
+	if h := b.health(now); h.State != breakerClosed || h.ConsecutiveFailures != 0 {
+		t.Fatalf("unexpected health after the source recovered: got %+v", h)
+	}
+}
+
+func TestCircuitBreakerTripsAndRecovers(t *testing.T) {
+	const cooldown = 200 * time.Millisecond
+	down := &atomic.Bool{}
+	calls := &atomic.Int32{}
+	down.Store(true)
+	toolsMap := withCircuitBreakers(
+		map[string]tools.Tool{
+			"flaky": downSourceTool{MockTool: MockTool{Name: "flaky"}, down: down, calls: calls},
+			"other": MockTool{Name: "other"},
+		},
+		map[string]string{"flaky": "my-pg", "other": "other-pg"},
+		nil,
+		2,
+		cooldown,
+	)
+	r, shutdown := setUpServer(t, "api", toolsMap, nil, func(s *Server) {
+		s.sources = map[string]sources.Source{"my-pg": healthSource{}, "other-pg": healthSource{}}
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	invoke := func(t *testing.T, name string) (*http.Response, errResponse) {
+		t.Helper()
+		resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", name), bytes.NewBuffer([]byte(`{}`)))
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got errResponse
+		_ = json.Unmarshal(body, &got)
+		return resp, got
+	}
+	health := func(t *testing.T) (string, *breakerHealth) {
+		t.Helper()
+		_, body, err := runRequest(ts, http.MethodGet, "/health", nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got healthResponse
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unable to parse health response: %s", err)
+		}
+		for _, s := range got.Sources {
+			if s.Name == "my-pg" && s.CircuitBreaker != nil {
+				return got.Status, s.CircuitBreaker
+			}
+		}
+		t.Fatalf("the circuit breaker is missing from the health response: %s", body)
+		return "", nil
+	}
+
+	// trip the breaker
+	for i := 0; i < 2; i++ {
+		if resp, _ := invoke(t, "flaky"); resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("unexpected status code for invocation %d: got %d, want %d", i+1, resp.StatusCode, http.StatusServiceUnavailable)
+		}
+	}
+	resp, got := invoke(t, "flaky")
+	if resp.StatusCode != http.StatusServiceUnavailable || got.Code != codeSourceUnavailable {
+		t.Fatalf("unexpected response of the open breaker: got %d and %q", resp.StatusCode, got.Code)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("expected the open breaker not to reach the source, got %d calls", n)
+	}
+	if resp, _ := invoke(t, "other"); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the tools of other sources to be invocable, got %d", resp.StatusCode)
+	}
+
+	// the source answers its health check, but the open breaker degrades it
+	if status, bh := health(t); status != healthDegraded || bh.State != breakerOpen {
+		t.Fatalf("unexpected health: got %q and %+v", status, bh)
+	}
+
+	// recover once the cooldown has passed
+	down.Store(false)
+	time.Sleep(cooldown)
+	if resp, _ := invoke(t, "flaky"); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the source to be invocable once recovered, got %d", resp.StatusCode)
+	}
+	if status, bh := health(t); status != healthOK || bh.State != breakerClosed {
+		t.Fatalf("unexpected health after recovery: got %q and %+v", status, bh)
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// cacheSweepInterval is how often the expired entries of the cache are
+// removed. Entries are otherwise only removed when their key is read again,
+// which may never happen since keys vary with the caller and parameters.
+const cacheSweepInterval = time.Minute
+
+// maxCacheEntries is the maximum number of results cached for each tool.
+const maxCacheEntries = 10000
+
+// resultCache holds the results of tool invocations, keyed by tool name and
+// then by the invocation parameters.
+type resultCache struct {
+	mu      sync.Mutex
+	entries map[string]map[string]cacheEntry
+	// hashKey is the random key sensitive parameter values are hashed with
+	// in cache keys, so that they can't be recovered by hashing guesses.
+	hashKey []byte
+	// swept is when the expired entries were last removed
+	swept time.Time
+}
+
+type cacheEntry struct {
+	result  []any
+	expires time.Time
+}
+
+func newResultCache() (*resultCache, error) {
+	hashKey := make([]byte, sha256.Size)
+	if _, err := rand.Read(hashKey); err != nil {
+		return nil, fmt.Errorf("unable to generate cache hash key: %w", err)
+	}
+	return &resultCache{entries: make(map[string]map[string]cacheEntry), hashKey: hashKey}, nil
+}
+
+// cacheCaller identifies the caller of an invocation in cache keys, so that
+// results are only shared between invocations of the same caller.
+type cacheCaller struct {
+	// Subjects are the "sub" claims of the verified auth services, by auth
+	// service name, nil for auth services without one
+	Subjects map[string]any `json:"subjects,omitempty"`
+	// Tenant is the claim that selects the source of tenant routed tools
+	Tenant any `json:"tenant,omitempty"`
+}
+
+// callerOf returns the caller of the invocation of ctx. tenant is the tenant
+// routing of the tool, if any.
+func callerOf(ctx context.Context, tenant *tools.TenantSources) cacheCaller {
+	claims := tools.AllClaimsFromContext(ctx)
+	caller := cacheCaller{Subjects: claimSubjects(claims)}
+	if tenant != nil {
+		caller.Tenant = claims[tenant.AuthService][tenant.Claim]
+	}
+	return caller
+}
+
+// claimSubjects returns the "sub" claim of each auth service of claims, nil for
+// auth services without one, or nil if claims is empty.
+func claimSubjects(claims map[string]map[string]any) map[string]any {
+	if len(claims) == 0 {
+		return nil
+	}
+	subjects := make(map[string]any, len(claims))
+	for name, c := range claims {
+		subjects[name] = c["sub"]
+	}
+	return subjects
+}
+
+// key returns the cache key of an invocation by caller with params. The
+// values of the sensitive parameters are replaced by their hash.
+func (c *resultCache) key(caller cacheCaller, params tools.ParamValues, sensitive map[string]bool) (string, error) {
+	if len(sensitive) > 0 {
+		hashed := make(tools.ParamValues, len(params))
+		for i, p := range params {
+			hashed[i] = p
+			if !sensitive[p.Name] {
+				continue
+			}
+			v, err := json.Marshal(p.Value)
+			if err != nil {
+				return "", err
+			}
+			mac := hmac.New(sha256.New, c.hashKey)
+			mac.Write(v)
+			hashed[i].Value = hex.EncodeToString(mac.Sum(nil))
+		}
+		params = hashed
+	}
+	k, err := json.Marshal(struct {
+		Caller cacheCaller       `json:"caller"`
+		Params tools.ParamValues `json:"params"`
+	}{caller, params})
+	if err != nil {
+		return "", err
+	}
+	return string(k), nil
+}
+
+func (c *resultCache) get(toolName, key string) ([]any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[toolName][key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(c.entries[toolName], key)
+		return nil, false
+	}
+	return e.result, true
+}
+
+// set caches result under key for ttl. When the tool already has
+// maxCacheEntries results cached, the one that expires first is evicted.
+func (c *resultCache) set(toolName, key string, result []any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if now.Sub(c.swept) >= cacheSweepInterval {
+		c.sweep(now)
+	}
+	entries, ok := c.entries[toolName]
+	if !ok {
+		entries = make(map[string]cacheEntry)
+		c.entries[toolName] = entries
+	}
+	if _, ok := entries[key]; !ok && len(entries) >= maxCacheEntries {
+		var first string
+		for k, e := range entries {
+			if first == "" || e.expires.Before(entries[first].expires) {
+				first = k
+			}
+		}
+		delete(entries, first)
+	}
+	entries[key] = cacheEntry{result: result, expires: now.Add(ttl)}
+}
+
+// sweep removes the entries that expired at now. c.mu must be held.
+func (c *resultCache) sweep(now time.Time) {
+	for toolName, entries := range c.entries {
+		for key, e := range entries {
+			if now.After(e.expires) {
+				delete(entries, key)
+			}
+		}
+		if len(entries) == 0 {
+			delete(c.entries, toolName)
+		}
+	}
+	c.swept = now
+}
+
+// invalidate clears all cached results of a tool.
+func (c *resultCache) invalidate(toolName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, toolName)
+}
+
+// validate interface
+var _ tools.Tool = cachedTool{}
+
+// cachedTool is a Tool that caches its results and clears the cached results
+// of the tools it invalidates.
+type cachedTool struct {
+	tools.Tool
+	name        string
+	cache       *resultCache
+	ttl         time.Duration
+	invalidates []string
+	// sensitive are the names of the parameters whose values are hashed in
+	// cache keys
+	sensitive map[string]bool
+	// tenant is the tenant routing of the tool, nil if it has none
+	tenant *tools.TenantSources
+}
+
+func (t cachedTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t cachedTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	var key string
+	if t.ttl > 0 {
+		var err error
+		if key, err = t.cache.key(callerOf(ctx, t.tenant), params, t.sensitive); err != nil {
+			return nil, fmt.Errorf("unable to compute cache key: %w", err)
+		}
+		if res, ok := t.cache.get(t.name, key); ok {
+			return res, nil
+		}
+	}
+
+	res, err := t.Tool.Invoke(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.ttl > 0 {
+		t.cache.set(t.name, key, res, t.ttl)
+	}
+	for _, name := range t.invalidates {
+		t.cache.invalidate(name)
+	}
+	return res, nil
+}
+
+// Stream streams the invocations of the wrapped tool without caching their
+// results, and clears the cached results of the tools it invalidates after
+// the rows were streamed.
+func (t cachedTool) Stream() (tools.Streamer, bool) {
+	s, ok := tools.GetStreamer(t.Tool)
+	if !ok || len(t.invalidates) == 0 {
+		return s, ok
+	}
+	return tools.StreamerFunc(func(ctx context.Context, params tools.ParamValues, yield func(row any) error) error {
+		if err := s.InvokeStream(ctx, params, yield); err != nil {
+			return err
+		}
+		for _, name := range t.invalidates {
+			t.cache.invalidate(name)
+		}
+		return nil
+	}), true
+}
+
+// withResultCache wraps the tools that declare caching options so that they
+// share a single result cache, which is created if cache is nil.
+func withResultCache(toolsMap map[string]tools.Tool, cache *resultCache) (map[string]tools.Tool, error) {
+	if cache == nil {
+		var err error
+		if cache, err = newResultCache(); err != nil {
+			return nil, err
+		}
+	}
+	wrapped := make(map[string]tools.Tool, len(toolsMap))
+	for name, t := range toolsMap {
+		opts := tools.GetOptions(t)
+		for _, n := range opts.Invalidates {
+			if _, ok := toolsMap[n]; !ok {
+				return nil, fmt.Errorf("tool %q invalidates tool %q, which does not exist", name, n)
+			}
+		}
+		if opts.CacheTTL == 0 && len(opts.Invalidates) == 0 {
+			wrapped[name] = t
+			continue
+		}
+		wrapped[name] = cachedTool{
+			Tool:        t,
+			name:        name,
+			cache:       cache,
+			ttl:         opts.CacheTTL,
+			invalidates: opts.Invalidates,
+			sensitive:   sensitiveParams(t),
+			tenant:      opts.TenantSources,
+		}
+	}
+	return wrapped, nil
+}
+
+// resultCacheOf returns the result cache of the tools in toolsMap, or nil if
+// none of them is cached.
+func resultCacheOf(toolsMap map[string]tools.Tool) *resultCache {
+	for _, t := range toolsMap {
+		if c, ok := findWrapper[cachedTool](t); ok {
+			return c.cache
+		}
+	}
+	return nil
+}
+
+// sensitiveParams returns the names of the sensitive parameters of t.
+func sensitiveParams(t tools.Tool) map[string]bool {
+	var sensitive map[string]bool
+	for _, p := range t.Manifest().Parameters {
+		if !p.Sensitive {
+			continue
+		}
+		if sensitive == nil {
+			sensitive = make(map[string]bool)
+		}
+		sensitive[p.Name] = true
+	}
+	return sensitive
+}
@@ -0,0 +1,246 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// storeTool reads or writes a shared value, depending on whether it is given
+// parameters.
+type storeTool struct {
+	MockTool
+	value *int
+}
+
+func (t storeTool) Invoke(_ context.Context, params tools.ParamValues) ([]any, error) {
+	if len(params) > 0 {
+		*t.value = params[0].Value.(int)
+	}
+	return []any{*t.value}, nil
+}
+
+func TestResultCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	value := 1
+	toolsMap, err := withResultCache(map[string]tools.Tool{
+		"read": tools.ToolWithOptions{
+			Tool:    storeTool{MockTool: MockTool{Name: "read"}, value: &value},
+			Options: tools.Options{CacheTTL: time.Hour},
+		},
+		"write": tools.ToolWithOptions{
+			Tool:    storeTool{MockTool: MockTool{Name: "write"}, value: &value},
+			Options: tools.Options{Invalidates: []string{"read"}},
+		},
+		"sneaky-write": storeTool{MockTool: MockTool{Name: "sneaky-write"}, value: &value},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	read := func() []any {
+		res, err := toolsMap["read"].Invoke(ctx, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return res
+	}
+	write := func(name string, v int) {
+		if _, err := toolsMap[name].Invoke(ctx, tools.ParamValues{{Name: "value", Value: v}}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if got, want := read(), []any{1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected result: got %v, want %v", got, want)
+	}
+
+	// writes from a tool without invalidation links leave the cache stale
+	write("sneaky-write", 2)
+	if got, want := read(), []any{1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected cached result: got %v, want %v", got, want)
+	}
+
+	// writes from an invalidating tool refresh the cached result
+	write("write", 3)
+	if got, want := read(), []any{3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected refreshed result: got %v, want %v", got, want)
+	}
+}
+
+func TestResultCacheUnknownInvalidates(t *testing.T) {
+	_, err := withResultCache(map[string]tools.Tool{
+		"write": tools.ToolWithOptions{
+			Tool:    MockTool{Name: "write"},
+			Options: tools.Options{Invalidates: []string{"missing"}},
+		},
+	}, nil)
+	if err == nil {
+		t.Fatalf("expected an error, but got nil")
+	}
+}
+
+// countingTool counts its invocations and returns the value of its first
+// parameter.
+type countingTool struct {
+	MockTool
+	count *int
+}
+
+func (t countingTool) Invoke(_ context.Context, params tools.ParamValues) ([]any, error) {
+	*t.count++
+	return []any{params[0].Value}, nil
+}
+
+func TestResultCacheSensitiveParams(t *testing.T) {
+	ctx := context.Background()
+	token := tools.NewStringParameter("token", "api token")
+	token.Sensitive = true
+	count := 0
+	toolsMap, err := withResultCache(map[string]tools.Tool{
+		"lookup": tools.ToolWithOptions{
+			Tool:    countingTool{MockTool: MockTool{Name: "lookup", Params: tools.Parameters{token}}, count: &count},
+			Options: tools.Options{CacheTTL: time.Hour},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	invoke := func(v string) {
+		res, err := toolsMap["lookup"].Invoke(ctx, tools.ParamValues{{Name: "token", Value: v}})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if want := []any{v}; !reflect.DeepEqual(res, want) {
+			t.Fatalf("unexpected result: got %v, want %v", res, want)
+		}
+	}
+
+	// distinct sensitive values are cached separately
+	invoke("secret-a")
+	invoke("secret-b")
+	invoke("secret-a")
+	if count != 2 {
+		t.Fatalf("unexpected number of invocations: got %d, want 2", count)
+	}
+
+	// the raw values don't appear in the cache keys
+	cache := toolsMap["lookup"].(cachedTool).cache
+	if len(cache.entries["lookup"]) != 2 {
+		t.Fatalf("unexpected number of cache entries: got %d, want 2", len(cache.entries["lookup"]))
+	}
+	for key := range cache.entries["lookup"] {
+		if strings.Contains(key, "secret") {
+			t.Fatalf("cache key contains a sensitive value: %s", key)
+		}
+	}
+}
+
+// tenantTool returns the tenant claim of the caller, as a tool whose source
+// is routed by tenant would return the rows of the tenant's source.
+type tenantTool struct {
+	MockTool
+	count *int
+}
+
+func (t tenantTool) Invoke(ctx context.Context, _ tools.ParamValues) ([]any, error) {
+	*t.count++
+	claims, _ := tools.ClaimsFromContext(ctx, "my-auth")
+	return []any{claims["tenant"], claims["sub"]}, nil
+}
+
+func TestResultCacheCallers(t *testing.T) {
+	count := 0
+	toolsMap, err := withResultCache(map[string]tools.Tool{
+		"list": tools.ToolWithOptions{
+			Tool: tenantTool{MockTool: MockTool{Name: "list"}, count: &count},
+			Options: tools.Options{
+				CacheTTL:      time.Hour,
+				TenantSources: &tools.TenantSources{AuthService: "my-auth", Claim: "tenant"},
+			},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	invoke := func(tenant, sub string) {
+		t.Helper()
+		ctx := tools.WithClaims(context.Background(), map[string]map[string]any{
+			"my-auth": {"tenant": tenant, "sub": sub},
+		})
+		res, err := toolsMap["list"].Invoke(ctx, tools.ParamValues{{Name: "limit", Value: 10}})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if want := []any{tenant, sub}; !reflect.DeepEqual(res, want) {
+			t.Fatalf("unexpected result: got %v, want %v", res, want)
+		}
+	}
+
+	// the same parameters are cached separately for each tenant and caller
+	invoke("acme", "alice")
+	invoke("globex", "alice")
+	invoke("acme", "bob")
+	invoke("acme", "alice")
+	invoke("globex", "alice")
+	if count != 3 {
+		t.Fatalf("unexpected number of invocations: got %d, want 3", count)
+	}
+}
+
+func TestResultCacheExpiredEntries(t *testing.T) {
+	c, err := newResultCache()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	result := []any{"result"}
+
+	// expired entries are removed by the next sweep, even if their key is
+	// never read again
+	c.set("lookup", "expired", result, time.Nanosecond)
+	c.set("lookup", "fresh", result, time.Hour)
+	c.set("other", "expired", result, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	c.swept = time.Time{}
+	c.set("lookup", "new", result, time.Hour)
+	if _, ok := c.entries["lookup"]["expired"]; ok {
+		t.Fatalf("expired entry not removed")
+	}
+	if _, ok := c.entries["other"]; ok {
+		t.Fatalf("tool without fresh entries not removed")
+	}
+	if len(c.entries["lookup"]) != 2 {
+		t.Fatalf("unexpected entries after the sweep: %v", c.entries["lookup"])
+	}
+
+	// the number of entries of a tool is capped, evicting those that expire
+	// first
+	c.set("capped", "first", result, time.Minute)
+	for i := 0; i < maxCacheEntries; i++ {
+		c.set("capped", fmt.Sprintf("key-%d", i), result, time.Hour)
+	}
+	if n := len(c.entries["capped"]); n != maxCacheEntries {
+		t.Fatalf("unexpected number of entries: got %d, want %d", n, maxCacheEntries)
+	}
+	if _, ok := c.entries["capped"]["first"]; ok {
+		t.Fatalf("entry that expires first not evicted")
+	}
+}
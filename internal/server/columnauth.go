@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// validate interface
+var _ tools.Tool = columnAuthTool{}
+
+// columnAuthTool is a Tool that omits the columns of its results that the
+// caller isn't authorized to see.
+type columnAuthTool struct {
+	tools.Tool
+	columns map[string]tools.ColumnAuth
+}
+
+func (t columnAuthTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t columnAuthTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	res, err := t.Tool.Invoke(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	denied := t.denied(ctx)
+	if len(denied) == 0 {
+		return res, nil
+	}
+	filtered := make([]any, len(res))
+	for i, row := range res {
+		filtered[i] = withoutColumns(row, denied)
+	}
+	return filtered, nil
+}
+
+// Stream omits the unauthorized columns of each streamed row.
+func (t columnAuthTool) Stream() (tools.Streamer, bool) {
+	s, ok := tools.GetStreamer(t.Tool)
+	if !ok {
+		return nil, false
+	}
+	return tools.StreamerFunc(func(ctx context.Context, params tools.ParamValues, yield func(row any) error) error {
+		denied := t.denied(ctx)
+		return s.InvokeStream(ctx, params, func(row any) error {
+			return yield(withoutColumns(row, denied))
+		})
+	}), true
+}
+
+// denied returns the columns that the caller in ctx may not see.
+func (t columnAuthTool) denied(ctx context.Context) []string {
+	var denied []string
+	for column, c := range t.columns {
+		if !c.Allowed(ctx) {
+			denied = append(denied, column)
+		}
+	}
+	return denied
+}
+
+// withoutColumns returns row without the given columns. The row is copied
+// rather than modified, since it may be shared with the result cache. Rows
+// that aren't maps are returned unchanged.
+func withoutColumns(row any, columns []string) any {
+	m, ok := row.(map[string]any)
+	if !ok || len(columns) == 0 {
+		return row
+	}
+	filtered := make(map[string]any, len(m))
+	for k, v := range m {
+		filtered[k] = v
+	}
+	for _, c := range columns {
+		delete(filtered, c)
+	}
+	return filtered
+}
+
+// withColumnAuth wraps the tools that restrict some of their columns. It must
+// wrap the result cache, so that cached results are filtered for each caller.
+// Tools with scalar results can't restrict columns, since their rows are
+// reduced to a bare value before the columns could be omitted.
+func withColumnAuth(toolsMap map[string]tools.Tool) (map[string]tools.Tool, error) {
+	for name, t := range toolsMap {
+		opts := tools.GetOptions(t)
+		if len(opts.ColumnAuth) == 0 {
+			continue
+		}
+		if opts.ResultMode == tools.ResultModeScalar {
+			return nil, fmt.Errorf("tool %q can't have columnAuth with resultMode %q", name, tools.ResultModeScalar)
+		}
+		toolsMap[name] = columnAuthTool{Tool: t, columns: opts.ColumnAuth}
+	}
+	return toolsMap, nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// roleAuthService is an auth service whose token is the role of the caller.
+type roleAuthService struct{}
+
+func (roleAuthService) AuthServiceKind() string { return "role" }
+
+func (roleAuthService) GetName() string { return "my-role-auth" }
+
+func (roleAuthService) GetClaimsFromHeader(_ context.Context, h http.Header) (map[string]any, error) {
+	role := auth.TokenFromHeader(h, "my-role-auth")
+	if role == "" {
+		return nil, nil
+	}
+	return map[string]any{"roles": []any{"employee", role}}, nil
+}
+
+// employeeTool returns a single row with a salary column.
+type employeeTool struct {
+	MockTool
+}
+
+func (t employeeTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+	return []any{map[string]any{"name": "alice", "salary": 100}}, nil
+}
+
+func TestToolInvokeColumnAuth(t *testing.T) {
+	tool := tools.ToolWithOptions{
+		Tool: employeeTool{MockTool: MockTool{Name: "employees"}},
+		Options: tools.Options{
+			// the result is cached for privileged callers first
+			CacheTTL: time.Minute,
+			ColumnAuth: map[string]tools.ColumnAuth{
+				"salary": {AuthService: "my-role-auth", Claim: "roles", Values: []string{"hr"}},
+			},
+		},
+	}
+	toolsMap, err := withResultCache(map[string]tools.Tool{"employees": tool}, nil)
+	if err != nil {
+		t.Fatalf("unable to set up result cache: %s", err)
+	}
+	toolsMap, err = withColumnAuth(toolsMap)
+	if err != nil {
+		t.Fatalf("unable to set up column auth: %s", err)
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil, func(s *Server) {
+		s.authServices = map[string]auth.AuthService{"my-role-auth": roleAuthService{}}
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name string
+		role string
+		want []map[string]any
+	}{
+		{
+			name: "privileged caller",
+			role: "hr",
+			want: []map[string]any{{"name": "alice", "salary": float64(100)}},
+		},
+		{
+			name: "unprivileged caller",
+			role: "engineering",
+			want: []map[string]any{{"name": "alice"}},
+		},
+		{
+			name: "anonymous caller",
+			want: []map[string]any{{"name": "alice"}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, ts.URL+"/tool/employees/invoke", bytes.NewBufferString(`{}`))
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			if tc.role != "" {
+				req.Header.Set("my-role-auth_token", tc.role)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d", resp.StatusCode)
+			}
+			var got resultResponse
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			var rows []map[string]any
+			if err := json.Unmarshal([]byte(got.Result), &rows); err != nil {
+				t.Fatalf("unable to parse result: %s", err)
+			}
+			if !reflect.DeepEqual(rows, tc.want) {
+				t.Fatalf("unexpected result: got %v, want %v", rows, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithColumnAuthScalar(t *testing.T) {
+	// the rows of scalar tools are reduced to a value, so restricted columns
+	// can't be omitted from them
+	tool := tools.ToolWithOptions{
+		Tool: employeeTool{MockTool: MockTool{Name: "salary"}},
+		Options: tools.Options{
+			ResultMode: tools.ResultModeScalar,
+			ColumnAuth: map[string]tools.ColumnAuth{
+				"salary": {AuthService: "my-role-auth", Claim: "roles", Values: []string{"hr"}},
+			},
+		},
+	}
+	if _, err := withColumnAuth(map[string]tools.Tool{"salary": tool}); err == nil {
+		t.Fatalf("expected an error for columnAuth with scalar results")
+	}
+}
@@ -49,8 +49,8 @@ func (t MockTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
 }
 
 // claims is a map of user info decoded from an auth token
-func (t MockTool) ParseParams(data map[string]any, claimsMap map[string]map[string]any) (tools.ParamValues, error) {
-	return tools.ParseParams(t.Params, data, claimsMap)
+func (t MockTool) ParseParams(ctx context.Context, data map[string]any, claimsMap map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(ctx, t.Params, data, claimsMap)
 }
 
 func (t MockTool) Manifest() tools.Manifest {
@@ -134,8 +134,9 @@ func setUpResources(t *testing.T, mockTools []MockTool) (map[string]tools.Tool,
 	return toolsMap, toolsets
 }
 
-// setUpServer create a new server with tools and toolsets that are given
-func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, toolsets map[string]tools.Toolset) (chi.Router, func()) {
+// setUpServer create a new server with tools and toolsets that are given.
+// Optional funcs can be provided to modify the server before the router is created.
+func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, toolsets map[string]tools.Toolset, opts ...func(*Server)) (chi.Router, func()) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
@@ -143,7 +144,7 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 		t.Fatalf("unable to initialize logger: %s", err)
 	}
 
-	otelShutdown, err := telemetry.SetupOTel(ctx, fakeVersionString, "", false, "toolbox")
+	otelShutdown, err := telemetry.SetupOTel(ctx, fakeVersionString, "", false, "toolbox", false, false)
 	if err != nil {
 		t.Fatalf("unable to setup otel: %s", err)
 	}
@@ -159,6 +160,9 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 	}
 
 	server := Server{version: fakeVersionString, logger: testLogger, instrumentation: instrumentation, sseManager: sseManager, tools: tools, toolsets: toolsets}
+	for _, o := range opts {
+		o(&server)
+	}
 	var r chi.Router
 	switch router {
 	case "api":
@@ -0,0 +1,210 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultCompressionMinBytes is the minimum size in bytes of the responses
+// that are compressed when ServerConfig.CompressionMinBytes is not set.
+const DefaultCompressionMinBytes = 1024
+
+// streamingContentTypes are the media types of responses that are streamed,
+// which are never compressed so that each part reaches the client as soon as
+// it is flushed.
+var streamingContentTypes = map[string]bool{
+	ndjsonContentType:   true,
+	"text/event-stream": true,
+}
+
+// acceptsGzip reports whether the Accept-Encoding header of r allows gzip.
+func acceptsGzip(r *http.Request) bool {
+	for _, v := range r.Header.Values("Accept-Encoding") {
+		for _, part := range strings.Split(v, ",") {
+			coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			coding = strings.ToLower(strings.TrimSpace(coding))
+			if coding != "gzip" && coding != "*" {
+				continue
+			}
+			if q, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); ok {
+				if f, err := strconv.ParseFloat(q, 64); err == nil && f == 0 {
+					continue
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// compressResponses is a middleware that compresses responses of at least
+// s.compressMinBytes with gzip, for clients that accept it. Streamed
+// responses, and responses that are already encoded, are sent as is.
+func compressResponses(s *Server) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if s.compressMinBytes < 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Accept-Encoding")
+			if !acceptsGzip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			gw := &gzipResponseWriter{ResponseWriter: w, minBytes: s.compressMinBytes}
+			defer gw.close()
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+// gzipResponseWriter buffers the start of a response until it is known
+// whether to compress it: once minBytes have been written, the response is
+// compressed, and if the handler completes or flushes first, it is sent as
+// is.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minBytes int
+
+	// status is the status code written by the handler, 0 if none yet
+	status int
+	buf    []byte
+	// decided is true once the response is either compressed or sent as is
+	decided bool
+	// gz compresses the response, nil if it is sent as is
+	gz *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		// informational responses precede the response, and are sent as is
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.status != 0 {
+		return
+	}
+	w.status = code
+	if !w.compressible() {
+		w.passThrough()
+	}
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= w.minBytes {
+		if err := w.startGzip(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush sends the buffered response as is, since a response that is flushed
+// before it is large enough to be compressed is likely streamed.
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		w.passThrough()
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// compressible reports whether the response may be compressed, judging by
+// its status and headers.
+func (w *gzipResponseWriter) compressible() bool {
+	if w.status < http.StatusOK || w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		return false
+	}
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	return !streamingContentTypes[mediaType]
+}
+
+// passThrough sends the status and the buffered response as is.
+func (w *gzipResponseWriter) passThrough() {
+	w.decided = true
+	w.ResponseWriter.WriteHeader(w.status)
+	if len(w.buf) > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf)
+		w.buf = nil
+	}
+}
+
+// startGzip sends the status and starts compressing the response with the
+// buffered bytes, unless its headers, which may have been set after the
+// status, show it must be sent as is.
+func (w *gzipResponseWriter) startGzip() error {
+	if !w.compressible() {
+		w.passThrough()
+		return nil
+	}
+	w.decided = true
+	h := w.Header()
+	h.Set("Content-Encoding", "gzip")
+	h.Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.status)
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+	_, err := w.gz.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+// close completes the response once the handler returned.
+func (w *gzipResponseWriter) close() {
+	if !w.decided {
+		if w.status == 0 {
+			// the handler wrote nothing, let the server send its default
+			return
+		}
+		w.passThrough()
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+	}
+}
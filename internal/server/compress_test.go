@@ -0,0 +1,168 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestCompressResponses(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"many_rows": rowsTool{MockTool: MockTool{Name: "many_rows"}, rows: 500},
+		"one_row":   rowsTool{MockTool: MockTool{Name: "one_row"}, rows: 1},
+		"stream":    streamTool{MockTool: MockTool{Name: "stream"}, rows: []any{map[string]any{"id": 1}, map[string]any{"id": 2}}},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil, func(s *Server) {
+		s.compressMinBytes = 256
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	// send sets Accept-Encoding itself, so that the client doesn't decompress
+	// the response transparently
+	send := func(t *testing.T, path, acceptEncoding, accept string) (*http.Response, []byte) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, ts.URL+path, bytes.NewBufferString(`{}`))
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read body: %s", err)
+		}
+		return resp, body
+	}
+
+	tcs := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		accept         string
+		wantGzip       bool
+	}{
+		{
+			name:           "large response",
+			path:           "/tool/many_rows/invoke",
+			acceptEncoding: "gzip, deflate",
+			wantGzip:       true,
+		},
+		{
+			name:           "gzip not accepted",
+			path:           "/tool/many_rows/invoke",
+			acceptEncoding: "identity",
+		},
+		{
+			name:           "gzip refused",
+			path:           "/tool/many_rows/invoke",
+			acceptEncoding: "gzip;q=0, identity",
+		},
+		{
+			name:           "response under the threshold",
+			path:           "/tool/one_row/invoke",
+			acceptEncoding: "gzip",
+		},
+		{
+			name:           "streamed response",
+			path:           "/tool/stream/invoke",
+			acceptEncoding: "gzip",
+			accept:         ndjsonContentType,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			plainResp, want := send(t, tc.path, "identity", tc.accept)
+			if plainResp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", plainResp.StatusCode, http.StatusOK, want)
+			}
+
+			resp, body := send(t, tc.path, tc.acceptEncoding, tc.accept)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+			}
+			if got := resp.Header.Get("Vary"); got != "Accept-Encoding" {
+				t.Fatalf("unexpected Vary header: got %q, want %q", got, "Accept-Encoding")
+			}
+			if !tc.wantGzip {
+				if got := resp.Header.Get("Content-Encoding"); got != "" {
+					t.Fatalf("expected the response not to be compressed, got Content-Encoding %q", got)
+				}
+				if !bytes.Equal(body, want) {
+					t.Fatalf("unexpected body: got %s, want %s", body, want)
+				}
+				return
+			}
+			if got := resp.Header.Get("Content-Encoding"); got != "gzip" {
+				t.Fatalf("unexpected Content-Encoding header: got %q, want %q", got, "gzip")
+			}
+			if len(body) >= len(want) {
+				t.Fatalf("expected the compressed body to be smaller: got %d bytes, uncompressed %d bytes", len(body), len(want))
+			}
+			zr, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("unable to read gzip body: %s", err)
+			}
+			got, err := io.ReadAll(zr)
+			if err != nil {
+				t.Fatalf("unable to decompress body: %s", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("unexpected decompressed body: got %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tcs := []struct {
+		header string
+		want   bool
+	}{
+		{header: "", want: false},
+		{header: "gzip", want: true},
+		{header: "deflate, GZIP;q=0.5", want: true},
+		{header: "br, *", want: true},
+		{header: "gzip; q=0", want: false},
+		{header: "identity", want: false},
+	}
+	for _, tc := range tcs {
+		r, err := http.NewRequest(http.MethodGet, "/", nil)
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		if tc.header != "" {
+			r.Header.Set("Accept-Encoding", tc.header)
+		}
+		if got := acceptsGzip(r); got != tc.want {
+			t.Errorf("unexpected result for %q: got %t, want %t", tc.header, got, tc.want)
+		}
+	}
+}
@@ -16,19 +16,31 @@ package server
 import (
 	"context"
 	"fmt"
+	"maps"
 	"strings"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/auth/apikey"
 	"github.com/googleapis/genai-toolbox/internal/auth/google"
+	"github.com/googleapis/genai-toolbox/internal/auth/oidc"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
+// DefaultMaxRequestBytes is the maximum size in bytes of the bodies of invoke
+// and MCP requests when ServerConfig.MaxRequestBytes is not set.
+const DefaultMaxRequestBytes = 4 << 20
+
 type ServerConfig struct {
 	// Server version
 	Version string
+	// BuildCommit is the commit the server was built from, if known.
+	BuildCommit string
+	// BuildDate is when the server was built, if known.
+	BuildDate string
 	// Address is the address of the interface the server will listen on.
 	Address string
 	// Port is the port the server will listen on.
@@ -41,6 +53,8 @@ type ServerConfig struct {
 	ToolConfigs ToolConfigs
 	// ToolsetConfigs defines what tools are available.
 	ToolsetConfigs ToolsetConfigs
+	// PromptConfigs defines the prompts served to MCP clients.
+	PromptConfigs PromptConfigs
 	// LoggingFormat defines whether structured loggings are used.
 	LoggingFormat logFormat
 	// LogLevel defines the levels to log.
@@ -51,8 +65,125 @@ type ServerConfig struct {
 	TelemetryOTLP string
 	// TelemetryServiceName defines the value of service.name resource attribute.
 	TelemetryServiceName string
+	// TelemetryPrometheus defines whether metrics are served in the Prometheus
+	// format at /metrics.
+	TelemetryPrometheus bool
+	// TelemetryOTLPLogs defines whether logs are exported to the OTLP
+	// collector at TelemetryOTLP.
+	TelemetryOTLPLogs bool
 	// Stdio indicates if Toolbox is listening via MCP stdio.
 	Stdio bool
+	// MaxSSESessions is the maximum number of concurrent MCP SSE sessions.
+	// A value of 0 means there is no limit.
+	MaxSSESessions int
+	// MaxConcurrentInvocations is the maximum number of tool invocations that
+	// run at once. A value of 0 means there is no limit.
+	MaxConcurrentInvocations int
+	// InvocationQueueDepth is the number of invocations that may wait for a
+	// slot once MaxConcurrentInvocations is reached.
+	InvocationQueueDepth int
+	// InvocationQueueTimeout is how long a queued invocation waits for a slot
+	// before being rejected.
+	InvocationQueueTimeout time.Duration
+	// MaxUploadSize is the maximum size in bytes of invoke requests that
+	// upload files.
+	MaxUploadSize int64
+	// MaxRequestBytes is the maximum size in bytes of the bodies of other
+	// invoke requests and of MCP requests. It is DefaultMaxRequestBytes if 0,
+	// and there is no limit if it is negative.
+	MaxRequestBytes int64
+	// CompressionMinBytes is the minimum size in bytes of the /api and /mcp
+	// responses that are compressed with gzip for the clients that accept
+	// it. It is DefaultCompressionMinBytes if 0, and responses aren't
+	// compressed if it is negative.
+	CompressionMinBytes int
+	// RateLimit is the number of requests per second that each client may
+	// send to the /api and /mcp endpoints. A value of 0 means there is no
+	// limit.
+	RateLimit float64
+	// RateLimitBurst is the number of requests that each client may send at
+	// once before RateLimit applies. It is RateLimit, rounded up, if 0.
+	RateLimitBurst int
+	// RateLimitHeader is the header that identifies clients for RateLimit,
+	// such as one set by an authenticating proxy. Clients are identified by
+	// their IP address if it is empty or missing from a request.
+	RateLimitHeader string
+	// ResourcePollInterval is how often resources with MCP subscriptions are
+	// polled for changes.
+	ResourcePollInterval time.Duration
+	// HealthCheckInterval is how often the health of the sources is checked
+	// in the background. If 0, sources are checked on each health request.
+	HealthCheckInterval time.Duration
+	// MaxSourceResultBytes is the maximum size in bytes of the results of each
+	// source's tools that may be in flight at once. A value of 0 means there
+	// is no limit.
+	MaxSourceResultBytes int64
+	// CircuitBreakerThreshold is the number of consecutive invocations of a
+	// source's tools that must fail to reach it for them to fail fast for
+	// CircuitBreakerCooldown. A value of 0 disables circuit breakers.
+	CircuitBreakerThreshold int
+	// CircuitBreakerCooldown is how long the tools of a source fail fast once
+	// its circuit breaker opened, before an invocation tests whether the
+	// source recovered.
+	CircuitBreakerCooldown time.Duration
+	// SourceMeta includes the name and kind of the source that served each
+	// invocation in its result metadata.
+	SourceMeta bool
+	// ExecutionMetadata includes the duration and row count of each
+	// invocation, and whether its result was truncated, in the `_meta` field
+	// of its result.
+	ExecutionMetadata bool
+	// ToolNameMode defines how tool names that are not safe to use in URLs
+	// are handled.
+	ToolNameMode toolNameMode
+	// TimeZone is the time zone that time values in results are converted
+	// to. If empty, time values are returned in the zone set by the source.
+	TimeZone timeZone
+	// ShutdownGracePeriod is how long a shutdown waits for sessions and
+	// connections to close before forcing them closed.
+	ShutdownGracePeriod time.Duration
+	// AdminAuth is the claim of an auth service that callers of the admin
+	// endpoints under /api/admin need. The admin endpoints are disabled if
+	// neither AdminAuth nor AdminTokenFile are set.
+	AdminAuth tools.ColumnAuth
+	// AdminTokenFile is the file with a bearer token that grants access to
+	// the admin endpoints too, for callers without an auth service.
+	AdminTokenFile string
+	// McpToolCallTimeout is the timeout of MCP tool calls to tools that don't
+	// declare a `timeout` of their own. A value of 0 means there is no limit.
+	McpToolCallTimeout time.Duration
+	// ToolsListPageSize is the maximum number of tools returned by each MCP
+	// tools/list request. Clients page through the rest with the returned
+	// cursor. A value of 0 means all tools are returned at once.
+	ToolsListPageSize int
+	// ArgumentsKey is the key of the object that invoke arguments are nested
+	// under in HTTP request bodies and MCP tool calls. If empty, arguments
+	// are read from the top level.
+	ArgumentsKey string
+	// StdioFraming defines how MCP messages are delimited when listening via
+	// stdio.
+	StdioFraming stdioFraming
+	// CorsAllowedOrigins are the origins allowed to make cross-origin
+	// requests, or "*" for all origins. If empty, no CORS headers are sent.
+	CorsAllowedOrigins []string
+	// CorsAllowedMethods are the methods allowed in cross-origin requests. If
+	// empty, GET, POST and DELETE are allowed.
+	CorsAllowedMethods []string
+	// CorsAllowedHeaders are the headers allowed in cross-origin requests. If
+	// empty, the headers used by Toolbox clients are allowed.
+	CorsAllowedHeaders []string
+	// CorsAllowCredentials allows cross-origin requests to include
+	// credentials, such as cookies.
+	CorsAllowCredentials bool
+	// TLSCertFile and TLSKeyFile are the PEM files of the certificate and
+	// private key the server serves HTTPS with. If empty, the server serves
+	// plain HTTP.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSClientCAFile is the PEM file of the CA certificates that client
+	// certificates are verified against. If set, clients must present a
+	// certificate signed by one of them.
+	TLSClientCAFile string
 }
 
 type logFormat string
@@ -81,6 +212,95 @@ func (f *logFormat) Type() string {
 	return "logFormat"
 }
 
+type toolNameMode string
+
+// String is used by both fmt.Print and by Cobra in help text
+func (m *toolNameMode) String() string {
+	if string(*m) != "" {
+		return strings.ToLower(string(*m))
+	}
+	return "strict"
+}
+
+// validate tool name mode flag
+func (m *toolNameMode) Set(v string) error {
+	switch strings.ToLower(v) {
+	case "strict", "escape":
+		*m = toolNameMode(v)
+		return nil
+	default:
+		return fmt.Errorf(`tool name mode must be one of "strict", or "escape"`)
+	}
+}
+
+// Type is used in Cobra help text
+func (m *toolNameMode) Type() string {
+	return "toolNameMode"
+}
+
+type stdioFraming string
+
+const (
+	// stdioFramingNewline delimits each message with a newline.
+	stdioFramingNewline = "newline"
+	// stdioFramingContentLength prefixes each message with a Content-Length
+	// header, as in the Language Server Protocol.
+	stdioFramingContentLength = "content-length"
+)
+
+// String is used by both fmt.Print and by Cobra in help text
+func (f *stdioFraming) String() string {
+	if string(*f) != "" {
+		return strings.ToLower(string(*f))
+	}
+	return stdioFramingNewline
+}
+
+// validate stdio framing flag
+func (f *stdioFraming) Set(v string) error {
+	switch strings.ToLower(v) {
+	case stdioFramingNewline, stdioFramingContentLength:
+		*f = stdioFraming(v)
+		return nil
+	default:
+		return fmt.Errorf(`stdio framing must be one of "newline", or "content-length"`)
+	}
+}
+
+// Type is used in Cobra help text
+func (f *stdioFraming) Type() string {
+	return "stdioFraming"
+}
+
+type timeZone string
+
+// String is used by both fmt.Print and by Cobra in help text
+func (z *timeZone) String() string {
+	return string(*z)
+}
+
+// validate time zone flag
+func (z *timeZone) Set(v string) error {
+	if _, err := time.LoadLocation(v); err != nil {
+		return fmt.Errorf("invalid time zone %q: %w", v, err)
+	}
+	*z = timeZone(v)
+	return nil
+}
+
+// Type is used in Cobra help text
+func (z *timeZone) Type() string {
+	return "timeZone"
+}
+
+// Location returns the time zone as a *time.Location, or nil if it is empty.
+func (z timeZone) Location() (*time.Location, error) {
+	if z == "" {
+		return nil, nil
+	}
+	return time.LoadLocation(string(z))
+}
+
 type StringLevel string
 
 // String is used by both fmt.Print and by Cobra in help text
@@ -187,6 +407,18 @@ func (c *AuthServiceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(i
 				return fmt.Errorf("unable to parse as %q: %w", kind, err)
 			}
 			(*c)[name] = actual
+		case apikey.AuthServiceKind:
+			actual := apikey.Config{Name: name}
+			if err := dec.DecodeContext(ctx, &actual); err != nil {
+				return fmt.Errorf("unable to parse as %q: %w", kind, err)
+			}
+			(*c)[name] = actual
+		case oidc.AuthServiceKind:
+			actual := oidc.Config{Name: name}
+			if err := dec.DecodeContext(ctx, &actual); err != nil {
+				return fmt.Errorf("unable to parse as %q: %w", kind, err)
+			}
+			(*c)[name] = actual
 		default:
 			return fmt.Errorf("%q is not a valid kind of auth source", kind)
 		}
@@ -213,33 +445,68 @@ func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interfac
 		if err := u.Unmarshal(&v); err != nil {
 			return fmt.Errorf("unable to unmarshal %q: %w", name, err)
 		}
-
-		// Make `authRequired` an empty list instead of nil for Tool manifest
-		if v["authRequired"] == nil {
-			v["authRequired"] = []string{}
+		toolCfg, err := decodeTool(ctx, name, v)
+		if err != nil {
+			return err
 		}
+		(*c)[name] = toolCfg
+	}
+	return nil
+}
 
-		kindVal, ok := v["kind"]
-		if !ok {
-			return fmt.Errorf("missing 'kind' field for tool %q", name)
-		}
-		kindStr, ok := kindVal.(string)
-		if !ok {
-			return fmt.Errorf("invalid 'kind' field for tool %q (must be a string)", name)
-		}
+// decodeTool decodes the raw configuration v of the tool named name.
+func decodeTool(ctx context.Context, name string, v map[string]any) (tools.ToolConfig, error) {
+	// Make `authRequired` an empty list instead of nil for Tool manifest
+	if v["authRequired"] == nil {
+		v["authRequired"] = []string{}
+	}
 
-		yamlDecoder, err := util.NewStrictDecoder(v)
-		if err != nil {
-			return fmt.Errorf("error creating YAML decoder for tool %q: %w", name, err)
-		}
+	kindVal, ok := v["kind"]
+	if !ok {
+		return nil, fmt.Errorf("missing 'kind' field for tool %q", name)
+	}
+	kindStr, ok := kindVal.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'kind' field for tool %q (must be a string)", name)
+	}
 
-		toolCfg, err := tools.DecodeConfig(ctx, kindStr, name, yamlDecoder)
+	// Options are shared by all tool kinds, so they are decoded separately
+	rawCfg := maps.Clone(v)
+	opts, toolCfg, err := decodeToolConfig(ctx, kindStr, name, v)
+	if err != nil {
+		return nil, err
+	}
+	// keys declared by the tool's kind take precedence over options
+	if keep := tools.DeclaredOptionKeys(toolCfg); len(keep) > 0 {
+		opts, toolCfg, err = decodeToolConfig(ctx, kindStr, name, rawCfg, keep...)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		(*c)[name] = toolCfg
 	}
-	return nil
+	if !opts.IsZero() {
+		toolCfg = tools.ConfigWithOptions{ToolConfig: toolCfg, Options: opts}
+	}
+	return toolCfg, nil
+}
+
+// decodeToolConfig splits the Options, except those in keep, from the raw
+// tool configuration v and decodes the rest as a tool config of kind.
+func decodeToolConfig(ctx context.Context, kind, name string, v map[string]any, keep ...string) (tools.Options, tools.ToolConfig, error) {
+	opts, err := tools.SplitOptions(ctx, v, keep...)
+	if err != nil {
+		return opts, nil, fmt.Errorf("unable to parse options for tool %q: %w", name, err)
+	}
+
+	yamlDecoder, err := util.NewStrictDecoder(v)
+	if err != nil {
+		return opts, nil, fmt.Errorf("error creating YAML decoder for tool %q: %w", name, err)
+	}
+
+	toolCfg, err := tools.DecodeConfig(ctx, kind, name, yamlDecoder)
+	if err != nil {
+		return opts, nil, err
+	}
+	return opts, toolCfg, nil
 }
 
 // ToolConfigs is a type used to allow unmarshal of the toolset configs
@@ -261,3 +528,34 @@ func (c *ToolsetConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(inter
 	}
 	return nil
 }
+
+// PromptConfigs is a type used to allow unmarshal of the prompt configs
+type PromptConfigs map[string]tools.PromptConfig
+
+// validate interface
+var _ yaml.InterfaceUnmarshalerContext = &PromptConfigs{}
+
+func (c *PromptConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	*c = make(PromptConfigs)
+	var raw map[string]util.DelayedUnmarshaler
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	for name, u := range raw {
+		var v map[string]any
+		if err := u.Unmarshal(&v); err != nil {
+			return fmt.Errorf("unable to unmarshal %q: %w", name, err)
+		}
+		dec, err := util.NewStrictDecoder(v)
+		if err != nil {
+			return fmt.Errorf("error creating decoder for prompt %q: %w", name, err)
+		}
+		actual := tools.PromptConfig{Name: name}
+		if err := dec.DecodeContext(ctx, &actual); err != nil {
+			return fmt.Errorf("unable to parse prompt %q: %w", name, err)
+		}
+		(*c)[name] = actual
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Default methods and headers allowed in cross-origin requests, if none are
+// configured.
+var (
+	defaultCorsMethods = []string{http.MethodGet, http.MethodPost, http.MethodDelete}
+	defaultCorsHeaders = []string{"Accept", "Authorization", "Content-Type", toolVersionHeader}
+)
+
+// corsConfig is the CORS policy of the server.
+type corsConfig struct {
+	// allowedOrigins are the origins allowed to make cross-origin requests.
+	// "*" allows all origins.
+	allowedOrigins   []string
+	allowedMethods   []string
+	allowedHeaders   []string
+	allowCredentials bool
+}
+
+// allowsOrigin reports whether origin may make cross-origin requests.
+func (c corsConfig) allowsOrigin(origin string) bool {
+	for _, o := range c.allowedOrigins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// cors is a middleware that adds the CORS headers of c to the responses to
+// requests from allowed origins, and answers their preflight requests. It
+// does nothing if no origins are allowed.
+func cors(c corsConfig) func(http.Handler) http.Handler {
+	if len(c.allowedMethods) == 0 {
+		c.allowedMethods = defaultCorsMethods
+	}
+	if len(c.allowedHeaders) == 0 {
+		c.allowedHeaders = defaultCorsHeaders
+	}
+	methods := strings.Join(c.allowedMethods, ", ")
+	headers := strings.Join(c.allowedHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		if len(c.allowedOrigins) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			w.Header().Add("Vary", "Origin")
+			if origin == "" || !c.allowsOrigin(origin) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// the origin is echoed back rather than "*", which browsers
+			// reject for requests with credentials
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if c.allowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if !preflight {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			if !slices.Contains(c.allowedMethods, r.Header.Get("Access-Control-Request-Method")) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Methods", methods)
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCors(t *testing.T) {
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	cfg := corsConfig{
+		allowedOrigins:   []string{"https://app.example.com"},
+		allowedMethods:   []string{http.MethodGet, http.MethodPost},
+		allowedHeaders:   []string{"Content-Type", "Authorization"},
+		allowCredentials: true,
+	}
+
+	tcs := []struct {
+		desc       string
+		cfg        corsConfig
+		method     string
+		headers    map[string]string
+		wantStatus int
+		want       map[string]string
+	}{
+		{
+			desc:   "preflight from allowed origin",
+			cfg:    cfg,
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  http.MethodPost,
+				"Access-Control-Request-Headers": "Content-Type",
+			},
+			wantStatus: http.StatusNoContent,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Methods":     "GET, POST",
+				"Access-Control-Allow-Headers":     "Content-Type, Authorization",
+				"Access-Control-Allow-Credentials": "true",
+			},
+		},
+		{
+			desc:   "preflight for method not allowed",
+			cfg:    cfg,
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": http.MethodDelete,
+			},
+			wantStatus: http.StatusForbidden,
+			want: map[string]string{
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+		{
+			desc:   "preflight from other origin",
+			cfg:    cfg,
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://evil.example.com",
+				"Access-Control-Request-Method": http.MethodPost,
+			},
+			wantStatus: http.StatusOK,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":  "",
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+		{
+			desc:       "request from allowed origin",
+			cfg:        cfg,
+			method:     http.MethodPost,
+			headers:    map[string]string{"Origin": "https://app.example.com"},
+			wantStatus: http.StatusOK,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+			},
+		},
+		{
+			desc:       "wildcard origin with default methods",
+			cfg:        corsConfig{allowedOrigins: []string{"*"}},
+			method:     http.MethodOptions,
+			headers:    map[string]string{"Origin": "https://any.example.com", "Access-Control-Request-Method": http.MethodGet},
+			wantStatus: http.StatusNoContent,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":      "https://any.example.com",
+				"Access-Control-Allow-Methods":     "GET, POST, DELETE",
+				"Access-Control-Allow-Credentials": "",
+			},
+		},
+		{
+			desc:       "disabled by default",
+			cfg:        corsConfig{},
+			method:     http.MethodPost,
+			headers:    map[string]string{"Origin": "https://app.example.com"},
+			wantStatus: http.StatusOK,
+			want: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/api/toolset", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			cors(tc.cfg)(okHandler).ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("unexpected status code: want %d, got %d", tc.wantStatus, rec.Code)
+			}
+			for k, want := range tc.want {
+				if got := rec.Header().Get(k); got != want {
+					t.Errorf("unexpected %s header: want %q, got %q", k, want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// validate interface
+var _ tools.Tool = etagTool{}
+
+// etagTool is a Tool with the ETag of its definition.
+type etagTool struct {
+	tools.Tool
+	etag string
+}
+
+func (t etagTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+// computeETag returns a strong ETag of the definition of a tool, which is its
+// manifests and the kind of its source. It only changes when the definition
+// does, so that clients can cache the schema of the tool.
+func computeETag(t tools.Tool, sourceKind string) string {
+	// the manifests are served as JSON, so they can always be marshaled
+	b, _ := json.Marshal(struct {
+		Manifest    tools.Manifest    `json:"manifest"`
+		McpManifest tools.McpManifest `json:"mcpManifest"`
+		SourceKind  string            `json:"sourceKind"`
+	}{t.Manifest(), t.McpManifest(), sourceKind})
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// toolETag returns the ETag of a tool. Tools that were not wrapped by
+// withETags, which have no known source kind, are hashed on the fly.
+func toolETag(t tools.Tool) string {
+	if e, ok := t.(etagTool); ok {
+		return e.etag
+	}
+	return computeETag(t, "")
+}
+
+// withETags wraps each tool with the ETag of its definition. It wraps all
+// other tools, so that the ETag covers the parameters they add.
+// toolSources maps tool names to the name of their source.
+func withETags(toolsMap map[string]tools.Tool, toolSources map[string]string, sourceConfigs SourceConfigs) map[string]tools.Tool {
+	for name, t := range toolsMap {
+		var sourceKind string
+		if sc, ok := sourceConfigs[toolSources[name]]; ok {
+			sourceKind = sc.SourceConfigKind()
+		}
+		toolsMap[name] = etagTool{Tool: t, etag: computeETag(t, sourceKind)}
+	}
+	return toolsMap
+}
+
+// etagMatches returns whether an If-None-Match header matches etag. Weak
+// comparison is used, as required for If-None-Match.
+func etagMatches(h http.Header, etag string) bool {
+	for _, v := range h.Values("If-None-Match") {
+		for _, tag := range strings.Split(v, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+				return true
+			}
+		}
+	}
+	return false
+}
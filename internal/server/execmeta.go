@@ -0,0 +1,30 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import "time"
+
+// withExecutionMeta adds the execution metadata of an invocation to the
+// metadata of its result, which may be nil. The duration is formatted like
+// "1.5ms", and rowCount is the number of rows returned.
+func withExecutionMeta(meta map[string]any, duration time.Duration, rowCount int, truncated bool) map[string]any {
+	if meta == nil {
+		meta = make(map[string]any)
+	}
+	meta["duration"] = duration.String()
+	meta["rowCount"] = rowCount
+	meta["truncated"] = truncated
+	return meta
+}
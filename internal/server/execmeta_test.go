@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// checkExecutionMeta checks the execution metadata decoded from a JSON result.
+func checkExecutionMeta(t *testing.T, meta map[string]any, wantRows int, wantTruncated bool) {
+	t.Helper()
+	d, ok := meta["duration"].(string)
+	if !ok {
+		t.Fatalf("missing duration in metadata: %v", meta)
+	}
+	if _, err := time.ParseDuration(d); err != nil {
+		t.Fatalf("invalid duration %q: %s", d, err)
+	}
+	if got := meta["rowCount"]; got != float64(wantRows) {
+		t.Fatalf("unexpected rowCount: want %d, got %v", wantRows, got)
+	}
+	if got := meta["truncated"]; got != wantTruncated {
+		t.Fatalf("unexpected truncated: want %t, got %v", wantTruncated, got)
+	}
+}
+
+func TestToolInvokeExecutionMeta(t *testing.T) {
+	tool := rowsTool{MockTool: MockTool{Name: "many_rows"}, rows: 5}
+	toolsMap := map[string]tools.Tool{tool.Name: tool}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil, func(s *Server) { s.executionMetadata = true })
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name          string
+		requestBody   string
+		wantRows      int
+		wantTruncated bool
+	}{
+		{name: "full result", requestBody: `{}`, wantRows: 5},
+		{name: "truncated preview", requestBody: `{"_preview": 2}`, wantRows: 2, wantTruncated: true},
+		{name: "preview of the full result", requestBody: `{"_preview": 10}`, wantRows: 5},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, "/tool/many_rows/invoke", bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(body))
+			}
+			var got resultResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			checkExecutionMeta(t, got.Meta, tc.wantRows, tc.wantTruncated)
+		})
+	}
+}
+
+func TestMcpToolCallExecutionMeta(t *testing.T) {
+	tool := rowsTool{MockTool: MockTool{Name: "many_rows"}, rows: 5}
+	toolsMap := map[string]tools.Tool{tool.Name: tool}
+	r, shutdown := setUpServer(t, "mcp", toolsMap, nil, func(s *Server) { s.executionMetadata = true })
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "many-rows-call",
+		Request: mcp.Request{
+			Method: "tools/call",
+		},
+		Params: map[string]any{"name": "many_rows", "arguments": map[string]any{"_preview": 3}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var got struct {
+		Result struct {
+			Meta    map[string]any `json:"_meta"`
+			Content []any          `json:"content"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if len(got.Result.Content) != 3 {
+		t.Fatalf("unexpected content: %s", string(body))
+	}
+	checkExecutionMeta(t, got.Result.Meta, 3, true)
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// healthCheckTimeout bounds the health check of each source.
+const healthCheckTimeout = 5 * time.Second
+
+// Health statuses of the server and of its sources.
+const (
+	healthOK       = "ok"
+	healthDegraded = "degraded"
+	healthError    = "error"
+	healthUnknown  = "unknown"
+)
+
+// healthResponse is the document returned by the health endpoint.
+type healthResponse struct {
+	Status        string         `json:"status"`
+	Version       string         `json:"version"`
+	UptimeSeconds float64        `json:"uptimeSeconds"`
+	Sources       []sourceHealth `json:"sources"`
+	Tools         int            `json:"tools"`
+	Toolsets      int            `json:"toolsets"`
+}
+
+// sourceHealth is the health of a single source.
+type sourceHealth struct {
+	Name        string    `json:"name"`
+	Kind        string    `json:"kind"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	LastChecked time.Time `json:"lastChecked"`
+	// CircuitBreaker is the state of the circuit breaker of the source's
+	// tools, if any.
+	CircuitBreaker *breakerHealth `json:"circuitBreaker,omitempty"`
+}
+
+// healthHandler handles the request for the health of the server. The
+// sources are reported as last checked in the background, or checked
+// concurrently if they weren't yet, along with the state of their circuit
+// breakers. The overall status is degraded if any of them is unhealthy or has
+// an open circuit breaker.
+func healthHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	sourcesMap := s.sources
+	breakers := circuitBreakers(s.tools)
+	toolCount := len(s.tools)
+	toolsetCount := len(s.toolsets)
+	s.mu.RUnlock()
+
+	res := healthResponse{
+		Status:   healthOK,
+		Version:  s.version,
+		Sources:  make([]sourceHealth, 0, len(sourcesMap)),
+		Tools:    toolCount,
+		Toolsets: toolsetCount,
+	}
+	if !s.startTime.IsZero() {
+		res.UptimeSeconds = time.Since(s.startTime).Seconds()
+	}
+
+	unchecked := sourcesMap
+	if s.health != nil {
+		unchecked = make(map[string]sources.Source)
+		for name, src := range sourcesMap {
+			if h, ok := s.health.get(name); ok {
+				res.Sources = append(res.Sources, h)
+			} else {
+				unchecked[name] = src
+			}
+		}
+	}
+	res.Sources = append(res.Sources, checkSourcesHealth(r.Context(), unchecked)...)
+
+	sort.Slice(res.Sources, func(i, j int) bool { return res.Sources[i].Name < res.Sources[j].Name })
+	now := time.Now()
+	for i, h := range res.Sources {
+		if h.Status == healthError {
+			res.Status = healthDegraded
+		}
+		if b, ok := breakers[h.Name]; ok {
+			bh := b.health(now)
+			res.Sources[i].CircuitBreaker = &bh
+			if bh.State != breakerClosed {
+				res.Status = healthDegraded
+			}
+		}
+	}
+	render.JSON(w, r, res)
+}
+
+// checkSourcesHealth checks the health of the sources concurrently.
+func checkSourcesHealth(ctx context.Context, sourcesMap map[string]sources.Source) []sourceHealth {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	res := make([]sourceHealth, 0, len(sourcesMap))
+	for name, src := range sourcesMap {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h := checkSourceHealth(ctx, name, src)
+			mu.Lock()
+			defer mu.Unlock()
+			res = append(res, h)
+		}()
+	}
+	wg.Wait()
+	return res
+}
+
+// checkSourceHealth checks the health of the source with the given name.
+func checkSourceHealth(ctx context.Context, name string, src sources.Source) sourceHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	h := sourceHealth{Name: name, Kind: src.SourceKind(), Status: healthOK}
+	ok, err := sources.CheckHealth(ctx, src)
+	h.LastChecked = time.Now().UTC()
+	switch {
+	case !ok:
+		h.Status = healthUnknown
+	case err != nil:
+		h.Status = healthError
+		h.Error = err.Error()
+	}
+	return h
+}
+
+// healthCache holds the health of the sources as last checked in the
+// background. Its generation is incremented when the sources are reloaded, so
+// that checks of the previous sources are discarded.
+type healthCache struct {
+	mu      sync.Mutex
+	gen     int
+	results map[string]sourceHealth
+}
+
+func (c *healthCache) generation() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+// reset discards the results, and those of the checks in progress.
+func (c *healthCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.results = nil
+}
+
+// store replaces the results with the ones checked for the sources of gen.
+func (c *healthCache) store(gen int, checked []sourceHealth) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	c.results = make(map[string]sourceHealth, len(checked))
+	for _, h := range checked {
+		c.results[h.Name] = h
+	}
+}
+
+// get returns the last health of the source with the given name.
+func (c *healthCache) get(name string) (sourceHealth, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h, ok := c.results[name]
+	return h, ok
+}
+
+// checkHealthPeriodically checks the health of the sources every interval,
+// until the server shuts down.
+func (s *Server) checkHealthPeriodically(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.mu.RLock()
+		sourcesMap := s.sources
+		gen := s.health.generation()
+		s.mu.RUnlock()
+		s.health.store(gen, checkSourcesHealth(context.Background(), sourcesMap))
+
+		select {
+		case <-s.shutdown:
+			return
+		case <-ticker.C:
+		}
+	}
+}
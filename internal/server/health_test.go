@@ -0,0 +1,170 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/mysql"
+)
+
+// healthSource is a source whose health check returns err.
+type healthSource struct {
+	err error
+}
+
+func (s healthSource) SourceKind() string {
+	return "fake-health"
+}
+
+func (s healthSource) CheckHealth(context.Context) error {
+	return s.err
+}
+
+// plainSource is a source that can't be health checked.
+type plainSource struct{}
+
+func (plainSource) SourceKind() string {
+	return "fake-plain"
+}
+
+func TestHealth(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets, func(s *Server) {
+		s.startTime = time.Now().Add(-time.Minute)
+		s.sources = map[string]sources.Source{
+			"healthy":   healthSource{},
+			"unhealthy": healthSource{err: fmt.Errorf("connection refused")},
+			"unchecked": plainSource{},
+		}
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodGet, "/health", nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusOK, resp.StatusCode, string(body))
+	}
+	var got healthResponse
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unable to parse health response: %s", err)
+	}
+
+	if got.Version != fakeVersionString {
+		t.Errorf("unexpected version: want %q, got %q", fakeVersionString, got.Version)
+	}
+	if got.Status != healthDegraded {
+		t.Errorf("unexpected status: want %q, got %q", healthDegraded, got.Status)
+	}
+	if got.UptimeSeconds < 60 {
+		t.Errorf("unexpected uptime: want at least 60s, got %fs", got.UptimeSeconds)
+	}
+	if got.Tools != 2 || got.Toolsets != 3 {
+		t.Errorf("unexpected counts: want 2 tools and 3 toolsets, got %d and %d", got.Tools, got.Toolsets)
+	}
+
+	want := []struct {
+		name, kind, status, err string
+	}{
+		{"healthy", "fake-health", healthOK, ""},
+		{"unchecked", "fake-plain", healthUnknown, ""},
+		{"unhealthy", "fake-health", healthError, "connection refused"},
+	}
+	if len(got.Sources) != len(want) {
+		t.Fatalf("unexpected sources: want %d, got %+v", len(want), got.Sources)
+	}
+	for i, w := range want {
+		s := got.Sources[i]
+		if s.Name != w.name || s.Kind != w.kind || s.Status != w.status || s.Error != w.err {
+			t.Errorf("unexpected source health: want %+v, got %+v", w, s)
+		}
+		if s.LastChecked.IsZero() {
+			t.Errorf("source %q has no last checked time", s.Name)
+		}
+	}
+}
+
+func TestHealthUnreachableSource(t *testing.T) {
+	ctx := context.Background()
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	// nothing listens on port 1, so the source can't connect
+	cfg := ServerConfig{
+		Version:             fakeVersionString,
+		HealthCheckInterval: time.Hour,
+		SourceConfigs: SourceConfigs{
+			"unreachable": mysql.Config{
+				Name:     "unreachable",
+				Kind:     mysql.SourceKind,
+				Host:     "127.0.0.1",
+				Port:     "1",
+				Database: "my_db",
+				User:     "my_user",
+				Password: "my_pass",
+			},
+		},
+	}
+	s, err := NewServer(ctx, cfg, testLogger)
+	if err != nil {
+		t.Fatalf("expected the server to start with an unreachable source, got: %s", err)
+	}
+	defer func() { _ = s.Shutdown(ctx) }()
+
+	// wait for the first background check
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, ok := s.health.get("unreachable"); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the source was not checked in the background")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ts := runServer(s.root, false)
+	defer ts.Close()
+	resp, body, err := runRequest(ts, http.MethodGet, "/api/health", nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusOK, resp.StatusCode, string(body))
+	}
+	var got healthResponse
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unable to parse health response: %s", err)
+	}
+	if got.Status != healthDegraded {
+		t.Errorf("unexpected status: want %q, got %q", healthDegraded, got.Status)
+	}
+	if len(got.Sources) != 1 || got.Sources[0].Status != healthError || got.Sources[0].Error == "" {
+		t.Fatalf("expected the source to be unhealthy, got %+v", got.Sources)
+	}
+}
@@ -15,9 +15,12 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -26,22 +29,32 @@ const (
 	TracerName = "github.com/googleapis/genai-toolbox/internal/opentel"
 	MetricName = "github.com/googleapis/genai-toolbox/internal/opentel"
 
-	toolsetGetCountName = "toolbox.server.toolset.get.count"
-	toolGetCountName    = "toolbox.server.tool.get.count"
-	toolInvokeCountName = "toolbox.server.tool.invoke.count"
-	mcpSseCountName     = "toolbox.server.mcp.sse.count"
-	mcpPostCountName    = "toolbox.server.mcp.post.count"
+	toolsetGetCountName      = "toolbox.server.toolset.get.count"
+	toolGetCountName         = "toolbox.server.tool.get.count"
+	toolsListCountName       = "toolbox.server.tools.list.count"
+	toolInvokeCountName      = "toolbox.server.tool.invoke.count"
+	toolInvokeErrorCountName = "toolbox.server.tool.invoke.error.count"
+	toolInvokeDurationName   = "toolbox.server.tool.invoke.duration"
+	toolRowsExceededName     = "toolbox.server.tool.rows.exceeded.count"
+	mcpSseCountName          = "toolbox.server.mcp.sse.count"
+	mcpPostCountName         = "toolbox.server.mcp.post.count"
+	mcpSseSessionsName       = "toolbox.server.mcp.sse.sessions"
 )
 
 // Instrumentation defines the telemetry instrumentation for toolbox
 type Instrumentation struct {
-	Tracer     trace.Tracer
-	meter      metric.Meter
-	ToolsetGet metric.Int64Counter
-	ToolGet    metric.Int64Counter
-	ToolInvoke metric.Int64Counter
-	McpSse     metric.Int64Counter
-	McpPost    metric.Int64Counter
+	Tracer             trace.Tracer
+	meter              metric.Meter
+	ToolsetGet         metric.Int64Counter
+	ToolGet            metric.Int64Counter
+	ToolsList          metric.Int64Counter
+	ToolInvoke         metric.Int64Counter
+	ToolInvokeErrors   metric.Int64Counter
+	ToolInvokeDuration metric.Float64Histogram
+	ToolRowsExceeded   metric.Int64Counter
+	McpSse             metric.Int64Counter
+	McpPost            metric.Int64Counter
+	McpSseSessions     metric.Int64UpDownCounter
 }
 
 func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, error) {
@@ -69,6 +82,15 @@ func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, err
 		return nil, fmt.Errorf("unable to create %s metric: %w", toolGetCountName, err)
 	}
 
+	toolsList, err := meter.Int64Counter(
+		toolsListCountName,
+		metric.WithDescription("Number of tools list API calls."),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", toolsListCountName, err)
+	}
+
 	toolInvoke, err := meter.Int64Counter(
 		toolInvokeCountName,
 		metric.WithDescription("Number of tool Invoke API calls."),
@@ -78,6 +100,33 @@ func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, err
 		return nil, fmt.Errorf("unable to create %s metric: %w", toolInvokeCountName, err)
 	}
 
+	toolInvokeErrors, err := meter.Int64Counter(
+		toolInvokeErrorCountName,
+		metric.WithDescription("Number of tool invocations that returned an error."),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", toolInvokeErrorCountName, err)
+	}
+
+	toolInvokeDuration, err := meter.Float64Histogram(
+		toolInvokeDurationName,
+		metric.WithDescription("Duration of tool invocations."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", toolInvokeDurationName, err)
+	}
+
+	toolRowsExceeded, err := meter.Int64Counter(
+		toolRowsExceededName,
+		metric.WithDescription("Number of tool invocations that returned more rows than expected."),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", toolRowsExceededName, err)
+	}
+
 	mcpSse, err := meter.Int64Counter(
 		mcpSseCountName,
 		metric.WithDescription("Number of MCP SSE connection requests."),
@@ -96,14 +145,51 @@ func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, err
 		return nil, fmt.Errorf("unable to create %s metric: %w", mcpPostCountName, err)
 	}
 
+	mcpSseSessions, err := meter.Int64UpDownCounter(
+		mcpSseSessionsName,
+		metric.WithDescription("Number of currently open MCP SSE sessions."),
+		metric.WithUnit("{session}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", mcpSseSessionsName, err)
+	}
+
 	instrumentation := &Instrumentation{
-		Tracer:     tracer,
-		meter:      meter,
-		ToolsetGet: toolsetGet,
-		ToolGet:    toolGet,
-		ToolInvoke: toolInvoke,
-		McpSse:     mcpSse,
-		McpPost:    mcpPost,
+		Tracer:             tracer,
+		meter:              meter,
+		ToolsetGet:         toolsetGet,
+		ToolGet:            toolGet,
+		ToolsList:          toolsList,
+		ToolInvoke:         toolInvoke,
+		ToolInvokeErrors:   toolInvokeErrors,
+		ToolInvokeDuration: toolInvokeDuration,
+		ToolRowsExceeded:   toolRowsExceeded,
+		McpSse:             mcpSse,
+		McpPost:            mcpPost,
+		McpSseSessions:     mcpSseSessions,
 	}
 	return instrumentation, nil
 }
+
+// recordToolInvoke records the count, error count and duration of a single
+// tool invocation that started at start.
+func (i *Instrumentation) recordToolInvoke(ctx context.Context, toolName string, start time.Time, failed bool) {
+	status := "success"
+	if failed {
+		status = "error"
+	}
+	i.ToolInvoke.Add(
+		ctx,
+		1,
+		metric.WithAttributes(attribute.String("toolbox.name", toolName)),
+		metric.WithAttributes(attribute.String("toolbox.operation.status", status)),
+	)
+	if failed {
+		i.ToolInvokeErrors.Add(ctx, 1, metric.WithAttributes(attribute.String("toolbox.name", toolName)))
+	}
+	i.ToolInvokeDuration.Record(
+		ctx,
+		time.Since(start).Seconds(),
+		metric.WithAttributes(attribute.String("toolbox.name", toolName)),
+	)
+}
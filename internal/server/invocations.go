@@ -0,0 +1,156 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// invocationIDHeader carries the ID that an invocation can be canceled by.
+// Clients may choose the ID by sending it, and it is generated otherwise. IDs
+// are scoped to the caller, so that an invocation can only be canceled, or
+// collide with another, by the caller that started it.
+const invocationIDHeader = "Toolbox-Invocation-Id"
+
+// earlyInvocationIDHeader is sent with the value "true" by clients that want
+// the generated invocation ID in a 103 Early Hints response as soon as the
+// invocation starts. It is opt-in, since some clients and proxies don't handle
+// informational responses other than 100 Continue.
+const earlyInvocationIDHeader = "Toolbox-Early-Invocation-Id"
+
+// statusClientClosedRequest is the status of the responses to canceled
+// invocations. It isn't a standard HTTP status, but is commonly used for
+// requests canceled by the client.
+const statusClientClosedRequest = 499
+
+// codeInvocationCanceled is the error code used when an invocation is
+// canceled by its ID.
+const codeInvocationCanceled = "INVOCATION_CANCELED"
+
+// errInvocationCanceled is the cause of the invocations canceled by their ID.
+var errInvocationCanceled = errors.New("invocation was canceled")
+
+// invocationKey identifies an invocation by its ID among the invocations of
+// its caller.
+type invocationKey struct {
+	// caller identifies the caller by the subjects of its verified auth
+	// services
+	caller string
+	id     string
+}
+
+// newInvocationKey returns the key of the invocation with the given ID by the
+// caller with the given claims, keyed by auth service name.
+func newInvocationKey(claims map[string]map[string]any, id string) (invocationKey, error) {
+	caller, err := json.Marshal(claimSubjects(claims))
+	if err != nil {
+		return invocationKey{}, fmt.Errorf("unable to identify caller: %w", err)
+	}
+	return invocationKey{caller: string(caller), id: id}, nil
+}
+
+// invocations are the in-flight invocations, by their key.
+type invocations struct {
+	mu      sync.Mutex
+	cancels map[invocationKey]context.CancelCauseFunc
+}
+
+// start registers an invocation with the given key. It returns the context of
+// the invocation, which is canceled when the invocation is, and a function
+// that unregisters it once it is done.
+func (i *invocations) start(ctx context.Context, key invocationKey) (context.Context, func(), error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, ok := i.cancels[key]; ok {
+		return nil, nil, fmt.Errorf("invocation %q is already in progress", key.id)
+	}
+	if i.cancels == nil {
+		i.cancels = make(map[invocationKey]context.CancelCauseFunc)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	i.cancels[key] = cancel
+	done := func() {
+		i.mu.Lock()
+		defer i.mu.Unlock()
+		delete(i.cancels, key)
+		cancel(nil)
+	}
+	return ctx, done, nil
+}
+
+// cancel cancels the invocation with the given key, and reports whether it
+// was in progress.
+func (i *invocations) cancel(key invocationKey) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	cancel, ok := i.cancels[key]
+	if ok {
+		cancel(errInvocationCanceled)
+	}
+	return ok
+}
+
+// canceledError returns errInvocationCanceled if the invocation of ctx
+// failed with err because it was canceled by its ID, and err otherwise.
+func canceledError(ctx context.Context, err error) error {
+	if errors.Is(context.Cause(ctx), errInvocationCanceled) {
+		return errInvocationCanceled
+	}
+	return err
+}
+
+// invocationID returns the ID sent by the client in r, or a new one.
+func invocationID(r *http.Request) (string, error) {
+	id := r.Header.Get(invocationIDHeader)
+	if id == "" {
+		return uuid.New().String(), nil
+	}
+	if len(id) > 128 || !tools.IsValidName(id) {
+		return "", fmt.Errorf("invalid %s %q: IDs may only contain letters, numbers, underscores and hyphens, and be up to 128 characters long", invocationIDHeader, id)
+	}
+	return id, nil
+}
+
+// invocationCancelHandler handles the requests to cancel an invocation. Only
+// the invocations started by the same caller, as identified by the auth
+// tokens of the request, can be canceled.
+func invocationCancelHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "invocationId")
+	claims, _ := s.authenticate(r.Context(), r.Header)
+	key, err := newInvocationKey(claims, id)
+	if err != nil {
+		s.logger.DebugContext(r.Context(), err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	if !s.invocations.cancel(key) {
+		err := fmt.Errorf("invocation %q is not in progress", id)
+		s.logger.DebugContext(r.Context(), err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+	s.logger.InfoContext(r.Context(), fmt.Sprintf("canceled invocation %q", id))
+	render.JSON(w, r, map[string]any{"invocation": id, "canceled": true})
+}
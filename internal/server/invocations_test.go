@@ -0,0 +1,332 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// blockingTool is a MockTool whose invocations run until they are canceled.
+type blockingTool struct {
+	MockTool
+	started chan struct{}
+	stopped chan error
+}
+
+func (t blockingTool) Invoke(ctx context.Context, _ tools.ParamValues) ([]any, error) {
+	close(t.started)
+	<-ctx.Done()
+	t.stopped <- ctx.Err()
+	return nil, fmt.Errorf("unable to execute query: %w", ctx.Err())
+}
+
+func TestCancelInvocation(t *testing.T) {
+	tool := blockingTool{
+		MockTool: MockTool{Name: "slow"},
+		started:  make(chan struct{}),
+		stopped:  make(chan error, 1),
+	}
+	r, shutdown := setUpServer(t, "api", map[string]tools.Tool{"slow": tool}, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	type result struct {
+		resp *http.Response
+		body []byte
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/tool/slow/invoke", bytes.NewBufferString(`{}`))
+		if err != nil {
+			results <- result{err: err}
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(invocationIDHeader, "slow-query-1")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			results <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		var body bytes.Buffer
+		_, err = body.ReadFrom(resp.Body)
+		results <- result{resp: resp, body: body.Bytes(), err: err}
+	}()
+
+	select {
+	case <-tool.started:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the invocation didn't start")
+	}
+
+	// invocations that aren't in progress can't be canceled
+	resp, body, err := runRequest(ts, http.MethodPost, "/invocations/unknown/cancel", nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusNotFound, resp.StatusCode, body)
+	}
+
+	resp, body, err = runRequest(ts, http.MethodPost, "/invocations/slow-query-1/cancel", nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusOK, resp.StatusCode, body)
+	}
+
+	select {
+	case err := <-tool.stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("unexpected error of the query: want %v, got %v", context.Canceled, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the query wasn't canceled")
+	}
+
+	res := <-results
+	if res.err != nil {
+		t.Fatalf("unexpected error during request: %s", res.err)
+	}
+	if res.resp.StatusCode != statusClientClosedRequest {
+		t.Fatalf("unexpected status code: want %d, got %d: %s", statusClientClosedRequest, res.resp.StatusCode, res.body)
+	}
+	if got := res.resp.Header.Get(invocationIDHeader); got != "slow-query-1" {
+		t.Fatalf("unexpected invocation ID: want %q, got %q", "slow-query-1", got)
+	}
+	var got errResponse
+	if err := json.Unmarshal(res.body, &got); err != nil {
+		t.Fatalf("unable to parse response body: %s", err)
+	}
+	if got.Code != codeInvocationCanceled {
+		t.Fatalf("unexpected code: want %q, got %q", codeInvocationCanceled, got.Code)
+	}
+
+	// the invocation is no longer in progress
+	resp, body, err = runRequest(ts, http.MethodPost, "/invocations/slow-query-1/cancel", nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusNotFound, resp.StatusCode, body)
+	}
+}
+
+func TestInvocationID(t *testing.T) {
+	r, shutdown := setUpServer(t, "api", map[string]tools.Tool{"tool": MockTool{Name: "tool"}}, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, "/tool/tool/invoke", bytes.NewBufferString(`{}`))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, body)
+	}
+	if resp.Header.Get(invocationIDHeader) == "" {
+		t.Fatalf("expected an invocation ID to be generated")
+	}
+
+	// informational responses are only sent to clients that ask for them
+	var informational []int
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, _ textproto.MIMEHeader) error {
+			informational = append(informational, code)
+			return nil
+		},
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodPost, ts.URL+"/tool/tool/invoke", bytes.NewBufferString(`{}`))
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	resp.Body.Close()
+	if len(informational) != 0 {
+		t.Fatalf("unexpected informational responses without opting in: %v", informational)
+	}
+
+	req, err = http.NewRequest(http.MethodPost, ts.URL+"/tool/tool/invoke", bytes.NewBufferString(`{}`))
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(invocationIDHeader, "not/valid")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code for an invalid ID: want %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
+// subjectAuthService is an auth service whose token is the subject of the
+// caller.
+type subjectAuthService struct{}
+
+func (subjectAuthService) AuthServiceKind() string { return "subject" }
+
+func (subjectAuthService) GetName() string { return "my-subject-auth" }
+
+func (subjectAuthService) GetClaimsFromHeader(_ context.Context, h http.Header) (map[string]any, error) {
+	sub := auth.TokenFromHeader(h, "my-subject-auth")
+	if sub == "" {
+		return nil, nil
+	}
+	return map[string]any{"sub": sub}, nil
+}
+
+func TestCancelInvocationByCaller(t *testing.T) {
+	tool := blockingTool{
+		MockTool: MockTool{Name: "slow"},
+		started:  make(chan struct{}),
+		stopped:  make(chan error, 1),
+	}
+	r, shutdown := setUpServer(t, "api", map[string]tools.Tool{"slow": tool}, nil, func(s *Server) {
+		s.authServices = map[string]auth.AuthService{"my-subject-auth": subjectAuthService{}}
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	// the generated ID is received before the result when requested
+	ids := make(chan string, 1)
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			ids <- header.Get(invocationIDHeader)
+			return nil
+		},
+	}
+	results := make(chan *http.Response, 1)
+	go func() {
+		req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodPost, ts.URL+"/tool/slow/invoke", bytes.NewBufferString(`{}`))
+		if err != nil {
+			results <- nil
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("my-subject-auth_token", "alice")
+		req.Header.Set(earlyInvocationIDHeader, "true")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			results <- nil
+			return
+		}
+		resp.Body.Close()
+		results <- resp
+	}()
+
+	var id string
+	select {
+	case id = <-ids:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the invocation ID wasn't received")
+	}
+	<-tool.started
+
+	cancel := func(sub string) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/invocations/"+id+"/cancel", nil)
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if sub != "" {
+			req.Header.Set("my-subject-auth_token", sub)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// other callers can't cancel the invocation
+	for _, sub := range []string{"bob", ""} {
+		if status := cancel(sub); status != http.StatusNotFound {
+			t.Fatalf("unexpected status code canceling as %q: want %d, got %d", sub, http.StatusNotFound, status)
+		}
+	}
+	if status := cancel("alice"); status != http.StatusOK {
+		t.Fatalf("unexpected status code canceling as the caller: want %d, got %d", http.StatusOK, status)
+	}
+
+	select {
+	case resp := <-results:
+		if resp == nil {
+			t.Fatalf("the invocation request failed")
+		}
+		if resp.StatusCode != statusClientClosedRequest {
+			t.Fatalf("unexpected status code: want %d, got %d", statusClientClosedRequest, resp.StatusCode)
+		}
+		if got := resp.Header.Get(invocationIDHeader); got != id {
+			t.Fatalf("unexpected invocation ID: want %q, got %q", id, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the invocation wasn't canceled")
+	}
+}
+
+func TestInvocationIDScope(t *testing.T) {
+	var i invocations
+	ctx := context.Background()
+	alice, err := newInvocationKey(map[string]map[string]any{"my-auth": {"sub": "alice"}}, "report")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	bob, err := newInvocationKey(map[string]map[string]any{"my-auth": {"sub": "bob"}}, "report")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, done, err := i.start(ctx, alice)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer done()
+	// the same ID can be used by another caller, but not by the same one
+	_, doneBob, err := i.start(ctx, bob)
+	if err != nil {
+		t.Fatalf("unexpected error starting the invocation of another caller: %s", err)
+	}
+	defer doneBob()
+	if _, _, err := i.start(ctx, alice); err == nil {
+		t.Fatalf("expected an error starting an invocation with an ID in progress")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+var (
+	errInvocationQueueFull    = errors.New("server is at capacity: invocation queue is full")
+	errInvocationQueueTimeout = errors.New("server is at capacity: timed out waiting for an invocation slot")
+)
+
+// invocationLimiter bounds the number of tool invocations that run
+// concurrently. Invocations that arrive while all slots are in use wait in a
+// bounded FIFO queue for up to queueTimeout before being rejected.
+type invocationLimiter struct {
+	// slots holds a token for each running invocation
+	slots chan struct{}
+	// queue holds a token for each invocation waiting for a slot
+	queue        chan struct{}
+	queueTimeout time.Duration
+}
+
+// newInvocationLimiter returns an invocationLimiter. A nil limiter, which
+// never blocks, is returned if maxConcurrent is not positive.
+func newInvocationLimiter(maxConcurrent, queueDepth int, queueTimeout time.Duration) *invocationLimiter {
+	if maxConcurrent <= 0 {
+		return nil
+	}
+	return &invocationLimiter{
+		slots:        make(chan struct{}, maxConcurrent),
+		queue:        make(chan struct{}, max(queueDepth, 0)),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// acquire blocks until an invocation slot is available. On success, the
+// returned func must be called to release the slot.
+func (l *invocationLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	release := func() { <-l.slots }
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	// all slots are in use, wait in the queue if there is room
+	select {
+	case l.queue <- struct{}{}:
+	default:
+		return nil, errInvocationQueueFull
+	}
+	defer func() { <-l.queue }()
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+	// blocked senders on a channel are woken in the order they arrived
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, errInvocationQueueTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// limitInvocations is a middleware that holds an invocation slot for the
+// duration of the request, responding with a 503 if one can't be acquired.
+func limitInvocations(s *Server) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			release, err := s.invocationLimiter.acquire(r.Context())
+			if err != nil {
+				s.logger.DebugContext(r.Context(), err.Error())
+				_ = render.Render(w, r, newErrResponse(err, http.StatusServiceUnavailable))
+				return
+			}
+			defer release()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
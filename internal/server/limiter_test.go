@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInvocationLimiter(t *testing.T) {
+	ctx := context.Background()
+	l := newInvocationLimiter(1, 1, 100*time.Millisecond)
+
+	// saturate the only worker slot
+	release, err := l.acquire(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// the next invocation waits in the queue
+	queued := make(chan error)
+	go func() {
+		r, err := l.acquire(ctx)
+		if err == nil {
+			defer r()
+		}
+		queued <- err
+	}()
+	for len(l.queue) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the queue is full, so excess invocations are rejected right away
+	if _, err := l.acquire(ctx); !errors.Is(err, errInvocationQueueFull) {
+		t.Fatalf("unexpected error: want %q, got %v", errInvocationQueueFull, err)
+	}
+
+	// freeing the slot lets the queued invocation proceed
+	release()
+	if err := <-queued; err != nil {
+		t.Fatalf("queued invocation failed: %s", err)
+	}
+
+	// once the queue has room again, waiting invocations are rejected after the timeout
+	release, err = l.acquire(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer release()
+	start := time.Now()
+	if _, err := l.acquire(ctx); !errors.Is(err, errInvocationQueueTimeout) {
+		t.Fatalf("unexpected error: want %q, got %v", errInvocationQueueTimeout, err)
+	}
+	if elapsed := time.Since(start); elapsed < l.queueTimeout {
+		t.Fatalf("invocation was rejected before the timeout: %s", elapsed)
+	}
+}
+
+func TestNilInvocationLimiter(t *testing.T) {
+	l := newInvocationLimiter(0, 0, 0)
+	if l != nil {
+		t.Fatalf("expected a nil limiter when concurrency is unbounded")
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := l.acquire(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+)
+
+func TestListenInheritedListener(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// pre-open a listener and pass a duplicate of its fd as if it was
+	// inherited from the service manager
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to open listener: %s", err)
+	}
+	defer l.Close()
+	f, err := l.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("unable to get listener file: %s", err)
+	}
+	defer f.Close()
+
+	oldStart := listenFdsStart
+	listenFdsStart = int(f.Fd())
+	defer func() { listenFdsStart = oldStart }()
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	t.Setenv("LISTEN_FDS", "1")
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	// the configured port must be ignored
+	cfg := ServerConfig{Version: fakeVersionString, Address: "127.0.0.1", Port: 1}
+	s, err := NewServer(ctx, cfg, testLogger)
+	if err != nil {
+		t.Fatalf("unable to initialize server: %s", err)
+	}
+	if err := s.Listen(ctx); err != nil {
+		t.Fatalf("unable to start server: %s", err)
+	}
+	if got, want := s.listener.Addr().String(), l.Addr().String(); got != want {
+		t.Fatalf("unexpected listener address: got %q, want %q", got, want)
+	}
+	if _, ok := os.LookupEnv("LISTEN_FDS"); ok {
+		t.Fatalf("LISTEN_FDS should be unset after adopting the listener")
+	}
+
+	go func() {
+		_ = s.Serve(ctx)
+	}()
+	defer s.Shutdown(ctx)
+
+	resp, err := http.Get("http://" + l.Addr().String() + "/")
+	if err != nil {
+		t.Fatalf("error when sending a request: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestInheritedListenerOtherProcess(t *testing.T) {
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	t.Setenv("LISTEN_FDS", "1")
+
+	l, err := inheritedListener()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if l != nil {
+		t.Fatalf("expected no listener for another process")
+	}
+}
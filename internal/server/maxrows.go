@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// truncatedMeta is the result metadata set when rows were dropped from the
+// result of an invocation.
+const truncatedMeta = "truncated"
+
+// errMaxRowsReached stops a stream once the row after the last one returned
+// was seen.
+var errMaxRowsReached = errors.New("maximum number of rows reached")
+
+// validate interface
+var _ tools.Tool = maxRowsTool{}
+
+// maxRowsTool is a Tool whose results are truncated to at most maxRows rows.
+// Truncated results are flagged in the "truncated" metadata of the result.
+type maxRowsTool struct {
+	tools.Tool
+	maxRows int
+}
+
+func (t maxRowsTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t maxRowsTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	res, err := t.Tool.Invoke(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) > t.maxRows {
+		res = res[:t.maxRows]
+		tools.SetMeta(ctx, truncatedMeta, true)
+	}
+	return res, nil
+}
+
+// Stream returns a Streamer that stops after maxRows rows.
+func (t maxRowsTool) Stream() (tools.Streamer, bool) {
+	s, ok := tools.GetStreamer(t.Tool)
+	if !ok {
+		return nil, false
+	}
+	return tools.StreamerFunc(func(ctx context.Context, params tools.ParamValues, yield func(row any) error) error {
+		seen := 0
+		err := s.InvokeStream(ctx, params, func(row any) error {
+			if seen == t.maxRows {
+				tools.SetMeta(ctx, truncatedMeta, true)
+				return errMaxRowsReached
+			}
+			seen++
+			return yield(row)
+		})
+		if errors.Is(err, errMaxRowsReached) {
+			return nil
+		}
+		return err
+	}), true
+}
+
+// withMaxRows wraps the tools that declare maxRows.
+func withMaxRows(toolsMap map[string]tools.Tool) map[string]tools.Tool {
+	for name, t := range toolsMap {
+		maxRows := tools.GetOptions(t).MaxRows
+		if maxRows <= 0 {
+			continue
+		}
+		toolsMap[name] = maxRowsTool{Tool: t, maxRows: maxRows}
+	}
+	return toolsMap
+}
+
+// resultTruncated returns whether rows were dropped from a result, either by
+// a preview or because the tool returned more than its maxRows.
+func resultTruncated(meta map[string]any, preview *previewTool) bool {
+	return meta[truncatedMeta] == true || (preview != nil && preview.truncated)
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// maxRowsTools returns tools returning 5 rows, with the given maxRows.
+func maxRowsTools(maxRows ...int) map[string]tools.Tool {
+	toolsMap := map[string]tools.Tool{}
+	for _, n := range maxRows {
+		name := fmt.Sprintf("max_%d", n)
+		toolsMap[name] = tools.ToolWithOptions{
+			Tool:    rowsTool{MockTool: MockTool{Name: name}, rows: 5},
+			Options: tools.Options{MaxRows: n},
+		}
+	}
+	return withMaxRows(toolsMap)
+}
+
+func TestToolInvokeMaxRows(t *testing.T) {
+	r, shutdown := setUpServer(t, "api", maxRowsTools(2, 5), nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name          string
+		tool          string
+		wantRows      int
+		wantTruncated bool
+	}{
+		{name: "truncated", tool: "max_2", wantRows: 2, wantTruncated: true},
+		{name: "not truncated", tool: "max_5", wantRows: 5},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, "/tool/"+tc.tool+"/invoke", bytes.NewBufferString(`{}`))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(body))
+			}
+			var got resultResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			var rows []any
+			if err := json.Unmarshal([]byte(got.Result), &rows); err != nil {
+				t.Fatalf("unable to parse result: %s", err)
+			}
+			if len(rows) != tc.wantRows {
+				t.Fatalf("unexpected number of rows: want %d, got %d", tc.wantRows, len(rows))
+			}
+			if got.Truncated != tc.wantTruncated {
+				t.Fatalf("unexpected truncated: want %t, got %t", tc.wantTruncated, got.Truncated)
+			}
+		})
+	}
+}
+
+func TestMcpToolCallMaxRows(t *testing.T) {
+	r, shutdown := setUpServer(t, "mcp", maxRowsTools(2, 5), nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name          string
+		tool          string
+		wantRows      int
+		wantTruncated bool
+	}{
+		{name: "truncated", tool: "max_2", wantRows: 2, wantTruncated: true},
+		{name: "not truncated", tool: "max_5", wantRows: 5},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "max-rows-call",
+				Request: mcp.Request{
+					Method: "tools/call",
+				},
+				Params: map[string]any{"name": tc.tool, "arguments": map[string]any{}},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got struct {
+				Result struct {
+					Meta    map[string]any `json:"_meta"`
+					Content []any          `json:"content"`
+				} `json:"result"`
+			}
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if len(got.Result.Content) != tc.wantRows {
+				t.Fatalf("unexpected content: %s", string(body))
+			}
+			if truncated := got.Result.Meta["truncated"] == true; truncated != tc.wantTruncated {
+				t.Fatalf("unexpected truncated: want %t, got %v", tc.wantTruncated, got.Result.Meta)
+			}
+		})
+	}
+}
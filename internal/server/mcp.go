@@ -19,16 +19,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"github.com/google/uuid"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -41,12 +46,25 @@ type sseSession struct {
 	flusher    http.Flusher
 	done       chan struct{}
 	eventQueue chan string
+	// shutdown is closed to ask the session to drain its events and close.
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+	// closed is closed once the session's handler has returned.
+	closed chan struct{}
+
+	// mu guards subscriptions, which maps the uris of the resources the
+	// session is subscribed to, to their subscription.
+	mu            sync.Mutex
+	subscriptions map[string]subscription
 }
 
 // sseManager manages and control access to sse sessions
 type sseManager struct {
 	mu          sync.RWMutex
 	sseSessions map[string]*sseSession
+	// maxSSESessions caps the number of concurrent sse sessions. A value of 0
+	// means the number of sessions is unbounded.
+	maxSSESessions int
 }
 
 func (m *sseManager) get(id string) (*sseSession, bool) {
@@ -56,10 +74,16 @@ func (m *sseManager) get(id string) (*sseSession, bool) {
 	return session, ok
 }
 
-func (m *sseManager) add(id string, session *sseSession) {
+// add registers a new session. It returns false if the session could not be
+// added because the maximum number of sessions has been reached.
+func (m *sseManager) add(id string, session *sseSession) bool {
 	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.maxSSESessions > 0 && len(m.sseSessions) >= m.maxSSESessions {
+		return false
+	}
 	m.sseSessions[id] = session
-	m.mu.Unlock()
+	return true
 }
 
 func (m *sseManager) remove(id string) {
@@ -68,17 +92,54 @@ func (m *sseManager) remove(id string) {
 	m.mu.Unlock()
 }
 
+// list returns the open sessions.
+func (m *sseManager) list() []*sseSession {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sessions := make([]*sseSession, 0, len(m.sseSessions))
+	for _, session := range m.sseSessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// shutdown asks every open session to close, and waits for them to do so until
+// ctx is done. It returns an error for each session that has not closed in time.
+func (m *sseManager) shutdown(ctx context.Context) error {
+	sessions := m.list()
+	for _, session := range sessions {
+		session.shutdownOnce.Do(func() { close(session.shutdown) })
+	}
+	var errs []error
+	for _, session := range sessions {
+		select {
+		case <-session.closed:
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("sse session %q did not close in time: %w", session.sessionId, ctx.Err()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 type stdioSession struct {
-	server *Server
-	reader *bufio.Reader
-	writer io.Writer
+	server  *Server
+	reader  *bufio.Reader
+	writer  io.Writer
+	framing stdioFraming
+	// shutdown is closed when the server shuts down, after which no more
+	// messages are read.
+	shutdown <-chan struct{}
 }
 
+// NewStdioSession returns a stdio session that delimits messages as set by the
+// server's stdio framing.
 func NewStdioSession(s *Server, stdin io.Reader, stdout io.Writer) *stdioSession {
 	stdioSession := &stdioSession{
-		server: s,
-		reader: bufio.NewReader(stdin),
-		writer: stdout,
+		server:   s,
+		reader:   bufio.NewReader(stdin),
+		writer:   stdout,
+		framing:  s.stdioFraming,
+		shutdown: s.shutdown,
 	}
 	return stdioSession
 }
@@ -93,14 +154,20 @@ func (s *stdioSession) readInputStream(ctx context.Context) error {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
-		line, err := s.readLine(ctx)
+		var msg string
+		var err error
+		if s.framing.String() == stdioFramingContentLength {
+			msg, err = s.readFramed(ctx)
+		} else {
+			msg, err = s.readLine(ctx)
+		}
 		if err != nil {
 			if err == io.EOF {
 				return nil
 			}
 			return err
 		}
-		res, err := processMcpMessage(ctx, []byte(line), s.server, "")
+		res, err := processMcpPayload(ctx, []byte(msg), s.server, "", "")
 		if err != nil {
 			// errors during the processing of message will generate a valid MCP Error response.
 			// server can continue to run.
@@ -118,6 +185,52 @@ func (s *stdioSession) readInputStream(ctx context.Context) error {
 
 // readLine process each line within the input stream.
 func (s *stdioSession) readLine(ctx context.Context) (string, error) {
+	return s.read(ctx, func() (string, error) {
+		return s.reader.ReadString('\n')
+	})
+}
+
+// readFramed reads a message prefixed with a Content-Length header.
+func (s *stdioSession) readFramed(ctx context.Context) (string, error) {
+	return s.read(ctx, s.readContentLengthMessage)
+}
+
+// readContentLengthMessage reads the headers of a message, followed by as
+// many bytes as set by its Content-Length header.
+func (s *stdioSession) readContentLengthMessage() (string, error) {
+	length := -1
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return "", fmt.Errorf("invalid header %q", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || length < 0 {
+				return "", fmt.Errorf("invalid Content-Length header %q", line)
+			}
+		}
+	}
+	if length < 0 {
+		return "", fmt.Errorf("message is missing a Content-Length header")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.reader, body); err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// read runs readFn until it returns, or ctx is done.
+func (s *stdioSession) read(ctx context.Context, readFn func() (string, error)) (string, error) {
 	readChan := make(chan string, 1)
 	errChan := make(chan error, 1)
 	done := make(chan struct{})
@@ -130,7 +243,7 @@ func (s *stdioSession) readLine(ctx context.Context) (string, error) {
 		case <-done:
 			return
 		default:
-			line, err := s.reader.ReadString('\n')
+			line, err := readFn()
 			if err != nil {
 				select {
 				case errChan <- err:
@@ -150,6 +263,9 @@ func (s *stdioSession) readLine(ctx context.Context) (string, error) {
 	// if context is cancelled, return an empty string
 	case <-ctx.Done():
 		return "", ctx.Err()
+	// if the server is shutting down, end the session as if the input ended
+	case <-s.shutdown:
+		return "", io.EOF
 	// return error if error is found
 	case err := <-errChan:
 		return "", err
@@ -163,6 +279,10 @@ func (s *stdioSession) readLine(ctx context.Context) (string, error) {
 func (s *stdioSession) write(ctx context.Context, response any) error {
 	res, _ := json.Marshal(response)
 
+	if s.framing.String() == stdioFramingContentLength {
+		_, err := fmt.Fprintf(s.writer, "Content-Length: %d\r\n\r\n%s", len(res), res)
+		return err
+	}
 	_, err := fmt.Fprintf(s.writer, "%s\n", res)
 	return err
 }
@@ -173,7 +293,9 @@ func mcpRouter(s *Server) (chi.Router, error) {
 
 	r.Use(middleware.AllowContentType("application/json"))
 	r.Use(middleware.StripSlashes)
+	r.Use(compressResponses(s))
 	r.Use(render.SetContentType(render.ContentTypeJSON))
+	r.Use(limitRate(s))
 
 	r.Get("/sse", func(w http.ResponseWriter, r *http.Request) { sseHandler(s, w, r) })
 	r.Post("/", func(w http.ResponseWriter, r *http.Request) { httpHandler(s, w, r) })
@@ -186,6 +308,10 @@ func mcpRouter(s *Server) (chi.Router, error) {
 	return r, nil
 }
 
+// sseShutdownEvent is the last event sent on sse sessions closed by a server
+// shutdown, so that clients know to reconnect.
+const sseShutdownEvent = "event: shutdown\ndata: reconnect\n\n"
+
 // sseHandler handles sse initialization and message.
 func sseHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/mcp/sse")
@@ -197,11 +323,6 @@ func sseHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	span.SetAttributes(attribute.String("session_id", sessionId))
 	span.SetAttributes(attribute.String("toolset_name", toolsetName))
 
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-
 	var err error
 	defer func() {
 		if err != nil {
@@ -233,9 +354,25 @@ func sseHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		flusher:    flusher,
 		done:       make(chan struct{}),
 		eventQueue: make(chan string, 100),
+		shutdown:   make(chan struct{}),
+		closed:     make(chan struct{}),
 	}
-	s.sseManager.add(sessionId, session)
-	defer s.sseManager.remove(sessionId)
+	if !s.sseManager.add(sessionId, session) {
+		err = fmt.Errorf("maximum number of sse sessions reached")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusServiceUnavailable))
+		return
+	}
+	s.instrumentation.McpSseSessions.Add(ctx, 1)
+	defer func() {
+		s.sseManager.remove(sessionId)
+		s.instrumentation.McpSseSessions.Add(context.Background(), -1)
+		close(session.closed)
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
 
 	// https scheme formatting if (forwarded) request is a TLS request
 	proto := r.Header.Get("X-Forwarded-Proto")
@@ -270,6 +407,22 @@ func sseHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 			close(session.done)
 			s.logger.DebugContext(ctx, "client disconnected")
 			return
+			// server is shutting down, flush pending events and ask the
+			// client to reconnect
+		case <-session.shutdown:
+			for drained := false; !drained; {
+				select {
+				case event := <-session.eventQueue:
+					fmt.Fprint(w, event)
+				default:
+					drained = true
+				}
+			}
+			fmt.Fprint(w, sseShutdownEvent)
+			flusher.Flush()
+			close(session.done)
+			s.logger.DebugContext(ctx, "sse session closed for shutdown")
+			return
 		}
 	}
 }
@@ -287,6 +440,11 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	// retrieve sse session id, if applicable
 	sessionId := r.URL.Query().Get("sessionId")
 
+	// verify the auth tokens of the request, so that tools are called with
+	// the claims of the caller
+	claimsFromAuth, authTokens := s.authenticate(ctx, r.Header)
+	ctx = tools.WithClaims(tools.WithAuthTokens(ctx, authTokens), claimsFromAuth)
+
 	var err error
 	defer func() {
 		if err != nil {
@@ -307,15 +465,22 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	}()
 
 	// Read and returns a body from io.Reader
-	body, err := io.ReadAll(r.Body)
+	body, err := io.ReadAll(limitBody(w, r, s.maxRequestBytes))
 	if err != nil {
 		// Generate a new uuid if unable to decode
 		id := uuid.New().String()
 		s.logger.DebugContext(ctx, err.Error())
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = fmt.Errorf("request body is larger than %d bytes", maxBytesErr.Limit)
+			render.Status(r, http.StatusRequestEntityTooLarge)
+			render.JSON(w, r, newJSONRPCError(id, mcp.PARSE_ERROR, err.Error(), nil))
+			return
+		}
 		render.JSON(w, r, newJSONRPCError(id, mcp.PARSE_ERROR, err.Error(), nil))
 	}
 
-	res, err := processMcpMessage(ctx, body, s, toolsetName)
+	res, err := processMcpPayload(ctx, body, s, toolsetName, sessionId)
 	// notifications will return empty string
 	if res == nil {
 		// Notifications do not expect a response
@@ -348,8 +513,56 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, res)
 }
 
-// processMcpMessage process the messages received from clients
-func processMcpMessage(ctx context.Context, body []byte, s *Server, toolsetName string) (any, error) {
+// processMcpPayload processes a message, or a JSON-RPC batch of messages,
+// received from clients. The messages of a batch are processed concurrently,
+// and their responses are returned in the same order, without those of
+// notifications. A nil response means there is nothing to send back.
+func processMcpPayload(ctx context.Context, body []byte, s *Server, toolsetName, sessionId string) (any, error) {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		return processMcpMessage(ctx, body, s, toolsetName, sessionId)
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(trimmed, &batch); err != nil {
+		// Generate a new uuid if unable to decode
+		id := uuid.New().String()
+		return newJSONRPCError(id, mcp.PARSE_ERROR, err.Error(), nil), err
+	}
+	if len(batch) == 0 {
+		err := fmt.Errorf("batch is empty")
+		return newJSONRPCError(uuid.New().String(), mcp.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	results := make([]any, len(batch))
+	errs := make([]error, len(batch))
+	var wg sync.WaitGroup
+	for i, msg := range batch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = processMcpMessage(ctx, msg, s, toolsetName, sessionId)
+		}()
+	}
+	wg.Wait()
+
+	responses := make([]any, 0, len(results))
+	for _, res := range results {
+		if res != nil {
+			responses = append(responses, res)
+		}
+	}
+	err := errors.Join(errs...)
+	if len(responses) == 0 {
+		// the batch only has notifications
+		return nil, err
+	}
+	return responses, err
+}
+
+// processMcpMessage process the messages received from clients. sessionId is
+// the id of the sse session the message was sent on, if any.
+func processMcpMessage(ctx context.Context, body []byte, s *Server, toolsetName, sessionId string) (any, error) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return newJSONRPCError("", mcp.INTERNAL_ERROR, err.Error(), nil), err
@@ -397,7 +610,7 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, toolsetName
 			err = fmt.Errorf("invalid mcp initialize request: %w", err)
 			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
 		}
-		result := mcp.Initialize(s.version)
+		result := mcp.Initialize(s.version, req.Params.ProtocolVersion)
 		return mcp.JSONRPCResponse{
 			Jsonrpc: mcp.JSONRPC_VERSION,
 			Id:      baseMessage.Id,
@@ -409,12 +622,17 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, toolsetName
 			err = fmt.Errorf("invalid mcp tools list request: %w", err)
 			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
 		}
-		toolset, ok := s.toolsets[toolsetName]
+		toolset, ok := s.getToolset(toolsetName)
 		if !ok {
 			err = fmt.Errorf("toolset does not exist")
 			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
 		}
-		result := mcp.ToolsList(toolset)
+		// tools that require claims the caller doesn't have are hidden
+		toolset.McpManifest = s.listableMcpManifest(ctx, s.disabledTools.filterMcpManifest(toolset.McpManifest))
+		result, err := mcp.ToolsList(toolset, req.Params.Cursor, s.toolsListPageSize)
+		if err != nil {
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
+		}
 		return mcp.JSONRPCResponse{
 			Jsonrpc: mcp.JSONRPC_VERSION,
 			Id:      baseMessage.Id,
@@ -429,11 +647,24 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, toolsetName
 		toolName := req.Params.Name
 		toolArgument := req.Params.Arguments
 		logger.DebugContext(ctx, fmt.Sprintf("tool name: %s", toolName))
-		tool, ok := s.tools[toolName]
+		name, tool, ok := s.resolveTool(toolName, "")
 		if !ok {
 			err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
 			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
 		}
+		toolName = name
+		if s.disabledTools.has(toolName) {
+			err = toolDisabledError(toolName)
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), map[string]any{"code": codeToolDisabled}), err
+		}
+		if !tools.CanInvoke(ctx, tool) {
+			err = toolForbiddenError(toolName)
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		if s.mcpToolCallTimeout > 0 && tools.GetOptions(tool).Timeout <= 0 {
+			// tools without a timeout of their own are bounded by the server default
+			tool = callTimeoutTool{Tool: tool, timeout: s.mcpToolCallTimeout}
+		}
 
 		// marshal arguments and decode it using decodeJSON instead to prevent loss between floats/int.
 		aMarshal, err := json.Marshal(toolArgument)
@@ -446,35 +677,233 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, toolsetName
 			err = fmt.Errorf("unable to decode tools argument: %w", err)
 			return newJSONRPCError(baseMessage.Id, mcp.INTERNAL_ERROR, err.Error(), nil), err
 		}
+		if data, err = unwrapArguments(data, s.argumentsKey); err != nil {
+			err = fmt.Errorf("provided parameters were invalid: %w", err)
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
+		}
+
+		previewRows, err := splitPreview(data)
+		if err != nil {
+			err = fmt.Errorf("provided parameters were invalid: %w", err)
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
+		}
+		rowFilter, err := splitRowFilter(data, tool)
+		if err != nil {
+			err = fmt.Errorf("provided parameters were invalid: %w", err)
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
+		}
+		var preview *previewTool
+		if previewRows > 0 {
+			preview = &previewTool{Tool: tool, rows: previewRows}
+			tool = preview
+		}
 
 		// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
-		// Since MCP doesn't support auth, an empty map will be use every time.
-		claimsFromAuth := make(map[string]map[string]any)
+		// It is empty over stdio, which has no auth headers.
+		claimsFromAuth := tools.AllClaimsFromContext(ctx)
 
-		params, err := tool.ParseParams(data, claimsFromAuth)
+		params, err := tool.ParseParams(ctx, data, claimsFromAuth)
 		if err != nil {
 			err = fmt.Errorf("provided parameters were invalid: %w", err)
 			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
 		}
 		logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
-		if !tool.Authorized([]string{}) {
+		if !tool.Authorized(verifiedAuthServices(ctx)) {
 			err = fmt.Errorf("unauthorized Tool call: `authRequired` is set for the target Tool")
 			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
 		}
 
-		result := mcp.ToolCall(ctx, tool, params)
+		release, err := s.invocationLimiter.acquire(ctx)
+		if err != nil {
+			return newJSONRPCError(baseMessage.Id, mcp.INTERNAL_ERROR, err.Error(), nil), err
+		}
+		defer release()
+
+		// scope the invocation's context to the call, so that resources held
+		// for its result are released once it has been handled
+		ctx, cancel := context.WithCancel(withInvokeRowFilter(tools.WithToolName(ctx, toolName), rowFilter))
+		defer cancel()
+		start := time.Now()
+		result, err := mcp.ToolCall(ctx, tool, params)
+		duration := time.Since(start)
+		s.instrumentation.recordToolInvoke(ctx, toolName, start, err != nil || result.IsError)
+		if err != nil {
+			err = fmt.Errorf("error while invoking tool: %w", err)
+			return newJSONRPCError(baseMessage.Id, mcp.INTERNAL_ERROR, err.Error(), nil), err
+		}
+		if previewRows > 0 && !result.IsError {
+			if result.Meta == nil {
+				result.Meta = make(map[string]any)
+			}
+			result.Meta["preview"] = true
+		}
+		if s.executionMetadata && !result.IsError {
+			result.Meta = withExecutionMeta(result.Meta, duration, len(result.Content), resultTruncated(result.Meta, preview))
+		}
+		return mcp.JSONRPCResponse{
+			Jsonrpc: mcp.JSONRPC_VERSION,
+			Id:      baseMessage.Id,
+			Result:  result,
+		}, nil
+	case "prompts/list":
+		var req mcp.ListPromptsRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			err = fmt.Errorf("invalid mcp prompts list request: %w", err)
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		return mcp.JSONRPCResponse{
+			Jsonrpc: mcp.JSONRPC_VERSION,
+			Id:      baseMessage.Id,
+			Result:  mcp.PromptsList(s.getPrompts()),
+		}, nil
+	case "prompts/get":
+		var req mcp.GetPromptRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			err = fmt.Errorf("invalid mcp prompts get request: %w", err)
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		prompt, ok := s.getPrompts()[req.Params.Name]
+		if !ok {
+			err = fmt.Errorf("invalid prompt name: prompt with name %q does not exist", req.Params.Name)
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
+		}
+		result, err := mcp.PromptGet(ctx, prompt, req.Params.Arguments)
+		if err != nil {
+			err = fmt.Errorf("provided arguments were invalid: %w", err)
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
+		}
+		return mcp.JSONRPCResponse{
+			Jsonrpc: mcp.JSONRPC_VERSION,
+			Id:      baseMessage.Id,
+			Result:  result,
+		}, nil
+	case "resources/list":
+		var req mcp.ListResourcesRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			err = fmt.Errorf("invalid mcp resources list request: %w", err)
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		toolset, ok := s.getToolset(toolsetName)
+		if !ok {
+			err = fmt.Errorf("toolset does not exist")
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		toolset.McpManifest = s.listableMcpManifest(ctx, s.disabledTools.filterMcpManifest(toolset.McpManifest))
+		result := mcp.ResourcesList(toolset)
+		return mcp.JSONRPCResponse{
+			Jsonrpc: mcp.JSONRPC_VERSION,
+			Id:      baseMessage.Id,
+			Result:  result,
+		}, nil
+	case "resources/read":
+		var req mcp.ReadResourceRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			err = fmt.Errorf("invalid mcp resources read request: %w", err)
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		tool, err := resourceTool(ctx, s, req.Params.URI)
+		if err != nil {
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
+		}
+		result, err := mcp.ResourceRead(ctx, req.Params.URI, tool)
+		if err != nil {
+			err = fmt.Errorf("unable to read resource: %w", err)
+			return newJSONRPCError(baseMessage.Id, mcp.INTERNAL_ERROR, err.Error(), nil), err
+		}
 		return mcp.JSONRPCResponse{
 			Jsonrpc: mcp.JSONRPC_VERSION,
 			Id:      baseMessage.Id,
 			Result:  result,
 		}, nil
+	case "resources/subscribe":
+		var req mcp.SubscribeRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			err = fmt.Errorf("invalid mcp resources subscribe request: %w", err)
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		tool, err := resourceTool(ctx, s, req.Params.URI)
+		if err != nil {
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
+		}
+		session, ok := s.sseManager.get(sessionId)
+		if !ok {
+			err = fmt.Errorf("resource subscriptions require an sse session")
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		s.subscribe(ctx, session, req.Params.URI, tool)
+		return mcp.JSONRPCResponse{
+			Jsonrpc: mcp.JSONRPC_VERSION,
+			Id:      baseMessage.Id,
+			Result:  mcp.EmptyResult{},
+		}, nil
+	case "resources/unsubscribe":
+		var req mcp.UnsubscribeRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			err = fmt.Errorf("invalid mcp resources unsubscribe request: %w", err)
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		session, ok := s.sseManager.get(sessionId)
+		if !ok {
+			err = fmt.Errorf("resource subscriptions require an sse session")
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		s.unsubscribe(session, req.Params.URI)
+		return mcp.JSONRPCResponse{
+			Jsonrpc: mcp.JSONRPC_VERSION,
+			Id:      baseMessage.Id,
+			Result:  mcp.EmptyResult{},
+		}, nil
 	default:
 		err = fmt.Errorf("invalid method %s", baseMessage.Method)
 		return newJSONRPCError(baseMessage.Id, mcp.METHOD_NOT_FOUND, err.Error(), nil), err
 	}
 }
 
+// resourceTool returns the tool serving the resource at uri.
+func resourceTool(ctx context.Context, s *Server, uri string) (tools.Tool, error) {
+	toolName, ok := mcp.ToolNameFromResourceURI(uri)
+	if !ok {
+		return nil, fmt.Errorf("invalid resource uri %q", uri)
+	}
+	tool, ok := s.getTool(toolName)
+	if !ok || !mcp.IsResource(tool) {
+		return nil, fmt.Errorf("resource %q does not exist", uri)
+	}
+	if s.disabledTools.has(toolName) {
+		return nil, toolDisabledError(toolName)
+	}
+	if !tool.Authorized(verifiedAuthServices(ctx)) {
+		return nil, fmt.Errorf("unauthorized resource read: `authRequired` is set for the target Tool")
+	}
+	if !tools.CanInvoke(ctx, tool) {
+		return nil, toolForbiddenError(toolName)
+	}
+	return tool, nil
+}
+
+// callTimeoutTool bounds the invocations of a tool without a timeout of its
+// own by the default timeout of MCP tool calls. Unlike ToolWithOptions, it
+// doesn't hide the options of the tool it wraps from tools.GetOptions.
+type callTimeoutTool struct {
+	tools.Tool
+	timeout time.Duration
+}
+
+func (t callTimeoutTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t callTimeoutTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	res, err := t.Tool.Invoke(ctx, params)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w after %s: %w", tools.ErrTimeout, t.timeout, err)
+	}
+	return res, err
+}
+
 // newJSONRPCError is the response sent back when an error has been encountered in mcp.
 func newJSONRPCError(id mcp.RequestId, code int, message string, data any) mcp.JSONRPCError {
 	return mcp.JSONRPCError{
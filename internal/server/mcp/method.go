@@ -16,20 +16,38 @@ package mcp
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
-func Initialize(version string) InitializeResult {
-	toolsListChanged := false
+// Initialize returns an InitializeResult. The protocol version requested by
+// the client is used if it is supported, otherwise the latest supported
+// version is offered.
+func Initialize(version, requestedProtocolVersion string) InitializeResult {
+	protocolVersion := LATEST_PROTOCOL_VERSION
+	if slices.Contains(SUPPORTED_PROTOCOL_VERSIONS, requestedProtocolVersion) {
+		protocolVersion = requestedProtocolVersion
+	}
+	toolsListChanged, promptsListChanged := false, false
 	result := InitializeResult{
-		ProtocolVersion: LATEST_PROTOCOL_VERSION,
+		ProtocolVersion: protocolVersion,
 		Capabilities: ServerCapabilities{
 			Tools: &ListChanged{
 				ListChanged: &toolsListChanged,
 			},
+			Resources: &ResourcesCapability{
+				Subscribe: true,
+			},
+			Prompts: &ListChanged{
+				ListChanged: &promptsListChanged,
+			},
 		},
 		ServerInfo: Implementation{
 			Name:    SERVER_NAME,
@@ -39,25 +57,51 @@ func Initialize(version string) InitializeResult {
 	return result
 }
 
-// ToolsList return a ListToolsResult
-func ToolsList(toolset tools.Toolset) ListToolsResult {
+// ToolsList return a ListToolsResult. If pageSize is positive, at most
+// pageSize tools are returned, starting after the tool named by cursor, along
+// with the cursor of the next page if more tools remain.
+func ToolsList(toolset tools.Toolset, cursor Cursor, pageSize int) (ListToolsResult, error) {
 	mcpManifest := toolset.McpManifest
 
-	result := ListToolsResult{
-		Tools: mcpManifest,
+	start := 0
+	if cursor != "" {
+		name, err := base64.RawURLEncoding.DecodeString(string(cursor))
+		if err != nil {
+			return ListToolsResult{}, fmt.Errorf("invalid cursor %q", cursor)
+		}
+		i := slices.IndexFunc(mcpManifest, func(m tools.McpManifest) bool { return m.Name == string(name) })
+		if i < 0 {
+			return ListToolsResult{}, fmt.Errorf("invalid cursor %q", cursor)
+		}
+		start = i + 1
 	}
-	return result
+	mcpManifest = mcpManifest[start:]
+
+	result := ListToolsResult{}
+	if pageSize > 0 && len(mcpManifest) > pageSize {
+		mcpManifest = mcpManifest[:pageSize]
+		// the cursor is the name of the last tool of the page
+		last := mcpManifest[len(mcpManifest)-1].Name
+		result.NextCursor = Cursor(base64.RawURLEncoding.EncodeToString([]byte(last)))
+	}
+	result.Tools = mcpManifest
+	return result, nil
 }
 
 // ToolCall runs tool invocation and return a CallToolResult
-func ToolCall(ctx context.Context, tool tools.Tool, params tools.ParamValues) CallToolResult {
+func ToolCall(ctx context.Context, tool tools.Tool, params tools.ParamValues) (CallToolResult, error) {
+	ctx = tools.WithMeta(ctx)
 	res, err := tool.Invoke(ctx, params)
 	if err != nil {
+		// timeouts are reported as errors of the call rather than of the tool
+		if errors.Is(err, tools.ErrTimeout) {
+			return CallToolResult{}, err
+		}
 		text := TextContent{
 			Type: "text",
 			Text: err.Error(),
 		}
-		return CallToolResult{Content: []TextContent{text}, IsError: true}
+		return CallToolResult{Content: []TextContent{text}, IsError: true}, nil
 	}
 
 	content := make([]TextContent, 0)
@@ -71,5 +115,82 @@ func ToolCall(ctx context.Context, tool tools.Tool, params tools.ParamValues) Ca
 		}
 		content = append(content, text)
 	}
-	return CallToolResult{Content: content}
+	return CallToolResult{Result: Result{Meta: tools.MetaFromContext(ctx)}, Content: content}, nil
+}
+
+// resourceURIPrefix is the prefix of the URIs of tools served as resources.
+const resourceURIPrefix = "toolbox://tools/"
+
+// ResourceURI returns the URI of the resource served by a tool.
+func ResourceURI(toolName string) string {
+	return resourceURIPrefix + toolName
+}
+
+// ToolNameFromResourceURI returns the name of the tool serving a resource.
+func ToolNameFromResourceURI(uri string) (string, bool) {
+	return strings.CutPrefix(uri, resourceURIPrefix)
+}
+
+// IsResource returns true if a tool can be served as a resource. Only tools
+// that take no parameters can be read without further input.
+func IsResource(tool tools.Tool) bool {
+	return len(tool.McpManifest().InputSchema.Properties) == 0
+}
+
+// ResourcesList returns a ListResourcesResult with the tools of a toolset
+// that can be served as resources.
+func ResourcesList(toolset tools.Toolset) ListResourcesResult {
+	resources := make([]Resource, 0)
+	for _, t := range toolset.McpManifest {
+		if len(t.InputSchema.Properties) > 0 {
+			continue
+		}
+		resources = append(resources, Resource{
+			URI:         ResourceURI(t.Name),
+			Name:        t.Name,
+			Description: t.Description,
+			MimeType:    "application/json",
+		})
+	}
+	return ListResourcesResult{Resources: resources}
+}
+
+// ResourceRead invokes the tool serving a resource and returns its result as
+// the contents of the resource.
+func ResourceRead(ctx context.Context, uri string, tool tools.Tool) (ReadResourceResult, error) {
+	res, err := tool.Invoke(ctx, tools.ParamValues{})
+	if err != nil {
+		return ReadResourceResult{}, err
+	}
+	text, err := json.Marshal(res)
+	if err != nil {
+		return ReadResourceResult{}, fmt.Errorf("unable to marshal result: %w", err)
+	}
+	contents := TextResourceContents{URI: uri, MimeType: "application/json", Text: string(text)}
+	return ReadResourceResult{Contents: []TextResourceContents{contents}}, nil
+}
+
+// PromptsList returns a ListPromptsResult with the prompts, sorted by name.
+func PromptsList(prompts map[string]tools.Prompt) ListPromptsResult {
+	names := slices.Sorted(maps.Keys(prompts))
+	manifests := make([]tools.PromptMcpManifest, 0, len(names))
+	for _, name := range names {
+		manifests = append(manifests, prompts[name].McpManifest())
+	}
+	return ListPromptsResult{Prompts: manifests}
+}
+
+// PromptGet renders a prompt with the given arguments, and returns it as a
+// single user message.
+func PromptGet(ctx context.Context, prompt tools.Prompt, args map[string]string) (GetPromptResult, error) {
+	data := make(map[string]any, len(args))
+	for k, v := range args {
+		data[k] = v
+	}
+	text, err := prompt.Render(ctx, data)
+	if err != nil {
+		return GetPromptResult{}, err
+	}
+	msg := PromptMessage{Role: RoleUser, Content: TextContent{Type: "text", Text: text}}
+	return GetPromptResult{Description: prompt.Description, Messages: []PromptMessage{msg}}, nil
 }
@@ -22,7 +22,14 @@ import (
 const SERVER_NAME = "Toolbox"
 
 // LATEST_PROTOCOL_VERSION is the most recent version of the MCP protocol.
-const LATEST_PROTOCOL_VERSION = "2024-11-05"
+const LATEST_PROTOCOL_VERSION = "2025-03-26"
+
+// SUPPORTED_PROTOCOL_VERSIONS are the versions of the MCP protocol supported
+// by the server, from the most recent.
+var SUPPORTED_PROTOCOL_VERSIONS = []string{
+	LATEST_PROTOCOL_VERSION,
+	"2024-11-05",
+}
 
 // JSONRPC_VERSION is the version of JSON-RPC used by MCP.
 const JSONRPC_VERSION = "2.0"
@@ -185,7 +192,9 @@ type ClientCapabilities struct {
 // capabilities are defined here, in this schema, but this is not a closed set: any
 // server can define its own, additional capabilities.
 type ServerCapabilities struct {
-	Tools *ListChanged `json:"tools,omitempty"`
+	Tools     *ListChanged         `json:"tools,omitempty"`
+	Resources *ResourcesCapability `json:"resources,omitempty"`
+	Prompts   *ListChanged         `json:"prompts,omitempty"`
 }
 
 // Implementation describes the name and version of an MCP implementation.
@@ -293,3 +302,129 @@ type CallToolResult struct {
 	// If not set, this is assumed to be false (the call was successful).
 	IsError bool `json:"isError,omitempty"`
 }
+
+/* Prompts */
+
+// Sent from the client to request a list of prompts the server has.
+type ListPromptsRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a prompts/list request from the client.
+type ListPromptsResult struct {
+	PaginatedResult
+	Prompts []tools.PromptMcpManifest `json:"prompts"`
+}
+
+// Used by the client to get a prompt provided by the server.
+type GetPromptRequest struct {
+	Request
+	Params struct {
+		// The name of the prompt.
+		Name string `json:"name"`
+		// Arguments to use for templating the prompt.
+		Arguments map[string]string `json:"arguments,omitempty"`
+	} `json:"params"`
+}
+
+// PromptMessage describes a message returned as part of a prompt.
+type PromptMessage struct {
+	Role    Role        `json:"role"`
+	Content TextContent `json:"content"`
+}
+
+// The server's response to a prompts/get request from the client.
+type GetPromptResult struct {
+	Result
+	// An optional description for the prompt.
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+/* Resources */
+
+// ResourcesCapability represents the resource features supported by the server.
+type ResourcesCapability struct {
+	// Whether the server supports subscribing to resource updates.
+	Subscribe bool `json:"subscribe,omitempty"`
+	// Whether the server will notify clients when the list of resources changes.
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// Resource is a known resource that the server is capable of reading.
+type Resource struct {
+	// The URI of this resource.
+	URI string `json:"uri"`
+	// A human-readable name for this resource.
+	Name string `json:"name"`
+	// A description of what this resource represents.
+	Description string `json:"description,omitempty"`
+	// The MIME type of this resource, if known.
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// Sent from the client to request a list of resources the server has.
+type ListResourcesRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a resources/list request from the client.
+type ListResourcesResult struct {
+	PaginatedResult
+	Resources []Resource `json:"resources"`
+}
+
+// Sent from the client to the server, to read a specific resource URI.
+type ReadResourceRequest struct {
+	Request
+	Params struct {
+		// The URI of the resource to read.
+		URI string `json:"uri"`
+	} `json:"params"`
+}
+
+// TextResourceContents represents the text contents of a resource.
+type TextResourceContents struct {
+	// The URI of this resource.
+	URI string `json:"uri"`
+	// The MIME type of this resource, if known.
+	MimeType string `json:"mimeType,omitempty"`
+	// The text of the item.
+	Text string `json:"text"`
+}
+
+// The server's response to a resources/read request from the client.
+type ReadResourceResult struct {
+	Result
+	Contents []TextResourceContents `json:"contents"`
+}
+
+// Sent from the client to request resources/updated notifications from the
+// server whenever a particular resource changes.
+type SubscribeRequest struct {
+	Request
+	Params struct {
+		// The URI of the resource to subscribe to.
+		URI string `json:"uri"`
+	} `json:"params"`
+}
+
+// Sent from the client to request cancellation of resources/updated
+// notifications from the server.
+type UnsubscribeRequest struct {
+	Request
+	Params struct {
+		// The URI of the resource to unsubscribe from.
+		URI string `json:"uri"`
+	} `json:"params"`
+}
+
+// A notification from the server to the client, informing it that a resource
+// has changed and may need to be read again.
+type ResourceUpdatedNotification struct {
+	Method string `json:"method"`
+	Params struct {
+		// The URI of the resource that has been updated.
+		URI string `json:"uri"`
+	} `json:"params"`
+}
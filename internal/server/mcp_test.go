@@ -19,7 +19,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -27,14 +29,18 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
 const jsonrpcVersion = "2.0"
-const protocolVersion = "2024-11-05"
+const protocolVersion = "2025-03-26"
 const serverName = "Toolbox"
 
 var tool1InputSchema = map[string]any{
@@ -95,7 +101,59 @@ func TestMcpEndpoint(t *testing.T) {
 				"result": map[string]any{
 					"protocolVersion": protocolVersion,
 					"capabilities": map[string]any{
-						"tools": map[string]any{"listChanged": false},
+						"tools":     map[string]any{"listChanged": false},
+						"resources": map[string]any{"subscribe": true},
+						"prompts":   map[string]any{"listChanged": false},
+					},
+					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+				},
+			},
+		},
+		{
+			name: "initialize with supported version",
+			url:  "/",
+			body: mcp.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "mcp-initialize-supported",
+				Request: mcp.Request{
+					Method: "initialize",
+				},
+				Params: map[string]any{"protocolVersion": "2024-11-05"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "mcp-initialize-supported",
+				"result": map[string]any{
+					"protocolVersion": "2024-11-05",
+					"capabilities": map[string]any{
+						"tools":     map[string]any{"listChanged": false},
+						"resources": map[string]any{"subscribe": true},
+						"prompts":   map[string]any{"listChanged": false},
+					},
+					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+				},
+			},
+		},
+		{
+			name: "initialize with unsupported version",
+			url:  "/",
+			body: mcp.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "mcp-initialize-unsupported",
+				Request: mcp.Request{
+					Method: "initialize",
+				},
+				Params: map[string]any{"protocolVersion": "2023-01-01"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "mcp-initialize-unsupported",
+				"result": map[string]any{
+					"protocolVersion": protocolVersion,
+					"capabilities": map[string]any{
+						"tools":     map[string]any{"listChanged": false},
+						"resources": map[string]any{"subscribe": true},
+						"prompts":   map[string]any{"listChanged": false},
 					},
 					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
 				},
@@ -275,6 +333,511 @@ func TestMcpEndpoint(t *testing.T) {
 	}
 }
 
+func TestMcpToolsListPagination(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets, func(s *Server) { s.toolsListPageSize = 2 })
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	listTools := func(cursor mcp.Cursor) (mcp.ListToolsResult, *mcp.McpError) {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+			Jsonrpc: jsonrpcVersion,
+			Id:      "tools-list",
+			Request: mcp.Request{
+				Method: "tools/list",
+			},
+			Params: params,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got struct {
+			Result mcp.ListToolsResult `json:"result"`
+			Error  *mcp.McpError       `json:"error"`
+		}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		return got.Result, got.Error
+	}
+
+	var names []string
+	first, rpcErr := listTools("")
+	if rpcErr != nil {
+		t.Fatalf("unexpected error listing first page: %s", rpcErr.Message)
+	}
+	if len(first.Tools) != 2 || first.NextCursor == "" {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	for _, m := range first.Tools {
+		names = append(names, m.Name)
+	}
+
+	second, rpcErr := listTools(first.NextCursor)
+	if rpcErr != nil {
+		t.Fatalf("unexpected error listing second page: %s", rpcErr.Message)
+	}
+	if len(second.Tools) != 1 || second.NextCursor != "" {
+		t.Fatalf("unexpected second page: %+v", second)
+	}
+	for _, m := range second.Tools {
+		names = append(names, m.Name)
+	}
+
+	// every tool is listed exactly once across both pages
+	want := []string{tool1.Name, tool2.Name, tool3.Name}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("unexpected tools across pages: got %v, want %v", names, want)
+	}
+
+	_, rpcErr = listTools("not-a-cursor")
+	if rpcErr == nil || rpcErr.Code != mcp.INVALID_PARAMS {
+		t.Fatalf("expected an invalid params error for an unknown cursor, got %+v", rpcErr)
+	}
+}
+
+func TestMcpToolCallTimeout(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"slow": tools.ToolWithOptions{
+			Tool:    slowTool{MockTool: MockTool{Name: "slow"}},
+			Options: tools.Options{Timeout: 10 * time.Millisecond},
+		},
+	}
+	r, shutdown := setUpServer(t, "mcp", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "slow-call",
+		Request: mcp.Request{
+			Method: "tools/call",
+		},
+		Params: map[string]any{"name": "slow", "arguments": map[string]any{}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var got struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if got.Error.Code != mcp.INTERNAL_ERROR {
+		t.Fatalf("unexpected error code: want %d, got %d: %s", mcp.INTERNAL_ERROR, got.Error.Code, string(body))
+	}
+	if !strings.Contains(got.Error.Message, "tool invocation timed out after 10ms") {
+		t.Fatalf("unexpected error message: %q", got.Error.Message)
+	}
+}
+
+func TestMcpToolCallDefaultTimeout(t *testing.T) {
+	tcs := []struct {
+		name        string
+		tool        tools.Tool
+		wantMessage string
+	}{
+		{
+			name:        "server default",
+			tool:        slowTool{MockTool: MockTool{Name: "slow"}},
+			wantMessage: "tool invocation timed out after 20ms",
+		},
+		{
+			name: "tool timeout overrides server default",
+			tool: tools.ToolWithOptions{
+				Tool:    slowTool{MockTool: MockTool{Name: "slow"}},
+				Options: tools.Options{Timeout: 10 * time.Millisecond},
+			},
+			wantMessage: "tool invocation timed out after 10ms",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			toolsMap := map[string]tools.Tool{"slow": tc.tool}
+			r, shutdown := setUpServer(t, "mcp", toolsMap, nil, func(s *Server) { s.mcpToolCallTimeout = 20 * time.Millisecond })
+			defer shutdown()
+			ts := runServer(r, false)
+			defer ts.Close()
+
+			reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "slow-call",
+				Request: mcp.Request{
+					Method: "tools/call",
+				},
+				Params: map[string]any{"name": "slow", "arguments": map[string]any{}},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			start := time.Now()
+			_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("tool call was not cancelled in time, took %s", elapsed)
+			}
+			var got struct {
+				Error struct {
+					Code    int    `json:"code"`
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if got.Error.Code != mcp.INTERNAL_ERROR {
+				t.Fatalf("unexpected error code: want %d, got %d: %s", mcp.INTERNAL_ERROR, got.Error.Code, string(body))
+			}
+			if !strings.Contains(got.Error.Message, tc.wantMessage) {
+				t.Fatalf("unexpected error message: want %q, got %q", tc.wantMessage, got.Error.Message)
+			}
+		})
+	}
+}
+
+func TestMcpToolCallDefaultTimeoutKeepsOptions(t *testing.T) {
+	// the server default timeout must not hide the options of the tool, such
+	// as its scalar results, which don't support _filter
+	toolsMap := map[string]tools.Tool{
+		"count": tools.ToolWithOptions{
+			Tool:    MockTool{Name: "count"},
+			Options: tools.Options{ResultMode: tools.ResultModeScalar},
+		},
+	}
+	r, shutdown := setUpServer(t, "mcp", toolsMap, nil, func(s *Server) { s.mcpToolCallTimeout = time.Minute })
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "filter-call",
+		Request: mcp.Request{
+			Method: "tools/call",
+		},
+		Params: map[string]any{"name": "count", "arguments": map[string]any{"_filter": "n > 1"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var got struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if got.Error.Code != mcp.INVALID_PARAMS {
+		t.Fatalf("unexpected error code: want %d, got %d: %s", mcp.INVALID_PARAMS, got.Error.Code, string(body))
+	}
+	if !strings.Contains(got.Error.Message, "is not supported by tools with resultMode") {
+		t.Fatalf("unexpected error message: %q", got.Error.Message)
+	}
+}
+
+func TestMcpToolCallBooleanParameter(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"flag": MockTool{
+			Name:   "flag",
+			Params: tools.Parameters{tools.NewBooleanParameter("active", "whether the user is active")},
+		},
+	}
+	r, shutdown := setUpServer(t, "mcp", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "flag-call",
+		Request: mcp.Request{
+			Method: "tools/call",
+		},
+		Params: map[string]any{"name": "flag", "arguments": map[string]any{"active": "true"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var got struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if got.Error.Code != mcp.INVALID_PARAMS {
+		t.Fatalf("unexpected error code: want %d, got %d: %s", mcp.INVALID_PARAMS, got.Error.Code, string(body))
+	}
+	want := `provided parameters were invalid: unable to parse value for "active": parameter "active" must be a boolean (true or false)`
+	if got.Error.Message != want {
+		t.Fatalf("unexpected error message: want %q, got %q", want, got.Error.Message)
+	}
+}
+
+func TestMcpBatch(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"first":  MockTool{Name: "first"},
+		"second": MockTool{Name: "second"},
+	}
+	r, shutdown := setUpServer(t, "mcp", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	batch := []any{
+		mcp.JSONRPCRequest{
+			Jsonrpc: jsonrpcVersion,
+			Id:      "first-call",
+			Request: mcp.Request{Method: "tools/call"},
+			Params:  map[string]any{"name": "first", "arguments": map[string]any{}},
+		},
+		mcp.JSONRPCNotification{
+			Jsonrpc:      jsonrpcVersion,
+			Notification: mcp.Notification{Method: "notifications/initialized"},
+		},
+		mcp.JSONRPCRequest{
+			Jsonrpc: jsonrpcVersion,
+			Id:      "second-call",
+			Request: mcp.Request{Method: "tools/call"},
+			Params:  map[string]any{"name": "second", "arguments": map[string]any{}},
+		},
+	}
+	reqMarshal, err := json.Marshal(batch)
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	resp, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, body)
+	}
+	var got []struct {
+		Id     string `json:"id"`
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("unexpected number of responses: want 2, got %d: %s", len(got), body)
+	}
+	for i, name := range []string{"first", "second"} {
+		if got[i].Id != name+"-call" {
+			t.Fatalf("unexpected id of response %d: want %q, got %q", i, name+"-call", got[i].Id)
+		}
+		if len(got[i].Result.Content) != 1 || !strings.Contains(got[i].Result.Content[0].Text, name) {
+			t.Fatalf("unexpected result of response %d: %s", i, body)
+		}
+	}
+
+	// batches of notifications have no response
+	reqMarshal, err = json.Marshal(batch[1:2])
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	resp, body, err = runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusAccepted || len(body) != 0 {
+		t.Fatalf("unexpected response to a batch of notifications: %d %s", resp.StatusCode, body)
+	}
+}
+
+func TestMcpToolCallAuth(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"payroll": tools.ToolWithOptions{
+			Tool: MockTool{Name: "payroll"},
+			Options: tools.Options{
+				InvokeRequired: []tools.AccessRule{{AuthService: "my-role-auth", Claim: "roles", Values: []string{"hr"}}},
+			},
+		},
+	}
+	r, shutdown := setUpServer(t, "mcp", toolsMap, nil, func(s *Server) {
+		s.authServices = map[string]auth.AuthService{"my-role-auth": roleAuthService{}}
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "payroll-call",
+		Request: mcp.Request{
+			Method: "tools/call",
+		},
+		Params: map[string]any{"name": "payroll", "arguments": map[string]any{}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+
+	tcs := []struct {
+		name    string
+		role    string
+		wantErr bool
+	}{
+		{name: "privileged caller", role: "hr"},
+		{name: "unprivileged caller", role: "engineering", wantErr: true},
+		{name: "anonymous caller", wantErr: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, ts.URL+"/", bytes.NewBuffer(reqMarshal))
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			if tc.role != "" {
+				req.Header.Set("my-role-auth_token", tc.role)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+			var got map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if _, ok := got["error"]; ok != tc.wantErr {
+				t.Fatalf("unexpected response: %v", got)
+			}
+		})
+	}
+}
+
+func TestMcpToolCallAllowedValues(t *testing.T) {
+	region := tools.NewStringParameter("region", "region of the instance")
+	region.AllowedValues = []string{"us-east1", "us-west1"}
+	toolsMap := map[string]tools.Tool{
+		"regional": MockTool{Name: "regional", Params: tools.Parameters{region}},
+	}
+	r, shutdown := setUpServer(t, "mcp", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "regional-call",
+		Request: mcp.Request{
+			Method: "tools/call",
+		},
+		Params: map[string]any{"name": "regional", "arguments": map[string]any{"region": "eu-west1"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var got struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if got.Error.Code != mcp.INVALID_PARAMS {
+		t.Fatalf("unexpected error code: want %d, got %d: %s", mcp.INVALID_PARAMS, got.Error.Code, string(body))
+	}
+	want := `provided parameters were invalid: unable to parse value for "region": parameter "region" must be one of ["us-east1" "us-west1"]`
+	if got.Error.Message != want {
+		t.Fatalf("unexpected error message: want %q, got %q", want, got.Error.Message)
+	}
+}
+
+func TestMcpToolCallArgumentsKey(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"flag": MockTool{
+			Name:   "flag",
+			Params: tools.Parameters{tools.NewBooleanParameter("active", "whether the user is active")},
+		},
+	}
+	r, shutdown := setUpServer(t, "mcp", toolsMap, nil, func(s *Server) { s.argumentsKey = "args" })
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "flag-call",
+		Request: mcp.Request{
+			Method: "tools/call",
+		},
+		Params: map[string]any{"name": "flag", "arguments": map[string]any{"args": map[string]any{"active": true}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var got struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if got.Error != nil {
+		t.Fatalf("unexpected error: %s", got.Error.Message)
+	}
+	if len(got.Result.Content) != 1 || got.Result.Content[0].Text != `"flag"` {
+		t.Fatalf("unexpected result: %s", string(body))
+	}
+}
+
 func TestSseEndpoint(t *testing.T) {
 	r, shutdown := setUpServer(t, "mcp", nil, nil)
 	defer shutdown()
@@ -294,7 +857,8 @@ func TestSseEndpoint(t *testing.T) {
 	contentType := "text/event-stream"
 	cacheControl := "no-cache"
 	connection := "keep-alive"
-	accessControlAllowOrigin := "*"
+	// cross-origin requests are only allowed by the cors middleware
+	accessControlAllowOrigin := ""
 
 	testCases := []struct {
 		name   string
@@ -371,6 +935,43 @@ func TestSseEndpoint(t *testing.T) {
 	}
 }
 
+func TestSseEndpointMaxSessions(t *testing.T) {
+	maxSessions := 2
+	r, shutdown := setUpServer(t, "mcp", nil, nil, func(s *Server) {
+		s.sseManager.maxSSESessions = maxSessions
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	// open sessions up to the limit
+	for i := 0; i < maxSessions; i++ {
+		resp, err := runSseRequest(ts, "/sse", "")
+		if err != nil {
+			t.Fatalf("unable to run sse request: %s", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code for session #%d: want %d, got %d", i, http.StatusOK, resp.StatusCode)
+		}
+		// the endpoint event is only sent after the session is registered
+		buffer := make([]byte, 1024)
+		if _, err := resp.Body.Read(buffer); err != nil {
+			t.Fatalf("unable to read response: %s", err)
+		}
+	}
+
+	// the next session should be rejected
+	resp, err := runSseRequest(ts, "/sse", "")
+	if err != nil {
+		t.Fatalf("unable to run sse request: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected status code: want %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+	}
+}
+
 func runSseRequest(ts *httptest.Server, path string, proto string) (*http.Response, error) {
 	req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
 	if err != nil {
@@ -403,7 +1004,7 @@ func TestStdioSession(t *testing.T) {
 		t.Fatalf("unable to initialize logger: %s", err)
 	}
 
-	otelShutdown, err := telemetry.SetupOTel(ctx, fakeVersionString, "", false, "toolbox")
+	otelShutdown, err := telemetry.SetupOTel(ctx, fakeVersionString, "", false, "toolbox", false, false)
 	if err != nil {
 		t.Fatalf("unable to setup otel: %s", err)
 	}
@@ -460,3 +1061,208 @@ func TestStdioSession(t *testing.T) {
 		t.Fatalf("unexpected read: got %s, want %s", read, want)
 	}
 }
+
+func TestStdioSessionContentLengthFraming(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	ctx = util.WithLogger(ctx, testLogger)
+
+	instrumentation, err := CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	server := &Server{version: fakeVersionString, logger: testLogger, instrumentation: instrumentation, stdioFraming: stdioFramingContentLength}
+
+	// two messages, with headers in varying case and an extra header
+	var in bytes.Buffer
+	for i, header := range []string{"Content-Length", "content-length"} {
+		msg := fmt.Sprintf(`{"jsonrpc":"2.0","id":"%d","method":"initialize","params":{"protocolVersion":"%s"}}`, i, protocolVersion)
+		fmt.Fprintf(&in, "%s: %d\r\nContent-Type: application/vscode-jsonrpc; charset=utf-8\r\n\r\n%s", header, len(msg), msg)
+	}
+	var out bytes.Buffer
+	if err := NewStdioSession(server, &in, &out).Start(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	reader := bufio.NewReader(&out)
+	for i := 0; i < 2; i++ {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unable to read header: %s", err)
+		}
+		var length int
+		if _, err := fmt.Sscanf(header, "Content-Length: %d\r\n", &length); err != nil {
+			t.Fatalf("unexpected header %q: %s", header, err)
+		}
+		if sep, err := reader.ReadString('\n'); err != nil || sep != "\r\n" {
+			t.Fatalf("expected a blank line after the headers, got %q: %v", sep, err)
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			t.Fatalf("unable to read body: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unable to unmarshal body %q: %s", body, err)
+		}
+		if got["id"] != fmt.Sprint(i) {
+			t.Fatalf("unexpected id: got %v, want %d", got["id"], i)
+		}
+		if _, ok := got["result"]; !ok {
+			t.Fatalf("expected a result, got %v", got)
+		}
+	}
+	if rest, _ := io.ReadAll(reader); len(rest) != 0 {
+		t.Fatalf("unexpected trailing output: %q", rest)
+	}
+}
+
+func TestSseManagerShutdownTimeout(t *testing.T) {
+	m := &sseManager{sseSessions: make(map[string]*sseSession)}
+	// neither session is served by a handler, so they never close
+	for _, id := range []string{"session-a", "session-b"} {
+		m.add(id, &sseSession{sessionId: id, shutdown: make(chan struct{}), closed: make(chan struct{})})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := m.shutdown(ctx)
+	if err == nil {
+		t.Fatalf("expected an error for sessions that did not close")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, got %s", err)
+	}
+	for _, id := range []string{"session-a", "session-b"} {
+		if !strings.Contains(err.Error(), id) {
+			t.Fatalf("expected error to name session %q, got %s", id, err)
+		}
+	}
+}
+
+func TestMcpPrompts(t *testing.T) {
+	city := tools.NewStringParameter("city", "the city to visit")
+	days := tools.NewStringParameter("days", "the length of the trip")
+	days.Default = "3"
+	prompt, err := tools.PromptConfig{
+		Name:        "plan_trip",
+		Description: "Plan a trip to a city.",
+		Arguments:   tools.Parameters{city, days},
+		Message:     "Plan a {{.days}} day trip to {{.city}}.",
+	}.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize prompt: %s", err)
+	}
+	r, shutdown := setUpServer(t, "mcp", nil, nil, func(s *Server) {
+		s.prompts = map[string]tools.Prompt{"plan_trip": prompt}
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name    string
+		method  string
+		params  map[string]any
+		want    any
+		wantErr int
+	}{
+		{
+			name:   "prompts/list",
+			method: "prompts/list",
+			want: map[string]any{
+				"prompts": []any{
+					map[string]any{
+						"name":        "plan_trip",
+						"description": "Plan a trip to a city.",
+						"arguments": []any{
+							map[string]any{"name": "city", "description": "the city to visit", "required": true},
+							map[string]any{"name": "days", "description": "the length of the trip"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "prompts/get",
+			method: "prompts/get",
+			params: map[string]any{"name": "plan_trip", "arguments": map[string]any{"city": "Lisbon", "days": "5"}},
+			want: map[string]any{
+				"description": "Plan a trip to a city.",
+				"messages": []any{
+					map[string]any{
+						"role":    "user",
+						"content": map[string]any{"type": "text", "text": "Plan a 5 day trip to Lisbon."},
+					},
+				},
+			},
+		},
+		{
+			name:   "prompts/get with default argument",
+			method: "prompts/get",
+			params: map[string]any{"name": "plan_trip", "arguments": map[string]any{"city": "Lisbon"}},
+			want: map[string]any{
+				"description": "Plan a trip to a city.",
+				"messages": []any{
+					map[string]any{
+						"role":    "user",
+						"content": map[string]any{"type": "text", "text": "Plan a 3 day trip to Lisbon."},
+					},
+				},
+			},
+		},
+		{
+			name:    "prompts/get without required argument",
+			method:  "prompts/get",
+			params:  map[string]any{"name": "plan_trip"},
+			wantErr: mcp.INVALID_PARAMS,
+		},
+		{
+			name:    "prompts/get with unknown prompt",
+			method:  "prompts/get",
+			params:  map[string]any{"name": "unknown"},
+			wantErr: mcp.INVALID_PARAMS,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      tc.name,
+				Request: mcp.Request{Method: tc.method},
+				Params:  tc.params,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got struct {
+				Result any           `json:"result"`
+				Error  *mcp.McpError `json:"error"`
+			}
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if tc.wantErr != 0 {
+				if got.Error == nil || got.Error.Code != tc.wantErr {
+					t.Fatalf("expected error code %d, got %s", tc.wantErr, string(body))
+				}
+				return
+			}
+			if got.Error != nil {
+				t.Fatalf("unexpected error: %s", string(body))
+			}
+			if !reflect.DeepEqual(got.Result, tc.want) {
+				t.Fatalf("unexpected result: got %+v, want %+v", got.Result, tc.want)
+			}
+		})
+	}
+}
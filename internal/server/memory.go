@@ -0,0 +1,135 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+var errResultMemoryExceeded = errors.New("source is at capacity: too many result bytes in flight")
+
+// resultMemoryGuard accounts for the size of the results of a source's tools
+// that are still being handled, and rejects results that would take it past
+// its ceiling.
+type resultMemoryGuard struct {
+	mu       sync.Mutex
+	source   string
+	inFlight int64
+	ceiling  int64
+}
+
+// reserve accounts for n bytes of results. On success, the returned func must
+// be called to release them.
+func (g *resultMemoryGuard) reserve(n int64) (func(), error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.inFlight+n > g.ceiling {
+		return nil, fmt.Errorf("%w: %d bytes in flight, result of %d bytes exceeds the ceiling of %d bytes", errResultMemoryExceeded, g.inFlight, n, g.ceiling)
+	}
+	g.inFlight += n
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			g.inFlight -= n
+		})
+	}, nil
+}
+
+// validate interface
+var _ tools.Tool = memoryGuardedTool{}
+
+// memoryGuardedTool is a Tool whose results are accounted for by the memory
+// guard of its source until the invocation's context is done.
+type memoryGuardedTool struct {
+	tools.Tool
+	guard *resultMemoryGuard
+}
+
+func (t memoryGuardedTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t memoryGuardedTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	res, err := t.Tool.Invoke(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute result size: %w", err)
+	}
+	release, err := t.guard.reserve(int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+	// the result is in flight until the invocation has been responded to
+	context.AfterFunc(ctx, release)
+	return res, nil
+}
+
+// toolSource returns the name of the source a tool is configured with, or ""
+// if its kind doesn't use a source.
+func toolSource(tc tools.ToolConfig) string {
+	return tools.SourceName(tc)
+}
+
+// withResultMemoryGuards wraps each tool that uses a source so that the
+// results in flight for each source are limited to ceiling bytes. toolSources
+// maps tool names to the name of their source, and guards holds the existing
+// memory guards by source name, which are reused. The tools are returned
+// unchanged if ceiling is not positive.
+func withResultMemoryGuards(toolsMap map[string]tools.Tool, toolSources map[string]string, guards map[string]*resultMemoryGuard, ceiling int64) map[string]tools.Tool {
+	if ceiling <= 0 {
+		return toolsMap
+	}
+	guards = maps.Clone(guards)
+	if guards == nil {
+		guards = make(map[string]*resultMemoryGuard)
+	}
+	for name, t := range toolsMap {
+		src := toolSources[name]
+		if src == "" {
+			continue
+		}
+		g, ok := guards[src]
+		if !ok {
+			g = &resultMemoryGuard{source: src, ceiling: ceiling}
+			guards[src] = g
+		}
+		toolsMap[name] = memoryGuardedTool{Tool: t, guard: g}
+	}
+	return toolsMap
+}
+
+// resultMemoryGuards returns the memory guards of the tools in toolsMap, by
+// the name of their source.
+func resultMemoryGuards(toolsMap map[string]tools.Tool) map[string]*resultMemoryGuard {
+	guards := make(map[string]*resultMemoryGuard)
+	for _, t := range toolsMap {
+		if g, ok := findWrapper[memoryGuardedTool](t); ok {
+			guards[g.guard.source] = g.guard
+		}
+	}
+	return guards
+}
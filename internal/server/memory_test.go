@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestResultMemoryGuard(t *testing.T) {
+	// each result of 10 rows is 21 bytes once marshaled: [0,1,2,3,4,5,6,7,8,9]
+	toolsMap := withResultMemoryGuards(
+		map[string]tools.Tool{
+			"first":  rowsTool{MockTool: MockTool{Name: "first"}, rows: 10},
+			"second": rowsTool{MockTool: MockTool{Name: "second"}, rows: 10},
+			"other":  rowsTool{MockTool: MockTool{Name: "other"}, rows: 10},
+		},
+		map[string]string{"first": "my-pg", "second": "my-pg", "other": "other-pg"},
+		nil,
+		50,
+	)
+
+	// hold the results of two invocations in flight
+	var cancels []context.CancelFunc
+	for _, name := range []string{"first", "second"} {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cancels = append(cancels, cancel)
+		if _, err := toolsMap[name].Invoke(ctx, nil); err != nil {
+			t.Fatalf("unexpected error invoking %q: %s", name, err)
+		}
+	}
+
+	// a third result would take the source past its ceiling
+	_, err := toolsMap["first"].Invoke(context.Background(), nil)
+	if !errors.Is(err, errResultMemoryExceeded) {
+		t.Fatalf("expected %q, got %v", errResultMemoryExceeded, err)
+	}
+
+	// other sources are accounted for separately
+	if _, err := toolsMap["other"].Invoke(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error invoking a tool of another source: %s", err)
+	}
+
+	// results are released once their invocation is done
+	cancels[0]()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ctx, cancel := context.WithCancel(context.Background())
+		_, err := toolsMap["first"].Invoke(ctx, nil)
+		cancel()
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("result was not released: %s", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// sourceToolConfig is a ToolConfig of a kind that uses a source.
+type sourceToolConfig struct {
+	tools.ToolConfig
+	Source string
+}
+
+func TestToolSource(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   tools.ToolConfig
+		want string
+	}{
+		{
+			desc: "tool with source",
+			in:   sourceToolConfig{Source: "my-pg"},
+			want: "my-pg",
+		},
+		{
+			desc: "tool with options",
+			in: tools.ConfigWithOptions{
+				ToolConfig: sourceToolConfig{Source: "my-pg"},
+				Options:    tools.Options{ExpectedMaxRows: 10},
+			},
+			want: "my-pg",
+		},
+		{
+			desc: "tool without source",
+			in:   tools.ConfigWithOptions{},
+			want: "",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := toolSource(tc.in); got != tc.want {
+				t.Fatalf("unexpected source: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// defaultAllowedMethods are the methods of the invoke route for tools that
+// don't set allowedMethods.
+var defaultAllowedMethods = []string{http.MethodPost}
+
+// allowedMethods returns the HTTP methods tool can be invoked with.
+func allowedMethods(tool tools.Tool) []string {
+	if methods := tools.GetOptions(tool).AllowedMethods; len(methods) > 0 {
+		return methods
+	}
+	return defaultAllowedMethods
+}
+
+// queryArguments returns the invoke arguments of a GET request, which are its
+// query parameters. Values of string parameters are used as is, other values
+// are decoded as JSON, such as `?limit=10&ids=[1,2]`, or used as is if they
+// aren't valid JSON.
+func queryArguments(query url.Values, tool tools.Tool) map[string]any {
+	types := make(map[string]string)
+	for _, p := range tool.Manifest().Parameters {
+		types[p.Name] = p.Type
+	}
+	data := make(map[string]any, len(query))
+	for k := range query {
+		if k == "dryRun" {
+			continue
+		}
+		v := query.Get(k)
+		var decoded any
+		if types[k] != "string" && decodeJSON(strings.NewReader(v), &decoded) == nil {
+			data[k] = decoded
+			continue
+		}
+		data[k] = v
+	}
+	return data
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestToolInvokeAllowedMethods(t *testing.T) {
+	params := tools.Parameters{
+		tools.NewIntParameter("id", "the id"),
+		tools.NewStringParameter("name", "the name"),
+	}
+	toolsMap := map[string]tools.Tool{
+		"get_user": tools.ToolWithOptions{
+			Tool:    MockTool{Name: "get_user", Params: params},
+			Options: tools.Options{AllowedMethods: []string{http.MethodGet, http.MethodPost}},
+		},
+		// write tools keep the default of POST only
+		"delete_user": MockTool{Name: "delete_user", Params: params},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantAllow  string
+	}{
+		{
+			name:       "read tool with GET",
+			method:     http.MethodGet,
+			path:       "/tool/get_user/invoke?id=1&name=42",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "read tool with POST",
+			method:     http.MethodPost,
+			path:       "/tool/get_user/invoke",
+			body:       `{"id": 1, "name": "42"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "read tool with invalid query arguments",
+			method:     http.MethodGet,
+			path:       "/tool/get_user/invoke?id=one&name=42",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "write tool with GET",
+			method:     http.MethodGet,
+			path:       "/tool/delete_user/invoke?id=1&name=42",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantAllow:  "POST",
+		},
+		{
+			name:       "write tool with POST",
+			method:     http.MethodPost,
+			path:       "/tool/delete_user/invoke",
+			body:       `{"id": 1, "name": "42"}`,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, tc.method, tc.path, bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, body)
+			}
+			if got := resp.Header.Get("Allow"); got != tc.wantAllow {
+				t.Fatalf("unexpected Allow header: got %q, want %q", got, tc.wantAllow)
+			}
+			if tc.wantStatus != http.StatusMethodNotAllowed {
+				return
+			}
+			var errResp errResponse
+			if err := json.Unmarshal(body, &errResp); err != nil {
+				t.Fatalf("unable to parse error response: %s", err)
+			}
+			if errResp.ErrorText == "" {
+				t.Fatalf("expected an error message, got %s", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// normalizeToolName returns the name a tool is served under. Names that are
+// not URL-safe are rejected, unless mode is "escape", in which case they are
+// URL-escaped.
+func normalizeToolName(name string, mode toolNameMode) (string, error) {
+	if isValidToolName(name) {
+		return name, nil
+	}
+	if mode.String() == "escape" && name != "" {
+		return url.PathEscape(name), nil
+	}
+	return "", fmt.Errorf("invalid tool name %q: tool names may only contain letters, numbers, underscores and hyphens, optionally followed by \"@<version>\"", name)
+}
+
+// isValidToolName reports whether name is URL-safe, with an optional
+// "@<version>" suffix.
+func isValidToolName(name string) bool {
+	base, version, versioned := strings.Cut(name, "@")
+	if versioned && (version == "" || !tools.IsValidName(version)) {
+		return false
+	}
+	return base != "" && tools.IsValidName(base)
+}
+
+// validate interface
+var _ tools.Tool = renamedTool{}
+
+// renamedTool is a Tool served under a different name than it was configured
+// with.
+type renamedTool struct {
+	tools.Tool
+	name string
+}
+
+func (t renamedTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t renamedTool) McpManifest() tools.McpManifest {
+	m := t.Tool.McpManifest()
+	m.Name = t.name
+	return m
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestNormalizeToolName(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		name    string
+		mode    toolNameMode
+		want    string
+		wantErr bool
+	}{
+		{
+			desc: "valid name",
+			name: "my-tool_1",
+			want: "my-tool_1",
+		},
+		{
+			desc:    "invalid name",
+			name:    "my/tool",
+			wantErr: true,
+		},
+		{
+			desc:    "empty name",
+			name:    "",
+			mode:    "escape",
+			wantErr: true,
+		},
+		{
+			desc: "valid name escape mode",
+			name: "my-tool_1",
+			mode: "escape",
+			want: "my-tool_1",
+		},
+		{
+			desc: "invalid name escape mode",
+			name: "my/tool",
+			mode: "escape",
+			want: "my%2Ftool",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := normalizeToolName(tc.name, tc.mode)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, but got nil")
+				}
+				if !strings.Contains(err.Error(), tc.name) {
+					t.Fatalf("error does not name the offending tool: %s", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected name: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizedToolNameRoutable(t *testing.T) {
+	name, err := normalizeToolName("my/tool", "escape")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	mock := MockTool{Name: "my/tool"}
+	mock.manifest = mock.Manifest()
+	toolsMap := map[string]tools.Tool{name: renamedTool{Tool: mock, name: name}}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	// clients escape the normalized name like any other path segment
+	resp, body, err := runRequest(ts, http.MethodPost, "/tool/"+url.PathEscape(name)+"/invoke", bytes.NewBuffer([]byte(`{}`)))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	if got := toolsMap[name].McpManifest().Name; got != name {
+		t.Fatalf("unexpected mcp manifest name: got %q, want %q", got, name)
+	}
+}
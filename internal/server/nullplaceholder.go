@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// validate interface
+var _ tools.Tool = nullPlaceholderTool{}
+
+// nullPlaceholderTool is a Tool that replaces the NULL column values of its
+// rows with a placeholder.
+type nullPlaceholderTool struct {
+	tools.Tool
+	placeholder string
+}
+
+func (t nullPlaceholderTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t nullPlaceholderTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	res, err := t.Tool.Invoke(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	replaced := make([]any, len(res))
+	for i, row := range res {
+		replaced[i] = replaceNulls(row, t.placeholder)
+	}
+	return replaced, nil
+}
+
+// Stream replaces the NULL values of each streamed row.
+func (t nullPlaceholderTool) Stream() (tools.Streamer, bool) {
+	s, ok := tools.GetStreamer(t.Tool)
+	if !ok {
+		return nil, false
+	}
+	return tools.StreamerFunc(func(ctx context.Context, params tools.ParamValues, yield func(row any) error) error {
+		return s.InvokeStream(ctx, params, func(row any) error {
+			return yield(replaceNulls(row, t.placeholder))
+		})
+	}), true
+}
+
+// replaceNulls returns a copy of row with its nil column values replaced by
+// placeholder. Rows that aren't maps are returned unchanged. The row is
+// copied rather than modified, since it may be shared with the result cache.
+func replaceNulls(row any, placeholder string) any {
+	m, ok := row.(map[string]any)
+	if !ok {
+		return row
+	}
+	replaced := make(map[string]any, len(m))
+	for k, v := range m {
+		if v == nil {
+			v = placeholder
+		}
+		replaced[k] = v
+	}
+	return replaced
+}
+
+// withNullPlaceholders wraps the tools with a nullPlaceholder option so that
+// the NULL values of their results are replaced.
+func withNullPlaceholders(toolsMap map[string]tools.Tool) map[string]tools.Tool {
+	for name, t := range toolsMap {
+		if p := tools.GetOptions(t).NullPlaceholder; p != nil {
+			toolsMap[name] = nullPlaceholderTool{Tool: t, placeholder: *p}
+		}
+	}
+	return toolsMap
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// nullsTool returns rows with NULL columns.
+type nullsTool struct {
+	MockTool
+}
+
+func (t nullsTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+	return []any{
+		map[string]any{"id": 1, "email": nil, "phone": "555-0100"},
+		map[string]any{"id": 2, "email": "bob@example.com", "phone": nil},
+	}, nil
+}
+
+func TestToolInvokeNullPlaceholder(t *testing.T) {
+	placeholder := "N/A"
+	empty := ""
+	tcs := []struct {
+		name        string
+		placeholder *string
+		want        []map[string]any
+	}{
+		{
+			name: "no placeholder",
+			want: []map[string]any{
+				{"id": float64(1), "email": nil, "phone": "555-0100"},
+				{"id": float64(2), "email": "bob@example.com", "phone": nil},
+			},
+		},
+		{
+			name:        "placeholder",
+			placeholder: &placeholder,
+			want: []map[string]any{
+				{"id": float64(1), "email": "N/A", "phone": "555-0100"},
+				{"id": float64(2), "email": "bob@example.com", "phone": "N/A"},
+			},
+		},
+		{
+			name:        "empty placeholder",
+			placeholder: &empty,
+			want: []map[string]any{
+				{"id": float64(1), "email": "", "phone": "555-0100"},
+				{"id": float64(2), "email": "bob@example.com", "phone": ""},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tool := tools.ToolWithOptions{
+				Tool:    nullsTool{MockTool: MockTool{Name: "contacts"}},
+				Options: tools.Options{NullPlaceholder: tc.placeholder},
+			}
+			toolsMap := withNullPlaceholders(map[string]tools.Tool{"contacts": tool})
+			r, shutdown := setUpServer(t, "api", toolsMap, nil)
+			defer shutdown()
+			srv := runServer(r, false)
+			defer srv.Close()
+
+			resp, body, err := runRequest(srv, http.MethodPost, "/tool/contacts/invoke", bytes.NewBufferString(`{}`))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(body))
+			}
+			var got resultResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			var rows []map[string]any
+			if err := json.Unmarshal([]byte(got.Result), &rows); err != nil {
+				t.Fatalf("unable to parse result: %s", err)
+			}
+			if !reflect.DeepEqual(rows, tc.want) {
+				t.Fatalf("unexpected result: got %v, want %v", rows, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	logsdk "go.opentelemetry.io/otel/sdk/log"
+)
+
+// logRecorder is a log exporter that keeps the records it exports.
+type logRecorder struct {
+	mu      sync.Mutex
+	records []logsdk.Record
+}
+
+func (e *logRecorder) Export(_ context.Context, records []logsdk.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *logRecorder) Shutdown(context.Context) error { return nil }
+
+func (e *logRecorder) ForceFlush(context.Context) error { return nil }
+
+func TestToolInvokeExportsLogs(t *testing.T) {
+	exporter := &logRecorder{}
+	provider := logsdk.NewLoggerProvider(logsdk.WithProcessor(logsdk.NewSimpleProcessor(exporter)))
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	toolsMap := map[string]tools.Tool{"no_params": tool1}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil, func(s *Server) {
+		logger, err := log.NewOTelLogger(s.logger, provider, "debug")
+		if err != nil {
+			t.Fatalf("unable to initialize logger: %s", err)
+		}
+		s.logger = logger
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, "/tool/no_params/invoke", bytes.NewBufferString(`{}`))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, body)
+	}
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	for _, rec := range exporter.records {
+		if rec.Body().AsString() != "tool name: no_params" {
+			continue
+		}
+		if !rec.TraceID().IsValid() {
+			t.Fatalf("log record is not correlated with the invocation's trace")
+		}
+		if got := rec.SeverityText(); got != "DEBUG" {
+			t.Fatalf("unexpected severity: got %q, want %q", got, "DEBUG")
+		}
+		return
+	}
+	t.Fatalf("no log record was exported for the invocation, got %d records", len(exporter.records))
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const (
+	limitParam  = "limit"
+	offsetParam = "offset"
+	// defaultPageLimit is the number of rows of a page without a limit.
+	defaultPageLimit = 50
+	// maxPageLimit is the largest limit a caller can request.
+	maxPageLimit = 1000
+)
+
+// errPageFull stops a stream once the row after the page was seen.
+var errPageFull = errors.New("page is full")
+
+// validate interface
+var _ tools.Tool = paginatedTool{}
+
+// paginatedTool is a Tool that takes limit and offset parameters, and returns
+// a single row with a page of the rows of the wrapped tool:
+//
+//	{"rows": [...], "limit": 50, "offset": 0, "hasMore": true}
+//
+// total, the number of rows of the wrapped tool, is added once it is known,
+// which is when the page is the last one or the rows were not streamed.
+type paginatedTool struct {
+	tools.Tool
+	params tools.Parameters
+}
+
+func newPaginatedTool(t tools.Tool) paginatedTool {
+	minLimit, maxLimit, minOffset := 1, maxPageLimit, 0
+	limit := tools.NewIntParameter(limitParam, fmt.Sprintf("The maximum number of rows to return, at most %d.", maxPageLimit))
+	limit.Default, limit.Min, limit.Max = defaultPageLimit, &minLimit, &maxLimit
+	offset := tools.NewIntParameter(offsetParam, "The number of rows to skip.")
+	offset.Default, offset.Min = 0, &minOffset
+	return paginatedTool{Tool: t, params: tools.Parameters{limit, offset}}
+}
+
+func (t paginatedTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t paginatedTool) ParseParams(ctx context.Context, data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	rest := maps.Clone(data)
+	delete(rest, limitParam)
+	delete(rest, offsetParam)
+	params, err := t.Tool.ParseParams(ctx, rest, claims)
+	if err != nil {
+		return nil, err
+	}
+	page, err := tools.ParseParams(ctx, t.params, data, claims)
+	if err != nil {
+		return nil, err
+	}
+	return append(params, page...), nil
+}
+
+func (t paginatedTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	var limit, offset int
+	var rest tools.ParamValues
+	for _, p := range params {
+		switch p.Name {
+		case limitParam:
+			limit, _ = p.Value.(int)
+		case offsetParam:
+			offset, _ = p.Value.(int)
+		default:
+			rest = append(rest, p)
+		}
+	}
+	rows, hasMore, total, err := t.page(ctx, rest, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	res := map[string]any{
+		"rows":    rows,
+		"limit":   limit,
+		"offset":  offset,
+		"hasMore": hasMore,
+	}
+	if total >= 0 {
+		res["total"] = total
+	}
+	return []any{res}, nil
+}
+
+// page returns the rows of the page, whether more rows follow, and the total
+// number of rows or -1 if it isn't known. Rows are streamed when possible, so
+// that the invocation stops at the first row after the page. Cached tools are
+// invoked instead, so that their pages are served from the cache.
+func (t paginatedTool) page(ctx context.Context, params tools.ParamValues, limit, offset int) ([]any, bool, int, error) {
+	rows := []any{}
+	s, ok := tools.GetStreamer(t.Tool)
+	if !ok || tools.GetOptions(t.Tool).CacheTTL > 0 {
+		res, err := t.Tool.Invoke(ctx, params)
+		if err != nil {
+			return nil, false, 0, err
+		}
+		if offset < len(res) {
+			rows = append(rows, res[offset:min(offset+limit, len(res))]...)
+		}
+		return rows, offset+limit < len(res), len(res), nil
+	}
+	seen, hasMore := 0, false
+	err := s.InvokeStream(ctx, params, func(row any) error {
+		seen++
+		if seen <= offset {
+			return nil
+		}
+		if len(rows) == limit {
+			hasMore = true
+			return errPageFull
+		}
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errPageFull) {
+		return nil, false, 0, err
+	}
+	if hasMore {
+		return rows, true, -1, nil
+	}
+	return rows, false, seen, nil
+}
+
+// Stream returns false, since a page is returned as a single row.
+func (t paginatedTool) Stream() (tools.Streamer, bool) {
+	return nil, false
+}
+
+func (t paginatedTool) Manifest() tools.Manifest {
+	m := t.Tool.Manifest()
+	m.Parameters = slices.Clone(m.Parameters)
+	for _, p := range t.params {
+		m.Parameters = append(m.Parameters, p.Manifest())
+	}
+	return m
+}
+
+func (t paginatedTool) McpManifest() tools.McpManifest {
+	m := t.Tool.McpManifest()
+	m.InputSchema.Properties = maps.Clone(m.InputSchema.Properties)
+	if m.InputSchema.Properties == nil {
+		m.InputSchema.Properties = make(map[string]tools.ParameterMcpManifest)
+	}
+	for _, p := range t.params {
+		m.InputSchema.Properties[p.GetName()] = p.McpManifest()
+	}
+	return m
+}
+
+// withPagination wraps the tools with pagination enabled. It wraps all other
+// tools, so that the page is taken of the final rows.
+func withPagination(toolsMap map[string]tools.Tool) (map[string]tools.Tool, error) {
+	for name, t := range toolsMap {
+		opts := tools.GetOptions(t)
+		if !opts.Paginated {
+			continue
+		}
+		if opts.ResultMode == tools.ResultModeScalar {
+			return nil, fmt.Errorf("tool %q can't be paginated with resultMode %q", name, tools.ResultModeScalar)
+		}
+		for _, p := range t.Manifest().Parameters {
+			if p.Name == limitParam || p.Name == offsetParam {
+				return nil, fmt.Errorf("tool %q can't be paginated, since it has a %q parameter", name, p.Name)
+			}
+		}
+		toolsMap[name] = newPaginatedTool(t)
+	}
+	return toolsMap, nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// numbersTool returns the rows 1 to n, without streaming them.
+type numbersTool struct {
+	MockTool
+	n int
+}
+
+func (t numbersTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+	rows := make([]any, t.n)
+	for i := range rows {
+		rows[i] = map[string]any{"id": i + 1}
+	}
+	return rows, nil
+}
+
+func TestToolInvokePaginated(t *testing.T) {
+	var streamed []any
+	for i := 1; i <= 5; i++ {
+		streamed = append(streamed, map[string]any{"id": i})
+	}
+	paginated := tools.Options{Paginated: true}
+	toolsMap, err := withPagination(map[string]tools.Tool{
+		"streamed": tools.ToolWithOptions{
+			Tool:    streamTool{MockTool: MockTool{Name: "streamed"}, rows: streamed},
+			Options: paginated,
+		},
+		"invoked": tools.ToolWithOptions{
+			Tool:    numbersTool{MockTool: MockTool{Name: "invoked"}, n: 5},
+			Options: paginated,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to set up pagination: %s", err)
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	ids := func(ids ...float64) []any {
+		rows := []any{}
+		for _, id := range ids {
+			rows = append(rows, map[string]any{"id": id})
+		}
+		return rows
+	}
+	tcs := []struct {
+		name string
+		body string
+		want map[string]any
+		// last is whether the page is the last one. Only the last page of a
+		// streamed tool has a total, pages of invoked tools always have one.
+		last bool
+	}{
+		{
+			name: "first page",
+			body: `{"limit": 2}`,
+			want: map[string]any{"rows": ids(1, 2), "limit": 2.0, "offset": 0.0, "hasMore": true},
+		},
+		{
+			name: "middle page",
+			body: `{"limit": 2, "offset": 2}`,
+			want: map[string]any{"rows": ids(3, 4), "limit": 2.0, "offset": 2.0, "hasMore": true},
+		},
+		{
+			name: "last page",
+			body: `{"limit": 2, "offset": 4}`,
+			want: map[string]any{"rows": ids(5), "limit": 2.0, "offset": 4.0, "hasMore": false},
+			last: true,
+		},
+		{
+			name: "exact last page",
+			body: `{"limit": 5}`,
+			want: map[string]any{"rows": ids(1, 2, 3, 4, 5), "limit": 5.0, "offset": 0.0, "hasMore": false},
+			last: true,
+		},
+		{
+			name: "past the end",
+			body: `{"offset": 10}`,
+			want: map[string]any{"rows": ids(), "limit": 50.0, "offset": 10.0, "hasMore": false},
+			last: true,
+		},
+	}
+	for _, tool := range []string{"streamed", "invoked"} {
+		for _, tc := range tcs {
+			t.Run(tool+" "+tc.name, func(t *testing.T) {
+				resp, body, err := runRequest(ts, http.MethodPost, "/tool/"+tool+"/invoke", bytes.NewBufferString(tc.body))
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				if resp.StatusCode != http.StatusOK {
+					t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, body)
+				}
+				var got resultResponse
+				if err := json.Unmarshal(body, &got); err != nil {
+					t.Fatalf("unable to parse response body: %s", err)
+				}
+				var page []map[string]any
+				if err := json.Unmarshal([]byte(got.Result), &page); err != nil {
+					t.Fatalf("unable to parse result: %s", err)
+				}
+				want := map[string]any{}
+				for k, v := range tc.want {
+					want[k] = v
+				}
+				if tc.last || tool == "invoked" {
+					want["total"] = 5.0
+				}
+				if len(page) != 1 || !reflect.DeepEqual(page[0], want) {
+					t.Fatalf("unexpected page: got %v, want %v", page, want)
+				}
+			})
+		}
+	}
+
+	// the limit is bounded
+	resp, body, err := runRequest(ts, http.MethodPost, "/tool/streamed/invoke", bytes.NewBufferString(`{"limit": 0}`))
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected a 400 for a limit of 0, got %d: %s", resp.StatusCode, body)
+	}
+}
+
+func TestWithPaginationConflicts(t *testing.T) {
+	tcs := []struct {
+		name string
+		tool tools.Tool
+	}{
+		{
+			name: "limit parameter",
+			tool: tools.ToolWithOptions{
+				Tool: MockTool{Name: "conflict", Params: []tools.Parameter{
+					tools.NewIntParameter("limit", "the limit"),
+				}},
+				Options: tools.Options{Paginated: true},
+			},
+		},
+		{
+			name: "scalar result",
+			tool: tools.ToolWithOptions{
+				Tool:    MockTool{Name: "conflict"},
+				Options: tools.Options{Paginated: true, ResultMode: tools.ResultModeScalar},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := withPagination(map[string]tools.Tool{"conflict": tc.tool}); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
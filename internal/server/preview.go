@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const (
+	// previewArg is the reserved invoke argument used to request a preview.
+	previewArg = "_preview"
+	// defaultPreviewRows is the number of rows returned by `"_preview": true`.
+	defaultPreviewRows = 10
+)
+
+// splitPreview removes the reserved preview argument from data, and returns
+// the number of rows the preview is capped to. 0 means no preview was
+// requested. The argument may either be a boolean, or the number of rows.
+func splitPreview(data map[string]any) (int, error) {
+	v, ok := data[previewArg]
+	if !ok {
+		return 0, nil
+	}
+	delete(data, previewArg)
+
+	var rows int64
+	switch v := v.(type) {
+	case bool:
+		if v {
+			rows = defaultPreviewRows
+		}
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return 0, fmt.Errorf("%q must be a boolean or an integer: %w", previewArg, err)
+		}
+		rows = n
+	default:
+		return 0, fmt.Errorf("%q must be a boolean or an integer", previewArg)
+	}
+	if rows < 0 {
+		return 0, fmt.Errorf("%q must not be negative", previewArg)
+	}
+	return int(rows), nil
+}
+
+// validate interface
+var _ tools.Tool = &previewTool{}
+
+// previewTool is a Tool whose results are capped to a number of rows. It is
+// created for a single invocation.
+type previewTool struct {
+	tools.Tool
+	rows int
+	// truncated is whether rows were dropped from the result
+	truncated bool
+}
+
+func (t *previewTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t *previewTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	res, err := t.Tool.Invoke(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) > t.rows {
+		res = res[:t.rows]
+		t.truncated = true
+	}
+	return res, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// rowsTool returns a fixed number of rows.
+type rowsTool struct {
+	MockTool
+	rows int
+}
+
+func (t rowsTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+	res := make([]any, t.rows)
+	for i := range res {
+		res[i] = i
+	}
+	return res, nil
+}
+
+func TestToolInvokePreview(t *testing.T) {
+	tool := rowsTool{MockTool: MockTool{Name: "many_rows"}, rows: 50}
+	toolsMap := map[string]tools.Tool{tool.Name: tool}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name        string
+		requestBody string
+		wantRows    int
+		wantPreview bool
+		isErr       bool
+	}{
+		{
+			name:        "no preview",
+			requestBody: `{}`,
+			wantRows:    50,
+		},
+		{
+			name:        "default preview",
+			requestBody: `{"_preview": true}`,
+			wantRows:    defaultPreviewRows,
+			wantPreview: true,
+		},
+		{
+			name:        "preview with row count",
+			requestBody: `{"_preview": 3}`,
+			wantRows:    3,
+			wantPreview: true,
+		},
+		{
+			name:        "preview disabled",
+			requestBody: `{"_preview": false}`,
+			wantRows:    50,
+		},
+		{
+			name:        "invalid preview",
+			requestBody: `{"_preview": "yes"}`,
+			isErr:       true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, "/tool/many_rows/invoke", bytes.NewBufferString(tc.requestBody))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if tc.isErr {
+				if resp.StatusCode != http.StatusBadRequest {
+					t.Fatalf("unexpected status code: want %d, got %d", http.StatusBadRequest, resp.StatusCode)
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(body))
+			}
+
+			var got resultResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			if got.Preview != tc.wantPreview {
+				t.Fatalf("unexpected preview flag: got %t, want %t", got.Preview, tc.wantPreview)
+			}
+			var rows []any
+			if err := json.Unmarshal([]byte(got.Result), &rows); err != nil {
+				t.Fatalf("unable to parse result: %s", err)
+			}
+			if len(rows) != tc.wantRows {
+				t.Fatalf("unexpected number of rows: got %d, want %d", len(rows), tc.wantRows)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+	"golang.org/x/time/rate"
+)
+
+// rateLimiter limits the rate of requests of each client with a token
+// bucket. Clients are identified by the value of a header, such as one set by
+// an authenticating proxy, or by their IP address.
+type rateLimiter struct {
+	limit  rate.Limit
+	burst  int
+	header string
+
+	mu      sync.Mutex
+	clients map[string]*clientBucket
+	// lastSweep is when idle clients were last removed
+	lastSweep time.Time
+}
+
+type clientBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// newRateLimiter returns a rateLimiter that allows each client perSecond
+// requests per second, in bursts of up to burst requests. burst defaults to
+// perSecond, rounded up. A nil limiter, which allows all requests, is
+// returned if perSecond is not positive.
+func newRateLimiter(perSecond float64, burst int, header string) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(perSecond))
+	}
+	return &rateLimiter{
+		limit:   rate.Limit(perSecond),
+		burst:   burst,
+		header:  header,
+		clients: make(map[string]*clientBucket),
+	}
+}
+
+// clientKey returns the key of the client that sent r.
+func (l *rateLimiter) clientKey(r *http.Request) string {
+	if l.header != "" {
+		if v := r.Header.Get(l.header); v != "" {
+			return "header:" + v
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// allow takes a token from the bucket of the client with the given key. If
+// the bucket is empty, it returns false and how long until a token is
+// available.
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+	c, ok := l.clients[key]
+	if !ok {
+		c = &clientBucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	res := c.limiter.ReserveN(now, 1)
+	if d := res.DelayFrom(now); d > 0 {
+		// the request is rejected, so it doesn't use up the token
+		res.CancelAt(now)
+		return false, d
+	}
+	return true, 0
+}
+
+// sweep removes the clients that have been idle for long enough for their
+// bucket to be full again, since their buckets are the same as new ones.
+func (l *rateLimiter) sweep(now time.Time) {
+	refill := time.Duration(float64(l.burst) / float64(l.limit) * float64(time.Second))
+	if now.Sub(l.lastSweep) < refill {
+		return
+	}
+	for key, c := range l.clients {
+		if now.Sub(c.lastSeen) >= refill {
+			delete(l.clients, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// limitRate is a middleware that responds with a 429 to the requests of
+// clients that exceed the rate limit of the server, along with a Retry-After
+// header with the number of seconds until they may retry.
+func limitRate(s *Server) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if s.rateLimiter == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ok, wait := s.rateLimiter.allow(s.rateLimiter.clientKey(r), time.Now())
+			if ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			err := fmt.Errorf("rate limit exceeded, retry after %d seconds", retryAfter)
+			s.logger.DebugContext(r.Context(), err.Error())
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			errResp := newErrResponse(err, http.StatusTooManyRequests)
+			errResp.Details = map[string]any{"retryAfterSeconds": retryAfter}
+			_ = render.Render(w, r, errResp)
+		})
+	}
+}
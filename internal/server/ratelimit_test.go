@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestRateLimit(t *testing.T) {
+	const burst = 3
+	toolsMap := map[string]tools.Tool{"my_tool": MockTool{Name: "my_tool"}}
+
+	send := func(t *testing.T, url, method, client, body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if client != "" {
+			req.Header.Set("X-Client-Id", client)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	tcs := []struct {
+		name   string
+		router string
+		method string
+		path   string
+		body   string
+	}{
+		{
+			name:   "api invoke",
+			router: "api",
+			method: http.MethodPost,
+			path:   "/tool/my_tool/invoke",
+			body:   `{}`,
+		},
+		{
+			name:   "mcp",
+			router: "mcp",
+			method: http.MethodPost,
+			path:   "/",
+			body:   `{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r, shutdown := setUpServer(t, tc.router, toolsMap, nil, func(s *Server) {
+				// slow enough that no token is added back during the test
+				s.rateLimiter = newRateLimiter(0.01, burst, "X-Client-Id")
+			})
+			defer shutdown()
+			ts := runServer(r, false)
+			defer ts.Close()
+
+			for i := 0; i < burst; i++ {
+				if resp := send(t, ts.URL+tc.path, tc.method, "agent-1", tc.body); resp.StatusCode == http.StatusTooManyRequests {
+					t.Fatalf("request %d was rate limited", i+1)
+				}
+			}
+			resp := send(t, ts.URL+tc.path, tc.method, "agent-1", tc.body)
+			if resp.StatusCode != http.StatusTooManyRequests {
+				t.Fatalf("unexpected status code for request %d: got %d, want %d", burst+1, resp.StatusCode, http.StatusTooManyRequests)
+			}
+			if got := resp.Header.Get("Retry-After"); got != "100" {
+				t.Fatalf("unexpected Retry-After header: got %q, want %q", got, "100")
+			}
+
+			// other clients have their own bucket
+			if resp := send(t, ts.URL+tc.path, tc.method, "agent-2", tc.body); resp.StatusCode == http.StatusTooManyRequests {
+				t.Fatalf("request of another client was rate limited")
+			}
+			if tc.router == "api" {
+				if resp := send(t, ts.URL+"/health", http.MethodGet, "agent-1", ""); resp.StatusCode != http.StatusOK {
+					t.Fatalf("unexpected status code for the health check: got %d, want %d", resp.StatusCode, http.StatusOK)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterRefills(t *testing.T) {
+	l := newRateLimiter(2, 0, "")
+	if l.burst != 2 {
+		t.Fatalf("unexpected default burst: got %d, want 2", l.burst)
+	}
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.allow("ip:127.0.0.1", now); !ok {
+			t.Fatalf("request %d was rate limited", i+1)
+		}
+	}
+	ok, wait := l.allow("ip:127.0.0.1", now)
+	if ok || wait != 500*time.Millisecond {
+		t.Fatalf("unexpected result of request 3: got %t and %s, want false and 500ms", ok, wait)
+	}
+	if ok, _ := l.allow("ip:127.0.0.1", now.Add(500*time.Millisecond)); !ok {
+		t.Fatalf("request was rate limited after the bucket refilled")
+	}
+	if ok, _ := l.allow("ip:127.0.0.2", now.Add(2*time.Second)); !ok || len(l.clients) != 1 {
+		t.Fatalf("expected the idle client to be removed, got %d clients", len(l.clients))
+	}
+
+	if newRateLimiter(0, 10, "") != nil {
+		t.Fatalf("expected no limiter when the rate is 0")
+	}
+}
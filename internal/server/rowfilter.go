@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// filterArg is the reserved invoke argument used to filter the result rows.
+const filterArg = "_filter"
+
+// maxFilterLength is the maximum length in bytes of the filter of an
+// invocation, which any caller can send.
+const maxFilterLength = 4096
+
+type rowFilterKey struct{}
+
+// withInvokeRowFilter returns a context carrying the row filter of an
+// invocation.
+func withInvokeRowFilter(ctx context.Context, f *tools.RowFilter) context.Context {
+	if f == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, rowFilterKey{}, f)
+}
+
+// invokeRowFilter returns the row filter of the invocation of ctx, if any.
+func invokeRowFilter(ctx context.Context) *tools.RowFilter {
+	f, _ := ctx.Value(rowFilterKey{}).(*tools.RowFilter)
+	return f
+}
+
+// splitRowFilter removes the reserved filter argument from data, and returns
+// it compiled, or nil if no filter was requested.
+func splitRowFilter(data map[string]any, t tools.Tool) (*tools.RowFilter, error) {
+	v, ok := data[filterArg]
+	if !ok {
+		return nil, nil
+	}
+	delete(data, filterArg)
+	src, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("%q must be a string", filterArg)
+	}
+	if len(src) > maxFilterLength {
+		return nil, fmt.Errorf("%q must be at most %d bytes long", filterArg, maxFilterLength)
+	}
+	if tools.GetOptions(t).ResultMode == tools.ResultModeScalar {
+		return nil, fmt.Errorf("%q is not supported by tools with resultMode %q", filterArg, tools.ResultModeScalar)
+	}
+	return tools.CompileRowFilter(src)
+}
+
+// validate interface
+var _ tools.Tool = rowFilterTool{}
+
+// rowFilterTool is a Tool whose result rows are dropped unless they satisfy
+// its filter, if any, and the filter of the invocation, if any.
+type rowFilterTool struct {
+	tools.Tool
+	filter *tools.RowFilter
+}
+
+func (t rowFilterTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+// filters returns the filters that apply to the invocation of ctx.
+func (t rowFilterTool) filters(ctx context.Context) []*tools.RowFilter {
+	var filters []*tools.RowFilter
+	if t.filter != nil {
+		filters = append(filters, t.filter)
+	}
+	if f := invokeRowFilter(ctx); f != nil {
+		filters = append(filters, f)
+	}
+	return filters
+}
+
+func matchRow(filters []*tools.RowFilter, row any) bool {
+	for _, f := range filters {
+		if !f.Match(row) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t rowFilterTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	res, err := t.Tool.Invoke(ctx, params)
+	filters := t.filters(ctx)
+	if err != nil || len(filters) == 0 {
+		return res, err
+	}
+	// the rows may be shared with the result cache, so a new slice is built
+	filtered := make([]any, 0, len(res))
+	for _, row := range res {
+		if matchRow(filters, row) {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered, nil
+}
+
+// Stream drops the streamed rows that don't match.
+func (t rowFilterTool) Stream() (tools.Streamer, bool) {
+	s, ok := tools.GetStreamer(t.Tool)
+	if !ok {
+		return nil, false
+	}
+	return tools.StreamerFunc(func(ctx context.Context, params tools.ParamValues, yield func(row any) error) error {
+		filters := t.filters(ctx)
+		if len(filters) == 0 {
+			return s.InvokeStream(ctx, params, yield)
+		}
+		return s.InvokeStream(ctx, params, func(row any) error {
+			if !matchRow(filters, row) {
+				return nil
+			}
+			return yield(row)
+		})
+	}), true
+}
+
+// withRowFilters wraps all tools so that their rows can be filtered by the
+// rowFilter of the tool or of an invocation. It must wrap withColumnAuth, so
+// that rows can't be filtered by columns the caller isn't allowed to see.
+func withRowFilters(toolsMap map[string]tools.Tool) (map[string]tools.Tool, error) {
+	for name, t := range toolsMap {
+		var filter *tools.RowFilter
+		if opts := tools.GetOptions(t); opts.RowFilter != "" {
+			if opts.ResultMode == tools.ResultModeScalar {
+				return nil, fmt.Errorf("tool %q can't have a rowFilter with resultMode %q", name, tools.ResultModeScalar)
+			}
+			var err error
+			if filter, err = tools.CompileRowFilter(opts.RowFilter); err != nil {
+				return nil, fmt.Errorf("tool %q: %w", name, err)
+			}
+		}
+		toolsMap[name] = rowFilterTool{Tool: t, filter: filter}
+	}
+	return toolsMap, nil
+}
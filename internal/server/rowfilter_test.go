@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// ordersTool returns orders with a margin computed from their revenue and
+// cost, as a query would.
+type ordersTool struct {
+	MockTool
+}
+
+func (t ordersTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+	var rows []any
+	for i, o := range []struct{ revenue, cost int64 }{{500, 450}, {800, 600}, {300, 100}} {
+		rows = append(rows, map[string]any{"id": i + 1, "margin": o.revenue - o.cost})
+	}
+	return rows, nil
+}
+
+func TestToolInvokeRowFilter(t *testing.T) {
+	toolsMap, err := withRowFilters(map[string]tools.Tool{
+		"orders": ordersTool{MockTool: MockTool{Name: "orders"}},
+		"profitable_orders": tools.ToolWithOptions{
+			Tool:    ordersTool{MockTool: MockTool{Name: "profitable_orders"}},
+			Options: tools.Options{RowFilter: "margin >= 200"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to set up row filters: %s", err)
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name     string
+		toolName string
+		body     string
+		wantIDs  []float64
+	}{
+		{
+			name:     "no filter",
+			toolName: "orders",
+			body:     `{}`,
+			wantIDs:  []float64{1, 2, 3},
+		},
+		{
+			name:     "invocation filter",
+			toolName: "orders",
+			body:     `{"_filter": "margin > 100"}`,
+			wantIDs:  []float64{2, 3},
+		},
+		{
+			name:     "tool filter",
+			toolName: "profitable_orders",
+			body:     `{}`,
+			wantIDs:  []float64{2, 3},
+		},
+		{
+			name:     "tool and invocation filters",
+			toolName: "profitable_orders",
+			body:     `{"_filter": "id != 2"}`,
+			wantIDs:  []float64{3},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, "/tool/"+tc.toolName+"/invoke", bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, body)
+			}
+			var got resultResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			var rows []map[string]any
+			if err := json.Unmarshal([]byte(got.Result), &rows); err != nil {
+				t.Fatalf("unable to parse result: %s", err)
+			}
+			ids := []float64{}
+			for _, row := range rows {
+				ids = append(ids, row["id"].(float64))
+			}
+			if !reflect.DeepEqual(ids, tc.wantIDs) {
+				t.Fatalf("unexpected rows: got ids %v, want %v", ids, tc.wantIDs)
+			}
+		})
+	}
+
+	// invalid, deeply nested and overlong filters are rejected
+	for _, filter := range []string{
+		"margin >",
+		strings.Repeat("(", 2000) + "margin > 1" + strings.Repeat(")", 2000),
+		strings.Repeat("(", 1000000) + "margin > 1",
+	} {
+		reqBody, err := json.Marshal(map[string]any{"_filter": filter})
+		if err != nil {
+			t.Fatalf("unable to marshal request body: %s", err)
+		}
+		resp, body, err := runRequest(ts, http.MethodPost, "/tool/orders/invoke", bytes.NewBuffer(reqBody))
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected a 400 for an invalid filter, got %d: %.200s", resp.StatusCode, body)
+		}
+	}
+}
+
+func TestWithRowFiltersConflicts(t *testing.T) {
+	tcs := []struct {
+		name string
+		opts tools.Options
+	}{
+		{
+			name: "invalid filter",
+			opts: tools.Options{RowFilter: "margin >"},
+		},
+		{
+			name: "scalar result",
+			opts: tools.Options{RowFilter: "margin > 100", ResultMode: tools.ResultModeScalar},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tool := tools.ToolWithOptions{Tool: MockTool{Name: "conflict"}, Options: tc.opts}
+			if _, err := withRowFilters(map[string]tools.Tool{"conflict": tool}); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
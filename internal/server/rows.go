@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// validate interface
+var _ tools.Tool = rowWarningTool{}
+
+// rowWarningTool is a Tool that warns when it returns more rows than
+// expected. Results are never truncated.
+type rowWarningTool struct {
+	tools.Tool
+	name     string
+	expected int
+	logger   log.Logger
+	exceeded metric.Int64Counter
+}
+
+func (t rowWarningTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t rowWarningTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	res, err := t.Tool.Invoke(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) > t.expected {
+		t.logger.WarnContext(ctx, fmt.Sprintf("tool %q returned %d rows, more than the expected %d", t.name, len(res), t.expected))
+		t.exceeded.Add(ctx, 1, metric.WithAttributes(attribute.String("toolbox.name", t.name)))
+	}
+	return res, nil
+}
+
+// withRowWarnings wraps the tools that declare expectedMaxRows.
+func withRowWarnings(toolsMap map[string]tools.Tool, l log.Logger, exceeded metric.Int64Counter) map[string]tools.Tool {
+	for name, t := range toolsMap {
+		expected := tools.GetOptions(t).ExpectedMaxRows
+		if expected <= 0 {
+			continue
+		}
+		toolsMap[name] = rowWarningTool{Tool: t, name: name, expected: expected, logger: l, exceeded: exceeded}
+	}
+	return toolsMap
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRowWarnings(t *testing.T) {
+	ctx := context.Background()
+	var logs bytes.Buffer
+	logger, err := log.NewStdLogger(io.Discard, &logs, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+	exceeded, err := meter.Int64Counter(toolRowsExceededName)
+	if err != nil {
+		t.Fatalf("unable to create counter: %s", err)
+	}
+
+	toolsMap := withRowWarnings(map[string]tools.Tool{
+		"many_rows": tools.ToolWithOptions{
+			Tool:    rowsTool{MockTool: MockTool{Name: "many_rows"}, rows: 50},
+			Options: tools.Options{ExpectedMaxRows: 10},
+		},
+		"few_rows": tools.ToolWithOptions{
+			Tool:    rowsTool{MockTool: MockTool{Name: "few_rows"}, rows: 5},
+			Options: tools.Options{ExpectedMaxRows: 10},
+		},
+	}, logger, exceeded)
+	exceededCount := func() int64 {
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(ctx, &rm); err != nil {
+			t.Fatalf("unable to collect metrics: %s", err)
+		}
+		var count int64
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == toolRowsExceededName {
+					for _, dp := range sum.DataPoints {
+						count += dp.Value
+					}
+				}
+			}
+		}
+		return count
+	}
+
+	res, err := toolsMap["few_rows"].Invoke(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(res) != 5 {
+		t.Fatalf("unexpected number of rows: got %d, want %d", len(res), 5)
+	}
+	if logs.Len() != 0 || exceededCount() != 0 {
+		t.Fatalf("unexpected warning for tool within expected rows: %s", logs.String())
+	}
+
+	res, err = toolsMap["many_rows"].Invoke(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(res) != 50 {
+		t.Fatalf("results should not be truncated: got %d rows, want %d", len(res), 50)
+	}
+	if want := "returned 50 rows, more than the expected 10"; !strings.Contains(logs.String(), want) {
+		t.Fatalf("expected warning %q, got %q", want, logs.String())
+	}
+	if got := exceededCount(); got != 1 {
+		t.Fatalf("unexpected %s count: got %d, want %d", toolRowsExceededName, got, 1)
+	}
+}
@@ -16,11 +16,14 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,6 +35,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -39,17 +43,83 @@ import (
 // Server contains info for running an instance of Toolbox. Should be instantiated with NewServer().
 type Server struct {
 	version         string
+	buildCommit     string
+	buildDate       string
 	srv             *http.Server
 	listener        net.Listener
 	root            chi.Router
 	logger          log.Logger
 	instrumentation *Instrumentation
 	sseManager      *sseManager
+	// startTime is when the server was created
+	startTime time.Time
+	// invocationLimiter bounds concurrent tool invocations, nil if unbounded
+	invocationLimiter *invocationLimiter
+	// rateLimiter limits the rate of requests of each client, nil if unlimited
+	rateLimiter *rateLimiter
+	// maxUploadSize is the maximum size in bytes of multipart invoke requests
+	maxUploadSize int64
+	// maxRequestBytes is the maximum size in bytes of the bodies of other
+	// invoke requests and of MCP requests. There is no limit if it is not
+	// positive.
+	maxRequestBytes int64
+	// compressMinBytes is the minimum size in bytes of the responses that
+	// are compressed, which are never compressed if it is negative
+	compressMinBytes int
+	// resourcePollInterval is how often subscribed resources are polled
+	resourcePollInterval time.Duration
+	// health holds the results of the background health checks of the
+	// sources, nil if sources are checked on each health request
+	health *healthCache
+	// adminAuth is the claim that callers of the admin endpoints need, which
+	// is not checked if its auth service is empty
+	adminAuth tools.ColumnAuth
+	// adminToken is a bearer token that grants access to the admin
+	// endpoints, which are disabled if both it and adminAuth are empty
+	adminToken string
+	// disabledTools are the tools disabled through the admin endpoints
+	disabledTools disabledTools
+	// invocations are the in-flight invocations of the API, by the ID they
+	// can be canceled by
+	invocations invocations
+	// mcpToolCallTimeout bounds MCP tool calls to tools without a timeout of
+	// their own, no bound if 0
+	mcpToolCallTimeout time.Duration
+	// toolsListPageSize is the maximum number of tools returned by each MCP
+	// tools/list request, unbounded if 0
+	toolsListPageSize int
+	// executionMetadata adds the duration, row count and truncation of each
+	// invocation to the metadata of its result
+	executionMetadata bool
+	// argumentsKey is the key invoke arguments are nested under, top level if empty
+	argumentsKey string
+	// stdioFraming is how MCP messages are delimited over stdio
+	stdioFraming stdioFraming
+	// shutdownGracePeriod bounds how long Shutdown waits for sessions and
+	// connections to close, no bound if 0
+	shutdownGracePeriod time.Duration
+	// shutdown is closed when the server starts shutting down
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+	// stdioSessions tracks the stdio sessions that are being served
+	stdioSessions sync.WaitGroup
 
+	// reloadMu serializes Reload and the reloads of single tools
+	reloadMu sync.Mutex
+	// mu guards the resources below, which are replaced on Reload
+	mu sync.RWMutex
+	// config is the configuration the resources were initialized from
+	config       ServerConfig
 	sources      map[string]sources.Source
 	authServices map[string]auth.AuthService
-	tools        map[string]tools.Tool
-	toolsets     map[string]tools.Toolset
+	// initializedTools are the tools initialized from the tool configs,
+	// before they are wrapped, by the name they are configured under
+	initializedTools map[string]tools.Tool
+	// sourceUsers counts the invocations running against sources
+	sourceUsers *sourceUsers
+	tools       map[string]tools.Tool
+	toolsets    map[string]tools.Toolset
+	prompts     map[string]tools.Prompt
 }
 
 // NewServer returns a Server object based on provided Config.
@@ -97,7 +167,122 @@ func NewServer(ctx context.Context, cfg ServerConfig, l log.Logger) (*Server, er
 	}
 	httpLogger := httplog.NewLogger("httplog", httpOpts)
 	r.Use(httplog.RequestLogger(httpLogger))
+	r.Use(cors(corsConfig{
+		allowedOrigins:   cfg.CorsAllowedOrigins,
+		allowedMethods:   cfg.CorsAllowedMethods,
+		allowedHeaders:   cfg.CorsAllowedHeaders,
+		allowCredentials: cfg.CorsAllowCredentials,
+	}))
 
+	sourcesMap, authServicesMap, err := initializeConfigs(ctx, cfg, l, instrumentation)
+	if err != nil {
+		return nil, err
+	}
+	initializedTools := make(map[string]tools.Tool)
+	users := &sourceUsers{}
+	toolsMap, toolsetsMap, err := initializeTools(ctx, cfg, sourcesMap, initializedTools, nil, users, l, instrumentation)
+	if err != nil {
+		return nil, err
+	}
+	promptsMap, err := initializePrompts(ctx, cfg, l)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	adminToken, err := readAdminToken(cfg, authServicesMap)
+	if err != nil {
+		return nil, err
+	}
+	addr := net.JoinHostPort(cfg.Address, strconv.Itoa(cfg.Port))
+	srv := &http.Server{Addr: addr, Handler: r, TLSConfig: tlsConfig}
+
+	maxRequestBytes := cfg.MaxRequestBytes
+	if maxRequestBytes == 0 {
+		maxRequestBytes = DefaultMaxRequestBytes
+	}
+	compressMinBytes := cfg.CompressionMinBytes
+	if compressMinBytes == 0 {
+		compressMinBytes = DefaultCompressionMinBytes
+	}
+
+	sseManager := &sseManager{
+		mu:             sync.RWMutex{},
+		sseSessions:    make(map[string]*sseSession),
+		maxSSESessions: cfg.MaxSSESessions,
+	}
+
+	s := &Server{
+		version:         cfg.Version,
+		buildCommit:     cfg.BuildCommit,
+		buildDate:       cfg.BuildDate,
+		startTime:       time.Now(),
+		srv:             srv,
+		root:            r,
+		logger:          l,
+		instrumentation: instrumentation,
+		sseManager:      sseManager,
+		invocationLimiter: newInvocationLimiter(
+			cfg.MaxConcurrentInvocations,
+			cfg.InvocationQueueDepth,
+			cfg.InvocationQueueTimeout,
+		),
+		rateLimiter:          newRateLimiter(cfg.RateLimit, cfg.RateLimitBurst, cfg.RateLimitHeader),
+		maxUploadSize:        cfg.MaxUploadSize,
+		maxRequestBytes:      maxRequestBytes,
+		compressMinBytes:     compressMinBytes,
+		resourcePollInterval: cfg.ResourcePollInterval,
+		adminAuth:            cfg.AdminAuth,
+		adminToken:           adminToken,
+		argumentsKey:         cfg.ArgumentsKey,
+		executionMetadata:    cfg.ExecutionMetadata,
+		toolsListPageSize:    cfg.ToolsListPageSize,
+		mcpToolCallTimeout:   cfg.McpToolCallTimeout,
+		stdioFraming:         cfg.StdioFraming,
+		shutdownGracePeriod:  cfg.ShutdownGracePeriod,
+		shutdown:             make(chan struct{}),
+
+		config:           cfg,
+		sources:          sourcesMap,
+		authServices:     authServicesMap,
+		initializedTools: initializedTools,
+		sourceUsers:      users,
+		tools:            toolsMap,
+		toolsets:         toolsetsMap,
+		prompts:          promptsMap,
+	}
+	if cfg.HealthCheckInterval > 0 {
+		s.health = &healthCache{}
+		go s.checkHealthPeriodically(cfg.HealthCheckInterval)
+	}
+	// control plane
+	apiR, err := apiRouter(s)
+	if err != nil {
+		return nil, err
+	}
+	r.Mount("/api", apiR)
+	mcpR, err := mcpRouter(s)
+	if err != nil {
+		return nil, err
+	}
+	r.Mount("/mcp", mcpR)
+	if cfg.TelemetryPrometheus {
+		r.Handle("/metrics", promhttp.Handler())
+	}
+	// default endpoint for validating server is running
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("🧰 Hello, World! 🧰"))
+	})
+
+	return s, nil
+}
+
+// initializeConfigs initializes and validates the sources and auth services
+// of cfg.
+func initializeConfigs(ctx context.Context, cfg ServerConfig, l log.Logger, instrumentation *Instrumentation) (map[string]sources.Source, map[string]auth.AuthService, error) {
 	// initialize and validate the sources from configs
 	sourcesMap := make(map[string]sources.Source)
 	for name, sc := range cfg.SourceConfigs {
@@ -110,13 +295,20 @@ func NewServer(ctx context.Context, cfg ServerConfig, l log.Logger) (*Server, er
 			)
 			defer span.End()
 			s, err := sc.Initialize(childCtx, instrumentation.Tracer)
+			var unreachable *sources.UnreachableError
+			if errors.As(err, &unreachable) {
+				// the source is reported unhealthy until it can be reached
+				l.WarnContext(ctx, fmt.Sprintf("source %q is unreachable: %s", name, unreachable.Err))
+				return unreachable.Source, nil
+			}
 			if err != nil {
 				return nil, fmt.Errorf("unable to initialize source %q: %w", name, err)
 			}
 			return s, nil
 		}()
 		if err != nil {
-			return nil, err
+			closeSources(ctx, l, sourcesMap)
+			return nil, nil, err
 		}
 		sourcesMap[name] = s
 	}
@@ -140,16 +332,47 @@ func NewServer(ctx context.Context, cfg ServerConfig, l log.Logger) (*Server, er
 			return a, nil
 		}()
 		if err != nil {
-			return nil, err
+			closeSources(ctx, l, sourcesMap)
+			return nil, nil, err
 		}
 		authServicesMap[name] = a
 	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d authServices.", len(authServicesMap)))
+	return sourcesMap, authServicesMap, nil
+}
+
+// closeSources closes each source of sourcesMap, logging the ones that fail
+// to close.
+func closeSources(ctx context.Context, l log.Logger, sourcesMap map[string]sources.Source) {
+	for name, src := range sourcesMap {
+		if err := sources.Close(src); err != nil {
+			l.WarnContext(ctx, fmt.Sprintf("unable to close source %q: %s", name, err))
+		}
+	}
+}
+
+// initializeTools initializes and validates the tools and toolsets of cfg
+// against sourcesMap. initialized holds the tools already initialized from
+// their configs, by the name they are configured under, which are reused
+// instead of initializing their configs again. The tools that are initialized
+// are added to it. current holds the wrapped tools already served, by the name
+// they are served under, which are kept as they are along with the cached
+// results, circuit breakers and memory guards they share with the other
+// tools. The invocations of the tools are counted by users.
+func initializeTools(ctx context.Context, cfg ServerConfig, sourcesMap map[string]sources.Source, initialized map[string]tools.Tool, current map[string]tools.Tool, users *sourceUsers, l log.Logger, instrumentation *Instrumentation) (map[string]tools.Tool, map[string]tools.Toolset, error) {
+	toolNames, err := servedToolNames(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// initialize and validate the tools from configs
 	toolsMap := make(map[string]tools.Tool)
+	toolSources := make(map[string]string)
 	for name, tc := range cfg.ToolConfigs {
 		t, err := func() (tools.Tool, error) {
+			if t, ok := initialized[name]; ok {
+				return t, nil
+			}
 			_, span := instrumentation.Tracer.Start(
 				ctx,
 				"toolbox/server/tool/init",
@@ -161,12 +384,62 @@ func NewServer(ctx context.Context, cfg ServerConfig, l log.Logger) (*Server, er
 			if err != nil {
 				return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
 			}
+			if err := tools.CheckFromAuthClaims(t.Manifest()); err != nil {
+				return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
+			}
+			initialized[name] = t
 			return t, nil
 		}()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		if n := toolNames[name]; n != name {
+			l.WarnContext(ctx, fmt.Sprintf("tool %q will be served as %q", name, n))
+			t = renamedTool{Tool: t, name: n}
+		}
+		if _, version, ok := strings.Cut(toolNames[name], "@"); ok {
+			t = versionedTool{Tool: t, version: version}
+		}
+		toolsMap[toolNames[name]] = t
+		toolSources[toolNames[name]] = toolSource(tc)
+	}
+	toolsMap = withSourceUsers(toolsMap, users)
+	toolsMap = withCircuitBreakers(toolsMap, toolSources, circuitBreakers(current), cfg.CircuitBreakerThreshold, cfg.CircuitBreakerCooldown)
+	toolsMap, err = withResultCache(toolsMap, resultCacheOf(current))
+	if err != nil {
+		return nil, nil, err
+	}
+	toolsMap = withRowWarnings(toolsMap, l, instrumentation.ToolRowsExceeded)
+	loc, err := cfg.TimeZone.Location()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid time zone %q: %w", cfg.TimeZone, err)
+	}
+	toolsMap = withTimeZone(toolsMap, loc)
+	toolsMap, err = withColumnAuth(toolsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	toolsMap, err = withRowFilters(toolsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	// placeholders are set after filtering, so that filters see NULL values
+	toolsMap = withNullPlaceholders(toolsMap)
+	toolsMap = withResultMemoryGuards(toolsMap, toolSources, resultMemoryGuards(current), cfg.MaxSourceResultBytes)
+	toolsMap = withMaxRows(toolsMap)
+	if cfg.SourceMeta {
+		toolsMap = withSourceMeta(toolsMap, toolSources, cfg.SourceConfigs)
+	}
+	toolsMap, err = withPagination(toolsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	toolsMap = withETags(toolsMap, toolSources, cfg.SourceConfigs)
+	// the tools that are already served are kept as they are
+	for name, t := range current {
+		if _, ok := toolsMap[name]; ok {
+			toolsMap[name] = t
 		}
-		toolsMap[name] = t
 	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d tools.", len(toolsMap)))
 
@@ -175,14 +448,23 @@ func NewServer(ctx context.Context, cfg ServerConfig, l log.Logger) (*Server, er
 	for name := range toolsMap {
 		allToolNames = append(allToolNames, name)
 	}
-	if cfg.ToolsetConfigs == nil {
-		cfg.ToolsetConfigs = make(ToolsetConfigs)
+	toolsetConfigs := make(ToolsetConfigs, len(cfg.ToolsetConfigs)+1)
+	for name, tc := range cfg.ToolsetConfigs {
+		toolNamesInSet := make([]string, len(tc.ToolNames))
+		for i, n := range tc.ToolNames {
+			if normalized, ok := toolNames[n]; ok {
+				n = normalized
+			}
+			toolNamesInSet[i] = n
+		}
+		tc.ToolNames = toolNamesInSet
+		toolsetConfigs[name] = tc
 	}
-	cfg.ToolsetConfigs[""] = tools.ToolsetConfig{Name: "", ToolNames: allToolNames}
+	toolsetConfigs[""] = tools.ToolsetConfig{Name: "", ToolNames: allToolNames}
 
 	// initialize and validate the toolsets from configs
 	toolsetsMap := make(map[string]tools.Toolset)
-	for name, tc := range cfg.ToolsetConfigs {
+	for name, tc := range toolsetConfigs {
 		t, err := func() (tools.Toolset, error) {
 			_, span := instrumentation.Tracer.Start(
 				ctx,
@@ -197,53 +479,213 @@ func NewServer(ctx context.Context, cfg ServerConfig, l log.Logger) (*Server, er
 			return t, err
 		}()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		toolsetsMap[name] = t
 	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d toolsets.", len(toolsetsMap)))
+	return toolsMap, toolsetsMap, nil
+}
 
-	addr := net.JoinHostPort(cfg.Address, strconv.Itoa(cfg.Port))
-	srv := &http.Server{Addr: addr, Handler: r}
+// servedToolNames returns the names the tools of cfg are served under, by the
+// name they are configured under.
+func servedToolNames(cfg ServerConfig) (map[string]string, error) {
+	toolNames := make(map[string]string, len(cfg.ToolConfigs))
+	for name, tc := range cfg.ToolConfigs {
+		n, err := normalizeToolName(name, cfg.ToolNameMode)
+		if err != nil {
+			return nil, err
+		}
+		if c, ok := tc.(tools.ConfigWithOptions); ok {
+			if n, err = versionedName(n, c.Options.Version); err != nil {
+				return nil, err
+			}
+		}
+		toolNames[name] = n
+	}
+	return toolNames, nil
+}
 
-	sseManager := &sseManager{
-		mu:          sync.RWMutex{},
-		sseSessions: make(map[string]*sseSession),
+// findWrapper returns the first tool of type T in the chain of wrappers of t.
+func findWrapper[T tools.Tool](t tools.Tool) (T, bool) {
+	for t != nil {
+		if w, ok := t.(T); ok {
+			return w, true
+		}
+		u, ok := t.(interface{ Unwrap() tools.Tool })
+		if !ok {
+			break
+		}
+		t = u.Unwrap()
 	}
+	var zero T
+	return zero, false
+}
 
-	s := &Server{
-		version:         cfg.Version,
-		srv:             srv,
-		root:            r,
-		logger:          l,
-		instrumentation: instrumentation,
-		sseManager:      sseManager,
+// initializePrompts initializes and validates the prompts of cfg.
+func initializePrompts(ctx context.Context, cfg ServerConfig, l log.Logger) (map[string]tools.Prompt, error) {
+	promptsMap := make(map[string]tools.Prompt, len(cfg.PromptConfigs))
+	for name, pc := range cfg.PromptConfigs {
+		p, err := pc.Initialize()
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize prompt %q: %w", name, err)
+		}
+		promptsMap[name] = p
+	}
+	l.InfoContext(ctx, fmt.Sprintf("Initialized %d prompts.", len(promptsMap)))
+	return promptsMap, nil
+}
 
-		sources:      sourcesMap,
-		authServices: authServicesMap,
-		tools:        toolsMap,
-		toolsets:     toolsetsMap,
+// Reload replaces the sources, auth services, tools, toolsets and prompts of
+// the Server with the ones configured in cfg. Requests already in flight complete
+// against the previous resources, whose sources are closed once they do, and
+// resource subscriptions move to the new tools. If cfg fails to initialize, the
+// current resources are kept, the sources initialized from cfg are closed and
+// the error is returned. Other settings in cfg, such as the address, are
+// ignored.
+func (s *Server) Reload(ctx context.Context, cfg ServerConfig) error {
+	ctx, span := s.instrumentation.Tracer.Start(ctx, "toolbox/server/reload")
+	defer span.End()
+
+	ctx = util.WithUserAgent(ctx, s.version)
+	cfg.Version = s.version
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	sourcesMap, authServicesMap, err := initializeConfigs(ctx, cfg, s.logger, s.instrumentation)
+	if err != nil {
+		return err
 	}
-	// control plane
-	apiR, err := apiRouter(s)
+	initializedTools := make(map[string]tools.Tool)
+	users := &sourceUsers{}
+	toolsMap, toolsetsMap, err := initializeTools(ctx, cfg, sourcesMap, initializedTools, nil, users, s.logger, s.instrumentation)
 	if err != nil {
-		return nil, err
+		closeSources(ctx, s.logger, sourcesMap)
+		return err
 	}
-	r.Mount("/api", apiR)
-	mcpR, err := mcpRouter(s)
+	promptsMap, err := initializePrompts(ctx, cfg, s.logger)
 	if err != nil {
-		return nil, err
+		closeSources(ctx, s.logger, sourcesMap)
+		return err
 	}
-	r.Mount("/mcp", mcpR)
-	// default endpoint for validating server is running
-	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
-		_, _ = w.Write([]byte("🧰 Hello, World! 🧰"))
+
+	s.mu.Lock()
+	oldSources, oldUsers := s.sources, s.sourceUsers
+	s.config = cfg
+	s.sources = sourcesMap
+	s.authServices = authServicesMap
+	s.initializedTools = initializedTools
+	s.sourceUsers = users
+	s.tools = toolsMap
+	s.toolsets = toolsetsMap
+	s.prompts = promptsMap
+	// the results of the previous sources no longer apply
+	if s.health != nil {
+		s.health.reset()
+	}
+	s.mu.Unlock()
+
+	s.resubscribe()
+	// the previous sources are closed once the invocations still running
+	// against them complete
+	oldUsers.whenIdle(func() {
+		closeSources(context.WithoutCancel(ctx), s.logger, oldSources)
 	})
+	return nil
+}
 
-	return s, nil
+// reloadTool replaces the tool configured under name with the one configured
+// by tc, which is initialized against the current sources. The toolsets that
+// include the tool are updated along with it, and the other tools are kept as
+// they are, with their cached results and circuit breakers. If tc fails to
+// initialize, the current tool is kept and the error is returned.
+func (s *Server) reloadTool(ctx context.Context, name string, tc tools.ToolConfig) error {
+	ctx, span := s.instrumentation.Tracer.Start(ctx, "toolbox/server/tool/reload")
+	defer span.End()
+
+	ctx = util.WithUserAgent(ctx, s.version)
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	s.mu.RLock()
+	cfg := s.config
+	sourcesMap := s.sources
+	users := s.sourceUsers
+	initializedTools := maps.Clone(s.initializedTools)
+	current := maps.Clone(s.tools)
+	s.mu.RUnlock()
+	if _, ok := cfg.ToolConfigs[name]; !ok {
+		return fmt.Errorf("%w: %q", errToolNotConfigured, name)
+	}
+	toolNames, err := servedToolNames(cfg)
+	if err != nil {
+		return err
+	}
+	oldName := toolNames[name]
+	cfg.ToolConfigs = maps.Clone(cfg.ToolConfigs)
+	cfg.ToolConfigs[name] = tc
+	delete(initializedTools, name)
+	delete(current, oldName)
+	toolsMap, toolsetsMap, err := initializeTools(ctx, cfg, sourcesMap, initializedTools, current, users, s.logger, s.instrumentation)
+	if err != nil {
+		return err
+	}
+	// the results cached by the previous tool no longer apply
+	if cache := resultCacheOf(toolsMap); cache != nil {
+		cache.invalidate(oldName)
+	}
+
+	s.mu.Lock()
+	s.config = cfg
+	s.initializedTools = initializedTools
+	s.tools = toolsMap
+	s.toolsets = toolsetsMap
+	s.mu.Unlock()
+
+	s.resubscribe()
+	return nil
 }
 
-// Listen starts a listener for the given Server instance.
+// getTool returns the tool served under name.
+func (s *Server) getTool(name string) (tools.Tool, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.tools[name]
+	return t, ok
+}
+
+// getTools returns all tools, by the name they are served under. The map must
+// not be modified.
+func (s *Server) getTools() map[string]tools.Tool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tools
+}
+
+// getToolset returns the toolset with the given name.
+func (s *Server) getToolset(name string) (tools.Toolset, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.toolsets[name]
+	return t, ok
+}
+
+// getPrompts returns all prompts, by name. The map must not be modified.
+func (s *Server) getPrompts() map[string]tools.Prompt {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.prompts
+}
+
+// getAuthServices returns all auth services. The map must not be modified.
+func (s *Server) getAuthServices() map[string]auth.AuthService {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.authServices
+}
+
+// Listen starts a listener for the given Server instance. A listener passed
+// with socket activation (LISTEN_PID and LISTEN_FDS) is adopted instead of
+// opening a new one on the configured address.
 func (s *Server) Listen(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -251,8 +693,16 @@ func (s *Server) Listen(ctx context.Context) error {
 	if s.listener != nil {
 		return fmt.Errorf("server is already listening: %s", s.listener.Addr().String())
 	}
+	l, err := inheritedListener()
+	if err != nil {
+		return err
+	}
+	if l != nil {
+		s.listener = l
+		s.logger.DebugContext(ctx, fmt.Sprintf("server listening on inherited %s", l.Addr()))
+		return nil
+	}
 	lc := net.ListenConfig{KeepAlive: 30 * time.Second}
-	var err error
 	if s.listener, err = lc.Listen(ctx, "tcp", s.srv.Addr); err != nil {
 		return fmt.Errorf("failed to open listener for %q: %w", s.srv.Addr, err)
 	}
@@ -260,21 +710,61 @@ func (s *Server) Listen(ctx context.Context) error {
 	return nil
 }
 
-// Serve starts an HTTP server for the given Server instance.
+// Serve starts an HTTP server for the given Server instance, which serves
+// HTTPS if a TLS certificate is configured.
 func (s *Server) Serve(ctx context.Context) error {
+	if s.srv.TLSConfig != nil {
+		s.logger.DebugContext(ctx, "Starting a HTTPS server.")
+		// the certificate is already loaded in the TLS config
+		return s.srv.ServeTLS(s.listener, "", "")
+	}
 	s.logger.DebugContext(ctx, "Starting a HTTP server.")
 	return s.srv.Serve(s.listener)
 }
 
 // ServeStdio starts a new stdio session for mcp.
 func (s *Server) ServeStdio(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	s.stdioSessions.Add(1)
+	defer s.stdioSessions.Done()
 	stdioServer := NewStdioSession(s, stdin, stdout)
 	return stdioServer.Start(ctx)
 }
 
-// Shutdown gracefully shuts down the server without interrupting any active
-// connections. It uses http.Server.Shutdown() and has the same functionality.
+// Shutdown gracefully shuts down the server. Open sse sessions are sent a
+// final event asking clients to reconnect, and stdio sessions stop reading
+// new messages. It then uses http.Server.Shutdown() to wait for active
+// connections, until ctx is done or the shutdown grace period has passed, at
+// which point the remaining connections are closed. The errors for sessions
+// or connections that did not close in time are joined.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.DebugContext(ctx, "shutting down the server.")
-	return s.srv.Shutdown(ctx)
+	if s.shutdownGracePeriod > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.shutdownGracePeriod)
+		defer cancel()
+	}
+	s.shutdownOnce.Do(func() { close(s.shutdown) })
+
+	var errs []error
+	if err := s.sseManager.shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	stdioDone := make(chan struct{})
+	go func() {
+		s.stdioSessions.Wait()
+		close(stdioDone)
+	}()
+	select {
+	case <-stdioDone:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("stdio session did not close in time: %w", ctx.Err()))
+	}
+
+	if err := s.srv.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+		// force the remaining connections closed
+		_ = s.srv.Close()
+	}
+	return errors.Join(errs...)
 }
@@ -15,17 +15,24 @@
 package server_test
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/inmemory"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/inmemorylookup"
 )
 
 func TestServe(t *testing.T) {
@@ -39,7 +46,7 @@ func TestServe(t *testing.T) {
 		Port:    port,
 	}
 
-	otelShutdown, err := telemetry.SetupOTel(ctx, "0.0.0", "", false, "toolbox")
+	otelShutdown, err := telemetry.SetupOTel(ctx, "0.0.0", "", false, "toolbox", false, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
@@ -93,3 +100,546 @@ func TestServe(t *testing.T) {
 		t.Fatalf("version missing from output: %q", got)
 	}
 }
+
+func TestServeMetrics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	addr, port := "127.0.0.1", 5001
+	cfg := server.ServerConfig{
+		Version:             "0.0.0",
+		Address:             addr,
+		Port:                port,
+		TelemetryPrometheus: true,
+		SourceConfigs: server.SourceConfigs{
+			"my-mem": inmemory.Config{
+				Name:   "my-mem",
+				Kind:   inmemory.SourceKind,
+				Tables: map[string]inmemory.Table{"users": {{"id": 1}}},
+			},
+		},
+		ToolConfigs: server.ToolConfigs{
+			"list_users": inmemorylookup.Config{
+				Name:        "list_users",
+				Kind:        "in-memory-lookup",
+				Source:      "my-mem",
+				Description: "List users.",
+				Table:       "users",
+			},
+		},
+	}
+
+	otelShutdown, err := telemetry.SetupOTel(ctx, "0.0.0", "", false, "toolbox", true, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer func() {
+		err := otelShutdown(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}()
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s, err := server.NewServer(ctx, cfg, testLogger)
+	if err != nil {
+		t.Fatalf("unable to initialize server: %v", err)
+	}
+	if err := s.Listen(ctx); err != nil {
+		t.Fatalf("unable to start server: %v", err)
+	}
+	go func() {
+		_ = s.Serve(ctx)
+	}()
+
+	baseURL := fmt.Sprintf("http://%s:%d", addr, port)
+	invokeCount := func() string {
+		t.Helper()
+		resp, err := http.Get(baseURL + "/metrics")
+		if err != nil {
+			t.Fatalf("error when sending a request: %s", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: %d", resp.StatusCode)
+		}
+		raw, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("error reading from request body: %s", err)
+		}
+		for _, line := range strings.Split(string(raw), "\n") {
+			if strings.HasPrefix(line, "toolbox_server_tool_invoke_count_total{") && strings.Contains(line, `toolbox_name="list_users"`) {
+				fields := strings.Fields(line)
+				return fields[len(fields)-1]
+			}
+		}
+		return ""
+	}
+
+	if got := invokeCount(); got != "" {
+		t.Fatalf("expected no invocations before invoking the tool, got %q", got)
+	}
+
+	resp, err := http.Post(baseURL+"/api/tool/list_users/invoke", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("error when sending a request: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if got := invokeCount(); got != "1" {
+		t.Fatalf("unexpected invocation count: got %q, want %q", got, "1")
+	}
+}
+
+func TestShutdownDrainsSessions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	addr, port := "127.0.0.1", 5002
+	cfg := server.ServerConfig{
+		Version:             "0.0.0",
+		Address:             addr,
+		Port:                port,
+		ShutdownGracePeriod: 5 * time.Second,
+	}
+
+	otelShutdown, err := telemetry.SetupOTel(ctx, "0.0.0", "", false, "toolbox", false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer func() {
+		err := otelShutdown(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}()
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s, err := server.NewServer(ctx, cfg, testLogger)
+	if err != nil {
+		t.Fatalf("unable to initialize server: %v", err)
+	}
+	if err := s.Listen(ctx); err != nil {
+		t.Fatalf("unable to start server: %v", err)
+	}
+	go func() {
+		_ = s.Serve(ctx)
+	}()
+
+	resp, err := http.Get(fmt.Sprintf("http://%s:%d/mcp/sse", addr, port))
+	if err != nil {
+		t.Fatalf("error when sending a request: %s", err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	if line, err := reader.ReadString('\n'); err != nil || line != "event: endpoint\n" {
+		t.Fatalf("expected an endpoint event, got %q: %v", line, err)
+	}
+
+	// a stdio session whose input never ends
+	stdin, stdinWriter := io.Pipe()
+	defer stdinWriter.Close()
+	stdioErr := make(chan error, 1)
+	go func() {
+		stdioErr <- s.ServeStdio(ctx, stdin, io.Discard)
+	}()
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("unexpected error shutting down: %s", err)
+	}
+
+	rest, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("error reading from request body: %s", err)
+	}
+	if !strings.HasSuffix(string(rest), "event: shutdown\ndata: reconnect\n\n") {
+		t.Fatalf("expected a final shutdown event, got %q", rest)
+	}
+
+	select {
+	case err := <-stdioErr:
+		if err != nil {
+			t.Fatalf("unexpected error from stdio session: %s", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("stdio session did not end on shutdown")
+	}
+}
+
+func TestReload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	addr, port := "127.0.0.1", 5003
+	sourceConfigs := server.SourceConfigs{
+		"my-mem": inmemory.Config{
+			Name:   "my-mem",
+			Kind:   inmemory.SourceKind,
+			Tables: map[string]inmemory.Table{"users": {{"id": 1}}},
+		},
+	}
+	listUsers := inmemorylookup.Config{
+		Name:        "list_users",
+		Kind:        "in-memory-lookup",
+		Source:      "my-mem",
+		Description: "List users.",
+		Table:       "users",
+	}
+	cfg := server.ServerConfig{
+		Version:       "0.0.0",
+		Address:       addr,
+		Port:          port,
+		SourceConfigs: sourceConfigs,
+		ToolConfigs:   server.ToolConfigs{"list_users": listUsers},
+	}
+
+	otelShutdown, err := telemetry.SetupOTel(ctx, "0.0.0", "", false, "toolbox", false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer func() {
+		err := otelShutdown(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}()
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s, err := server.NewServer(ctx, cfg, testLogger)
+	if err != nil {
+		t.Fatalf("unable to initialize server: %v", err)
+	}
+	if err := s.Listen(ctx); err != nil {
+		t.Fatalf("unable to start server: %v", err)
+	}
+	go func() {
+		_ = s.Serve(ctx)
+	}()
+
+	invoke := func(name string) int {
+		t.Helper()
+		url := fmt.Sprintf("http://%s:%d/api/tool/%s/invoke", addr, port, name)
+		resp, err := http.Post(url, "application/json", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatalf("error when sending a request: %s", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := invoke("list_users_again"); status != http.StatusNotFound {
+		t.Fatalf("unexpected status code before reload: got %d, want %d", status, http.StatusNotFound)
+	}
+
+	listUsersAgain := listUsers
+	listUsersAgain.Name = "list_users_again"
+	cfg.ToolConfigs = server.ToolConfigs{"list_users": listUsers, "list_users_again": listUsersAgain}
+	if err := s.Reload(ctx, cfg); err != nil {
+		t.Fatalf("unexpected error reloading: %s", err)
+	}
+	for _, name := range []string{"list_users", "list_users_again"} {
+		if status := invoke(name); status != http.StatusOK {
+			t.Fatalf("unexpected status code invoking %q after reload: got %d, want %d", name, status, http.StatusOK)
+		}
+	}
+
+	// a config that fails to initialize keeps the current tools
+	broken := listUsers
+	broken.Name = "broken"
+	broken.Source = "missing"
+	cfg.ToolConfigs = server.ToolConfigs{"broken": broken}
+	if err := s.Reload(ctx, cfg); err == nil {
+		t.Fatalf("expected an error reloading an invalid config")
+	}
+	if status := invoke("list_users_again"); status != http.StatusOK {
+		t.Fatalf("unexpected status code after failed reload: got %d, want %d", status, http.StatusOK)
+	}
+}
+
+func TestToolETag(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	addr, port := "127.0.0.1", 5006
+	listUsers := inmemorylookup.Config{
+		Name:        "list_users",
+		Kind:        "in-memory-lookup",
+		Source:      "my-mem",
+		Description: "List users.",
+		Table:       "users",
+	}
+	cfg := server.ServerConfig{
+		Version: "0.0.0",
+		Address: addr,
+		Port:    port,
+		SourceConfigs: server.SourceConfigs{
+			"my-mem": inmemory.Config{
+				Name:   "my-mem",
+				Kind:   inmemory.SourceKind,
+				Tables: map[string]inmemory.Table{"users": {{"id": 1}}},
+			},
+		},
+		ToolConfigs: server.ToolConfigs{"list_users": listUsers},
+	}
+
+	otelShutdown, err := telemetry.SetupOTel(ctx, "0.0.0", "", false, "toolbox", false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer func() {
+		err := otelShutdown(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}()
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s, err := server.NewServer(ctx, cfg, testLogger)
+	if err != nil {
+		t.Fatalf("unable to initialize server: %v", err)
+	}
+	if err := s.Listen(ctx); err != nil {
+		t.Fatalf("unable to start server: %v", err)
+	}
+	go func() {
+		_ = s.Serve(ctx)
+	}()
+
+	// get returns the status code and ETag of the tool
+	get := func(ifNoneMatch string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s:%d/api/tool/list_users", addr, port), nil)
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("error when sending a request: %s", err)
+		}
+		defer resp.Body.Close()
+		etag := resp.Header.Get("ETag")
+		if resp.StatusCode == http.StatusOK {
+			var m tools.ToolsetManifest
+			if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			if got := m.ToolsManifest["list_users"].ETag; got != etag {
+				t.Fatalf("ETag of the manifest %q doesn't match the header %q", got, etag)
+			}
+		}
+		return resp.StatusCode, etag
+	}
+
+	status, etag := get("")
+	if status != http.StatusOK || etag == "" {
+		t.Fatalf("unexpected response: status code %d, ETag %q", status, etag)
+	}
+	if _, again := get(""); again != etag {
+		t.Fatalf("ETag changed across requests: %q then %q", etag, again)
+	}
+	if status, _ := get(etag); status != http.StatusNotModified {
+		t.Fatalf("unexpected status code for a matching ETag: got %d, want %d", status, http.StatusNotModified)
+	}
+
+	// reloading the same definition keeps the ETag
+	if err := s.Reload(ctx, cfg); err != nil {
+		t.Fatalf("unexpected error reloading: %s", err)
+	}
+	if _, reloaded := get(""); reloaded != etag {
+		t.Fatalf("ETag changed after reloading the same tool: %q then %q", etag, reloaded)
+	}
+
+	listUsers.Description = "List all users."
+	cfg.ToolConfigs = server.ToolConfigs{"list_users": listUsers}
+	if err := s.Reload(ctx, cfg); err != nil {
+		t.Fatalf("unexpected error reloading: %s", err)
+	}
+	if status, _ := get(etag); status != http.StatusOK {
+		t.Fatalf("unexpected status code for an outdated ETag: got %d, want %d", status, http.StatusOK)
+	}
+	if _, changed := get(""); changed == etag {
+		t.Fatalf("ETag didn't change after the tool changed: %q", changed)
+	}
+}
+
+func TestReloadTool(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	addr, port := "127.0.0.1", 5007
+	listUsers := inmemorylookup.Config{
+		Name:        "list_users",
+		Kind:        "in-memory-lookup",
+		Source:      "my-mem",
+		Description: "List users.",
+		Table:       "users",
+	}
+	adminTokenFile := filepath.Join(t.TempDir(), "admin-token")
+	if err := os.WriteFile(adminTokenFile, []byte("admin-secret"), 0o600); err != nil {
+		t.Fatalf("unable to write admin token file: %s", err)
+	}
+	cfg := server.ServerConfig{
+		Version:        "0.0.0",
+		Address:        addr,
+		Port:           port,
+		AdminTokenFile: adminTokenFile,
+		SourceConfigs: server.SourceConfigs{
+			"my-mem": inmemory.Config{
+				Name:   "my-mem",
+				Kind:   inmemory.SourceKind,
+				Tables: map[string]inmemory.Table{"users": {{"id": 1}, {"id": 2}}},
+			},
+		},
+		ToolConfigs:    server.ToolConfigs{"list_users": listUsers},
+		ToolsetConfigs: server.ToolsetConfigs{"users": tools.ToolsetConfig{Name: "users", ToolNames: []string{"list_users"}}},
+	}
+
+	otelShutdown, err := telemetry.SetupOTel(ctx, "0.0.0", "", false, "toolbox", false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer func() {
+		err := otelShutdown(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}()
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s, err := server.NewServer(ctx, cfg, testLogger)
+	if err != nil {
+		t.Fatalf("unable to initialize server: %v", err)
+	}
+	if err := s.Listen(ctx); err != nil {
+		t.Fatalf("unable to start server: %v", err)
+	}
+	go func() {
+		_ = s.Serve(ctx)
+	}()
+
+	// invoke returns the rows returned by list_users
+	invoke := func() []any {
+		t.Helper()
+		url := fmt.Sprintf("http://%s:%d/api/tool/list_users/invoke", addr, port)
+		resp, err := http.Post(url, "application/json", strings.NewReader(`{"id": 2}`))
+		if err != nil {
+			t.Fatalf("error when sending a request: %s", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		var body struct {
+			Result string `json:"result"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("unable to parse response body: %s", err)
+		}
+		var rows []any
+		if err := json.Unmarshal([]byte(body.Result), &rows); err != nil {
+			t.Fatalf("unable to parse result: %s", err)
+		}
+		return rows
+	}
+	// reload sends the tool config in body to the reload endpoint of name
+	reload := func(name, body string) int {
+		t.Helper()
+		url := fmt.Sprintf("http://%s:%d/api/admin/tool/%s/reload", addr, port, name)
+		req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Authorization", "Bearer admin-secret")
+		req.Header.Set("Content-Type", "application/yaml")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("error when sending a request: %s", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	// description returns the description of list_users in the users toolset
+	description := func() string {
+		t.Helper()
+		resp, err := http.Get(fmt.Sprintf("http://%s:%d/api/toolset/users", addr, port))
+		if err != nil {
+			t.Fatalf("error when sending a request: %s", err)
+		}
+		defer resp.Body.Close()
+		var m tools.ToolsetManifest
+		if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+			t.Fatalf("unable to parse response body: %s", err)
+		}
+		return m.ToolsManifest["list_users"].Description
+	}
+
+	// without parameters, the tool returns all users
+	if rows := invoke(); len(rows) != 2 {
+		t.Fatalf("unexpected rows before reload: got %v, want 2 rows", rows)
+	}
+
+	newConfig := `
+kind: in-memory-lookup
+source: my-mem
+description: Look up a user by id.
+table: users
+parameters:
+  - name: id
+    type: integer
+    description: The id of the user.
+`
+	if status := reload("list_users", newConfig); status != http.StatusOK {
+		t.Fatalf("unexpected status code reloading: got %d, want %d", status, http.StatusOK)
+	}
+	if rows := invoke(); len(rows) != 1 {
+		t.Fatalf("unexpected rows after reload: got %v, want 1 row", rows)
+	}
+	if got, want := description(), "Look up a user by id."; got != want {
+		t.Fatalf("unexpected description in toolset after reload: got %q, want %q", got, want)
+	}
+
+	// invalid configs are rejected and keep the current tool
+	invalid := map[string]string{
+		"unknown source": strings.Replace(newConfig, "my-mem", "missing", 1),
+		"missing field":  strings.Replace(newConfig, "table: users", "", 1),
+		"unknown kind":   strings.Replace(newConfig, "in-memory-lookup", "no-such-kind", 1),
+		"not a mapping":  "[]",
+	}
+	for name, body := range invalid {
+		if status := reload("list_users", body); status != http.StatusBadRequest {
+			t.Fatalf("unexpected status code reloading %s: got %d, want %d", name, status, http.StatusBadRequest)
+		}
+	}
+	if status := reload("no_such_tool", newConfig); status != http.StatusNotFound {
+		t.Fatalf("unexpected status code reloading an unknown tool: got %d, want %d", status, http.StatusNotFound)
+	}
+	if rows := invoke(); len(rows) != 1 {
+		t.Fatalf("unexpected rows after invalid reloads: got %v, want 1 row", rows)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// validate interface
+var _ tools.Tool = sourceMetaTool{}
+
+// sourceMetaTool is a Tool that records the source it is configured with in
+// the metadata of its results.
+type sourceMetaTool struct {
+	tools.Tool
+	source map[string]any
+}
+
+func (t sourceMetaTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t sourceMetaTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	res, err := t.Tool.Invoke(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	tools.SetMeta(ctx, "source", t.source)
+	return res, nil
+}
+
+// withSourceMeta wraps each tool that uses a source so that the name and kind
+// of the source are returned in the "source" metadata of its results.
+// toolSources maps tool names to the name of their source.
+func withSourceMeta(toolsMap map[string]tools.Tool, toolSources map[string]string, sourceConfigs SourceConfigs) map[string]tools.Tool {
+	for name, t := range toolsMap {
+		sc, ok := sourceConfigs[toolSources[name]]
+		if !ok {
+			continue
+		}
+		toolsMap[name] = sourceMetaTool{
+			Tool:   t,
+			source: map[string]any{"name": toolSources[name], "kind": sc.SourceConfigKind()},
+		}
+	}
+	return toolsMap
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources/inmemory"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestSourceMeta(t *testing.T) {
+	toolsMap := withSourceMeta(
+		map[string]tools.Tool{
+			"with_source":    rowsTool{MockTool: MockTool{Name: "with_source"}, rows: 1},
+			"without_source": rowsTool{MockTool: MockTool{Name: "without_source"}, rows: 1},
+		},
+		map[string]string{"with_source": "my-mem", "without_source": ""},
+		SourceConfigs{"my-mem": inmemory.Config{Name: "my-mem", Kind: inmemory.SourceKind}},
+	)
+
+	ctx := tools.WithMeta(context.Background())
+	if _, err := toolsMap["with_source"].Invoke(ctx, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]any{
+		"source": map[string]any{"name": "my-mem", "kind": inmemory.SourceKind},
+	}
+	if diff := cmp.Diff(want, tools.MetaFromContext(ctx)); diff != "" {
+		t.Fatalf("unexpected meta (-want +got):\n%s", diff)
+	}
+
+	ctx = tools.WithMeta(context.Background())
+	if _, err := toolsMap["without_source"].Invoke(ctx, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := tools.MetaFromContext(ctx); got != nil {
+		t.Fatalf("expected no meta for a tool without a source, got %v", got)
+	}
+}
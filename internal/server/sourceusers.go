@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// sourceUsers counts the invocations running against a set of sources, so
+// that the sources can be closed once they are replaced and the last of these
+// invocations completes.
+type sourceUsers struct {
+	mu    sync.Mutex
+	count int
+	// onIdle is called once count drops to 0, nil until the sources are
+	// replaced
+	onIdle func()
+}
+
+func (u *sourceUsers) acquire() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.count++
+}
+
+func (u *sourceUsers) release() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.count--
+	if u.count == 0 && u.onIdle != nil {
+		go u.onIdle()
+		u.onIdle = nil
+	}
+}
+
+// whenIdle calls f in a new goroutine once no invocation is running against
+// the sources.
+func (u *sourceUsers) whenIdle(f func()) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.count == 0 {
+		go f()
+		return
+	}
+	u.onIdle = f
+}
+
+// validate interface
+var _ tools.Tool = sourceUserTool{}
+
+// sourceUserTool is a Tool whose invocations are counted as users of the
+// sources it was initialized against.
+type sourceUserTool struct {
+	tools.Tool
+	users *sourceUsers
+}
+
+func (t sourceUserTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t sourceUserTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	t.users.acquire()
+	defer t.users.release()
+	return t.Tool.Invoke(ctx, params)
+}
+
+// ParseParams is counted as well, as parameters may be validated against the
+// source.
+func (t sourceUserTool) ParseParams(ctx context.Context, data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	t.users.acquire()
+	defer t.users.release()
+	return t.Tool.ParseParams(ctx, data, claims)
+}
+
+func (t sourceUserTool) Stream() (tools.Streamer, bool) {
+	s, ok := tools.GetStreamer(t.Tool)
+	if !ok {
+		return nil, false
+	}
+	return tools.StreamerFunc(func(ctx context.Context, params tools.ParamValues, yield func(row any) error) error {
+		t.users.acquire()
+		defer t.users.release()
+		return s.InvokeStream(ctx, params, yield)
+	}), true
+}
+
+// withSourceUsers wraps each tool so that its invocations are counted by
+// users.
+func withSourceUsers(toolsMap map[string]tools.Tool, users *sourceUsers) map[string]tools.Tool {
+	for name, t := range toolsMap {
+		toolsMap[name] = sourceUserTool{Tool: t, users: users}
+	}
+	return toolsMap
+}
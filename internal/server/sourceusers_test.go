@@ -0,0 +1,155 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// closingSource is a source that records being closed.
+type closingSource struct {
+	closed chan struct{}
+}
+
+func newClosingSource() *closingSource {
+	return &closingSource{closed: make(chan struct{})}
+}
+
+func (*closingSource) SourceKind() string {
+	return "fake-closing"
+}
+
+func (s *closingSource) Close() error {
+	close(s.closed)
+	return nil
+}
+
+// closingSourceConfig initializes to its source.
+type closingSourceConfig struct {
+	source *closingSource
+}
+
+func (closingSourceConfig) SourceConfigKind() string {
+	return "fake-closing"
+}
+
+func (c closingSourceConfig) Initialize(context.Context, trace.Tracer) (sources.Source, error) {
+	return c.source, nil
+}
+
+// brokenToolConfig is a tool config that fails to initialize.
+type brokenToolConfig struct{}
+
+func (brokenToolConfig) ToolConfigKind() string {
+	return "fake-broken"
+}
+
+func (brokenToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return nil, fmt.Errorf("broken")
+}
+
+func isClosed(s *closingSource) bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestReloadClosesSources(t *testing.T) {
+	ctx := context.Background()
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	old := newClosingSource()
+	users := &sourceUsers{}
+	s := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      &sseManager{sseSessions: make(map[string]*sseSession)},
+		sources:         map[string]sources.Source{"my-source": old},
+		sourceUsers:     users,
+	}
+
+	// an invocation still running against the old source
+	users.acquire()
+	replacement := newClosingSource()
+	cfg := ServerConfig{SourceConfigs: SourceConfigs{"my-source": closingSourceConfig{source: replacement}}}
+	if err := s.Reload(ctx, cfg); err != nil {
+		t.Fatalf("unexpected error reloading: %s", err)
+	}
+	if isClosed(old) {
+		t.Fatalf("source closed while an invocation is running against it")
+	}
+	users.release()
+	select {
+	case <-old.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("source not closed once the invocation completed")
+	}
+
+	// the sources of a config that fails to initialize are closed, and the
+	// current ones are kept
+	failed := newClosingSource()
+	cfg = ServerConfig{
+		SourceConfigs: SourceConfigs{"my-source": closingSourceConfig{source: failed}},
+		ToolConfigs:   ToolConfigs{"broken": brokenToolConfig{}},
+	}
+	if err := s.Reload(ctx, cfg); err == nil {
+		t.Fatalf("expected an error reloading an invalid config")
+	}
+	if !isClosed(failed) {
+		t.Fatalf("source of the failed reload not closed")
+	}
+	if isClosed(replacement) {
+		t.Fatalf("current source closed by a failed reload")
+	}
+}
+
+func TestSourceUsers(t *testing.T) {
+	users := &sourceUsers{}
+	idle := make(chan struct{})
+	users.acquire()
+	users.acquire()
+	users.whenIdle(func() { close(idle) })
+	users.release()
+	select {
+	case <-idle:
+		t.Fatalf("idle while an invocation is running")
+	case <-time.After(10 * time.Millisecond):
+	}
+	users.release()
+	select {
+	case <-idle:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("not idle once the invocations completed")
+	}
+}
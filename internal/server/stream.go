@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// ndjsonContentType is the media type of results streamed as one JSON row
+// per line.
+const ndjsonContentType = "application/x-ndjson"
+
+// acceptsNDJSON reports whether the client asked for results streamed as
+// newline-delimited JSON.
+func acceptsNDJSON(r *http.Request) bool {
+	for _, v := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(v)); err == nil && mediaType == ndjsonContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// streamErrorLine is the last line of a stream that failed after some rows
+// were written.
+type streamErrorLine struct {
+	Error string `json:"error"`
+}
+
+// streamResult invokes streamer and writes each row to w as a line of JSON,
+// flushing it to the client as soon as it is written. streamed reports
+// whether the response was started: if not, the caller is responsible for
+// reporting err. If the stream fails after it was started, an object with
+// the error is written as the last line.
+func streamResult(ctx context.Context, w http.ResponseWriter, streamer tools.Streamer, params tools.ParamValues) (streamed bool, err error) {
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	start := func() {
+		if streamed {
+			return
+		}
+		w.Header().Set("Content-Type", ndjsonContentType)
+		w.WriteHeader(http.StatusOK)
+		streamed = true
+	}
+
+	err = streamer.InvokeStream(ctx, params, func(row any) error {
+		start()
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if streamed {
+			_ = enc.Encode(streamErrorLine{Error: err.Error()})
+		}
+		return streamed, err
+	}
+	// results without rows are an empty stream
+	start()
+	return true, nil
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// defaultResourcePollInterval is used when no poll interval is configured.
+const defaultResourcePollInterval = 30 * time.Second
+
+// subscription is a subscription of an sse session to a resource.
+type subscription struct {
+	// cancel stops watching the resource
+	cancel context.CancelFunc
+	// reqCtx carries the auth of the request that subscribed, to resolve the
+	// resource again when the tools are reloaded
+	reqCtx context.Context
+}
+
+// subscribe starts watching a resource on behalf of an sse session, sending a
+// resources/updated notification to the session whenever it changes. Resources
+// of tools that are notified of changes by their source are watched through
+// these notifications, and other resources are polled. reqCtx is the context
+// of the request that subscribed.
+func (s *Server) subscribe(reqCtx context.Context, session *sseSession, uri string, tool tools.Tool) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if _, ok := session.subscriptions[uri]; ok {
+		return
+	}
+	s.watch(reqCtx, session, uri, tool)
+}
+
+// resubscribe moves the subscriptions of every sse session to the current
+// tools, once they are reloaded. This releases what the previous tools hold to
+// be notified, so that their sources can be closed. Subscriptions to resources
+// that no longer exist, or that the subscriber may no longer read, are
+// dropped.
+func (s *Server) resubscribe() {
+	for _, session := range s.sseManager.list() {
+		session.mu.Lock()
+		subscriptions := maps.Clone(session.subscriptions)
+		for uri, sub := range subscriptions {
+			sub.cancel()
+			delete(session.subscriptions, uri)
+			tool, err := resourceTool(sub.reqCtx, s, uri)
+			if err != nil {
+				s.logger.DebugContext(sub.reqCtx, fmt.Sprintf("dropping subscription to resource %q: %s", uri, err))
+				continue
+			}
+			s.watch(sub.reqCtx, session, uri, tool)
+		}
+		session.mu.Unlock()
+	}
+}
+
+// watch starts watching a resource with tool on behalf of an sse session.
+// session.mu must be held.
+func (s *Server) watch(reqCtx context.Context, session *sseSession, uri string, tool tools.Tool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if session.subscriptions == nil {
+		session.subscriptions = make(map[string]subscription)
+	}
+	session.subscriptions[uri] = subscription{cancel: cancel, reqCtx: context.WithoutCancel(reqCtx)}
+	if n, ok := tools.GetNotifier(tool); ok {
+		if changes, ok := n.Notify(util.WithLogger(ctx, s.logger)); ok {
+			go s.watchResource(ctx, session, uri, changes)
+			return
+		}
+	}
+	go s.pollResource(ctx, session, uri, tool)
+}
+
+// unsubscribe stops polling a resource on behalf of an sse session.
+func (s *Server) unsubscribe(session *sseSession, uri string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if sub, ok := session.subscriptions[uri]; ok {
+		sub.cancel()
+		delete(session.subscriptions, uri)
+	}
+}
+
+// pollResource reads a resource at every poll interval until the subscription
+// is cancelled or the session is closed.
+func (s *Server) pollResource(ctx context.Context, session *sseSession, uri string, tool tools.Tool) {
+	interval := s.resourcePollInterval
+	if interval <= 0 {
+		interval = defaultResourcePollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last, err := readResourceSnapshot(ctx, tool)
+	if err != nil {
+		s.logger.DebugContext(ctx, fmt.Sprintf("unable to read resource %q: %s", uri, err))
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-session.done:
+			return
+		case <-ticker.C:
+		}
+
+		current, err := readResourceSnapshot(ctx, tool)
+		if err != nil {
+			s.logger.DebugContext(ctx, fmt.Sprintf("unable to read resource %q: %s", uri, err))
+			continue
+		}
+		if current == last {
+			continue
+		}
+		last = current
+		if !s.notifyResourceUpdated(ctx, session, uri) {
+			return
+		}
+	}
+}
+
+// watchResource notifies an sse session each time changes receives a value,
+// until the subscription is cancelled. The subscription is cancelled when the
+// session is closed, releasing what the tool holds to be notified, such as a
+// database connection. Clients that reconnect subscribe again.
+func (s *Server) watchResource(ctx context.Context, session *sseSession, uri string, changes <-chan struct{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-session.done:
+			s.unsubscribe(session, uri)
+			return
+		case _, ok := <-changes:
+			if !ok {
+				return
+			}
+		}
+		if !s.notifyResourceUpdated(ctx, session, uri) {
+			s.unsubscribe(session, uri)
+			return
+		}
+	}
+}
+
+// notifyResourceUpdated queues a resources/updated notification on an sse
+// session. It returns false if the session is closed.
+func (s *Server) notifyResourceUpdated(ctx context.Context, session *sseSession, uri string) bool {
+	notification := struct {
+		Jsonrpc string `json:"jsonrpc"`
+		mcp.ResourceUpdatedNotification
+	}{Jsonrpc: mcp.JSONRPC_VERSION}
+	notification.Method = "notifications/resources/updated"
+	notification.Params.URI = uri
+	eventData, _ := json.Marshal(notification)
+	select {
+	case session.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
+	case <-session.done:
+		return false
+	default:
+		s.logger.DebugContext(ctx, "unable to add to event queue")
+	}
+	return true
+}
+
+// readResourceSnapshot returns the serialized contents of a resource, used to
+// detect changes between reads.
+func readResourceSnapshot(ctx context.Context, tool tools.Tool) (string, error) {
+	res, err := tool.Invoke(ctx, tools.ParamValues{})
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
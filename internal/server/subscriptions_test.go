@@ -0,0 +1,225 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// counterTool returns the current value of a counter.
+type counterTool struct {
+	MockTool
+	value *atomic.Int64
+}
+
+func (t counterTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+	return []any{t.value.Load()}, nil
+}
+
+func TestResourceSubscription(t *testing.T) {
+	value := &atomic.Int64{}
+	tool := counterTool{MockTool: MockTool{Name: "counter"}, value: value}
+	toolsMap := map[string]tools.Tool{tool.Name: tool}
+	r, shutdown := setUpServer(t, "mcp", toolsMap, nil, func(s *Server) {
+		s.resourcePollInterval = 10 * time.Millisecond
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, err := runSseRequest(ts, "/sse", "")
+	if err != nil {
+		t.Fatalf("unable to run sse request: %s", err)
+	}
+	defer resp.Body.Close()
+	events := make(chan string)
+	go func() {
+		defer close(events)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				events <- data
+			}
+		}
+	}()
+	nextEvent := func() string {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				t.Fatalf("sse stream closed unexpectedly")
+			}
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for sse event")
+		}
+		return ""
+	}
+
+	// the first event is the endpoint to send messages to
+	endpoint := nextEvent()
+	uri := mcp.ResourceURI(tool.Name)
+	body := fmt.Sprintf(`{"jsonrpc": "2.0", "id": "sub", "method": "resources/subscribe", "params": {"uri": %q}}`, uri)
+	postResp, err := http.Post(endpoint, "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("unable to send subscribe request: %s", err)
+	}
+	postResp.Body.Close()
+	if postResp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: want %d, got %d", http.StatusOK, postResp.StatusCode)
+	}
+	if e := nextEvent(); !strings.Contains(e, `"id":"sub"`) || strings.Contains(e, `"error"`) {
+		t.Fatalf("unexpected subscribe response: %s", e)
+	}
+
+	value.Store(42)
+	want := fmt.Sprintf(`{"jsonrpc":"2.0","method":"notifications/resources/updated","params":{"uri":%q}}`, uri)
+	if got := nextEvent(); got != want {
+		t.Fatalf("unexpected notification: got %s, want %s", got, want)
+	}
+}
+
+// notifierTool is a resource that is notified of changes through a channel.
+type notifierTool struct {
+	MockTool
+	changes chan struct{}
+	// listening receives true when a subscription starts listening, and
+	// false when it stops
+	listening chan bool
+}
+
+func (t notifierTool) Notify(ctx context.Context) (<-chan struct{}, bool) {
+	t.listening <- true
+	out := make(chan struct{})
+	go func() {
+		defer close(out)
+		defer func() { t.listening <- false }()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.changes:
+			}
+			select {
+			case out <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, true
+}
+
+// subscribeSse opens an sse session subscribed to the resource at uri. It
+// returns a func that waits for the next event, and a func closing the session.
+func subscribeSse(t *testing.T, ts *httptest.Server, uri string) (func() string, func()) {
+	resp, err := runSseRequest(ts, "/sse", "")
+	if err != nil {
+		t.Fatalf("unable to run sse request: %s", err)
+	}
+	events := make(chan string)
+	go func() {
+		defer close(events)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				events <- data
+			}
+		}
+	}()
+	nextEvent := func() string {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				t.Fatalf("sse stream closed unexpectedly")
+			}
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for sse event")
+		}
+		return ""
+	}
+
+	endpoint := nextEvent()
+	body := fmt.Sprintf(`{"jsonrpc": "2.0", "id": "sub", "method": "resources/subscribe", "params": {"uri": %q}}`, uri)
+	postResp, err := http.Post(endpoint, "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("unable to send subscribe request: %s", err)
+	}
+	postResp.Body.Close()
+	if e := nextEvent(); !strings.Contains(e, `"id":"sub"`) || strings.Contains(e, `"error"`) {
+		t.Fatalf("unexpected subscribe response: %s", e)
+	}
+	return nextEvent, func() { resp.Body.Close() }
+}
+
+func TestResourceSubscriptionNotified(t *testing.T) {
+	tool := notifierTool{
+		MockTool:  MockTool{Name: "events"},
+		changes:   make(chan struct{}),
+		listening: make(chan bool, 1),
+	}
+	toolsMap := map[string]tools.Tool{tool.Name: tool}
+	// resources with notifications are never polled
+	r, shutdown := setUpServer(t, "mcp", toolsMap, nil, func(s *Server) {
+		s.resourcePollInterval = time.Hour
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	waitListening := func(want bool) {
+		t.Helper()
+		select {
+		case got := <-tool.listening:
+			if got != want {
+				t.Fatalf("unexpected listening state: want %t, got %t", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for listening state %t", want)
+		}
+	}
+
+	uri := mcp.ResourceURI(tool.Name)
+	want := fmt.Sprintf(`{"jsonrpc":"2.0","method":"notifications/resources/updated","params":{"uri":%q}}`, uri)
+	nextEvent, closeSession := subscribeSse(t, ts, uri)
+	waitListening(true)
+	tool.changes <- struct{}{}
+	if got := nextEvent(); got != want {
+		t.Fatalf("unexpected notification: got %s, want %s", got, want)
+	}
+
+	// a dropped session stops listening, and a reconnected session listens again
+	closeSession()
+	waitListening(false)
+	nextEvent, closeSession = subscribeSse(t, ts, uri)
+	defer closeSession()
+	waitListening(true)
+	tool.changes <- struct{}{}
+	if got := nextEvent(); got != want {
+		t.Fatalf("unexpected notification after reconnecting: got %s, want %s", got, want)
+	}
+}
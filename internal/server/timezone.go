@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// validate interface
+var _ tools.Tool = timeZoneTool{}
+
+// timeZoneTool is a Tool that converts the time values in its results to a
+// single time zone.
+type timeZoneTool struct {
+	tools.Tool
+	loc *time.Location
+}
+
+func (t timeZoneTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t timeZoneTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	res, err := t.Tool.Invoke(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]any, len(res))
+	for i, v := range res {
+		converted[i] = inTimeZone(v, t.loc)
+	}
+	return converted, nil
+}
+
+// Stream converts the time values of each streamed row.
+func (t timeZoneTool) Stream() (tools.Streamer, bool) {
+	s, ok := tools.GetStreamer(t.Tool)
+	if !ok {
+		return nil, false
+	}
+	return tools.StreamerFunc(func(ctx context.Context, params tools.ParamValues, yield func(row any) error) error {
+		return s.InvokeStream(ctx, params, func(row any) error {
+			return yield(inTimeZone(row, t.loc))
+		})
+	}), true
+}
+
+// inTimeZone converts time values in v, including those nested in maps and
+// slices, to loc. Maps and slices are copied rather than modified, since they
+// may be shared with the result cache.
+func inTimeZone(v any, loc *time.Location) any {
+	switch v := v.(type) {
+	case time.Time:
+		return v.In(loc)
+	case *time.Time:
+		if v == nil {
+			return v
+		}
+		t := v.In(loc)
+		return &t
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = inTimeZone(e, loc)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, e := range v {
+			s[i] = inTimeZone(e, loc)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// withTimeZone wraps all tools so their time values are converted to loc. The
+// tools are returned unchanged if loc is nil.
+func withTimeZone(toolsMap map[string]tools.Tool, loc *time.Location) map[string]tools.Tool {
+	if loc == nil {
+		return toolsMap
+	}
+	for name, t := range toolsMap {
+		toolsMap[name] = timeZoneTool{Tool: t, loc: loc}
+	}
+	return toolsMap
+}
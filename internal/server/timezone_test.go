@@ -0,0 +1,93 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// timestampTool returns a single row with a timestamp column.
+type timestampTool struct {
+	MockTool
+	ts time.Time
+}
+
+func (t timestampTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+	return []any{map[string]any{"id": 1, "created_at": t.ts}}, nil
+}
+
+func TestToolInvokeTimeZone(t *testing.T) {
+	// the source returns timestamps in its own zone
+	ts := time.Date(2025, 3, 1, 9, 30, 0, 0, time.FixedZone("UTC-8", -8*60*60))
+	tool := timestampTool{MockTool: MockTool{Name: "timestamps"}, ts: ts}
+
+	tcs := []struct {
+		name string
+		loc  *time.Location
+		want string
+	}{
+		{
+			name: "source zone",
+			want: "2025-03-01T09:30:00-08:00",
+		},
+		{
+			name: "utc",
+			loc:  time.UTC,
+			want: "2025-03-01T17:30:00Z",
+		},
+		{
+			name: "configured zone",
+			loc:  time.FixedZone("UTC+5:30", 5*60*60+30*60),
+			want: "2025-03-01T23:00:00+05:30",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			toolsMap := withTimeZone(map[string]tools.Tool{tool.Name: tool}, tc.loc)
+			r, shutdown := setUpServer(t, "api", toolsMap, nil)
+			defer shutdown()
+			srv := runServer(r, false)
+			defer srv.Close()
+
+			resp, body, err := runRequest(srv, http.MethodPost, "/tool/timestamps/invoke", bytes.NewBufferString(`{}`))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(body))
+			}
+			var got resultResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			var rows []map[string]any
+			if err := json.Unmarshal([]byte(got.Result), &rows); err != nil {
+				t.Fatalf("unable to parse result: %s", err)
+			}
+			want := []map[string]any{{"id": float64(1), "created_at": tc.want}}
+			if !reflect.DeepEqual(rows, want) {
+				t.Fatalf("unexpected result: got %v, want %v", rows, want)
+			}
+		})
+	}
+}
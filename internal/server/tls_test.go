@@ -0,0 +1,274 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/inmemory"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/tools/inmemorylookup"
+)
+
+// testCert is a certificate and its key, signed by a test CA.
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certFile string
+	keyFile  string
+}
+
+// newTestCert creates a certificate signed by parent, or a self-signed CA if
+// parent is nil, and writes it to dir.
+func newTestCert(t *testing.T, dir, name string, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA, tmpl.BasicConstraintsValid = true, true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("unable to parse certificate: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unable to marshal key: %s", err)
+	}
+	c := &testCert{
+		cert:     cert,
+		key:      key,
+		certFile: filepath.Join(dir, name+".crt"),
+		keyFile:  filepath.Join(dir, name+".key"),
+	}
+	if err := os.WriteFile(c.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("unable to write certificate: %s", err)
+	}
+	if err := os.WriteFile(c.keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("unable to write key: %s", err)
+	}
+	return c
+}
+
+// tlsClient returns a client that trusts ca, and presents cert if it's not nil.
+func tlsClient(t *testing.T, ca, cert *testCert) *http.Client {
+	t.Helper()
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	tlsConfig := &tls.Config{RootCAs: pool}
+	if cert != nil {
+		pair, err := tls.LoadX509KeyPair(cert.certFile, cert.keyFile)
+		if err != nil {
+			t.Fatalf("unable to load client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+}
+
+func TestServeTLS(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	ca := newTestCert(t, dir, "ca", nil)
+	serverCert := newTestCert(t, dir, "server", ca)
+	clientCert := newTestCert(t, dir, "client", ca)
+
+	otelShutdown, err := telemetry.SetupOTel(ctx, "0.0.0", "", false, "toolbox", false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer func() {
+		err := otelShutdown(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}()
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tcs := []struct {
+		name         string
+		port         int
+		clientCAFile string
+	}{
+		{
+			name: "tls",
+			port: 5004,
+		},
+		{
+			name:         "mutual tls",
+			port:         5005,
+			clientCAFile: ca.certFile,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			addr := "127.0.0.1"
+			cfg := server.ServerConfig{
+				Version:         "0.0.0",
+				Address:         addr,
+				Port:            tc.port,
+				TLSCertFile:     serverCert.certFile,
+				TLSKeyFile:      serverCert.keyFile,
+				TLSClientCAFile: tc.clientCAFile,
+				SourceConfigs: server.SourceConfigs{
+					"my-mem": inmemory.Config{
+						Name:   "my-mem",
+						Kind:   inmemory.SourceKind,
+						Tables: map[string]inmemory.Table{"users": {{"id": 1}}},
+					},
+				},
+				ToolConfigs: server.ToolConfigs{
+					"list_users": inmemorylookup.Config{
+						Name:        "list_users",
+						Kind:        "in-memory-lookup",
+						Source:      "my-mem",
+						Description: "List users.",
+						Table:       "users",
+					},
+				},
+			}
+			s, err := server.NewServer(ctx, cfg, testLogger)
+			if err != nil {
+				t.Fatalf("unable to initialize server: %v", err)
+			}
+			if err := s.Listen(ctx); err != nil {
+				t.Fatalf("unable to start server: %v", err)
+			}
+			go func() {
+				_ = s.Serve(ctx)
+			}()
+			defer func() { _ = s.Shutdown(ctx) }()
+
+			invoke := func(client *http.Client, scheme string) (*http.Response, error) {
+				url := fmt.Sprintf("%s://%s:%d/api/tool/list_users/invoke", scheme, addr, tc.port)
+				resp, err := client.Post(url, "application/json", strings.NewReader("{}"))
+				if err == nil {
+					resp.Body.Close()
+				}
+				return resp, err
+			}
+
+			// the client presents a certificate only if it is required
+			var cert *testCert
+			if tc.clientCAFile != "" {
+				cert = clientCert
+			}
+			resp, err := invoke(tlsClient(t, ca, cert), "https")
+			if err != nil {
+				t.Fatalf("error when sending an HTTPS request: %s", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected status code: %d", resp.StatusCode)
+			}
+
+			// plain HTTP is refused
+			resp, err = invoke(http.DefaultClient, "http")
+			if err == nil && resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("expected a plain HTTP request to be refused, got status code %d", resp.StatusCode)
+			}
+
+			if tc.clientCAFile != "" {
+				// clients without a certificate are refused
+				if resp, err := invoke(tlsClient(t, ca, nil), "https"); err == nil {
+					t.Fatalf("expected a request without a client certificate to fail, got status code %d", resp.StatusCode)
+				}
+			}
+		})
+	}
+}
+
+func TestNewServerInvalidTLS(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	ca := newTestCert(t, dir, "ca", nil)
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tcs := []struct {
+		name string
+		cfg  server.ServerConfig
+	}{
+		{
+			name: "certificate without key",
+			cfg:  server.ServerConfig{TLSCertFile: ca.certFile},
+		},
+		{
+			name: "client CA without certificate",
+			cfg:  server.ServerConfig{TLSClientCAFile: ca.certFile},
+		},
+		{
+			name: "missing certificate file",
+			cfg:  server.ServerConfig{TLSCertFile: filepath.Join(dir, "missing.crt"), TLSKeyFile: ca.keyFile},
+		},
+		{
+			name: "invalid client CA",
+			cfg:  server.ServerConfig{TLSCertFile: ca.certFile, TLSKeyFile: ca.keyFile, TLSClientCAFile: ca.keyFile},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cfg.Version = "0.0.0"
+			if _, err := server.NewServer(ctx, tc.cfg, testLogger); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const (
+	// paramsField is the multipart form field holding the non-file
+	// parameters as a JSON object.
+	paramsField = "params"
+	// fileParamType is the manifest type of "file" parameters.
+	fileParamType = "file"
+)
+
+// isMultipart returns true if the request body is multipart/form-data.
+func isMultipart(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "multipart/form-data"
+}
+
+// decodeMultipart decodes the parameters of a multipart/form-data invoke
+// request. Uploaded files are passed as the content of the "file" parameter
+// with the same name as the form field, and the remaining parameters are read
+// from the JSON object in the "params" field.
+func decodeMultipart(r *http.Request, tool tools.Tool, maxSize int64) (map[string]any, error) {
+	if err := r.ParseMultipartForm(maxSize); err != nil {
+		return nil, fmt.Errorf("unable to parse multipart form: %w", err)
+	}
+	defer r.MultipartForm.RemoveAll() //nolint:errcheck
+
+	data := make(map[string]any)
+	if v := r.MultipartForm.Value[paramsField]; len(v) > 0 {
+		if err := decodeJSON(strings.NewReader(v[0]), &data); err != nil {
+			return nil, fmt.Errorf("%q field was invalid JSON: %w", paramsField, err)
+		}
+	}
+
+	fileParams := make(map[string]bool)
+	for _, p := range tool.Manifest().Parameters {
+		if p.Type == fileParamType {
+			fileParams[p.Name] = true
+		}
+	}
+	for name, headers := range r.MultipartForm.File {
+		if !fileParams[name] {
+			return nil, fmt.Errorf("unexpected file %q: tool has no file parameter with that name", name)
+		}
+		if len(headers) != 1 {
+			return nil, fmt.Errorf("expected a single file for parameter %q, got %d", name, len(headers))
+		}
+		f, err := headers[0].Open()
+		if err != nil {
+			return nil, fmt.Errorf("unable to open file %q: %w", name, err)
+		}
+		content, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read file %q: %w", name, err)
+		}
+		data[name] = string(content)
+	}
+	return data, nil
+}
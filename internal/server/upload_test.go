@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// csvTool returns the rows of an uploaded CSV file, prefixed with a table name.
+type csvTool struct {
+	MockTool
+}
+
+func (t csvTool) Invoke(_ context.Context, params tools.ParamValues) ([]any, error) {
+	m := params.AsMap()
+	records, err := csv.NewReader(strings.NewReader(m["rows"].(string))).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]any, 0, len(records))
+	for _, r := range records {
+		out = append(out, m["table"].(string)+":"+strings.Join(r, "|"))
+	}
+	return out, nil
+}
+
+func newUploadRequest(t *testing.T, url string, params string, files map[string]string) *http.Request {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if params != "" {
+		if err := w.WriteField("params", params); err != nil {
+			t.Fatalf("unable to write field: %s", err)
+		}
+	}
+	for name, content := range files {
+		fw, err := w.CreateFormFile(name, name+".csv")
+		if err != nil {
+			t.Fatalf("unable to create form file: %s", err)
+		}
+		if _, err := io.WriteString(fw, content); err != nil {
+			t.Fatalf("unable to write form file: %s", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unable to close multipart writer: %s", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, url, &body)
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestToolInvokeFileUpload(t *testing.T) {
+	tool := csvTool{MockTool: MockTool{
+		Name: "csv_tool",
+		Params: []tools.Parameter{
+			tools.NewStringParameter("table", "table to insert into"),
+			tools.NewFileParameter("rows", "csv file of rows to insert"),
+		},
+	}}
+	toolsMap := map[string]tools.Tool{tool.Name: tool}
+	r, shutdown := setUpServer(t, "api", toolsMap, nil, func(s *Server) {
+		s.maxUploadSize = 1024
+	})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name           string
+		params         string
+		files          map[string]string
+		want           []any
+		wantStatusCode int
+	}{
+		{
+			name:           "upload csv",
+			params:         `{"table": "users"}`,
+			files:          map[string]string{"rows": "1,alice\n2,bob\n3,sid\n"},
+			want:           []any{"users:1|alice", "users:2|bob", "users:3|sid"},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "missing file",
+			params:         `{"table": "users"}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "undeclared file",
+			params:         `{"table": "users"}`,
+			files:          map[string]string{"rows": "1,alice\n", "other": "2,bob\n"},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "file too large",
+			params:         `{"table": "users"}`,
+			files:          map[string]string{"rows": strings.Repeat("1,alice\n", 200)},
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req := newUploadRequest(t, ts.URL+"/tool/csv_tool/invoke", tc.params, tc.files)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatusCode {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.wantStatusCode, resp.StatusCode, string(body))
+			}
+			if tc.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var got resultResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response body: %s", err)
+			}
+			var rows []any
+			if err := json.Unmarshal([]byte(got.Result), &rows); err != nil {
+				t.Fatalf("unable to parse result: %s", err)
+			}
+			if !reflect.DeepEqual(rows, tc.want) {
+				t.Fatalf("unexpected result: got %v, want %v", rows, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// toolVersionHeader selects the version of a tool invoked by its unversioned
+// name.
+const toolVersionHeader = "Toolbox-Tool-Version"
+
+// versionedName returns the name a tool is served under, given the name it
+// was configured with and its `version` option. Versioned tools are served as
+// "<name>@<version>", so several versions of a tool can coexist.
+func versionedName(name, version string) (string, error) {
+	if version == "" {
+		return name, nil
+	}
+	if !tools.IsValidName(version) {
+		return "", fmt.Errorf("invalid version %q for tool %q: versions may only contain letters, numbers, underscores and hyphens", version, name)
+	}
+	if _, v, ok := strings.Cut(name, "@"); ok {
+		if v != version {
+			return "", fmt.Errorf("version %q of tool %q doesn't match its name", version, name)
+		}
+		return name, nil
+	}
+	return name + "@" + version, nil
+}
+
+// resolveTool returns the tool that name refers to, along with the name it is
+// served under. If name has no version suffix, the version may be given
+// separately. An unversioned name also refers to the only version of a tool.
+func (s *Server) resolveTool(name, version string) (string, tools.Tool, bool) {
+	if version != "" && !strings.Contains(name, "@") {
+		name = name + "@" + version
+	}
+	toolsMap := s.getTools()
+	if t, ok := toolsMap[name]; ok {
+		return name, t, true
+	}
+	if strings.Contains(name, "@") {
+		return "", nil, false
+	}
+	var found string
+	for n := range toolsMap {
+		if base, _, ok := strings.Cut(n, "@"); ok && base == name {
+			if found != "" {
+				// ambiguous, a version must be given
+				return "", nil, false
+			}
+			found = n
+		}
+	}
+	if found == "" {
+		return "", nil, false
+	}
+	return found, toolsMap[found], true
+}
+
+// validate interface
+var _ tools.Tool = versionedTool{}
+
+// versionedTool is one of several versions of a tool.
+type versionedTool struct {
+	tools.Tool
+	version string
+}
+
+func (t versionedTool) Unwrap() tools.Tool {
+	return t.Tool
+}
+
+func (t versionedTool) Manifest() tools.Manifest {
+	m := t.Tool.Manifest()
+	m.Version = t.version
+	return m
+}
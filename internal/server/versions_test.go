@@ -0,0 +1,179 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestVersionedName(t *testing.T) {
+	tcs := []struct {
+		name    string
+		version string
+		want    string
+		wantErr bool
+	}{
+		{name: "my-tool", want: "my-tool"},
+		{name: "my-tool", version: "v1", want: "my-tool@v1"},
+		{name: "my-tool@v1", version: "v1", want: "my-tool@v1"},
+		{name: "my-tool@v1", version: "v2", wantErr: true},
+		{name: "my-tool", version: "v1.0", wantErr: true},
+	}
+	for _, tc := range tcs {
+		got, err := versionedName(tc.name, tc.version)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("versionedName(%q, %q): expected error", tc.name, tc.version)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("versionedName(%q, %q): unexpected error: %s", tc.name, tc.version, err)
+		}
+		if got != tc.want {
+			t.Fatalf("versionedName(%q, %q): want %q, got %q", tc.name, tc.version, tc.want, got)
+		}
+	}
+}
+
+func TestToolInvokeVersions(t *testing.T) {
+	v1 := MockTool{
+		Name:   "my-tool@v1",
+		Params: tools.Parameters{tools.NewIntParameter("id", "The id to look up.")},
+	}
+	v2 := MockTool{
+		Name:   "my-tool@v2",
+		Params: tools.Parameters{tools.NewStringParameter("query", "The query to run.")},
+	}
+	toolsMap := map[string]tools.Tool{
+		v1.Name: versionedTool{Tool: v1, version: "v1"},
+		v2.Name: versionedTool{Tool: v2, version: "v2"},
+	}
+	tc := tools.ToolsetConfig{ToolNames: []string{v1.Name, v2.Name}}
+	toolset, err := tc.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, map[string]tools.Toolset{"": toolset})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	invoke := func(path, version, body string) (int, string) {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+path, bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if version != "" {
+			req.Header.Set(toolVersionHeader, version)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		defer resp.Body.Close()
+		got, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body: %s", err)
+		}
+		return resp.StatusCode, string(got)
+	}
+
+	testCases := []struct {
+		name       string
+		path       string
+		version    string
+		body       string
+		wantStatus int
+		want       string
+	}{
+		{
+			name:       "v1 by suffix",
+			path:       "/tool/my-tool@v1/invoke",
+			body:       `{"id": 1}`,
+			wantStatus: http.StatusOK,
+			want:       "my-tool@v1",
+		},
+		{
+			name:       "v2 by suffix",
+			path:       "/tool/my-tool@v2/invoke",
+			body:       `{"query": "hello"}`,
+			wantStatus: http.StatusOK,
+			want:       "my-tool@v2",
+		},
+		{
+			name:       "v2 by header",
+			path:       "/tool/my-tool/invoke",
+			version:    "v2",
+			body:       `{"query": "hello"}`,
+			wantStatus: http.StatusOK,
+			want:       "my-tool@v2",
+		},
+		{
+			name:       "v1 rejects v2 parameters",
+			path:       "/tool/my-tool@v1/invoke",
+			body:       `{"query": "hello"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown version",
+			path:       "/tool/my-tool@v3/invoke",
+			body:       `{}`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "ambiguous without version",
+			path:       "/tool/my-tool/invoke",
+			body:       `{"id": 1}`,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, body := invoke(tc.path, tc.version, tc.body)
+			if status != tc.wantStatus {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.wantStatus, status, body)
+			}
+			if !strings.Contains(body, tc.want) {
+				t.Fatalf("unexpected response: want %q in %s", tc.want, body)
+			}
+		})
+	}
+
+	resp, body, err := runRequest(ts, http.MethodGet, "/toolset", nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+	var m tools.ToolsetManifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		t.Fatalf("unable to parse toolset manifest: %s", err)
+	}
+	for name, version := range map[string]string{"my-tool@v1": "v1", "my-tool@v2": "v2"} {
+		if got := m.ToolsManifest[name].Version; got != version {
+			t.Fatalf("unexpected version for %q: want %q, got %q", name, version, got)
+		}
+	}
+}
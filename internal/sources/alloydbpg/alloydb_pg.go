@@ -48,16 +48,20 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name     string         `yaml:"name" validate:"required"`
-	Kind     string         `yaml:"kind" validate:"required"`
-	Project  string         `yaml:"project" validate:"required"`
-	Region   string         `yaml:"region" validate:"required"`
-	Cluster  string         `yaml:"cluster" validate:"required"`
-	Instance string         `yaml:"instance" validate:"required"`
-	IPType   sources.IPType `yaml:"ipType" validate:"required"`
-	User     string         `yaml:"user"`
-	Password string         `yaml:"password"`
-	Database string         `yaml:"database" validate:"required"`
+	Name        string         `yaml:"name" validate:"required"`
+	Kind        string         `yaml:"kind" validate:"required"`
+	Project     string         `yaml:"project" validate:"required"`
+	Region      string         `yaml:"region" validate:"required"`
+	Cluster     string         `yaml:"cluster" validate:"required"`
+	Instance    string         `yaml:"instance" validate:"required"`
+	IPType      sources.IPType `yaml:"ipType" validate:"required"`
+	User        string         `yaml:"user"`
+	Password    string         `yaml:"password"`
+	Database    string         `yaml:"database" validate:"required"`
+	HealthQuery string         `yaml:"healthQuery"`
+
+	// PoolConfig sizes the connection pool.
+	sources.PoolConfig `yaml:",inline"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -65,21 +69,25 @@ func (r Config) SourceConfigKind() string {
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
-	pool, err := initAlloyDBPgConnectionPool(ctx, tracer, r.Name, r.Project, r.Region, r.Cluster, r.Instance, r.IPType.String(), r.User, r.Password, r.Database)
+	pool, err := initAlloyDBPgConnectionPool(ctx, tracer, r.Name, r.Project, r.Region, r.Cluster, r.Instance, r.IPType.String(), r.User, r.Password, r.Database, r.PoolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create pool: %w", err)
 	}
 
-	err = pool.Ping(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to connect successfully: %w", err)
-	}
-
 	s := &Source{
 		Name: r.Name,
 		Kind: SourceKind,
 		Pool: pool,
 	}
+
+	if r.HealthQuery != "" {
+		_, err = pool.Exec(ctx, r.HealthQuery)
+	} else {
+		err = pool.Ping(ctx)
+	}
+	if err != nil {
+		return nil, &sources.UnreachableError{Source: s, Err: err}
+	}
 	return s, nil
 }
 
@@ -145,7 +153,7 @@ func getConnectionConfig(ctx context.Context, user, pass, dbname string) (string
 	return dsn, useIAM, nil
 }
 
-func initAlloyDBPgConnectionPool(ctx context.Context, tracer trace.Tracer, name, project, region, cluster, instance, ipType, user, pass, dbname string) (*pgxpool.Pool, error) {
+func initAlloyDBPgConnectionPool(ctx context.Context, tracer trace.Tracer, name, project, region, cluster, instance, ipType, user, pass, dbname string, poolConfig sources.PoolConfig) (*pgxpool.Pool, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
@@ -179,6 +187,10 @@ func initAlloyDBPgConnectionPool(ctx context.Context, tracer trace.Tracer, name,
 		return d.Dial(ctx, i)
 	}
 
+	if err := poolConfig.ApplyPgx(config); err != nil {
+		return nil, err
+	}
+
 	// Interact with the driver directly as you normally would
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
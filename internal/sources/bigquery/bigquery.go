@@ -16,7 +16,9 @@ package bigquery
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
 	"github.com/goccy/go-yaml"
@@ -24,6 +26,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 )
 
@@ -52,6 +55,12 @@ type Config struct {
 	Kind     string `yaml:"kind" validate:"required"`
 	Project  string `yaml:"project" validate:"required"`
 	Location string `yaml:"location"`
+	// Credentials is the path of a service account key file to authenticate
+	// with. Application Default Credentials are used if it is not set.
+	Credentials string `yaml:"credentials"`
+	// MaximumBytesBilled, if set, has BigQuery fail the queries that would
+	// bill more bytes, instead of running them.
+	MaximumBytesBilled int64 `yaml:"maximumBytesBilled" validate:"gte=0"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -61,7 +70,7 @@ func (r Config) SourceConfigKind() string {
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
 	// Initializes a BigQuery Google SQL source
-	client, err := initBigQueryConnection(ctx, tracer, r.Name, r.Project, r.Location)
+	client, err := initBigQueryConnection(ctx, tracer, r.Name, r.Project, r.Location, r.Credentials)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +79,8 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 		Kind:     SourceKind,
 		Client:   client,
 		Location: r.Location,
+
+		MaximumBytesBilled: r.MaximumBytesBilled,
 	}
 	return s, nil
 
@@ -83,6 +94,8 @@ type Source struct {
 	Kind     string `yaml:"kind"`
 	Client   *bigqueryapi.Client
 	Location string `yaml:"location"`
+
+	MaximumBytesBilled int64 `yaml:"maximumBytesBilled"`
 }
 
 func (s *Source) SourceKind() string {
@@ -94,19 +107,31 @@ func (s *Source) BigQueryClient() *bigqueryapi.Client {
 	return s.Client
 }
 
+// Close closes the client's connections.
+func (s *Source) Close() error {
+	return s.Client.Close()
+}
+
+// BigQueryMaximumBytesBilled returns the maximum bytes billed of the queries
+// of the source, or 0 if the project default applies.
+func (s *Source) BigQueryMaximumBytesBilled() int64 {
+	return s.MaximumBytesBilled
+}
+
 func initBigQueryConnection(
 	ctx context.Context,
 	tracer trace.Tracer,
 	name string,
 	project string,
 	location string,
+	credentials string,
 ) (*bigqueryapi.Client, error) {
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
 
-	cred, err := google.FindDefaultCredentials(ctx, bigqueryapi.Scope)
+	cred, err := findCredentials(ctx, credentials)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find default Google Cloud credentials with scope %q: %w", bigqueryapi.Scope, err)
+		return nil, err
 	}
 
 	userAgent, err := util.UserAgentFromContext(ctx)
@@ -115,9 +140,65 @@ func initBigQueryConnection(
 	}
 
 	client, err := bigqueryapi.NewClient(ctx, project, option.WithUserAgent(userAgent), option.WithCredentials(cred))
-	client.Location = location
 	if err != nil {
 		return nil, fmt.Errorf("failed to create BigQuery client for project %q: %w", project, err)
 	}
+	client.Location = location
 	return client, nil
 }
+
+// findCredentials returns the credentials of the service account key file at
+// path, or the Application Default Credentials if path is empty.
+func findCredentials(ctx context.Context, path string) (*google.Credentials, error) {
+	if path == "" {
+		cred, err := google.FindDefaultCredentials(ctx, bigqueryapi.Scope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find default Google Cloud credentials with scope %q: %w", bigqueryapi.Scope, err)
+		}
+		return cred, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read credentials file: %w", err)
+	}
+	cred, err := google.CredentialsFromJSON(ctx, b, bigqueryapi.Scope)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse credentials file %q: %w", path, err)
+	}
+	return cred, nil
+}
+
+// JobCost returns the bytes processed and billed by a query job, as reported in
+// its status, or nil if the status has no statistics.
+func JobCost(status *bigqueryapi.JobStatus) map[string]any {
+	if status == nil || status.Statistics == nil {
+		return nil
+	}
+	cost := map[string]any{"totalBytesProcessed": status.Statistics.TotalBytesProcessed}
+	if q, ok := status.Statistics.Details.(*bigqueryapi.QueryStatistics); ok {
+		cost["totalBytesBilled"] = q.TotalBytesBilled
+	}
+	return cost
+}
+
+// bytesBilledLimitExceeded is the reason of the errors of queries that would
+// bill more than their maximum bytes billed.
+const bytesBilledLimitExceeded = "bytesBilledLimitExceeded"
+
+// LimitError wraps err with sources.ErrLimitExceeded if the query failed
+// because it would bill more than its maximum bytes billed.
+func LimitError(err error) error {
+	var jobErr *bigqueryapi.Error
+	if errors.As(err, &jobErr) && jobErr.Reason == bytesBilledLimitExceeded {
+		return fmt.Errorf("%w: %w", sources.ErrLimitExceeded, err)
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		for _, e := range apiErr.Errors {
+			if e.Reason == bytesBilledLimitExceeded {
+				return fmt.Errorf("%w: %w", sources.ErrLimitExceeded, err)
+			}
+		}
+	}
+	return err
+}
@@ -15,13 +15,18 @@
 package bigquery_test
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
+	bigqueryapi "cloud.google.com/go/bigquery"
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/bigquery"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"google.golang.org/api/googleapi"
 )
 
 func TestParseFromYamlBigQuery(t *testing.T) {
@@ -48,6 +53,44 @@ func TestParseFromYamlBigQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with credentials",
+			in: `
+			sources:
+				my-instance:
+					kind: bigquery
+					project: my-project
+					location: us
+					credentials: /secrets/bigquery-key.json
+			`,
+			want: server.SourceConfigs{
+				"my-instance": bigquery.Config{
+					Name:        "my-instance",
+					Kind:        bigquery.SourceKind,
+					Project:     "my-project",
+					Location:    "us",
+					Credentials: "/secrets/bigquery-key.json",
+				},
+			},
+		},
+		{
+			desc: "with maximum bytes billed",
+			in: `
+			sources:
+				my-instance:
+					kind: bigquery
+					project: my-project
+					maximumBytesBilled: 1000000000
+			`,
+			want: server.SourceConfigs{
+				"my-instance": bigquery.Config{
+					Name:               "my-instance",
+					Kind:               bigquery.SourceKind,
+					Project:            "my-project",
+					MaximumBytesBilled: 1000000000,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -113,3 +156,86 @@ func TestFailParseFromYaml(t *testing.T) {
 		})
 	}
 }
+
+func TestJobCost(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		status *bigqueryapi.JobStatus
+		want   map[string]any
+	}{
+		{
+			desc: "query job",
+			status: &bigqueryapi.JobStatus{
+				State: bigqueryapi.Done,
+				Statistics: &bigqueryapi.JobStatistics{
+					TotalBytesProcessed: 2048,
+					Details: &bigqueryapi.QueryStatistics{
+						TotalBytesProcessed: 2048,
+						TotalBytesBilled:    10485760,
+					},
+				},
+			},
+			want: map[string]any{"totalBytesProcessed": int64(2048), "totalBytesBilled": int64(10485760)},
+		},
+		{
+			desc: "dry run",
+			status: &bigqueryapi.JobStatus{
+				Statistics: &bigqueryapi.JobStatistics{TotalBytesProcessed: 512},
+			},
+			want: map[string]any{"totalBytesProcessed": int64(512)},
+		},
+		{
+			desc:   "no statistics",
+			status: &bigqueryapi.JobStatus{State: bigqueryapi.Running},
+		},
+		{
+			desc: "no status",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := bigquery.JobCost(tc.status)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect cost: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestLimitError(t *testing.T) {
+	tcs := []struct {
+		desc string
+		err  error
+		want bool
+	}{
+		{
+			desc: "job error",
+			err:  &bigqueryapi.Error{Reason: "bytesBilledLimitExceeded", Message: "Query exceeded limit for bytes billed: 1000000000."},
+			want: true,
+		},
+		{
+			desc: "api error",
+			err:  &googleapi.Error{Code: 400, Errors: []googleapi.ErrorItem{{Reason: "bytesBilledLimitExceeded"}}},
+			want: true,
+		},
+		{
+			desc: "other job error",
+			err:  &bigqueryapi.Error{Reason: "invalidQuery", Message: "Syntax error"},
+		},
+		{
+			desc: "other error",
+			err:  fmt.Errorf("unable to read results"),
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := bigquery.LimitError(tc.err)
+			if got := errors.Is(err, sources.ErrLimitExceeded); got != tc.want {
+				t.Fatalf("unexpected limit error: got %t, want %t", got, tc.want)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected the error to wrap %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -86,6 +86,11 @@ func (s *Source) BigtableClient() *bigtable.Client {
 	return s.Client
 }
 
+// Close closes the client's connections.
+func (s *Source) Close() error {
+	return s.Client.Close()
+}
+
 func initBigtableClient(ctx context.Context, tracer trace.Tracer, name, project, instance string) (*bigtable.Client, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
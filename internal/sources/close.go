@@ -0,0 +1,43 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"database/sql"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Close releases the connections held by src, by closing its connection pool
+// if it doesn't implement io.Closer. Sources holding neither are left as they
+// are. src must not be used afterwards.
+func Close(src Source) error {
+	switch s := src.(type) {
+	case io.Closer:
+		return s.Close()
+	case interface{ PostgresPool() *pgxpool.Pool }:
+		s.PostgresPool().Close()
+		return nil
+	case interface{ MySQLPool() *sql.DB }:
+		return s.MySQLPool().Close()
+	case interface{ MSSQLDB() *sql.DB }:
+		return s.MSSQLDB().Close()
+	case interface{ SQLiteDB() *sql.DB }:
+		return s.SQLiteDB().Close()
+	default:
+		return nil
+	}
+}
@@ -48,16 +48,20 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 
 type Config struct {
 	// Cloud SQL MSSQL configs
-	Name      string         `yaml:"name" validate:"required"`
-	Kind      string         `yaml:"kind" validate:"required"`
-	Project   string         `yaml:"project" validate:"required"`
-	Region    string         `yaml:"region" validate:"required"`
-	Instance  string         `yaml:"instance" validate:"required"`
-	IPAddress string         `yaml:"ipAddress" validate:"required"`
-	IPType    sources.IPType `yaml:"ipType" validate:"required"`
-	User      string         `yaml:"user" validate:"required"`
-	Password  string         `yaml:"password" validate:"required"`
-	Database  string         `yaml:"database" validate:"required"`
+	Name        string         `yaml:"name" validate:"required"`
+	Kind        string         `yaml:"kind" validate:"required"`
+	Project     string         `yaml:"project" validate:"required"`
+	Region      string         `yaml:"region" validate:"required"`
+	Instance    string         `yaml:"instance" validate:"required"`
+	IPAddress   string         `yaml:"ipAddress" validate:"required"`
+	IPType      sources.IPType `yaml:"ipType" validate:"required"`
+	User        string         `yaml:"user" validate:"required"`
+	Password    string         `yaml:"password" validate:"required"`
+	Database    string         `yaml:"database" validate:"required"`
+	HealthQuery string         `yaml:"healthQuery"`
+
+	// PoolConfig sizes the connection pool.
+	sources.PoolConfig `yaml:",inline"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -67,22 +71,26 @@ func (r Config) SourceConfigKind() string {
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
 	// Initializes a Cloud SQL MSSQL source
-	db, err := initCloudSQLMssqlConnection(ctx, tracer, r.Name, r.Project, r.Region, r.Instance, r.IPAddress, r.IPType.String(), r.User, r.Password, r.Database)
+	db, err := initCloudSQLMssqlConnection(ctx, tracer, r.Name, r.Project, r.Region, r.Instance, r.IPAddress, r.IPType.String(), r.User, r.Password, r.Database, r.PoolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create db connection: %w", err)
 	}
 
-	// Verify db connection
-	err = db.PingContext(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to connect successfully: %w", err)
-	}
-
 	s := &Source{
 		Name: r.Name,
 		Kind: SourceKind,
 		Db:   db,
 	}
+
+	// Verify db connection
+	if r.HealthQuery != "" {
+		_, err = db.ExecContext(ctx, r.HealthQuery)
+	} else {
+		err = db.PingContext(ctx)
+	}
+	if err != nil {
+		return nil, &sources.UnreachableError{Source: s, Err: err}
+	}
 	return s, nil
 }
 
@@ -105,7 +113,7 @@ func (s *Source) MSSQLDB() *sql.DB {
 	return s.Db
 }
 
-func initCloudSQLMssqlConnection(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipAddress, ipType, user, pass, dbname string) (*sql.DB, error) {
+func initCloudSQLMssqlConnection(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipAddress, ipType, user, pass, dbname string, poolConfig sources.PoolConfig) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
@@ -132,12 +140,10 @@ func initCloudSQLMssqlConnection(ctx context.Context, tracer trace.Tracer, name,
 	}
 
 	// Open database connection
-	db, err := sql.Open(
-		"cloudsql-sqlserver-driver",
-		dsn,
-	)
+	db, err := sources.OpenDB("cloudsql-sqlserver-driver", dsn, poolConfig.ConnMaxIdleTime)
 	if err != nil {
 		return nil, err
 	}
+	poolConfig.ApplyDB(db)
 	return db, nil
 }
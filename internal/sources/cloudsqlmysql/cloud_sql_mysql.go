@@ -47,15 +47,19 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name     string         `yaml:"name" validate:"required"`
-	Kind     string         `yaml:"kind" validate:"required"`
-	Project  string         `yaml:"project" validate:"required"`
-	Region   string         `yaml:"region" validate:"required"`
-	Instance string         `yaml:"instance" validate:"required"`
-	IPType   sources.IPType `yaml:"ipType" validate:"required"`
-	User     string         `yaml:"user" validate:"required"`
-	Password string         `yaml:"password" validate:"required"`
-	Database string         `yaml:"database" validate:"required"`
+	Name        string         `yaml:"name" validate:"required"`
+	Kind        string         `yaml:"kind" validate:"required"`
+	Project     string         `yaml:"project" validate:"required"`
+	Region      string         `yaml:"region" validate:"required"`
+	Instance    string         `yaml:"instance" validate:"required"`
+	IPType      sources.IPType `yaml:"ipType" validate:"required"`
+	User        string         `yaml:"user" validate:"required"`
+	Password    string         `yaml:"password" validate:"required"`
+	Database    string         `yaml:"database" validate:"required"`
+	HealthQuery string         `yaml:"healthQuery"`
+
+	// PoolConfig sizes the connection pool.
+	sources.PoolConfig `yaml:",inline"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -63,21 +67,25 @@ func (r Config) SourceConfigKind() string {
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
-	pool, err := initCloudSQLMySQLConnectionPool(ctx, tracer, r.Name, r.Project, r.Region, r.Instance, r.IPType.String(), r.User, r.Password, r.Database)
+	pool, err := initCloudSQLMySQLConnectionPool(ctx, tracer, r.Name, r.Project, r.Region, r.Instance, r.IPType.String(), r.User, r.Password, r.Database, r.PoolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create pool: %w", err)
 	}
 
-	err = pool.PingContext(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to connect successfully: %w", err)
-	}
-
 	s := &Source{
 		Name: r.Name,
 		Kind: SourceKind,
 		Pool: pool,
 	}
+
+	if r.HealthQuery != "" {
+		_, err = pool.ExecContext(ctx, r.HealthQuery)
+	} else {
+		err = pool.PingContext(ctx)
+	}
+	if err != nil {
+		return nil, &sources.UnreachableError{Source: s, Err: err}
+	}
 	return s, nil
 }
 
@@ -97,7 +105,7 @@ func (s *Source) MySQLPool() *sql.DB {
 	return s.Pool
 }
 
-func initCloudSQLMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipType, user, pass, dbname string) (*sql.DB, error) {
+func initCloudSQLMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipType, user, pass, dbname string, poolConfig sources.PoolConfig) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
@@ -121,12 +129,10 @@ func initCloudSQLMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, n
 
 	// Tell the driver to use the Cloud SQL Go Connector to create connections
 	dsn := fmt.Sprintf("%s:%s@cloudsql-mysql(%s:%s:%s)/%s", user, pass, project, region, instance, dbname)
-	db, err := sql.Open(
-		"cloudsql-mysql",
-		dsn,
-	)
+	db, err := sources.OpenDB("cloudsql-mysql", dsn, poolConfig.ConnMaxIdleTime)
 	if err != nil {
 		return nil, err
 	}
+	poolConfig.ApplyDB(db)
 	return db, nil
 }
@@ -0,0 +1,93 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrUnavailable can be wrapped by sources to report that they could not be
+// reached.
+var ErrUnavailable = errors.New("source is unavailable")
+
+// IsUnavailable reports whether err was caused by a failure to reach a source,
+// as opposed to an error in the request itself.
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrUnavailable) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	// other network errors, such as timeouts reading a response, may be
+	// caused by the request itself
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// ErrLimitExceeded can be wrapped by sources to report that a query exceeded a
+// resource limit enforced by their database.
+var ErrLimitExceeded = errors.New("query exceeded a resource limit of the source")
+
+// pgLimitCodes are the SQLSTATE codes of PostgreSQL errors caused by a query
+// exceeding a limit, such as statement_timeout or temp_file_limit.
+var pgLimitCodes = map[string]bool{
+	"57014": true, // query_canceled
+	"53200": true, // out_of_memory
+	"53400": true, // configuration_limit_exceeded
+}
+
+// IsLimitExceeded reports whether err was caused by a query exceeding a
+// resource limit of its database, such as a timeout, rather than by an error
+// in the query.
+func IsLimitExceeded(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrLimitExceeded) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgLimitCodes[pgErr.Code]
+}
+
+// UnreachableError is returned by SourceConfig.Initialize when the source was
+// created, but could not connect to its database. The source is usable once
+// the database can be reached, so that Toolbox can start without it.
+type UnreachableError struct {
+	Source Source
+	Err    error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("unable to connect successfully: %s", e.Err)
+}
+
+func (e *UnreachableError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestIsUnavailable(t *testing.T) {
+	tcs := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "unavailable", err: fmt.Errorf("breaker is open: %w", ErrUnavailable), want: true},
+		{name: "connection refused", err: fmt.Errorf("query failed: %w", syscall.ECONNREFUSED), want: true},
+		{
+			name: "dial",
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("i/o timeout")},
+			want: true,
+		},
+		{
+			name: "read timeout",
+			err:  &net.OpError{Op: "read", Net: "tcp", Err: fmt.Errorf("i/o timeout")},
+			want: false,
+		},
+		{name: "dns", err: &net.DNSError{Err: "no such host", Name: "db.example.com"}, want: true},
+		{name: "deadline", err: context.DeadlineExceeded, want: false},
+		{name: "query", err: fmt.Errorf("syntax error"), want: false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsUnavailable(tc.err); got != tc.want {
+				t.Fatalf("unexpected result for %v: got %t, want %t", tc.err, got, tc.want)
+			}
+		})
+	}
+}
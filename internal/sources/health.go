@@ -0,0 +1,47 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HealthChecker can be implemented by sources to check whether they are
+// reachable.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// CheckHealth checks whether src is reachable, by pinging its connection pool
+// if it doesn't implement HealthChecker. ok is false if src can't be checked.
+func CheckHealth(ctx context.Context, src Source) (ok bool, err error) {
+	switch s := src.(type) {
+	case HealthChecker:
+		return true, s.CheckHealth(ctx)
+	case interface{ PostgresPool() *pgxpool.Pool }:
+		return true, s.PostgresPool().Ping(ctx)
+	case interface{ MySQLPool() *sql.DB }:
+		return true, s.MySQLPool().PingContext(ctx)
+	case interface{ MSSQLDB() *sql.DB }:
+		return true, s.MSSQLDB().PingContext(ctx)
+	case interface{ SQLiteDB() *sql.DB }:
+		return true, s.SQLiteDB().PingContext(ctx)
+	default:
+		return false, nil
+	}
+}
@@ -51,6 +51,46 @@ type Config struct {
 	Timeout        string            `yaml:"timeout"`
 	DefaultHeaders map[string]string `yaml:"headers"`
 	QueryParams    map[string]string `yaml:"queryParams"`
+	Auth           AuthConfig        `yaml:"auth"`
+}
+
+// AuthConfig configures the credentials sent with every request to the
+// source.
+type AuthConfig struct {
+	// Type is either "bearer" or "basic". No credentials are sent if it is
+	// empty.
+	Type     string `yaml:"type"`
+	Token    string `yaml:"token"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+func (a AuthConfig) validate() error {
+	switch a.Type {
+	case "":
+	case "bearer":
+		if a.Token == "" {
+			return fmt.Errorf("auth type %q requires a token", a.Type)
+		}
+	case "basic":
+		if a.Username == "" {
+			return fmt.Errorf("auth type %q requires a username", a.Type)
+		}
+	default:
+		return fmt.Errorf("unsupported auth type %q, must be \"bearer\" or \"basic\"", a.Type)
+	}
+	return nil
+}
+
+// Apply sets the Authorization header of req from the configured
+// credentials.
+func (a AuthConfig) Apply(req *http.Request) {
+	switch a.Type {
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+a.Token)
+	case "basic":
+		req.SetBasicAuth(a.Username, a.Password)
+	}
 }
 
 func (r Config) SourceConfigKind() string {
@@ -73,12 +113,17 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 		return nil, fmt.Errorf("failed to parse BaseUrl %v", err)
 	}
 
+	if err := r.Auth.validate(); err != nil {
+		return nil, err
+	}
+
 	s := &Source{
 		Name:           r.Name,
 		Kind:           SourceKind,
 		BaseURL:        r.BaseURL,
 		DefaultHeaders: r.DefaultHeaders,
 		QueryParams:    r.QueryParams,
+		Auth:           r.Auth,
 		Client:         &client,
 	}
 	return s, nil
@@ -93,6 +138,7 @@ type Source struct {
 	BaseURL        string            `yaml:"baseUrl"`
 	DefaultHeaders map[string]string `yaml:"headers"`
 	QueryParams    map[string]string `yaml:"queryParams"`
+	Auth           AuthConfig        `yaml:"auth"`
 	Client         *http.Client
 }
 
@@ -15,6 +15,7 @@
 package http_test
 
 import (
+	"context"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
@@ -45,6 +46,10 @@ func TestParseFromYamlHttp(t *testing.T) {
 					queryParams:
 						api-key: test_api_key
 						param: param-value
+					auth:
+						type: basic
+						username: user
+						password: pass
 			`,
 			want: map[string]sources.SourceConfig{
 				"my-http-instance": http.Config{
@@ -54,6 +59,7 @@ func TestParseFromYamlHttp(t *testing.T) {
 					Timeout:        "10s",
 					DefaultHeaders: map[string]string{"Authorization": "test_header", "Custom-Header": "custom"},
 					QueryParams:    map[string]string{"api-key": "test_api_key", "param": "param-value"},
+					Auth:           http.AuthConfig{Type: "basic", Username: "user", Password: "pass"},
 				},
 			},
 		},
@@ -124,3 +130,45 @@ func TestFailParseFromYaml(t *testing.T) {
 		})
 	}
 }
+
+func TestInitializeInvalidAuth(t *testing.T) {
+	tcs := []struct {
+		desc string
+		auth http.AuthConfig
+		err  string
+	}{
+		{
+			desc: "unknown type",
+			auth: http.AuthConfig{Type: "digest"},
+			err:  `unsupported auth type "digest", must be "bearer" or "basic"`,
+		},
+		{
+			desc: "bearer without token",
+			auth: http.AuthConfig{Type: "bearer"},
+			err:  `auth type "bearer" requires a token`,
+		},
+		{
+			desc: "basic without username",
+			auth: http.AuthConfig{Type: "basic", Password: "pass"},
+			err:  `auth type "basic" requires a username`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := http.Config{
+				Name:    "my-http-instance",
+				Kind:    http.SourceKind,
+				BaseURL: "http://test_server/",
+				Timeout: "10s",
+				Auth:    tc.auth,
+			}
+			_, err := cfg.Initialize(context.Background(), nil)
+			if err == nil {
+				t.Fatalf("expect initialization to fail")
+			}
+			if err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %q, want %q", err.Error(), tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// OpenDB opens a database/sql pool like sql.Open. If maxIdleTime is set,
+// connections that have been idle for longer are closed by a background sweep,
+// and are never reused even if the sweep hasn't reached them yet, so that
+// connections the database closed server-side aren't handed out.
+func OpenDB(driverName, dsn string, maxIdleTime time.Duration) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil || maxIdleTime <= 0 {
+		return db, err
+	}
+	drv := db.Driver()
+	_ = db.Close()
+
+	var connector driver.Connector = dsnConnector{dsn: dsn, driver: drv}
+	if dc, ok := drv.(driver.DriverContext); ok {
+		if connector, err = dc.OpenConnector(dsn); err != nil {
+			return nil, fmt.Errorf("unable to open connector: %w", err)
+		}
+	}
+	db = sql.OpenDB(idleConnector{Connector: connector, maxIdleTime: maxIdleTime})
+	db.SetConnMaxIdleTime(maxIdleTime)
+	return db, nil
+}
+
+// dsnConnector is a driver.Connector for drivers that don't implement
+// driver.DriverContext.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// idleConnector wraps the connections of a driver.Connector to track how long
+// they have been idle.
+type idleConnector struct {
+	driver.Connector
+	maxIdleTime time.Duration
+}
+
+func (c idleConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ic := &idleConn{Conn: conn, maxIdleTime: c.maxIdleTime}
+	ic.touch()
+	return ic, nil
+}
+
+// idleConn is a driver.Conn that refuses to be reused once it has been idle
+// for longer than maxIdleTime. It forwards the optional driver interfaces to
+// the wrapped connection.
+type idleConn struct {
+	driver.Conn
+	maxIdleTime time.Duration
+	// lastUsed is when the connection was last returned to the pool, in unix
+	// nanoseconds
+	lastUsed atomic.Int64
+}
+
+func (c *idleConn) touch() {
+	c.lastUsed.Store(time.Now().UnixNano())
+}
+
+// ResetSession is called by database/sql before a pooled connection is
+// reused. Returning driver.ErrBadConn makes it discard the connection and
+// open a new one. database/sql only closes idle connections when its
+// background sweep runs, so connections idle for longer may still be handed
+// out until then.
+func (c *idleConn) ResetSession(ctx context.Context) error {
+	if time.Since(time.Unix(0, c.lastUsed.Load())) > c.maxIdleTime {
+		return driver.ErrBadConn
+	}
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid is called by database/sql when the connection is returned to the
+// pool, which is when it starts being idle.
+func (c *idleConn) IsValid() bool {
+	c.touch()
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *idleConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *idleConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *idleConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	if opts.ReadOnly || opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, fmt.Errorf("driver does not support transaction options")
+	}
+	//nolint:staticcheck // fallback for drivers without ConnBeginTx
+	return c.Conn.Begin()
+}
+
+func (c *idleConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if q, ok := c.Conn.(driver.QueryerContext); ok {
+		return q.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *idleConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if e, ok := c.Conn.(driver.ExecerContext); ok {
+		return e.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *idleConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
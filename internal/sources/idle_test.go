@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingDriver counts the connections it opens.
+type countingDriver struct {
+	opened atomic.Int32
+}
+
+func (d *countingDriver) Open(string) (driver.Conn, error) {
+	d.opened.Add(1)
+	return countingConn{}, nil
+}
+
+type countingConn struct{}
+
+func (countingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (countingConn) Close() error { return nil }
+
+func (countingConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func TestOpenDBIdleTime(t *testing.T) {
+	d := &countingDriver{}
+	sql.Register("sources-counting", d)
+	maxIdleTime := 50 * time.Millisecond
+	db, err := OpenDB("sources-counting", "", maxIdleTime)
+	if err != nil {
+		t.Fatalf("unable to open database: %s", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	use := func(d time.Duration) {
+		t.Helper()
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatalf("unable to get a connection: %s", err)
+		}
+		time.Sleep(d)
+		conn.Close()
+	}
+
+	// a connection in use for longer than maxIdleTime isn't idle, and is
+	// reused once returned
+	use(2 * maxIdleTime)
+	use(0)
+	if got := d.opened.Load(); got != 1 {
+		t.Fatalf("unexpected number of connections opened: got %d, want 1", got)
+	}
+
+	// a connection idle for longer than maxIdleTime is replaced
+	time.Sleep(2 * maxIdleTime)
+	use(0)
+	if got := d.opened.Load(); got != 2 {
+		t.Fatalf("unexpected number of connections opened: got %d, want 2", got)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "mongodb"
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name     string `yaml:"name" validate:"required"`
+	Kind     string `yaml:"kind" validate:"required"`
+	Uri      string `yaml:"uri" validate:"required"`
+	Database string `yaml:"database" validate:"required"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	client, err := initMongoDBClient(ctx, tracer, r.Uri, r.Name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client: %w", err)
+	}
+
+	err = client.Ping(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	}
+
+	s := &Source{
+		Name:   r.Name,
+		Kind:   SourceKind,
+		Client: client,
+		DB:     client.Database(r.Database),
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name   string `yaml:"name"`
+	Kind   string `yaml:"kind"`
+	Client *mongo.Client
+	DB     *mongo.Database
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+func (s *Source) MongoDatabase() *mongo.Database {
+	return s.DB
+}
+
+// Close disconnects the client.
+func (s *Source) Close() error {
+	return s.Client.Disconnect(context.Background())
+}
+
+// CheckHealth pings the primary of the deployment.
+func (s *Source) CheckHealth(ctx context.Context) error {
+	return s.Client.Ping(ctx, nil)
+}
+
+// ParseDocument parses a document, such as a filter or a projection, from
+// relaxed Extended JSON. The order of its fields is kept.
+func ParseDocument(payload string) (bson.D, error) {
+	var doc bson.D
+	if err := bson.UnmarshalExtJSON([]byte(payload), false, &doc); err != nil {
+		return nil, fmt.Errorf("invalid document %s: %w", payload, err)
+	}
+	return doc, nil
+}
+
+// ParsePipeline parses an aggregation pipeline, an array of stages, from
+// relaxed Extended JSON.
+func ParsePipeline(payload string) ([]bson.D, error) {
+	// Extended JSON can only be unmarshalled into a document
+	var doc struct {
+		Pipeline []bson.D `bson:"pipeline"`
+	}
+	if err := bson.UnmarshalExtJSON([]byte(`{"pipeline": `+payload+`}`), false, &doc); err != nil {
+		return nil, fmt.Errorf("invalid pipeline %s: %w", payload, err)
+	}
+	return doc.Pipeline, nil
+}
+
+// Documents reads all documents of cur, as JSON objects. Documents are
+// converted to relaxed Extended JSON, so that values without a JSON type, such
+// as ObjectIds and dates, are returned as {"$oid": ...} and {"$date": ...}.
+func Documents(ctx context.Context, cur *mongo.Cursor) ([]any, error) {
+	defer cur.Close(ctx)
+	out := []any{}
+	for cur.Next(ctx) {
+		b, err := bson.MarshalExtJSON(cur.Current, false, false)
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert document: %w", err)
+		}
+		var doc map[string]any
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return nil, fmt.Errorf("unable to convert document: %w", err)
+		}
+		out = append(out, doc)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read documents: %w", err)
+	}
+	return out, nil
+}
+
+func initMongoDBClient(ctx context.Context, tracer trace.Tracer, uri, name string) (*mongo.Client, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
+	defer span.End()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create connection client: %w", err)
+	}
+	return client, nil
+}
@@ -46,13 +46,17 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 
 type Config struct {
 	// Cloud SQL MSSQL configs
-	Name     string `yaml:"name" validate:"required"`
-	Kind     string `yaml:"kind" validate:"required"`
-	Host     string `yaml:"host" validate:"required"`
-	Port     string `yaml:"port" validate:"required"`
-	User     string `yaml:"user" validate:"required"`
-	Password string `yaml:"password" validate:"required"`
-	Database string `yaml:"database" validate:"required"`
+	Name        string `yaml:"name" validate:"required"`
+	Kind        string `yaml:"kind" validate:"required"`
+	Host        string `yaml:"host" validate:"required"`
+	Port        string `yaml:"port" validate:"required"`
+	User        string `yaml:"user" validate:"required"`
+	Password    string `yaml:"password" validate:"required"`
+	Database    string `yaml:"database" validate:"required"`
+	HealthQuery string `yaml:"healthQuery"`
+
+	// PoolConfig sizes the connection pool.
+	sources.PoolConfig `yaml:",inline"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -62,22 +66,26 @@ func (r Config) SourceConfigKind() string {
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
 	// Initializes a MSSQL source
-	db, err := initMssqlConnection(ctx, tracer, r.Name, r.Host, r.Port, r.User, r.Password, r.Database)
+	db, err := initMssqlConnection(ctx, tracer, r.Name, r.Host, r.Port, r.User, r.Password, r.Database, r.PoolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create db connection: %w", err)
 	}
 
-	// Verify db connection
-	err = db.PingContext(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to connect successfully: %w", err)
-	}
-
 	s := &Source{
 		Name: r.Name,
 		Kind: SourceKind,
 		Db:   db,
 	}
+
+	// Verify db connection
+	if r.HealthQuery != "" {
+		_, err = db.ExecContext(ctx, r.HealthQuery)
+	} else {
+		err = db.PingContext(ctx)
+	}
+	if err != nil {
+		return nil, &sources.UnreachableError{Source: s, Err: err}
+	}
 	return s, nil
 }
 
@@ -100,7 +108,7 @@ func (s *Source) MSSQLDB() *sql.DB {
 	return s.Db
 }
 
-func initMssqlConnection(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname string) (*sql.DB, error) {
+func initMssqlConnection(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname string, poolConfig sources.PoolConfig) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
@@ -109,9 +117,10 @@ func initMssqlConnection(ctx context.Context, tracer trace.Tracer, name, host, p
 	dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%s?database=%s", user, pass, host, port, dbname)
 
 	// Open database connection
-	db, err := sql.Open("sqlserver", dsn)
+	db, err := sources.OpenDB("sqlserver", dsn, poolConfig.ConnMaxIdleTime)
 	if err != nil {
 		return nil, fmt.Errorf("sql.Open: %w", err)
 	}
+	poolConfig.ApplyDB(db)
 	return db, nil
 }
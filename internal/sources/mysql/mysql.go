@@ -45,13 +45,17 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name     string `yaml:"name" validate:"required"`
-	Kind     string `yaml:"kind" validate:"required"`
-	Host     string `yaml:"host" validate:"required"`
-	Port     string `yaml:"port" validate:"required"`
-	User     string `yaml:"user" validate:"required"`
-	Password string `yaml:"password" validate:"required"`
-	Database string `yaml:"database" validate:"required"`
+	Name        string `yaml:"name" validate:"required"`
+	Kind        string `yaml:"kind" validate:"required"`
+	Host        string `yaml:"host" validate:"required"`
+	Port        string `yaml:"port" validate:"required"`
+	User        string `yaml:"user" validate:"required"`
+	Password    string `yaml:"password" validate:"required"`
+	Database    string `yaml:"database" validate:"required"`
+	HealthQuery string `yaml:"healthQuery"`
+
+	// PoolConfig sizes the connection pool.
+	sources.PoolConfig `yaml:",inline"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -59,21 +63,25 @@ func (r Config) SourceConfigKind() string {
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
-	pool, err := initMySQLConnectionPool(ctx, tracer, r.Name, r.Host, r.Port, r.User, r.Password, r.Database)
+	pool, err := initMySQLConnectionPool(ctx, tracer, r.Name, r.Host, r.Port, r.User, r.Password, r.Database, r.PoolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create pool: %w", err)
 	}
 
-	err = pool.PingContext(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to connect successfully: %w", err)
-	}
-
 	s := &Source{
 		Name: r.Name,
 		Kind: SourceKind,
 		Pool: pool,
 	}
+
+	if r.HealthQuery != "" {
+		_, err = pool.ExecContext(ctx, r.HealthQuery)
+	} else {
+		err = pool.PingContext(ctx)
+	}
+	if err != nil {
+		return nil, &sources.UnreachableError{Source: s, Err: err}
+	}
 	return s, nil
 }
 
@@ -93,7 +101,31 @@ func (s *Source) MySQLPool() *sql.DB {
 	return s.Pool
 }
 
-func initMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname string) (*sql.DB, error) {
+// Warnings returns the warnings raised by the last statement run on conn, as
+// listed by `SHOW WARNINGS`.
+func Warnings(ctx context.Context, conn *sql.Conn) ([]map[string]any, error) {
+	rows, err := conn.QueryContext(ctx, "SHOW WARNINGS")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var warnings []map[string]any
+	for rows.Next() {
+		var level, message string
+		var code int64
+		if err := rows.Scan(&level, &code, &message); err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, map[string]any{"level": level, "code": code, "message": message})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+func initMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname string, poolConfig sources.PoolConfig) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
@@ -102,9 +134,10 @@ func initMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, hos
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", user, pass, host, port, dbname)
 
 	// Interact with the driver directly as you normally would
-	pool, err := sql.Open("mysql", dsn)
+	pool, err := sources.OpenDB("mysql", dsn, poolConfig.ConnMaxIdleTime)
 	if err != nil {
 		return nil, fmt.Errorf("sql.Open: %w", err)
 	}
+	poolConfig.ApplyDB(pool)
 	return pool, nil
 }
@@ -16,10 +16,12 @@ package mysql_test
 
 import (
 	"testing"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/mysql"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 )
@@ -54,6 +56,40 @@ func TestParseFromYamlCloudSQLMySQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with pool settings",
+			in: `
+			sources:
+				my-mysql-instance:
+					kind: mysql
+					host: 0.0.0.0
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					maxOpenConns: 20
+					maxIdleConns: 5
+					connMaxLifetime: 1h
+					connMaxIdleTime: 5m
+			`,
+			want: server.SourceConfigs{
+				"my-mysql-instance": mysql.Config{
+					Name:     "my-mysql-instance",
+					Kind:     mysql.SourceKind,
+					Host:     "0.0.0.0",
+					Port:     "my-port",
+					Database: "my_db",
+					User:     "my_user",
+					Password: "my_pass",
+					PoolConfig: sources.PoolConfig{
+						MaxOpenConns:    20,
+						MaxIdleConns:    5,
+						ConnMaxLifetime: time.Hour,
+						ConnMaxIdleTime: 5 * time.Minute,
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -107,6 +143,21 @@ func TestFailParseFromYaml(t *testing.T) {
 			`,
 			err: "unable to parse source \"my-mysql-instance\" as \"mysql\": Key: 'Config.Host' Error:Field validation for 'Host' failed on the 'required' tag",
 		},
+		{
+			desc: "negative pool size",
+			in: `
+			sources:
+				my-mysql-instance:
+					kind: mysql
+					host: 0.0.0.0
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					maxOpenConns: -1
+			`,
+			err: "unable to parse source \"my-mysql-instance\" as \"mysql\": [4:15] Key: 'PoolConfig.MaxOpenConns' Error:Field validation for 'MaxOpenConns' failed on the 'gte' tag\n   1 | database: my_db\n   2 | host: 0.0.0.0\n   3 | kind: mysql\n>  4 | maxOpenConns: -1\n                     ^\n   5 | password: my_pass\n   6 | port: my-port\n   7 | user: my_user",
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -100,6 +100,11 @@ func (s *Source) Neo4jDatabase() string {
 	return s.Database
 }
 
+// Close closes the driver's connections.
+func (s *Source) Close() error {
+	return s.Driver.Close(context.Background())
+}
+
 func initNeo4jDriver(ctx context.Context, tracer trace.Tracer, uri, user, password, name string) (neo4j.DriverWithContext, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -0,0 +1,73 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PoolConfig sizes the connection pool of a source. Unset fields keep the
+// defaults of the driver.
+type PoolConfig struct {
+	// MaxOpenConns is the maximum number of open connections.
+	MaxOpenConns int `yaml:"maxOpenConns" validate:"gte=0"`
+	// MaxIdleConns is the maximum number of idle connections kept open.
+	MaxIdleConns int `yaml:"maxIdleConns" validate:"gte=0"`
+	// ConnMaxLifetime closes connections that have been open for longer.
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime" validate:"gte=0"`
+	// ConnMaxIdleTime, if set, closes connections that have been idle for
+	// longer, such as before the database times them out.
+	ConnMaxIdleTime time.Duration `yaml:"connMaxIdleTime" validate:"gte=0"`
+}
+
+// ApplyDB sizes the pool of db, which was opened by OpenDB with
+// c.ConnMaxIdleTime.
+func (c PoolConfig) ApplyDB(db *sql.DB) {
+	if c.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(c.MaxOpenConns)
+	}
+	if c.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(c.MaxIdleConns)
+	}
+	if c.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	}
+}
+
+// ApplyPgx sizes the pool configured by config. pgx pools don't bound their
+// idle connections, so MaxIdleConns can't be set.
+func (c PoolConfig) ApplyPgx(config *pgxpool.Config) error {
+	if c.MaxIdleConns > 0 {
+		return fmt.Errorf("maxIdleConns is not supported by PostgreSQL sources")
+	}
+	if c.MaxOpenConns > math.MaxInt32 {
+		return fmt.Errorf("maxOpenConns must be at most %d", math.MaxInt32)
+	}
+	if c.MaxOpenConns > 0 {
+		config.MaxConns = int32(c.MaxOpenConns)
+	}
+	if c.ConnMaxLifetime > 0 {
+		config.MaxConnLifetime = c.ConnMaxLifetime
+	}
+	if c.ConnMaxIdleTime > 0 {
+		config.MaxConnIdleTime = c.ConnMaxIdleTime
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// defaultApplicationName is the application_name of the connections of
+// sources that don't set applicationName.
+const defaultApplicationName = "genai-toolbox"
+
+// setApplicationName sets the application_name that the connections of the
+// pool report to the database, e.g. in pg_stat_activity. If perTool is set,
+// the name of the invoking tool is appended to it while a connection is used
+// by the tool.
+func setApplicationName(config *pgxpool.Config, name string, perTool bool) {
+	if name == "" {
+		name = defaultApplicationName
+	}
+	config.ConnConfig.RuntimeParams["application_name"] = name
+	if perTool {
+		config.BeforeAcquire = withToolApplicationName(name)
+	}
+}
+
+// toolApplicationName returns the application_name of a connection acquired
+// with ctx.
+func toolApplicationName(ctx context.Context, name string) string {
+	if toolName, ok := tools.ToolNameFromContext(ctx); ok {
+		return name + "/" + toolName
+	}
+	return name
+}
+
+// withToolApplicationName returns a BeforeAcquire hook that updates the
+// application_name of the acquired connection to that of the invoking tool.
+func withToolApplicationName(name string) func(context.Context, *pgx.Conn) bool {
+	return func(ctx context.Context, conn *pgx.Conn) bool {
+		want := toolApplicationName(ctx, name)
+		// the server reports changes of application_name, so it is only set
+		// when the connection was last used by another tool
+		if conn.PgConn().ParameterStatus("application_name") == want {
+			return true
+		}
+		_, err := conn.Exec(ctx, "SELECT set_config('application_name', $1, false)", want)
+		// connections whose name can't be set are discarded
+		return err == nil
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestSetApplicationName(t *testing.T) {
+	tcs := []struct {
+		desc        string
+		name        string
+		perTool     bool
+		want        string
+		wantAcquire bool
+	}{
+		{desc: "default", want: "genai-toolbox"},
+		{desc: "configured", name: "reports", want: "reports"},
+		{desc: "per tool", name: "reports", perTool: true, want: "reports", wantAcquire: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			config, err := pgxpool.ParseConfig("postgres://my_user@my-host:5432/my_db")
+			if err != nil {
+				t.Fatalf("unable to parse config: %s", err)
+			}
+			setApplicationName(config, tc.name, tc.perTool)
+			if got := config.ConnConfig.RuntimeParams["application_name"]; got != tc.want {
+				t.Fatalf("unexpected application_name: got %q, want %q", got, tc.want)
+			}
+			if got := config.BeforeAcquire != nil; got != tc.wantAcquire {
+				t.Fatalf("unexpected BeforeAcquire hook: got %t, want %t", got, tc.wantAcquire)
+			}
+		})
+	}
+}
+
+func TestToolApplicationName(t *testing.T) {
+	ctx := context.Background()
+	if got := toolApplicationName(ctx, "genai-toolbox"); got != "genai-toolbox" {
+		t.Fatalf("unexpected name without tool: got %q", got)
+	}
+	ctx = tools.WithToolName(ctx, "list_flights")
+	if got, want := toolApplicationName(ctx, "genai-toolbox"), "genai-toolbox/list_flights"; got != want {
+		t.Fatalf("unexpected name with tool: got %q, want %q", got, want)
+	}
+}
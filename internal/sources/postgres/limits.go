@@ -0,0 +1,35 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// setQueryLimits sets the resource limits that the database enforces on the
+// queries of the pool. They are run-time parameters of each connection, so
+// that they apply to every invocation without a round trip.
+func setQueryLimits(config *pgxpool.Config, workMem string, statementTimeout time.Duration) {
+	if workMem != "" {
+		config.ConnConfig.RuntimeParams["work_mem"] = workMem
+	}
+	if statementTimeout > 0 {
+		// statement_timeout is in milliseconds, and 0 disables it
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(max(statementTimeout.Milliseconds(), 1), 10)
+	}
+}
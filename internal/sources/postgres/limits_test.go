@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestSetQueryLimits(t *testing.T) {
+	tcs := []struct {
+		desc             string
+		workMem          string
+		statementTimeout time.Duration
+		want             map[string]string
+	}{
+		{desc: "no limits", want: map[string]string{}},
+		{
+			desc:             "limits",
+			workMem:          "64MB",
+			statementTimeout: 30 * time.Second,
+			want:             map[string]string{"work_mem": "64MB", "statement_timeout": "30000"},
+		},
+		{
+			desc:             "timeout below a millisecond",
+			statementTimeout: time.Microsecond,
+			want:             map[string]string{"statement_timeout": "1"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			config, err := pgxpool.ParseConfig("postgres://my_user@my-host:5432/my_db")
+			if err != nil {
+				t.Fatalf("unable to parse config: %s", err)
+			}
+			setQueryLimits(config, tc.workMem, tc.statementTimeout)
+			got := map[string]string{}
+			for _, k := range []string{"work_mem", "statement_timeout"} {
+				if v, ok := config.ConnConfig.RuntimeParams[k]; ok {
+					got[k] = v
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected run-time parameters (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsLimitExceeded(t *testing.T) {
+	tcs := []struct {
+		desc string
+		err  error
+		want bool
+	}{
+		{
+			desc: "statement timeout",
+			err:  &pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"},
+			want: true,
+		},
+		{
+			desc: "temp file limit",
+			err:  &pgconn.PgError{Code: "53400", Message: "temporary file size exceeds temp_file_limit (1024kB)"},
+			want: true,
+		},
+		{
+			desc: "syntax error",
+			err:  &pgconn.PgError{Code: "42601", Message: "syntax error at or near \"SELEC\""},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := fmt.Errorf("unable to read rows: %w", tc.err)
+			if got := sources.IsLimitExceeded(err); got != tc.want {
+				t.Fatalf("unexpected result: got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
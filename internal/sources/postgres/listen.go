@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// listenRetryInterval is how long Listen waits before listening again after
+// its connection was lost.
+const listenRetryInterval = 5 * time.Second
+
+// Listen runs LISTEN on channel with a connection of pool dedicated to it, and
+// signals the returned channel whenever a notification arrives, until ctx is
+// done. Notifications that arrive while a signal is pending are coalesced.
+//
+// If the connection is lost, onError is called and a new connection listens
+// in its place. As notifications may have been missed meanwhile, the returned
+// channel is signalled once listening again.
+func Listen(ctx context.Context, pool *pgxpool.Pool, channel string, onError func(error)) <-chan struct{} {
+	notified := make(chan struct{}, 1)
+	signal := func() {
+		select {
+		case notified <- struct{}{}:
+		default:
+		}
+	}
+	go func() {
+		defer close(notified)
+		reconnected := false
+		for {
+			err := listen(ctx, pool, channel, func() {
+				if reconnected {
+					signal()
+				}
+			}, signal)
+			if ctx.Err() != nil {
+				return
+			}
+			onError(err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(listenRetryInterval):
+			}
+			reconnected = true
+		}
+	}()
+	return notified
+}
+
+// listen listens on channel with a single connection, calling onListen once
+// listening and onNotification for each notification, until the connection
+// fails or ctx is done.
+func listen(ctx context.Context, pool *pgxpool.Pool, channel string, onListen, onNotification func()) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// the connection is closed rather than returned to the pool, so that it
+	// isn't reused while still listening
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return err
+	}
+	onListen()
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+		onNotification()
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestPasswordRotation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the mock resolver returns a new credential after the first resolution
+	var calls atomic.Int32
+	resolve := func(context.Context) (string, error) {
+		if calls.Add(1) == 1 {
+			return "old-password", nil
+		}
+		return "new-password", nil
+	}
+	password, err := sources.NewRotatingSecret(ctx, resolve)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	beforeConnect := withPassword(password)
+
+	cc := &pgx.ConnConfig{}
+	if err := beforeConnect(ctx, cc); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cc.Password != "old-password" {
+		t.Fatalf("unexpected password before rotation: got %q, want %q", cc.Password, "old-password")
+	}
+
+	rotated := make(chan struct{}, 1)
+	onRotate := func() {
+		select {
+		case rotated <- struct{}{}:
+		default:
+		}
+	}
+	onError := func(err error) {
+		t.Errorf("unexpected error refreshing password: %s", err)
+	}
+	go password.Watch(ctx, 10*time.Millisecond, onRotate, onError)
+
+	select {
+	case <-rotated:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("password was not rotated")
+	}
+
+	// connections opened after the rotation use the new credential
+	cc = &pgx.ConnConfig{}
+	if err := beforeConnect(ctx, cc); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cc.Password != "new-password" {
+		t.Fatalf("unexpected password after rotation: got %q, want %q", cc.Password, "new-password")
+	}
+}
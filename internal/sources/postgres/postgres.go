@@ -17,15 +17,22 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel/trace"
 )
 
 const SourceKind string = "postgres"
 
+// defaultPasswordRefreshInterval is how often a passwordFile is re-read when
+// passwordRefreshInterval is not set.
+const defaultPasswordRefreshInterval = time.Minute
+
 // validate interface
 var _ sources.SourceConfig = Config{}
 
@@ -49,8 +56,28 @@ type Config struct {
 	Host     string `yaml:"host" validate:"required"`
 	Port     string `yaml:"port" validate:"required"`
 	User     string `yaml:"user" validate:"required"`
-	Password string `yaml:"password" validate:"required"`
-	Database string `yaml:"database" validate:"required"`
+	Password string `yaml:"password" validate:"required_without=PasswordFile"`
+	// PasswordFile is the path of a file holding the password, such as a
+	// mounted secret. It is re-read so that the password can be rotated
+	// without restarting Toolbox.
+	PasswordFile            string        `yaml:"passwordFile" validate:"excluded_with=Password"`
+	PasswordRefreshInterval time.Duration `yaml:"passwordRefreshInterval"`
+	Database                string        `yaml:"database" validate:"required"`
+	HealthQuery             string        `yaml:"healthQuery"`
+	// ApplicationName is the application_name reported by the connections,
+	// "genai-toolbox" by default.
+	ApplicationName string `yaml:"applicationName"`
+	// ToolApplicationName appends the name of the invoking tool to the
+	// application_name while a connection is used by it.
+	ToolApplicationName bool `yaml:"toolApplicationName"`
+	// WorkMem is the work_mem of the queries, the memory each of their sort
+	// and hash operations may use before spilling to disk (e.g. "64MB").
+	WorkMem string `yaml:"workMem"`
+	// StatementTimeout has the database cancel queries that run for longer.
+	StatementTimeout time.Duration `yaml:"statementTimeout" validate:"gte=0"`
+
+	// PoolConfig sizes the connection pool.
+	sources.PoolConfig `yaml:",inline"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -58,14 +85,18 @@ func (r Config) SourceConfigKind() string {
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
-	pool, err := initPostgresConnectionPool(ctx, tracer, r.Name, r.Host, r.Port, r.User, r.Password, r.Database)
+	resolve := sources.StaticSecret(r.Password)
+	if r.PasswordFile != "" {
+		resolve = sources.FileSecret(r.PasswordFile)
+	}
+	password, err := sources.NewRotatingSecret(ctx, resolve)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create pool: %w", err)
+		return nil, fmt.Errorf("unable to resolve password: %w", err)
 	}
 
-	err = pool.Ping(ctx)
+	pool, err := initPostgresConnectionPool(ctx, tracer, r.Name, r.Host, r.Port, r.User, password, r.Database, r.ApplicationName, r.ToolApplicationName, r.WorkMem, r.StatementTimeout, r.PoolConfig)
 	if err != nil {
-		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+		return nil, fmt.Errorf("unable to create pool: %w", err)
 	}
 
 	s := &Source{
@@ -73,6 +104,36 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 		Kind: SourceKind,
 		Pool: pool,
 	}
+
+	if r.PasswordFile != "" {
+		interval := r.PasswordRefreshInterval
+		if interval <= 0 {
+			interval = defaultPasswordRefreshInterval
+		}
+		logger, err := util.LoggerFromContext(ctx)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		onError := func(err error) {
+			logger.WarnContext(context.Background(), fmt.Sprintf("unable to refresh password of source %q: %s", r.Name, err))
+		}
+		// the password is watched until the source is closed
+		watchCtx, cancel := context.WithCancel(context.Background())
+		s.stopWatch = cancel
+		// close the connections opened with the previous password, so that
+		// new ones are opened with the rotated password
+		go password.Watch(watchCtx, interval, pool.Reset, onError)
+	}
+
+	if r.HealthQuery != "" {
+		_, err = pool.Exec(ctx, r.HealthQuery)
+	} else {
+		err = pool.Ping(ctx)
+	}
+	if err != nil {
+		return nil, &sources.UnreachableError{Source: s, Err: err}
+	}
 	return s, nil
 }
 
@@ -82,23 +143,57 @@ type Source struct {
 	Name string `yaml:"name"`
 	Kind string `yaml:"kind"`
 	Pool *pgxpool.Pool
+
+	// stopWatch stops refreshing the password, nil if it isn't refreshed
+	stopWatch context.CancelFunc
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close stops refreshing the password and closes the connection pool, once
+// the connections in use are released.
+func (s *Source) Close() error {
+	if s.stopWatch != nil {
+		s.stopWatch()
+	}
+	s.Pool.Close()
+	return nil
+}
+
 func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
 
-func initPostgresConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname string) (*pgxpool.Pool, error) {
+// withPassword returns a BeforeConnect hook that connects with the latest
+// value of password.
+func withPassword(password *sources.RotatingSecret) func(context.Context, *pgx.ConnConfig) error {
+	return func(_ context.Context, cc *pgx.ConnConfig) error {
+		cc.Password = password.Value()
+		return nil
+	}
+}
+
+func initPostgresConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user string, pass *sources.RotatingSecret, dbname, appName string, perTool bool, workMem string, statementTimeout time.Duration, poolConfig sources.PoolConfig) (*pgxpool.Pool, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
-	// urlExample := "postgres:dd//username:password@localhost:5432/database_name"
-	i := fmt.Sprintf("postgres://%s:%s@%s:%s/%s", user, pass, host, port, dbname)
-	pool, err := pgxpool.New(ctx, i)
+	// urlExample := "postgres://username@localhost:5432/database_name"
+	// the password is set on each new connection, as it may be rotated
+	i := fmt.Sprintf("postgres://%s@%s:%s/%s", user, host, port, dbname)
+	config, err := pgxpool.ParseConfig(i)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse connection uri: %w", err)
+	}
+	config.BeforeConnect = withPassword(pass)
+	setApplicationName(config, appName, perTool)
+	setQueryLimits(config, workMem, statementTimeout)
+	if err := poolConfig.ApplyPgx(config); err != nil {
+		return nil, err
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %w", err)
 	}
@@ -15,13 +15,20 @@
 package postgres_test
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestParseFromYamlPostgres(t *testing.T) {
@@ -54,6 +61,120 @@ func TestParseFromYamlPostgres(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with pool settings",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: my-host
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					maxOpenConns: 20
+					connMaxLifetime: 1h
+					connMaxIdleTime: 5m
+			`,
+			want: server.SourceConfigs{
+				"my-pg-instance": postgres.Config{
+					Name:     "my-pg-instance",
+					Kind:     postgres.SourceKind,
+					Host:     "my-host",
+					Port:     "my-port",
+					Database: "my_db",
+					User:     "my_user",
+					Password: "my_pass",
+					PoolConfig: sources.PoolConfig{
+						MaxOpenConns:    20,
+						ConnMaxLifetime: time.Hour,
+						ConnMaxIdleTime: 5 * time.Minute,
+					},
+				},
+			},
+		},
+		{
+			desc: "with query limits",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: my-host
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					workMem: 64MB
+					statementTimeout: 30s
+			`,
+			want: server.SourceConfigs{
+				"my-pg-instance": postgres.Config{
+					Name:             "my-pg-instance",
+					Kind:             postgres.SourceKind,
+					Host:             "my-host",
+					Port:             "my-port",
+					Database:         "my_db",
+					User:             "my_user",
+					Password:         "my_pass",
+					WorkMem:          "64MB",
+					StatementTimeout: 30 * time.Second,
+				},
+			},
+		},
+		{
+			desc: "password file",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: my-host
+					port: my-port
+					database: my_db
+					user: my_user
+					passwordFile: /secrets/pg-password
+					passwordRefreshInterval: 5m
+			`,
+			want: server.SourceConfigs{
+				"my-pg-instance": postgres.Config{
+					Name:                    "my-pg-instance",
+					Kind:                    postgres.SourceKind,
+					Host:                    "my-host",
+					Port:                    "my-port",
+					Database:                "my_db",
+					User:                    "my_user",
+					PasswordFile:            "/secrets/pg-password",
+					PasswordRefreshInterval: 5 * time.Minute,
+				},
+			},
+		},
+		{
+			desc: "application name",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: my-host
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					applicationName: reports
+					toolApplicationName: true
+			`,
+			want: server.SourceConfigs{
+				"my-pg-instance": postgres.Config{
+					Name:                "my-pg-instance",
+					Kind:                postgres.SourceKind,
+					Host:                "my-host",
+					Port:                "my-port",
+					Database:            "my_db",
+					User:                "my_user",
+					Password:            "my_pass",
+					ApplicationName:     "reports",
+					ToolApplicationName: true,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -105,7 +226,7 @@ func TestFailParseFromYaml(t *testing.T) {
 					database: my_db
 					user: my_user
 			`,
-			err: "unable to parse source \"my-pg-instance\" as \"postgres\": Key: 'Config.Password' Error:Field validation for 'Password' failed on the 'required' tag",
+			err: "unable to parse source \"my-pg-instance\" as \"postgres\": Key: 'Config.Password' Error:Field validation for 'Password' failed on the 'required_without' tag",
 		},
 	}
 	for _, tc := range tcs {
@@ -125,3 +246,37 @@ func TestFailParseFromYaml(t *testing.T) {
 		})
 	}
 }
+
+func TestClose(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("my_pass"), 0o600); err != nil {
+		t.Fatalf("unable to write password file: %s", err)
+	}
+	// nothing listens on the port, so the source is returned as unreachable
+	_, err = postgres.Config{
+		Name:                    "my-pg-instance",
+		Kind:                    postgres.SourceKind,
+		Host:                    "127.0.0.1",
+		Port:                    "1",
+		User:                    "my_user",
+		PasswordFile:            passwordFile,
+		PasswordRefreshInterval: time.Millisecond,
+		Database:                "my_db",
+	}.Initialize(ctx, noop.NewTracerProvider().Tracer(""))
+	var unreachable *sources.UnreachableError
+	if !errors.As(err, &unreachable) {
+		t.Fatalf("expected the source to be unreachable, got %v", err)
+	}
+	src := unreachable.Source.(*postgres.Source)
+
+	if err := src.Close(); err != nil {
+		t.Fatalf("unexpected error closing the source: %s", err)
+	}
+	if err := src.Pool.Ping(context.Background()); err == nil {
+		t.Fatalf("expected the pool to be closed")
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis_test
+
+import (
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/redis"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlRedis(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			sources:
+				my-redis:
+					kind: redis
+					address: 127.0.0.1:6379
+			`,
+			want: server.SourceConfigs{
+				"my-redis": redis.Config{
+					Name:    "my-redis",
+					Kind:    redis.SourceKind,
+					Address: "127.0.0.1:6379",
+				},
+			},
+		},
+		{
+			desc: "with password and database",
+			in: `
+			sources:
+				my-redis:
+					kind: redis
+					address: redis.internal:6379
+					password: my_pass
+					database: 2
+			`,
+			want: server.SourceConfigs{
+				"my-redis": redis.Config{
+					Name:     "my-redis",
+					Kind:     redis.SourceKind,
+					Address:  "redis.internal:6379",
+					Password: "my_pass",
+					Database: 2,
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Sources); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYaml(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{
+			desc: "missing address",
+			in: `
+			sources:
+				my-redis:
+					kind: redis
+					database: 1
+			`,
+			err: "Key: 'Config.Address' Error:Field validation for 'Address' failed on the 'required' tag",
+		},
+		{
+			desc: "negative database",
+			in: `
+			sources:
+				my-redis:
+					kind: redis
+					address: 127.0.0.1:6379
+					database: -1
+			`,
+			err: "Key: 'Config.Database' Error:Field validation for 'Database' failed on the 'gte' tag",
+		},
+		{
+			desc: "extra field",
+			in: `
+			sources:
+				my-redis:
+					kind: redis
+					address: 127.0.0.1:6379
+					foo: bar
+			`,
+			err: "unknown field \"foo\"",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err == nil {
+				t.Fatalf("expect parsing to fail")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %q, want it to contain %q", err, tc.err)
+			}
+		})
+	}
+}
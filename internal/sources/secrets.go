@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretResolver returns the current value of a secret, such as a database
+// password.
+type SecretResolver func(ctx context.Context) (string, error)
+
+// StaticSecret returns a SecretResolver for a secret that never changes.
+func StaticSecret(value string) SecretResolver {
+	return func(context.Context) (string, error) {
+		return value, nil
+	}
+}
+
+// FileSecret returns a SecretResolver that reads a secret from a file, such as
+// a mounted secret volume. Surrounding whitespace is trimmed.
+func FileSecret(path string) SecretResolver {
+	return func(context.Context) (string, error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("unable to read secret file: %w", err)
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+}
+
+// RotatingSecret holds the latest value of a secret that may be rotated
+// while Toolbox is running.
+type RotatingSecret struct {
+	mu      sync.RWMutex
+	value   string
+	resolve SecretResolver
+}
+
+// NewRotatingSecret returns a RotatingSecret holding the current value of the
+// secret.
+func NewRotatingSecret(ctx context.Context, resolve SecretResolver) (*RotatingSecret, error) {
+	value, err := resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &RotatingSecret{value: value, resolve: resolve}, nil
+}
+
+// Value returns the latest value of the secret.
+func (s *RotatingSecret) Value() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.value
+}
+
+// Refresh resolves the secret again, and returns true if its value changed.
+func (s *RotatingSecret) Refresh(ctx context.Context) (bool, error) {
+	value, err := s.resolve(ctx)
+	if err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if value == s.value {
+		return false, nil
+	}
+	s.value = value
+	return true, nil
+}
+
+// Watch refreshes the secret every interval until ctx is done, calling
+// onRotate whenever its value changes. Errors are passed to onError, and the
+// previous value is kept.
+func (s *RotatingSecret) Watch(ctx context.Context, interval time.Duration, onRotate func(), onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		changed, err := s.Refresh(ctx)
+		if err != nil {
+			onError(err)
+			continue
+		}
+		if changed {
+			onRotate()
+		}
+	}
+}
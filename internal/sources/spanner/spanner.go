@@ -89,6 +89,12 @@ func (s *Source) SpannerClient() *spanner.Client {
 	return s.Client
 }
 
+// Close closes the client's sessions.
+func (s *Source) Close() error {
+	s.Client.Close()
+	return nil
+}
+
 func (s *Source) DatabaseDialect() string {
 	return s.Dialect
 }
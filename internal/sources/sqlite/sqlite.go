@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -45,9 +46,13 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name     string `yaml:"name" validate:"required"`
-	Kind     string `yaml:"kind" validate:"required"`
-	Database string `yaml:"database" validate:"required"` // Path to SQLite database file
+	Name        string `yaml:"name" validate:"required"`
+	Kind        string `yaml:"kind" validate:"required"`
+	Database    string `yaml:"database" validate:"required"` // Path to SQLite database file
+	HealthQuery string `yaml:"healthQuery"`
+	// ConnMaxIdleTime, if set, closes connections that have been idle for
+	// longer, such as before the database times them out.
+	ConnMaxIdleTime time.Duration `yaml:"connMaxIdleTime"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -55,12 +60,16 @@ func (r Config) SourceConfigKind() string {
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
-	db, err := initSQLiteConnection(ctx, tracer, r.Name, r.Database)
+	db, err := initSQLiteConnection(ctx, tracer, r.Name, r.Database, r.ConnMaxIdleTime)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create db connection: %w", err)
 	}
 
-	err = db.PingContext(context.Background())
+	if r.HealthQuery != "" {
+		_, err = db.ExecContext(context.Background(), r.HealthQuery)
+	} else {
+		err = db.PingContext(context.Background())
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to connect successfully: %w", err)
 	}
@@ -89,13 +98,13 @@ func (s *Source) SQLiteDB() *sql.DB {
 	return s.Db
 }
 
-func initSQLiteConnection(ctx context.Context, tracer trace.Tracer, name, dbPath string) (*sql.DB, error) {
+func initSQLiteConnection(ctx context.Context, tracer trace.Tracer, name, dbPath string, maxIdleTime time.Duration) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
 
 	// Open database connection
-	db, err := sql.Open("sqlite", dbPath)
+	db, err := sources.OpenDB("sqlite", dbPath, maxIdleTime)
 	if err != nil {
 		return nil, fmt.Errorf("sql.Open: %w", err)
 	}
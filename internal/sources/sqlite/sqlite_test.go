@@ -15,7 +15,11 @@
 package sqlite_test
 
 import (
+	"context"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
@@ -23,6 +27,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/sqlite"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestParseFromYamlSQLite(t *testing.T) {
@@ -47,6 +52,58 @@ func TestParseFromYamlSQLite(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "in-memory database",
+			in: `
+            sources:
+                my-sqlite-db:
+                    kind: sqlite
+                    database: ":memory:"
+            `,
+			want: map[string]sources.SourceConfig{
+				"my-sqlite-db": sqlite.Config{
+					Name:     "my-sqlite-db",
+					Kind:     sqlite.SourceKind,
+					Database: ":memory:",
+				},
+			},
+		},
+		{
+			desc: "with health query",
+			in: `
+            sources:
+                my-sqlite-db:
+                    kind: sqlite
+                    database: /path/to/database.db
+                    healthQuery: SELECT 1
+            `,
+			want: map[string]sources.SourceConfig{
+				"my-sqlite-db": sqlite.Config{
+					Name:        "my-sqlite-db",
+					Kind:        sqlite.SourceKind,
+					Database:    "/path/to/database.db",
+					HealthQuery: "SELECT 1",
+				},
+			},
+		},
+		{
+			desc: "with idle connection eviction",
+			in: `
+            sources:
+                my-sqlite-db:
+                    kind: sqlite
+                    database: /path/to/database.db
+                    connMaxIdleTime: 5m
+            `,
+			want: map[string]sources.SourceConfig{
+				"my-sqlite-db": sqlite.Config{
+					Name:            "my-sqlite-db",
+					Kind:            sqlite.SourceKind,
+					Database:        "/path/to/database.db",
+					ConnMaxIdleTime: 5 * time.Minute,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -64,3 +121,67 @@ func TestParseFromYamlSQLite(t *testing.T) {
 		})
 	}
 }
+
+func TestInitializeHealthQuery(t *testing.T) {
+	ctx := context.Background()
+	tracer := noop.NewTracerProvider().Tracer("")
+
+	// the health query leaves a trace that it was executed
+	cfg := sqlite.Config{
+		Name:        "my-sqlite-db",
+		Kind:        sqlite.SourceKind,
+		Database:    filepath.Join(t.TempDir(), "health.db"),
+		HealthQuery: "CREATE TABLE health_checked (id INTEGER)",
+	}
+	src, err := cfg.Initialize(ctx, tracer)
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	db := src.(*sqlite.Source).SQLiteDB()
+	var name string
+	if err := db.QueryRowContext(ctx, "SELECT name FROM sqlite_master WHERE name = 'health_checked'").Scan(&name); err != nil {
+		t.Fatalf("health query was not executed: %s", err)
+	}
+
+	// a failing health query fails the health check, even though a ping succeeds
+	cfg.Database = filepath.Join(t.TempDir(), "unhealthy.db")
+	cfg.HealthQuery = "SELECT * FROM missing_table"
+	_, err = cfg.Initialize(ctx, tracer)
+	if err == nil || !strings.Contains(err.Error(), "missing_table") {
+		t.Fatalf("expected health query to fail, got %v", err)
+	}
+}
+
+func TestConnMaxIdleTime(t *testing.T) {
+	ctx := context.Background()
+	tracer := noop.NewTracerProvider().Tracer("")
+
+	cfg := sqlite.Config{
+		Name:            "my-sqlite-db",
+		Kind:            sqlite.SourceKind,
+		Database:        filepath.Join(t.TempDir(), "idle.db"),
+		ConnMaxIdleTime: 200 * time.Millisecond,
+	}
+	src, err := cfg.Initialize(ctx, tracer)
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	db := src.(*sqlite.Source).SQLiteDB()
+
+	// temporary tables only exist on the connection that created them
+	if _, err := db.ExecContext(ctx, "CREATE TEMP TABLE conn_marker (id INTEGER)"); err != nil {
+		t.Fatalf("unable to create temporary table: %s", err)
+	}
+	reused := func() bool {
+		_, err := db.ExecContext(ctx, "SELECT * FROM conn_marker")
+		return err == nil
+	}
+	if !reused() {
+		t.Fatalf("expected a connection that wasn't idle for long to be reused")
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if reused() {
+		t.Fatalf("expected a connection idle for longer than connMaxIdleTime not to be reused")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NewValuesQuery returns a function that runs statement on the source and
+// returns the values of the first column of its rows, as strings. It returns
+// an error if the source can't run SQL statements.
+func NewValuesQuery(src Source, statement string) (func(context.Context) ([]string, error), error) {
+	switch s := src.(type) {
+	case interface{ PostgresPool() *pgxpool.Pool }:
+		pool := s.PostgresPool()
+		return func(ctx context.Context) ([]string, error) {
+			return queryPgValues(ctx, pool, statement)
+		}, nil
+	case interface{ MySQLPool() *sql.DB }:
+		return sqlValuesQuery(s.MySQLPool(), statement), nil
+	case interface{ MSSQLDB() *sql.DB }:
+		return sqlValuesQuery(s.MSSQLDB(), statement), nil
+	case interface{ SQLiteDB() *sql.DB }:
+		return sqlValuesQuery(s.SQLiteDB(), statement), nil
+	default:
+		return nil, fmt.Errorf("source kind %q does not support values queries", src.SourceKind())
+	}
+}
+
+func queryPgValues(ctx context.Context, pool *pgxpool.Pool, statement string) ([]string, error) {
+	rows, err := pool.Query(ctx, statement)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	defer rows.Close()
+	values := []string{}
+	for rows.Next() {
+		v, err := rows.Values()
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		if len(v) > 0 && v[0] != nil {
+			values = append(values, fmt.Sprint(v[0]))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+	}
+	return values, nil
+}
+
+func sqlValuesQuery(db *sql.DB, statement string) func(context.Context) ([]string, error) {
+	return func(ctx context.Context) ([]string, error) {
+		rows, err := db.QueryContext(ctx, statement)
+		if err != nil {
+			return nil, fmt.Errorf("unable to execute query: %w", err)
+		}
+		defer rows.Close()
+		cols, err := rows.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get column names: %w", err)
+		}
+		if len(cols) == 0 {
+			return nil, fmt.Errorf("values query returned no columns")
+		}
+		values := []string{}
+		dest := make([]any, len(cols))
+		for rows.Next() {
+			// only the first column is used, the others are discarded
+			var first sql.NullString
+			dest[0] = &first
+			for i := 1; i < len(dest); i++ {
+				dest[i] = new(any)
+			}
+			if err := rows.Scan(dest...); err != nil {
+				return nil, fmt.Errorf("unable to parse row: %w", err)
+			}
+			if first.Valid {
+				values = append(values, first.String)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+		}
+		return values, nil
+	}
+}
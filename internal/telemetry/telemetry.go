@@ -23,17 +23,21 @@ import (
 	texporter "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
 	"go.opentelemetry.io/contrib/propagators/autoprop"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/log/global"
+	logsdk "go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
 )
 
 // setupOTelSDK bootstraps the OpenTelemetry pipeline.
 // If it does not return an error, make sure to call shutdown for proper cleanup.
-func SetupOTel(ctx context.Context, versionString, telemetryOTLP string, telemetryGCP bool, telemetryServiceName string) (shutdown func(context.Context) error, err error) {
+func SetupOTel(ctx context.Context, versionString, telemetryOTLP string, telemetryGCP bool, telemetryServiceName string, telemetryPrometheus bool, telemetryOTLPLogs bool) (shutdown func(context.Context) error, err error) {
 	var shutdownFuncs []func(context.Context) error
 
 	// shutdown calls cleanup functions registered via shutdownFuncs.
@@ -72,7 +76,7 @@ func SetupOTel(ctx context.Context, versionString, telemetryOTLP string, telemet
 	shutdownFuncs = append(shutdownFuncs, tracerProvider.Shutdown)
 	otel.SetTracerProvider(tracerProvider)
 
-	meterProvider, err := newMeterProvider(ctx, res, telemetryOTLP, telemetryGCP)
+	meterProvider, err := newMeterProvider(ctx, res, telemetryOTLP, telemetryGCP, telemetryPrometheus)
 	if err != nil {
 		errMsg := fmt.Errorf("unable to set up meter provider: %w", err)
 		handleErr(errMsg)
@@ -81,6 +85,22 @@ func SetupOTel(ctx context.Context, versionString, telemetryOTLP string, telemet
 	shutdownFuncs = append(shutdownFuncs, meterProvider.Shutdown)
 	otel.SetMeterProvider(meterProvider)
 
+	if telemetryOTLPLogs {
+		if telemetryOTLP == "" {
+			handleErr(fmt.Errorf("unable to set up logger provider: exporting logs requires an OTLP endpoint"))
+			return
+		}
+		var loggerProvider *logsdk.LoggerProvider
+		loggerProvider, err = newLoggerProvider(ctx, res, telemetryOTLP)
+		if err != nil {
+			errMsg := fmt.Errorf("unable to set up logger provider: %w", err)
+			handleErr(errMsg)
+			return
+		}
+		shutdownFuncs = append(shutdownFuncs, loggerProvider.Shutdown)
+		global.SetLoggerProvider(loggerProvider)
+	}
+
 	return shutdown, nil
 }
 
@@ -135,7 +155,7 @@ func newTracerProvider(ctx context.Context, r *resource.Resource, telemetryOTLP
 
 // newMeterProvider creates MeterProvider.
 // MeterProvider is a factory for Meters, and is responsible for creating metrics.
-func newMeterProvider(ctx context.Context, r *resource.Resource, telemetryOTLP string, telemetryGCP bool) (*metric.MeterProvider, error) {
+func newMeterProvider(ctx context.Context, r *resource.Resource, telemetryOTLP string, telemetryGCP bool, telemetryPrometheus bool) (*metric.MeterProvider, error) {
 	metricOpts := []metric.Option{}
 	if telemetryOTLP != "" {
 		// otlpmetrichttp provides an OTLP metrics exporter using HTTP with protobuf payloads.
@@ -153,7 +173,32 @@ func newMeterProvider(ctx context.Context, r *resource.Resource, telemetryOTLP s
 		}
 		metricOpts = append(metricOpts, metric.WithReader(metric.NewPeriodicReader(gcpExporter)))
 	}
+	if telemetryPrometheus {
+		// prometheus registers a pull-based reader with the default Prometheus
+		// registry, which is served by the server's /metrics endpoint.
+		promExporter, err := prometheus.New()
+		if err != nil {
+			return nil, err
+		}
+		metricOpts = append(metricOpts, metric.WithReader(promExporter))
+	}
 
 	meterProvider := metric.NewMeterProvider(metricOpts...)
 	return meterProvider, nil
 }
+
+// newLoggerProvider creates LoggerProvider.
+// LoggerProvider is a factory for Loggers, and is responsible for exporting log records.
+func newLoggerProvider(ctx context.Context, r *resource.Resource, telemetryOTLP string) (*logsdk.LoggerProvider, error) {
+	// otlploghttp provides an OTLP log exporter using HTTP with protobuf payloads.
+	// By default, the telemetry is sent to https://localhost:4318/v1/logs.
+	otlpExporter, err := otlploghttp.New(ctx, otlploghttp.WithEndpoint(telemetryOTLP))
+	if err != nil {
+		return nil, err
+	}
+	loggerProvider := logsdk.NewLoggerProvider(
+		logsdk.WithProcessor(logsdk.NewBatchProcessor(otlpExporter)),
+		logsdk.WithResource(r),
+	)
+	return loggerProvider, nil
+}
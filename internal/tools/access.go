@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import "context"
+
+// AccessRule is a requirement on the callers of a tool: a token verified by
+// an auth service and, if Claim is set, a claim of that token, such as a role.
+type AccessRule struct {
+	AuthService string `yaml:"authService" validate:"required"`
+	Claim       string `yaml:"claim"`
+	// Values are the claim values that satisfy the rule, as for ColumnAuth.
+	Values []string `yaml:"values"`
+}
+
+// Allowed returns true if the caller's claims in ctx satisfy r.
+func (r AccessRule) Allowed(ctx context.Context) bool {
+	claims, ok := ClaimsFromContext(ctx, r.AuthService)
+	if !ok {
+		return false
+	}
+	return r.Claim == "" || claimMatches(claims, r.Claim, r.Values)
+}
+
+// allowedAny returns true if rules is empty, or if the caller satisfies any
+// of them.
+func allowedAny(ctx context.Context, rules []AccessRule) bool {
+	if len(rules) == 0 {
+		return true
+	}
+	for _, r := range rules {
+		if r.Allowed(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanList returns true if the caller, whose claims are in ctx, may see t in
+// listings.
+func CanList(ctx context.Context, t Tool) bool {
+	return allowedAny(ctx, GetOptions(t).ListRequired)
+}
+
+// CanInvoke returns true if the caller, whose claims are in ctx, may invoke
+// t. It is checked in addition to the tool's authRequired.
+func CanInvoke(ctx context.Context, t Tool) bool {
+	return allowedAny(ctx, GetOptions(t).InvokeRequired)
+}
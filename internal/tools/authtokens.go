@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+type authTokensKey struct{}
+
+// WithAuthTokens returns a context carrying the raw tokens of the auth
+// services verified for an invocation, keyed by auth service name.
+func WithAuthTokens(ctx context.Context, tokens map[string]string) context.Context {
+	return context.WithValue(ctx, authTokensKey{}, tokens)
+}
+
+type claimsKey struct{}
+
+// WithClaims returns a context carrying the claims of the auth services
+// verified for an invocation, keyed by auth service name.
+func WithClaims(ctx context.Context, claims map[string]map[string]any) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims of the auth service named name, if it
+// was verified for the invocation.
+func ClaimsFromContext(ctx context.Context, name string) (map[string]any, bool) {
+	claims, _ := ctx.Value(claimsKey{}).(map[string]map[string]any)
+	c, ok := claims[name]
+	return c, ok
+}
+
+// AllClaimsFromContext returns the claims of all the auth services verified
+// for the invocation, keyed by auth service name.
+func AllClaimsFromContext(ctx context.Context) map[string]map[string]any {
+	claims, _ := ctx.Value(claimsKey{}).(map[string]map[string]any)
+	if claims == nil {
+		return make(map[string]map[string]any)
+	}
+	return claims
+}
+
+// AuthTokenFromContext returns the raw token of the first of authServices
+// that was verified for the invocation. If authServices is empty, the token
+// of any verified auth service is returned, in lexical order of their names.
+func AuthTokenFromContext(ctx context.Context, authServices []string) (string, bool) {
+	tokens, _ := ctx.Value(authTokensKey{}).(map[string]string)
+	if len(authServices) == 0 {
+		authServices = slices.Sorted(maps.Keys(tokens))
+	}
+	for _, name := range authServices {
+		if token, ok := tokens[name]; ok && token != "" {
+			return token, true
+		}
+	}
+	return "", false
+}
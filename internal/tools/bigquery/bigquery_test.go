@@ -64,6 +64,70 @@ func TestParseFromYamlBigQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with cost estimate",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-sql
+					source: my-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					estimateCost: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquery.Config{
+					Name:         "example_tool",
+					Kind:         "bigquery-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+					AuthRequired: []string{},
+					EstimateCost: true,
+				},
+			},
+		},
+		{
+			desc: "with template parameters",
+			in: `
+			tools:
+				example_tool:
+					kind: bigquery-sql
+					source: my-instance
+					description: some description
+					statement: |
+						SELECT * FROM {{.dataset}}.{{.tableName}} WHERE country = @country;
+					parameters:
+						- name: country
+						  type: string
+						  description: some description
+					templateParameters:
+						- name: dataset
+						  type: string
+						  description: The dataset to select hotels from.
+						- name: tableName
+						  type: string
+						  description: The table to select hotels from.
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigquery.Config{
+					Name:         "example_tool",
+					Kind:         "bigquery-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM {{.dataset}}.{{.tableName}} WHERE country = @country;\n",
+					AuthRequired: []string{},
+					Parameters: []tools.Parameter{
+						tools.NewStringParameter("country", "some description"),
+					},
+					TemplateParameters: []tools.Parameter{
+						tools.NewStringParameter("dataset", "The dataset to select hotels from."),
+						tools.NewStringParameter("tableName", "The table to select hotels from."),
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -44,6 +44,7 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.T
 
 type compatibleSource interface {
 	BigQueryClient() *bigqueryapi.Client
+	BigQueryMaximumBytesBilled() int64
 }
 
 // validate compatible sources are still compatible
@@ -57,6 +58,7 @@ type Config struct {
 	Source       string   `yaml:"source" validate:"required"`
 	Description  string   `yaml:"description" validate:"required"`
 	AuthRequired []string `yaml:"authRequired"`
+	EstimateCost bool     `yaml:"estimateCost"`
 }
 
 // validate interface
@@ -90,13 +92,15 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	// finish tool setup
 	t := Tool{
-		Name:         cfg.Name,
-		Kind:         kind,
-		Parameters:   parameters,
-		AuthRequired: cfg.AuthRequired,
-		Client:       s.BigQueryClient(),
-		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
-		mcpManifest:  mcpManifest,
+		Name:               cfg.Name,
+		Kind:               kind,
+		Parameters:         parameters,
+		AuthRequired:       cfg.AuthRequired,
+		EstimateCost:       cfg.EstimateCost,
+		Client:             s.BigQueryClient(),
+		MaximumBytesBilled: s.BigQueryMaximumBytesBilled(),
+		manifest:           tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:        mcpManifest,
 	}
 	return t, nil
 }
@@ -110,8 +114,13 @@ type Tool struct {
 	AuthRequired []string         `yaml:"authRequired"`
 	Parameters   tools.Parameters `yaml:"parameters"`
 	Client       *bigqueryapi.Client
+	EstimateCost bool
 	manifest     tools.Manifest
 	mcpManifest  tools.McpManifest
+
+	// MaximumBytesBilled is the maximum bytes billed of the queries, or 0
+	// for the project default.
+	MaximumBytesBilled int64
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
@@ -121,12 +130,30 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 		return nil, fmt.Errorf("unable to get cast %s", sliceParams[0])
 	}
 
-	query := t.Client.Query(sql)
-	query.Location = t.Client.Location
+	newQuery := func() *bigqueryapi.Query {
+		query := t.Client.Query(sql)
+		query.Location = t.Client.Location
+		query.MaxBytesBilled = t.MaximumBytesBilled
+		return query
+	}
+
+	if t.EstimateCost {
+		dryRun := newQuery()
+		dryRun.DryRun = true
+		job, err := dryRun.Run(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to estimate query cost: %w", err)
+		}
+		tools.SetMeta(ctx, "estimatedCost", bigqueryds.JobCost(job.LastStatus()))
+	}
 
-	it, err := query.Read(ctx)
+	job, err := newQuery().Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", bigqueryds.LimitError(err))
+	}
+	it, err := job.Read(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
+		return nil, fmt.Errorf("unable to execute query: %w", bigqueryds.LimitError(err))
 	}
 
 	var out []any
@@ -146,11 +173,17 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 		out = append(out, vMap)
 	}
 
+	// the cost is informational, so the results are returned even if the
+	// job statistics can't be retrieved
+	if status, err := job.Status(ctx); err == nil {
+		tools.SetMeta(ctx, "cost", bigqueryds.JobCost(status))
+	}
+
 	return out, nil
 }
 
-func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
-	return tools.ParseParams(t.Parameters, data, claims)
+func (t Tool) ParseParams(ctx context.Context, data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(ctx, t.Parameters, data, claims)
 }
 
 func (t Tool) Manifest() tools.Manifest {
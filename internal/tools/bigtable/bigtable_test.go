@@ -64,6 +64,35 @@ func TestParseFromYamlBigtable(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "float parameter",
+			in: `
+			tools:
+				example_tool:
+					kind: bigtable-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					parameters:
+						- name: price
+						  type: float
+						  description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": bigtable.Config{
+					Name:         "example_tool",
+					Kind:         "bigtable-sql",
+					Source:       "my-pg-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+					AuthRequired: []string{},
+					Parameters: []tools.Parameter{
+						tools.NewFloatParameter("price", "some description"),
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
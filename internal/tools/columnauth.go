@@ -0,0 +1,62 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// ColumnAuth is the claim of an auth service that a caller needs to see a
+// result column.
+type ColumnAuth struct {
+	AuthService string `yaml:"authService" validate:"required"`
+	Claim       string `yaml:"claim" validate:"required"`
+	// Values are the claim values that grant access. If the claim is a list,
+	// such as groups, any of its elements may match. Any value other than
+	// false or empty grants access if Values is empty.
+	Values []string `yaml:"values"`
+}
+
+// Allowed returns true if the caller's claims in ctx grant access.
+func (c ColumnAuth) Allowed(ctx context.Context) bool {
+	claims, _ := ClaimsFromContext(ctx, c.AuthService)
+	return claimMatches(claims, c.Claim, c.Values)
+}
+
+// claimMatches returns true if the claim named claim has one of values, or
+// any value other than false or empty if values is empty. If the claim is a
+// list, such as groups, any of its elements may match.
+func claimMatches(claims map[string]any, claim string, values []string) bool {
+	v, ok := claims[claim]
+	if !ok {
+		return false
+	}
+	vs, ok := v.([]any)
+	if !ok {
+		vs = []any{v}
+	}
+	for _, v := range vs {
+		if len(values) == 0 {
+			if v != nil && v != false && v != "" {
+				return true
+			}
+		} else if slices.Contains(values, fmt.Sprint(v)) {
+			return true
+		}
+	}
+	return false
+}
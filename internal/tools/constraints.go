@@ -0,0 +1,435 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// constraint is a compiled `validate` expression of a tool. Expressions
+// compare parameter values and literals, and combine the comparisons:
+//
+//	startDate <= endDate
+//	limit == null || (limit > 0 && limit <= 100)
+//	!(status == "closed" && reopen)
+//
+// Literals are numbers, strings in single or double quotes, true, false and
+// null, which is the value of omitted optional parameters. Numbers and
+// strings can be ordered, other values can only be tested for equality.
+type constraint struct {
+	src  string
+	root node
+}
+
+// compileConstraint parses src, and returns an error if it refers to a
+// parameter that is not in params.
+func compileConstraint(src string, params []ParameterManifest) (constraint, error) {
+	c, idents, err := parseExpression(src)
+	if err != nil {
+		return constraint{}, fmt.Errorf("invalid validate expression %q: %w", src, err)
+	}
+	declared := make(map[string]bool, len(params))
+	for _, param := range params {
+		declared[param.Name] = true
+	}
+	for _, name := range idents {
+		if !declared[name] {
+			return constraint{}, fmt.Errorf("invalid validate expression %q: tool has no parameter %q", src, name)
+		}
+	}
+	return c, nil
+}
+
+// parseExpression parses src, and returns the identifiers it refers to.
+func parseExpression(src string) (constraint, []string, error) {
+	p := &exprParser{src: src}
+	if err := p.tokenize(); err != nil {
+		return constraint{}, nil, err
+	}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return constraint{}, nil, err
+	}
+	return constraint{src: src, root: root}, p.idents, nil
+}
+
+// check returns an error if the parameter values don't satisfy c.
+func (c constraint) check(values map[string]any) error {
+	v, err := c.root.eval(values)
+	if err != nil {
+		return fmt.Errorf("unable to evaluate %q: %w", c.src, err)
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return fmt.Errorf("validate expression %q does not evaluate to a boolean", c.src)
+	}
+	if !b {
+		return fmt.Errorf("parameters must satisfy %q", c.src)
+	}
+	return nil
+}
+
+// node is a node of a parsed expression.
+type node interface {
+	eval(values map[string]any) (any, error)
+}
+
+type literalNode struct{ v any }
+
+func (n literalNode) eval(map[string]any) (any, error) { return n.v, nil }
+
+type identNode struct{ name string }
+
+func (n identNode) eval(values map[string]any) (any, error) { return values[n.name], nil }
+
+type notNode struct{ x node }
+
+func (n notNode) eval(values map[string]any) (any, error) {
+	v, err := n.x.eval(values)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("operand of ! is not a boolean: %v", v)
+	}
+	return !b, nil
+}
+
+// logicalNode is a && or || expression. The right operand is only evaluated
+// if the left one doesn't decide the result, so that it can be guarded by a
+// null check.
+type logicalNode struct {
+	op   string
+	x, y node
+}
+
+func (n logicalNode) eval(values map[string]any) (any, error) {
+	x, err := n.operand(n.x, values)
+	if err != nil {
+		return nil, err
+	}
+	// true || y and false && y are decided by the left operand
+	if x == (n.op == "||") {
+		return x, nil
+	}
+	return n.operand(n.y, values)
+}
+
+func (n logicalNode) operand(x node, values map[string]any) (bool, error) {
+	v, err := x.eval(values)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("operand of %s is not a boolean: %v", n.op, v)
+	}
+	return b, nil
+}
+
+type compareNode struct {
+	op   string
+	x, y node
+}
+
+func (n compareNode) eval(values map[string]any) (any, error) {
+	x, err := n.x.eval(values)
+	if err != nil {
+		return nil, err
+	}
+	y, err := n.y.eval(values)
+	if err != nil {
+		return nil, err
+	}
+	x, y = normalizeNumber(x), normalizeNumber(y)
+	if n.op == "==" || n.op == "!=" {
+		return reflect.DeepEqual(x, y) == (n.op == "=="), nil
+	}
+	var c int
+	switch xv := x.(type) {
+	case float64:
+		yv, ok := y.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare %v and %v", x, y)
+		}
+		switch {
+		case xv < yv:
+			c = -1
+		case xv > yv:
+			c = 1
+		}
+	case string:
+		yv, ok := y.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare %q and %v", x, y)
+		}
+		c = strings.Compare(xv, yv)
+	default:
+		return nil, fmt.Errorf("cannot compare %v and %v", x, y)
+	}
+	switch n.op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+// normalizeNumber converts numbers to float64, so that they compare equal
+// regardless of how they were decoded. Other values are returned unchanged.
+func normalizeNumber(v any) any {
+	switch v := v.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	}
+	return v
+}
+
+type token struct {
+	// kind is "ident", "number", "string" or the operator itself.
+	kind string
+	text string
+	v    any
+}
+
+// maxExprDepth is the maximum nesting depth of parentheses and negations in
+// an expression, which bounds the recursion of the parser and of evaluation.
+const maxExprDepth = 64
+
+type exprParser struct {
+	src    string
+	tokens []token
+	pos    int
+	idents []string
+	depth  int
+}
+
+var exprOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")"}
+
+func (p *exprParser) tokenize() error {
+	s := p.src
+	for i := 0; i < len(s); {
+		r := rune(s[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			end := strings.IndexRune(s[i+1:], r)
+			if end < 0 {
+				return fmt.Errorf("unterminated string")
+			}
+			text := s[i+1 : i+1+end]
+			p.tokens = append(p.tokens, token{kind: "string", text: text, v: text})
+			i += end + 2
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(s) && unicode.IsDigit(rune(s[i+1]))):
+			j := i + 1
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			f, err := strconv.ParseFloat(s[i:j], 64)
+			if err != nil {
+				return fmt.Errorf("invalid number %q", s[i:j])
+			}
+			p.tokens = append(p.tokens, token{kind: "number", text: s[i:j], v: f})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || s[j] == '_') {
+				j++
+			}
+			p.tokens = append(p.tokens, token{kind: "ident", text: s[i:j]})
+			i = j
+		default:
+			matched := false
+			for _, op := range exprOperators {
+				if strings.HasPrefix(s[i:], op) {
+					p.tokens = append(p.tokens, token{kind: op, text: op})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return fmt.Errorf("unexpected character %q", r)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos].kind
+	}
+	return ""
+}
+
+func (p *exprParser) parseOr() (node, error) {
+	x, err := p.parseAnd()
+	for err == nil && p.peek() == "||" {
+		p.pos++
+		var y node
+		if y, err = p.parseAnd(); err == nil {
+			x = logicalNode{op: "||", x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+func (p *exprParser) parseAnd() (node, error) {
+	x, err := p.parseNot()
+	for err == nil && p.peek() == "&&" {
+		p.pos++
+		var y node
+		if y, err = p.parseNot(); err == nil {
+			x = logicalNode{op: "&&", x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+// nest enters a nested expression, which must be left by calling the returned
+// func.
+func (p *exprParser) nest() (func(), error) {
+	if p.depth >= maxExprDepth {
+		return nil, fmt.Errorf("expression is nested more than %d levels deep", maxExprDepth)
+	}
+	p.depth++
+	return func() { p.depth-- }, nil
+}
+
+func (p *exprParser) parseNot() (node, error) {
+	if p.peek() == "!" {
+		p.pos++
+		leave, err := p.nest()
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		x, err := p.parseNot()
+		return notNode{x: x}, err
+	}
+	return p.parseCompare()
+}
+
+func (p *exprParser) parseCompare() (node, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	switch op := p.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.pos++
+		y, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return compareNode{op: op, x: x, y: y}, nil
+	}
+	return x, nil
+}
+
+func (p *exprParser) parsePrimary() (node, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case "number", "string":
+		return literalNode{v: t.v}, nil
+	case "ident":
+		switch t.text {
+		case "true":
+			return literalNode{v: true}, nil
+		case "false":
+			return literalNode{v: false}, nil
+		case "null":
+			return literalNode{v: nil}, nil
+		}
+		p.idents = append(p.idents, t.text)
+		return identNode{name: t.text}, nil
+	case "(":
+		leave, err := p.nest()
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return x, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+// RowFilter is a compiled expression over the fields of a result row, such as
+// `margin > 100 && region == "emea"`, written in the language of `validate`
+// expressions. Fields that are missing from a row are null.
+type RowFilter struct {
+	c constraint
+}
+
+// CompileRowFilter parses src. Since the fields of the rows aren't known in
+// advance, it doesn't check the identifiers src refers to.
+func CompileRowFilter(src string) (*RowFilter, error) {
+	c, _, err := parseExpression(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid row filter %q: %w", src, err)
+	}
+	return &RowFilter{c: c}, nil
+}
+
+// Match reports whether row satisfies f. As in a SQL WHERE clause, rows for
+// which the expression can't be evaluated, such as because it compares a null
+// field, don't match, and neither do rows that aren't objects.
+func (f *RowFilter) Match(row any) bool {
+	values, ok := row.(map[string]any)
+	if !ok {
+		return false
+	}
+	v, err := f.c.root.eval(values)
+	return err == nil && v == true
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import "context"
+
+// DryRunResult is what a tool would run for an invocation.
+type DryRunResult struct {
+	// Statement is the statement with its template parameters resolved.
+	Statement string `json:"statement"`
+	// Parameters maps the name of each parameter to its resolved value.
+	Parameters map[string]any `json:"parameters"`
+	// Binds are the values bound to the placeholders of Statement, in order.
+	Binds []any `json:"binds"`
+}
+
+// DryRunner is implemented by tools that can render what they would run for
+// an invocation without running it.
+type DryRunner interface {
+	DryRun(ctx context.Context, params ParamValues) (DryRunResult, error)
+}
+
+// GetDryRunner returns the DryRunner of the tool, if any. Tools that wrap
+// another Tool can implement `Unwrap() Tool` to expose its DryRunner.
+func GetDryRunner(t Tool) (DryRunner, bool) {
+	for {
+		switch d := t.(type) {
+		case DryRunner:
+			return d, true
+		case interface{ Unwrap() Tool }:
+			t = d.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
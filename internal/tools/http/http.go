@@ -59,9 +59,13 @@ type Config struct {
 	Method       tools.HTTPMethod  `yaml:"method" validate:"required"`
 	Headers      map[string]string `yaml:"headers"`
 	RequestBody  string            `yaml:"requestBody"`
+	PathParams   tools.Parameters  `yaml:"pathParams"`
 	QueryParams  tools.Parameters  `yaml:"queryParams"`
 	BodyParams   tools.Parameters  `yaml:"bodyParams"`
 	HeaderParams tools.Parameters  `yaml:"headerParams"`
+	// ForwardAuthToken sends the caller's verified auth token to the
+	// upstream as a bearer token in the Authorization header.
+	ForwardAuthToken bool `yaml:"forwardAuthToken"`
 }
 
 // validate interface
@@ -90,6 +94,11 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing URL: %s", err)
 	}
+	if len(cfg.PathParams) > 0 {
+		if _, err := template.New("path").Parse(u.Path); err != nil {
+			return nil, fmt.Errorf("error parsing path: %s", err)
+		}
+	}
 
 	// Get existing query parameters from the URL
 	queryParameters := u.Query()
@@ -105,10 +114,11 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	maps.Copy(combinedHeaders, cfg.Headers)
 
 	// Create a slice for all parameters
-	allParameters := slices.Concat(cfg.BodyParams, cfg.HeaderParams, cfg.QueryParams)
+	allParameters := slices.Concat(cfg.PathParams, cfg.BodyParams, cfg.HeaderParams, cfg.QueryParams)
 
 	// Create parameter MCP manifest
 	paramManifest := slices.Concat(
+		cfg.PathParams.Manifest(),
 		cfg.QueryParams.Manifest(),
 		cfg.BodyParams.Manifest(),
 		cfg.HeaderParams.Manifest(),
@@ -117,12 +127,14 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		paramManifest = make([]tools.ParameterManifest, 0)
 	}
 
+	pathMcpManifest := cfg.PathParams.McpManifest()
 	queryMcpManifest := cfg.QueryParams.McpManifest()
 	bodyMcpManifest := cfg.BodyParams.McpManifest()
 	headerMcpManifest := cfg.HeaderParams.McpManifest()
 
 	// Concatenate parameters for MCP `required` field
 	concatRequiredManifest := slices.Concat(
+		pathMcpManifest.Required,
 		queryMcpManifest.Required,
 		bodyMcpManifest.Required,
 		headerMcpManifest.Required,
@@ -133,6 +145,9 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	// Concatenate parameters for MCP `properties` field
 	concatPropertiesManifest := make(map[string]tools.ParameterMcpManifest)
+	for name, p := range pathMcpManifest.Properties {
+		concatPropertiesManifest[name] = p
+	}
 	for name, p := range queryMcpManifest.Properties {
 		concatPropertiesManifest[name] = p
 	}
@@ -145,16 +160,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	// Create a new McpToolsSchema with all parameters
 	paramMcpManifest := tools.McpToolsSchema{
-		Type:       "object",
-		Properties: concatPropertiesManifest,
-		Required:   concatRequiredManifest,
+		Type:              "object",
+		Properties:        concatPropertiesManifest,
+		Required:          concatRequiredManifest,
+		DependentRequired: allParameters.McpManifest().DependentRequired,
 	}
 
 	// Verify there are no duplicate parameter names
 	seenNames := make(map[string]bool)
 	for _, param := range paramManifest {
 		if _, exists := seenNames[param.Name]; exists {
-			return nil, fmt.Errorf("parameter name must be unique across queryParams, bodyParams, and headerParams. Duplicate parameter: %s", param.Name)
+			return nil, fmt.Errorf("parameter name must be unique across pathParams, queryParams, bodyParams, and headerParams. Duplicate parameter: %s", param.Name)
 		}
 		seenNames[param.Name] = true
 	}
@@ -173,14 +189,18 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		Method:       cfg.Method,
 		AuthRequired: cfg.AuthRequired,
 		RequestBody:  cfg.RequestBody,
+		PathParams:   cfg.PathParams,
 		QueryParams:  cfg.QueryParams,
 		BodyParams:   cfg.BodyParams,
 		HeaderParams: cfg.HeaderParams,
 		Headers:      combinedHeaders,
+		Auth:         s.Auth,
 		Client:       s.Client,
 		AllParams:    allParameters,
 		manifest:     tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
 		mcpManifest:  mcpManifest,
+
+		ForwardAuthToken: cfg.ForwardAuthToken,
 	}, nil
 }
 
@@ -197,11 +217,15 @@ type Tool struct {
 	Method       tools.HTTPMethod  `yaml:"method"`
 	Headers      map[string]string `yaml:"headers"`
 	RequestBody  string            `yaml:"requestBody"`
+	PathParams   tools.Parameters  `yaml:"pathParams"`
 	QueryParams  tools.Parameters  `yaml:"queryParams"`
 	BodyParams   tools.Parameters  `yaml:"bodyParams"`
 	HeaderParams tools.Parameters  `yaml:"headerParams"`
 	AllParams    tools.Parameters  `yaml:"allParams"`
 
+	ForwardAuthToken bool `yaml:"forwardAuthToken"`
+
+	Auth        httpsrc.AuthConfig
 	Client      *http.Client
 	manifest    tools.Manifest
 	mcpManifest tools.McpManifest
@@ -240,8 +264,37 @@ func getRequestBody(bodyParams tools.Parameters, requestBodyPayload string, para
 	return result.String(), nil
 }
 
+// Helper function to populate the path parameters of the request URL. Values
+// are escaped so that they always fill a single path segment.
+func getPath(u *url.URL, pathParams tools.Parameters, paramsMap map[string]any) error {
+	pathParamValues, err := tools.GetParams(pathParams, paramsMap)
+	if err != nil {
+		return err
+	}
+	escapedParamsMap := make(map[string]string)
+	for _, p := range pathParamValues {
+		escapedParamsMap[p.Name] = url.PathEscape(fmt.Sprintf("%v", p.Value))
+	}
+	templ, err := template.New("path").Parse(u.Path)
+	if err != nil {
+		return fmt.Errorf("error parsing path: %s", err)
+	}
+	var result bytes.Buffer
+	if err := templ.Execute(&result, escapedParamsMap); err != nil {
+		return fmt.Errorf("error replacing path: %s", err)
+	}
+	u.RawPath = result.String()
+	u.Path, err = url.PathUnescape(u.RawPath)
+	return err
+}
+
 // Helper function to generate the HTTP request URL upon Tool invocation.
-func getURL(u *url.URL, queryParams tools.Parameters, paramsMap map[string]any) (string, error) {
+func getURL(u *url.URL, pathParams, queryParams tools.Parameters, paramsMap map[string]any) (string, error) {
+	if len(pathParams) > 0 {
+		if err := getPath(u, pathParams, paramsMap); err != nil {
+			return "", err
+		}
+	}
 	// Set dynamic query parameters
 	query := u.Query()
 	for _, p := range queryParams {
@@ -278,10 +331,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 		return nil, fmt.Errorf("error populating request body: %s", err)
 	}
 
-	// Calculate URL
-	urlString, err := getURL(t.URL, t.QueryParams, paramsMap)
+	// Calculate URL on a copy, so that invocations don't share parameters
+	u := *t.URL
+	urlString, err := getURL(&u, t.PathParams, t.QueryParams, paramsMap)
 	if err != nil {
-		return nil, fmt.Errorf("error populating query parameters: %s", err)
+		return nil, fmt.Errorf("error populating URL parameters: %s", err)
 	}
 
 	req, _ := http.NewRequest(string(t.Method), urlString, strings.NewReader(requestBody))
@@ -295,6 +349,13 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 	for k, v := range allHeaders {
 		req.Header.Set(k, v)
 	}
+	t.Auth.Apply(req)
+	if t.ForwardAuthToken {
+		// the token is omitted if the caller didn't send one
+		if token, ok := tools.AuthTokenFromContext(ctx, t.AuthRequired); ok {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
 
 	// Make request and fetch response
 	resp, err := t.Client.Do(req)
@@ -326,8 +387,8 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 	return []any{data}, nil
 }
 
-func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
-	return tools.ParseParams(t.AllParams, data, claims)
+func (t Tool) ParseParams(ctx context.Context, data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(ctx, t.AllParams, data, claims)
 }
 
 func (t Tool) Manifest() tools.Manifest {
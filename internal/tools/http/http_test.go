@@ -15,12 +15,17 @@
 package http_test
 
 import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	httpsrc "github.com/googleapis/genai-toolbox/internal/sources/http"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	http "github.com/googleapis/genai-toolbox/internal/tools/http"
@@ -197,3 +202,203 @@ func TestFailParseFromYamlHTTP(t *testing.T) {
 	}
 
 }
+
+func TestInvokeForwardAuthToken(t *testing.T) {
+	var gotAuthorization string
+	upstream := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		gotAuthorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer upstream.Close()
+
+	srcs := map[string]sources.Source{
+		"my-instance": &httpsrc.Source{
+			Name:    "my-instance",
+			Kind:    httpsrc.SourceKind,
+			BaseURL: upstream.URL,
+			Client:  upstream.Client(),
+		},
+	}
+	ctx := tools.WithAuthTokens(context.Background(), map[string]string{"my-google-auth-service": "user-token"})
+
+	tcs := []struct {
+		desc             string
+		forwardAuthToken bool
+		want             string
+	}{
+		{
+			desc:             "forwarded",
+			forwardAuthToken: true,
+			want:             "Bearer user-token",
+		},
+		{
+			desc:             "not forwarded",
+			forwardAuthToken: false,
+			want:             "",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotAuthorization = ""
+			cfg := http.Config{
+				Name:             "example_tool",
+				Kind:             "http",
+				Source:           "my-instance",
+				Description:      "some description",
+				AuthRequired:     []string{"my-google-auth-service"},
+				Path:             "/search",
+				Method:           "GET",
+				ForwardAuthToken: tc.forwardAuthToken,
+			}
+			tool, err := cfg.Initialize(srcs)
+			if err != nil {
+				t.Fatalf("unable to initialize tool: %s", err)
+			}
+			if _, err := tool.Invoke(ctx, tools.ParamValues{}); err != nil {
+				t.Fatalf("unexpected error invoking tool: %s", err)
+			}
+			if gotAuthorization != tc.want {
+				t.Fatalf("unexpected Authorization header: got %q, want %q", gotAuthorization, tc.want)
+			}
+		})
+	}
+}
+
+func TestInvokePathParams(t *testing.T) {
+	var gotPath, gotQuery string
+	upstream := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotQuery = r.URL.RawQuery
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer upstream.Close()
+
+	srcs := map[string]sources.Source{
+		"my-instance": &httpsrc.Source{
+			Name:    "my-instance",
+			Kind:    httpsrc.SourceKind,
+			BaseURL: upstream.URL,
+			Client:  upstream.Client(),
+		},
+	}
+	cfg := http.Config{
+		Name:        "example_tool",
+		Kind:        "http",
+		Source:      "my-instance",
+		Description: "some description",
+		Path:        "/users/{{.user}}/orders/{{.id}}",
+		Method:      "GET",
+		PathParams: tools.Parameters{
+			tools.NewStringParameter("user", "user name"),
+			tools.NewIntParameter("id", "order id"),
+		},
+		QueryParams: tools.Parameters{tools.NewStringParameter("status", "order status")},
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	tcs := []struct {
+		desc      string
+		in        map[string]any
+		wantPath  string
+		wantQuery string
+	}{
+		{
+			desc:      "simple values",
+			in:        map[string]any{"user": "alice", "id": 7, "status": "open"},
+			wantPath:  "/users/alice/orders/7",
+			wantQuery: "status=open",
+		},
+		{
+			desc:      "values are escaped",
+			in:        map[string]any{"user": "a/b c", "id": 8, "status": "closed"},
+			wantPath:  "/users/a%2Fb%20c/orders/8",
+			wantQuery: "status=closed",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params, err := tool.ParseParams(context.Background(), tc.in, nil)
+			if err != nil {
+				t.Fatalf("unable to parse params: %s", err)
+			}
+			if _, err := tool.Invoke(context.Background(), params); err != nil {
+				t.Fatalf("unexpected error invoking tool: %s", err)
+			}
+			if gotPath != tc.wantPath {
+				t.Fatalf("unexpected path: got %q, want %q", gotPath, tc.wantPath)
+			}
+			if gotQuery != tc.wantQuery {
+				t.Fatalf("unexpected query: got %q, want %q", gotQuery, tc.wantQuery)
+			}
+		})
+	}
+
+	if _, err := tool.ParseParams(context.Background(), map[string]any{"user": "alice", "status": "open"}, nil); err == nil {
+		t.Fatalf("expected an error for a missing path parameter")
+	}
+}
+
+func TestInvokeSourceAuth(t *testing.T) {
+	var gotAuthorization string
+	upstream := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		gotAuthorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer upstream.Close()
+
+	tcs := []struct {
+		desc string
+		auth httpsrc.AuthConfig
+		want string
+	}{
+		{
+			desc: "bearer",
+			auth: httpsrc.AuthConfig{Type: "bearer", Token: "secret"},
+			want: "Bearer secret",
+		},
+		{
+			desc: "basic",
+			auth: httpsrc.AuthConfig{Type: "basic", Username: "user", Password: "pass"},
+			want: "Basic dXNlcjpwYXNz",
+		},
+		{
+			desc: "none",
+			want: "",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotAuthorization = ""
+			srcs := map[string]sources.Source{
+				"my-instance": &httpsrc.Source{
+					Name:    "my-instance",
+					Kind:    httpsrc.SourceKind,
+					BaseURL: upstream.URL,
+					Auth:    tc.auth,
+					Client:  upstream.Client(),
+				},
+			}
+			cfg := http.Config{
+				Name:        "example_tool",
+				Kind:        "http",
+				Source:      "my-instance",
+				Description: "some description",
+				Path:        "/search",
+				Method:      "GET",
+			}
+			tool, err := cfg.Initialize(srcs)
+			if err != nil {
+				t.Fatalf("unable to initialize tool: %s", err)
+			}
+			if _, err := tool.Invoke(context.Background(), tools.ParamValues{}); err != nil {
+				t.Fatalf("unexpected error invoking tool: %s", err)
+			}
+			if gotAuthorization != tc.want {
+				t.Fatalf("unexpected Authorization header: got %q, want %q", gotAuthorization, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inmemorylookup_test
+
+import (
+	"context"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/inmemory"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/inmemorylookup"
+)
+
+func TestParseFromYamlInMemoryLookup(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: in-memory-lookup
+					source: my-in-memory-source
+					description: some description
+					table: users
+					parameters:
+						- name: id
+						  type: integer
+						  description: some description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": inmemorylookup.Config{
+					Name:         "example_tool",
+					Kind:         "in-memory-lookup",
+					Source:       "my-in-memory-source",
+					Description:  "some description",
+					Table:        "users",
+					AuthRequired: []string{},
+					Parameters: []tools.Parameter{
+						tools.NewIntParameter("id", "some description"),
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInvokeInMemoryLookup(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"my-in-memory-source": &inmemory.Source{
+			Name: "my-in-memory-source",
+			Kind: inmemory.SourceKind,
+			Tables: map[string]inmemory.Table{
+				"users": {
+					{"id": uint64(1), "name": "alice", "team": "red"},
+					{"id": uint64(2), "name": "bob", "team": "blue"},
+					{"id": uint64(3), "name": "sid", "team": "red"},
+				},
+			},
+		},
+	}
+	tcs := []struct {
+		desc   string
+		params tools.Parameters
+		data   map[string]any
+		want   []any
+	}{
+		{
+			desc: "all rows",
+			data: map[string]any{},
+			want: []any{
+				map[string]any{"id": uint64(1), "name": "alice", "team": "red"},
+				map[string]any{"id": uint64(2), "name": "bob", "team": "blue"},
+				map[string]any{"id": uint64(3), "name": "sid", "team": "red"},
+			},
+		},
+		{
+			desc:   "key lookup",
+			params: tools.Parameters{tools.NewIntParameter("id", "user id")},
+			data:   map[string]any{"id": 2},
+			want: []any{
+				map[string]any{"id": uint64(2), "name": "bob", "team": "blue"},
+			},
+		},
+		{
+			desc:   "multiple matches",
+			params: tools.Parameters{tools.NewStringParameter("team", "team name")},
+			data:   map[string]any{"team": "red"},
+			want: []any{
+				map[string]any{"id": uint64(1), "name": "alice", "team": "red"},
+				map[string]any{"id": uint64(3), "name": "sid", "team": "red"},
+			},
+		},
+		{
+			desc:   "no matches",
+			params: tools.Parameters{tools.NewStringParameter("team", "team name")},
+			data:   map[string]any{"team": "green"},
+			want:   []any{},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := inmemorylookup.Config{
+				Name:        "lookup",
+				Kind:        "in-memory-lookup",
+				Source:      "my-in-memory-source",
+				Description: "some description",
+				Table:       "users",
+				Parameters:  tc.params,
+			}
+			tool, err := cfg.Initialize(srcs)
+			if err != nil {
+				t.Fatalf("unable to initialize tool: %s", err)
+			}
+			params, err := tool.ParseParams(context.Background(), tc.data, nil)
+			if err != nil {
+				t.Fatalf("unable to parse params: %s", err)
+			}
+			got, err := tool.Invoke(context.Background(), params)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInvokeInMemoryLookupDefaultArray(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	srcs := map[string]sources.Source{
+		"my-in-memory-source": &inmemory.Source{
+			Name: "my-in-memory-source",
+			Kind: inmemory.SourceKind,
+			Tables: map[string]inmemory.Table{
+				"teams": {
+					{"name": "red", "members": []any{"alice", "sid"}},
+					{"name": "blue", "members": []any{"bob"}},
+				},
+			},
+		},
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: in-memory-lookup
+			source: my-in-memory-source
+			description: some description
+			table: teams
+			parameters:
+				- name: members
+				  type: array
+				  description: members of the team
+				  default: [alice, sid]
+				  items:
+				    name: member
+				    type: string
+				    description: member of the team
+	`
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	tool, err := got.Tools["example_tool"].Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	// the default array is bound when the parameter is omitted
+	params, err := tool.ParseParams(context.Background(), map[string]any{}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	if diff := cmp.Diff(tools.ParamValues{{Name: "members", Value: []any{"alice", "sid"}}}, params); diff != "" {
+		t.Fatalf("incorrect params: diff %v", diff)
+	}
+	res, err := tool.Invoke(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []any{map[string]any{"name": "red", "members": []any{"alice", "sid"}}}
+	if diff := cmp.Diff(want, res); diff != "" {
+		t.Fatalf("incorrect result: diff %v", diff)
+	}
+}
+
+func TestFailInitializeInMemoryLookup(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"my-in-memory-source": &inmemory.Source{Name: "my-in-memory-source", Kind: inmemory.SourceKind},
+	}
+	cfg := inmemorylookup.Config{
+		Name:        "lookup",
+		Kind:        "in-memory-lookup",
+		Source:      "my-in-memory-source",
+		Description: "some description",
+		Table:       "missing",
+	}
+	_, err := cfg.Initialize(srcs)
+	if err == nil {
+		t.Fatalf("expected an error, but got nil")
+	}
+	want := `no table named "missing" in source "my-in-memory-source"`
+	if err.Error() != want {
+		t.Fatalf("unexpected error: got %q, want %q", err, want)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"sync"
+)
+
+type metaKey struct{}
+
+// meta collects metadata about the result of a single invocation.
+type meta struct {
+	mu     sync.Mutex
+	values map[string]any
+}
+
+// WithMeta returns a context that collects the result metadata set by a tool
+// during an invocation.
+func WithMeta(ctx context.Context) context.Context {
+	return context.WithValue(ctx, metaKey{}, &meta{values: make(map[string]any)})
+}
+
+// SetMeta records metadata about the result of the current invocation. It is a
+// no-op if the context was not created with WithMeta.
+func SetMeta(ctx context.Context, key string, value any) {
+	m, ok := ctx.Value(metaKey{}).(*meta)
+	if !ok {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+}
+
+// MetaFromContext returns the result metadata collected so far, or nil if
+// there is none.
+func MetaFromContext(ctx context.Context) map[string]any {
+	m, ok := ctx.Value(metaKey{}).(*meta)
+	if !ok {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.values) == 0 {
+		return nil
+	}
+	values := make(map[string]any, len(m.values))
+	for k, v := range m.values {
+		values[k] = v
+	}
+	return values
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodbaggregate_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mongodbaggregate"
+)
+
+func TestParseFromYamlMongoDBAggregate(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: mongodb-aggregate
+					source: my-mongodb
+					description: some tool description
+					authRequired:
+						- my-google-auth-service
+					collection: orders
+					pipeline: |
+						[{"$match": {"country": {{.country}}}}, {"$group": {"_id": "$status", "count": {"$sum": 1}}}]
+					parameters:
+						- name: country
+						  type: string
+						  description: country parameter description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mongodbaggregate.Config{
+					Name:         "example_tool",
+					Kind:         "mongodb-aggregate",
+					Source:       "my-mongodb",
+					Description:  "some tool description",
+					AuthRequired: []string{"my-google-auth-service"},
+					Collection:   "orders",
+					Pipeline:     "[{\"$match\": {\"country\": {{.country}}}}, {\"$group\": {\"_id\": \"$status\", \"count\": {\"$sum\": 1}}}]\n",
+					Parameters: []tools.Parameter{
+						tools.NewStringParameter("country", "country parameter description"),
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlMongoDBAggregate(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: mongodb-aggregate
+			source: my-mongodb
+			description: some tool description
+			collection: orders
+	`
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err == nil {
+		t.Fatalf("expected a tool without a pipeline to be rejected")
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodbfind
+
+import (
+	"context"
+	"fmt"
+	"text/template"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	mongodbsrc "github.com/googleapis/genai-toolbox/internal/sources/mongodb"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const kind string = "mongodb-find"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name, Filter: "{}"}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	MongoDatabase() *mongo.Database
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &mongodbsrc.Source{}
+
+var compatibleSources = [...]string{mongodbsrc.SourceKind}
+
+type Config struct {
+	Name         string           `yaml:"name" validate:"required"`
+	Kind         string           `yaml:"kind" validate:"required"`
+	Source       string           `yaml:"source" validate:"required"`
+	Description  string           `yaml:"description" validate:"required"`
+	Collection   string           `yaml:"collection" validate:"required"`
+	Filter       string           `yaml:"filter"`
+	Projection   string           `yaml:"projection"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	// verify the templates are valid
+	if _, err := template.New("filter").Parse(cfg.Filter); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	if _, err := template.New("projection").Parse(cfg.Projection); err != nil {
+		return nil, fmt.Errorf("invalid projection: %w", err)
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: cfg.Parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   cfg.Parameters,
+		Collection:   cfg.Collection,
+		Filter:       cfg.Filter,
+		Projection:   cfg.Projection,
+		AuthRequired: cfg.AuthRequired,
+		Database:     s.MongoDatabase(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: cfg.Parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	AuthRequired []string         `yaml:"authRequired"`
+
+	Database    *mongo.Database
+	Collection  string
+	Filter      string
+	Projection  string
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	paramsMap := params.AsMap()
+
+	filterJSON, err := tools.ResolveJSONTemplate("filter", t.Filter, paramsMap)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve filter: %w", err)
+	}
+	filter, err := mongodbsrc.ParseDocument(filterJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find()
+	if t.Projection != "" {
+		projectionJSON, err := tools.ResolveJSONTemplate("projection", t.Projection, paramsMap)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve projection: %w", err)
+		}
+		projection, err := mongodbsrc.ParseDocument(projectionJSON)
+		if err != nil {
+			return nil, err
+		}
+		opts.SetProjection(projection)
+	}
+
+	cur, err := t.Database.Collection(t.Collection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute find: %w", err)
+	}
+	return mongodbsrc.Documents(ctx, cur)
+}
+
+func (t Tool) ParseParams(ctx context.Context, data map[string]any, claimsMap map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(ctx, t.Parameters, data, claimsMap)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
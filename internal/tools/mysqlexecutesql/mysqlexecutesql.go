@@ -123,7 +123,14 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 		return nil, fmt.Errorf("unable to get cast %s", sliceParams[0])
 	}
 
-	results, err := t.Pool.QueryContext(ctx, sql)
+	// warnings are only listed on the connection the statement was run on
+	conn, err := t.Pool.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	results, err := conn.QueryContext(ctx, sql)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
@@ -174,11 +181,19 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 		return nil, fmt.Errorf("errors encountered by results.Scan: %w", err)
 	}
 
+	warnings, err := mysql.Warnings(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve warnings: %w", err)
+	}
+	if len(warnings) > 0 {
+		tools.SetMeta(ctx, "warnings", warnings)
+	}
+
 	return out, nil
 }
 
-func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
-	return tools.ParseParams(t.Parameters, data, claims)
+func (t Tool) ParseParams(ctx context.Context, data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(ctx, t.Parameters, data, claims)
 }
 
 func (t Tool) Manifest() tools.Manifest {
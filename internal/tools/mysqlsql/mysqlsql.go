@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -57,10 +58,18 @@ type Config struct {
 	Kind               string           `yaml:"kind" validate:"required"`
 	Source             string           `yaml:"source" validate:"required"`
 	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
+	Statement          string           `yaml:"statement" validate:"required_without=Statements"`
 	AuthRequired       []string         `yaml:"authRequired"`
 	Parameters         tools.Parameters `yaml:"parameters"`
 	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	// Statements are run in order in a single transaction instead of
+	// Statement, which is committed if all of them succeed and rolled back
+	// otherwise. The parameters are shared by all statements.
+	Statements []string `yaml:"statements" validate:"excluded_with=Statement,dive,required"`
+	// ResultStatement is the position, starting at 1, of the statement of
+	// Statements whose rows are returned. The rows of the last statement are
+	// returned if it is 0.
+	ResultStatement int `yaml:"resultStatement" validate:"gte=0"`
 }
 
 // validate interface
@@ -83,6 +92,14 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
+	if err := tools.BindValuesQueries(srcs, cfg.Parameters, cfg.TemplateParameters); err != nil {
+		return nil, err
+	}
+
+	if cfg.ResultStatement > len(cfg.Statements) {
+		return nil, fmt.Errorf("resultStatement %d is out of range, the tool has %d statements", cfg.ResultStatement, len(cfg.Statements))
+	}
+
 	allParameters, paramManifest, paramMcpManifest := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
 
 	mcpManifest := tools.McpManifest{
@@ -99,6 +116,8 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		TemplateParameters: cfg.TemplateParameters,
 		AllParams:          allParameters,
 		Statement:          cfg.Statement,
+		Statements:         cfg.Statements,
+		ResultStatement:    cfg.ResultStatement,
 		AuthRequired:       cfg.AuthRequired,
 		Pool:               s.MySQLPool(),
 		manifest:           tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
@@ -109,6 +128,8 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 // validate interface
 var _ tools.Tool = Tool{}
+var _ tools.DryRunner = Tool{}
+var _ tools.Streamer = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -118,33 +139,169 @@ type Tool struct {
 	TemplateParameters tools.Parameters `yaml:"templateParameters"`
 	AllParams          tools.Parameters `yaml:"allParams"`
 
-	Pool        *sql.DB
-	Statement   string
-	manifest    tools.Manifest
-	mcpManifest tools.McpManifest
+	Pool            *sql.DB
+	Statement       string
+	Statements      []string
+	ResultStatement int
+	manifest        tools.Manifest
+	mcpManifest     tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+// resolve returns statement with its template parameters resolved, and the
+// values to bind to its placeholders. Since placeholders are positional, the
+// values bound by template helpers follow the parameters.
+func (t Tool) resolve(statement string, params tools.ParamValues) (string, []any, error) {
 	paramsMap := params.AsMap()
-	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
+	newParams, err := tools.GetParams(t.Parameters, paramsMap)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to extract standard params %w", err)
+	}
+	sliceParams := newParams.AsSlice()
+	if len(t.Statements) > 0 {
+		// the statements of a transaction share the parameters, and each
+		// binds them in order, one for each of its placeholders
+		sliceParams = sliceParams[:min(len(sliceParams), strings.Count(statement, "?"))]
+	}
+
+	placeholder := func(int) string { return "?" }
+	newStatement, binds, err := tools.ResolveTemplateParamsWithBinds(t.TemplateParameters, statement, paramsMap, placeholder, len(sliceParams))
 	if err != nil {
-		return nil, fmt.Errorf("unable to extract template params %w", err)
+		return "", nil, fmt.Errorf("unable to extract template params %w", err)
 	}
+	return newStatement, append(sliceParams, binds...), nil
+}
 
-	newParams, err := tools.GetParams(t.Parameters, paramsMap)
+// DryRun returns the statement that would be run for params, without running
+// it.
+func (t Tool) DryRun(_ context.Context, params tools.ParamValues) (tools.DryRunResult, error) {
+	if len(t.Statements) > 0 {
+		return tools.DryRunResult{}, fmt.Errorf("dry runs of tools with several statements are not supported")
+	}
+	newStatement, sliceParams, err := t.resolve(t.Statement, params)
 	if err != nil {
-		return nil, fmt.Errorf("unable to extract standard params %w", err)
+		return tools.DryRunResult{}, err
 	}
+	return tools.DryRunResult{Statement: newStatement, Parameters: params.AsMap(), Binds: sliceParams}, nil
+}
 
-	sliceParams := newParams.AsSlice()
-	results, err := t.Pool.QueryContext(ctx, newStatement, sliceParams...)
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	if len(t.Statements) > 0 {
+		return t.invokeTransaction(ctx, params)
+	}
+	newStatement, sliceParams, err := t.resolve(t.Statement, params)
+	if err != nil {
+		return nil, err
+	}
+	// warnings are only listed on the connection the statement was run on
+	conn, err := t.Pool.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	results, err := conn.QueryContext(ctx, newStatement, sliceParams...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
+	var out []any
+	err = streamRows(results, func(row any) error {
+		out = append(out, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	warnings, err := mysql.Warnings(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve warnings: %w", err)
+	}
+	if len(warnings) > 0 {
+		tools.SetMeta(ctx, "warnings", warnings)
+	}
+
+	return out, nil
+}
+
+// invokeTransaction runs the statements of the tool in a single transaction,
+// and returns the rows of its result statement. The transaction is rolled
+// back if any statement fails. The warnings of the statements aren't reported.
+func (t Tool) invokeTransaction(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	tx, err := t.Pool.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	// rolling back a committed transaction has no effect
+	defer func() { _ = tx.Rollback() }()
+
+	result := len(t.Statements)
+	if t.ResultStatement > 0 {
+		result = t.ResultStatement
+	}
+	var out []any
+	for i, statement := range t.Statements {
+		newStatement, sliceParams, err := t.resolve(statement, params)
+		if err != nil {
+			return nil, err
+		}
+		if i+1 != result {
+			if _, err := tx.ExecContext(ctx, newStatement, sliceParams...); err != nil {
+				return nil, fmt.Errorf("unable to execute statement %d: %w", i+1, err)
+			}
+			continue
+		}
+		results, err := tx.QueryContext(ctx, newStatement, sliceParams...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to execute statement %d: %w", i+1, err)
+		}
+		err = streamRows(results, func(row any) error {
+			out = append(out, row)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+	return out, nil
+}
+
+// InvokeStream runs the statement and passes each row to yield as it is read.
+// Unlike Invoke, it doesn't report the warnings of the statement.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues, yield func(row any) error) error {
+	if len(t.Statements) > 0 {
+		// the rows are only known once the transaction has completed
+		res, err := t.invokeTransaction(ctx, params)
+		if err != nil {
+			return err
+		}
+		for _, row := range res {
+			if err := yield(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	newStatement, sliceParams, err := t.resolve(t.Statement, params)
+	if err != nil {
+		return err
+	}
+	results, err := t.Pool.QueryContext(ctx, newStatement, sliceParams...)
+	if err != nil {
+		return fmt.Errorf("unable to execute query: %w", err)
+	}
+	return streamRows(results, yield)
+}
+
+// streamRows passes each row to yield as a map keyed by column name, and
+// closes results.
+func streamRows(results *sql.Rows, yield func(row any) error) error {
+	defer results.Close()
 	cols, err := results.Columns()
 	if err != nil {
-		return nil, fmt.Errorf("unable to retrieve rows column name: %w", err)
+		return fmt.Errorf("unable to retrieve rows column name: %w", err)
 	}
 
 	// create an array of values for each column, which can be re-used to scan each row
@@ -156,14 +313,13 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 
 	colTypes, err := results.ColumnTypes()
 	if err != nil {
-		return nil, fmt.Errorf("unable to get column types: %w", err)
+		return fmt.Errorf("unable to get column types: %w", err)
 	}
 
-	var out []any
 	for results.Next() {
 		err := results.Scan(values...)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse row: %w", err)
+			return fmt.Errorf("unable to parse row: %w", err)
 		}
 		vMap := make(map[string]any)
 		for i, name := range cols {
@@ -180,23 +336,24 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 				vMap[name] = rawValues[i]
 			}
 		}
-		out = append(out, vMap)
+		if err := yield(vMap); err != nil {
+			return err
+		}
 	}
 
 	err = results.Close()
 	if err != nil {
-		return nil, fmt.Errorf("unable to close rows: %w", err)
+		return fmt.Errorf("unable to close rows: %w", err)
 	}
 
 	if err := results.Err(); err != nil {
-		return nil, fmt.Errorf("errors encountered by results.Scan: %w", err)
+		return fmt.Errorf("errors encountered by results.Scan: %w", err)
 	}
-
-	return out, nil
+	return nil
 }
 
-func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
-	return tools.ParseParams(t.AllParams, data, claims)
+func (t Tool) ParseParams(ctx context.Context, data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(ctx, t.AllParams, data, claims)
 }
 
 func (t Tool) Manifest() tools.Manifest {
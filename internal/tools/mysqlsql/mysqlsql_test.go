@@ -15,6 +15,13 @@
 package mysqlsql_test
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
@@ -74,6 +81,36 @@ func TestParseFromYamlMySQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "statements",
+			in: `
+			tools:
+				example_tool:
+					kind: mysql-sql
+					source: my-mysql-instance
+					description: some description
+					statements:
+						- INSERT INTO orders (id) VALUES (?);
+						- UPDATE stock SET count = count - 1 WHERE order_id = ?;
+						- SELECT * FROM orders WHERE id = ?;
+					resultStatement: 3
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mysqlsql.Config{
+					Name:        "example_tool",
+					Kind:        "mysql-sql",
+					Source:      "my-mysql-instance",
+					Description: "some description",
+					Statements: []string{
+						"INSERT INTO orders (id) VALUES (?);",
+						"UPDATE stock SET count = count - 1 WHERE order_id = ?;",
+						"SELECT * FROM orders WHERE id = ?;",
+					},
+					ResultStatement: 3,
+					AuthRequired:    []string{},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -156,6 +193,49 @@ func TestParseFromYamlWithTemplateParamsMySQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "values helper",
+			in: `
+			tools:
+				example_tool:
+					kind: mysql-sql
+					source: my-mysql-instance
+					description: some description
+					statement: |
+						INSERT INTO users (id, name) VALUES {{values .users "id" "name"}};
+					templateParameters:
+						- name: users
+						  type: array
+						  description: The users to insert.
+						  items:
+								name: user
+								type: object
+								description: A user.
+								properties:
+									- name: id
+									  type: integer
+									  description: The id of the user.
+									- name: name
+									  type: string
+									  description: The name of the user.
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mysqlsql.Config{
+					Name:         "example_tool",
+					Kind:         "mysql-sql",
+					Source:       "my-mysql-instance",
+					Description:  "some description",
+					Statement:    "INSERT INTO users (id, name) VALUES {{values .users \"id\" \"name\"}};\n",
+					AuthRequired: []string{},
+					TemplateParameters: []tools.Parameter{
+						tools.NewArrayParameter("users", "The users to insert.", tools.NewObjectParameter("user", "A user.",
+							tools.NewIntParameter("id", "The id of the user."),
+							tools.NewStringParameter("name", "The name of the user."),
+						)),
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -173,3 +253,268 @@ func TestParseFromYamlWithTemplateParamsMySQL(t *testing.T) {
 		})
 	}
 }
+
+// recordingDriver is a database/sql driver that records the statements it
+// runs, and returns no rows. Statements must be run with as many arguments as
+// they have placeholders.
+type recordingDriver struct {
+	mu         sync.Mutex
+	statements []recordedStatement
+}
+
+type recordedStatement struct {
+	query string
+	args  []driver.Value
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) {
+	return recordingConn{d}, nil
+}
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return recordingStmt{d: c.d, query: query}, nil
+}
+
+func (recordingConn) Close() error { return nil }
+
+func (recordingConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("transactions are not supported")
+}
+
+type recordingStmt struct {
+	d     *recordingDriver
+	query string
+}
+
+func (recordingStmt) Close() error { return nil }
+
+func (s recordingStmt) NumInput() int { return strings.Count(s.query, "?") }
+
+func (recordingStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+func (s recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.statements = append(s.d.statements, recordedStatement{query: s.query, args: args})
+	return emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string { return nil }
+
+func (emptyRows) Close() error { return nil }
+
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+func TestInvokeValues(t *testing.T) {
+	d := &recordingDriver{}
+	sql.Register("mysqlsql-recording", d)
+	db, err := sql.Open("mysqlsql-recording", "")
+	if err != nil {
+		t.Fatalf("unable to open database: %s", err)
+	}
+	defer db.Close()
+
+	tool := mysqlsql.Tool{
+		Name: "insert_users",
+		Kind: "mysql-sql",
+		Parameters: tools.Parameters{
+			tools.NewStringParameter("org", "The organization of the users."),
+		},
+		TemplateParameters: tools.Parameters{
+			tools.NewArrayParameter("users", "The users to insert.", tools.NewObjectParameter("user", "A user.",
+				tools.NewIntParameter("id", "The id of the user."),
+				tools.NewStringParameter("name", "The name of the user."),
+			)),
+		},
+		Pool:      db,
+		Statement: `INSERT INTO users (org, id, name) SELECT ?, id, name FROM (VALUES {{values .users "id" "name"}}) AS rows (id, name)`,
+	}
+	params := tools.ParamValues{
+		{Name: "org", Value: "acme"},
+		{Name: "users", Value: []any{
+			map[string]any{"id": 1, "name": "Alice"},
+			map[string]any{"id": 2, "name": "Bob"},
+			map[string]any{"id": 3, "name": "Carol"},
+		}},
+	}
+	if _, err := tool.Invoke(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// the statement is followed by SHOW WARNINGS
+	if len(d.statements) == 0 {
+		t.Fatalf("no statement was run")
+	}
+	got := d.statements[0]
+	wantQuery := "INSERT INTO users (org, id, name) SELECT ?, id, name FROM (VALUES (?, ?), (?, ?), (?, ?)) AS rows (id, name)"
+	if got.query != wantQuery {
+		t.Fatalf("unexpected statement: got %q, want %q", got.query, wantQuery)
+	}
+	wantArgs := []driver.Value{"acme", int64(1), "Alice", int64(2), "Bob", int64(3), "Carol"}
+	if diff := cmp.Diff(wantArgs, got.args); diff != "" {
+		t.Fatalf("incorrect args: diff %v", diff)
+	}
+}
+
+// txDriver is a database/sql driver that supports transactions. Statements
+// starting with INSERT add a row, a statement starting with FAIL fails, and
+// other statements return the number of rows as a column named count. Rows
+// added in a transaction are only kept once it is committed.
+type txDriver struct {
+	mu        sync.Mutex
+	committed int
+	pending   int
+	args      [][]driver.Value
+}
+
+func (d *txDriver) Open(string) (driver.Conn, error) {
+	return txConn{d}, nil
+}
+
+type txConn struct{ d *txDriver }
+
+func (c txConn) Prepare(query string) (driver.Stmt, error) {
+	return txStmt{d: c.d, query: query}, nil
+}
+
+func (txConn) Close() error { return nil }
+
+func (c txConn) Begin() (driver.Tx, error) {
+	return txTx(c), nil
+}
+
+type txTx struct{ d *txDriver }
+
+func (tx txTx) Commit() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.committed += tx.d.pending
+	tx.d.pending = 0
+	return nil
+}
+
+func (tx txTx) Rollback() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.pending = 0
+	return nil
+}
+
+type txStmt struct {
+	d     *txDriver
+	query string
+}
+
+func (txStmt) Close() error { return nil }
+
+func (s txStmt) NumInput() int { return strings.Count(s.query, "?") }
+
+func (s txStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.args = append(s.d.args, append([]driver.Value(nil), args...))
+	switch {
+	case strings.HasPrefix(s.query, "FAIL"):
+		return nil, fmt.Errorf("statement failed")
+	case strings.HasPrefix(s.query, "INSERT"):
+		s.d.pending++
+		return driver.RowsAffected(1), nil
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (s txStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if _, err := s.Exec(args); err != nil {
+		return nil, err
+	}
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	return &countRows{count: int64(s.d.committed + s.d.pending)}, nil
+}
+
+// countRows is a single row with the count column.
+type countRows struct {
+	count int64
+	done  bool
+}
+
+func (*countRows) Columns() []string { return []string{"count"} }
+
+func (*countRows) Close() error { return nil }
+
+func (r *countRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.count
+	return nil
+}
+
+func TestInvokeTransaction(t *testing.T) {
+	d := &txDriver{}
+	sql.Register("mysqlsql-tx", d)
+	db, err := sql.Open("mysqlsql-tx", "")
+	if err != nil {
+		t.Fatalf("unable to open database: %s", err)
+	}
+	defer db.Close()
+
+	newTool := func(statements []string, resultStatement int) mysqlsql.Tool {
+		return mysqlsql.Tool{
+			Name: "place_order",
+			Kind: "mysql-sql",
+			Parameters: tools.Parameters{
+				tools.NewIntParameter("id", "The id of the order."),
+				tools.NewIntParameter("quantity", "The quantity ordered."),
+			},
+			Pool:            db,
+			Statements:      statements,
+			ResultStatement: resultStatement,
+		}
+	}
+	params := tools.ParamValues{{Name: "id", Value: 1}, {Name: "quantity", Value: 2}}
+
+	// a failing statement rolls back the statements before it
+	tool := newTool([]string{
+		"INSERT INTO orders (id, quantity) VALUES (?, ?)",
+		"INSERT INTO order_events (order_id) VALUES (?)",
+		"FAIL",
+		"SELECT count(*) AS count FROM orders",
+	}, 0)
+	if _, err := tool.Invoke(context.Background(), params); err == nil || !strings.Contains(err.Error(), "unable to execute statement 3") {
+		t.Fatalf("unexpected error: got %v, want the third statement to fail", err)
+	}
+	if d.committed != 0 {
+		t.Fatalf("expected the failed transaction to be rolled back, got %d committed rows", d.committed)
+	}
+
+	// parameters are shared, and bound in order to the placeholders of each
+	// statement
+	d.args = nil
+	tool = newTool([]string{
+		"INSERT INTO orders (id, quantity) VALUES (?, ?)",
+		"SELECT count(*) AS count FROM orders",
+		"INSERT INTO order_events (order_id) VALUES (?)",
+	}, 2)
+	res, err := tool.Invoke(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if d.committed != 2 {
+		t.Fatalf("expected the transaction to be committed, got %d committed rows", d.committed)
+	}
+	if diff := cmp.Diff([]any{map[string]any{"count": int64(1)}}, res); diff != "" {
+		t.Fatalf("unexpected result of the result statement: diff %v", diff)
+	}
+	wantArgs := [][]driver.Value{{int64(1), int64(2)}, nil, {int64(1)}}
+	if diff := cmp.Diff(wantArgs, d.args); diff != "" {
+		t.Fatalf("incorrect args: diff %v", diff)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import "context"
+
+// Notifier is implemented by tools that can be told by their source when
+// their result may have changed, rather than having to be polled.
+type Notifier interface {
+	// Notify returns a channel that receives a value whenever the result of
+	// the tool may have changed, until ctx is done. It returns false if the
+	// tool isn't configured to be notified.
+	Notify(ctx context.Context) (<-chan struct{}, bool)
+}
+
+// GetNotifier returns the Notifier of the tool, if any. Tools that wrap
+// another Tool can implement `Unwrap() Tool` to expose its Notifier.
+func GetNotifier(t Tool) (Notifier, bool) {
+	for {
+		switch n := t.(type) {
+		case Notifier:
+			return n, true
+		case interface{ Unwrap() Tool }:
+			t = n.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
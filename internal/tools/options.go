@@ -0,0 +1,477 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// Options are settings that can be declared on any tool, regardless of its
+// kind. They are split from the raw tool configuration before the
+// kind-specific configuration is decoded.
+type Options struct {
+	// Examples are sample invocations of the tool along with their expected
+	// results.
+	Examples []Example `yaml:"examples"`
+	// CacheTTL is how long successful results are cached for. Results are not
+	// cached if it is 0.
+	CacheTTL time.Duration `yaml:"cacheTTL"`
+	// Invalidates lists the tools whose cached results are cleared after a
+	// successful invocation of this tool.
+	Invalidates []string `yaml:"invalidates"`
+	// ExpectedMaxRows is the number of rows the tool is expected to return at
+	// most. Exceeding it logs a warning and is recorded in telemetry, but the
+	// results are returned in full.
+	ExpectedMaxRows int `yaml:"expectedMaxRows"`
+	// MaxRows is the number of rows the tool returns at most. Results with
+	// more rows are truncated, and flagged as truncated. Results are not
+	// truncated if it is 0.
+	MaxRows int `yaml:"maxRows" validate:"gte=0"`
+	// StrictArguments rejects invocations with arguments that don't match any
+	// of the tool's parameters, instead of ignoring them.
+	StrictArguments bool `yaml:"strictArguments"`
+	// Timeout is how long an invocation may run before it is canceled. There
+	// is no timeout if it is 0.
+	Timeout time.Duration `yaml:"timeout"`
+	// ResultMode is the shape of the results. With ResultModeScalar, the
+	// single value of a single-row, single-column result is returned instead
+	// of the rows.
+	ResultMode string `yaml:"resultMode" validate:"omitempty,oneof=rows scalar"`
+	// Version is the version of the tool. Versioned tools are served as
+	// "<name>@<version>", so that several versions of a tool can coexist.
+	Version string `yaml:"version"`
+	// Safe declares that the tool has no side effects, so that it can be
+	// retried when its source is unavailable, and its results are cached
+	// for DefaultSafeCacheTTL unless cacheTTL is set.
+	Safe bool `yaml:"safe"`
+	// TenantSources routes each invocation to a source chosen by a claim of
+	// the caller, instead of the source of the tool.
+	TenantSources *TenantSources `yaml:"tenantSources"`
+	// ColumnAuth maps result columns to the claim a caller needs to see
+	// them. The columns are omitted from the results of other callers.
+	ColumnAuth map[string]ColumnAuth `yaml:"columnAuth" validate:"dive"`
+	// Validate lists expressions over the parameter values, such as
+	// `startDate <= endDate`, that must hold for an invocation to run.
+	Validate []string `yaml:"validate"`
+	// Retry retries invocations that fail because the source is
+	// unavailable. It overrides the retries of safe tools.
+	Retry *Retry `yaml:"retry"`
+	// Paginated adds limit and offset parameters to the tool, and returns a
+	// page of its rows along with whether more rows follow.
+	Paginated bool `yaml:"paginated"`
+	// AllowedMethods are the HTTP methods the tool can be invoked with on
+	// the invoke route. Defaults to POST only.
+	AllowedMethods []string `yaml:"allowedMethods" validate:"dive,oneof=GET POST"`
+	// RowFilter is an expression over the fields of the result rows, such
+	// as `margin > 100`. Rows that don't satisfy it are dropped.
+	RowFilter string `yaml:"rowFilter"`
+	// ListRequired are the rules a caller must satisfy one of to see the
+	// tool in listings. The tool is hidden from other callers.
+	ListRequired []AccessRule `yaml:"listRequired" validate:"dive"`
+	// InvokeRequired are the rules a caller must satisfy one of to invoke
+	// the tool, regardless of whether they can see it.
+	InvokeRequired []AccessRule `yaml:"invokeRequired" validate:"dive"`
+	// NullPlaceholder replaces the NULL column values of the results, for
+	// clients that can't handle JSON null. NULL values are returned as null
+	// if it is not set.
+	NullPlaceholder *string `yaml:"nullPlaceholder"`
+}
+
+// Retry configures how invocations are retried after transient source
+// errors, such as a reset connection. Other errors are never retried.
+type Retry struct {
+	// MaxAttempts is the number of invocations made, including the first.
+	// Defaults to DefaultRetryMaxAttempts.
+	MaxAttempts int `yaml:"maxAttempts" validate:"gte=0"`
+	// Backoff is the delay before the first retry, doubled for each further
+	// retry. Defaults to DefaultRetryBackoff.
+	Backoff time.Duration `yaml:"backoff" validate:"gte=0"`
+	// Jitter randomizes each delay by up to this fraction of it, so that
+	// callers that failed together don't retry together.
+	Jitter float64 `yaml:"jitter" validate:"gte=0,lte=1"`
+}
+
+const (
+	// DefaultRetryMaxAttempts is the number of attempts of a retry without
+	// maxAttempts.
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryBackoff is the first delay of a retry without backoff.
+	DefaultRetryBackoff = 100 * time.Millisecond
+)
+
+// delay returns the delay before the given retry, starting at 1.
+func (r Retry) delay(retry int) time.Duration {
+	d := r.Backoff << (retry - 1)
+	if r.Jitter > 0 {
+		d = time.Duration(float64(d) * (1 + r.Jitter*(2*rand.Float64()-1)))
+	}
+	return d
+}
+
+// ResultModeScalar returns the single value of a result instead of its rows.
+const ResultModeScalar = "scalar"
+
+// DefaultSafeCacheTTL is how long the results of safe tools are cached for
+// when they don't set cacheTTL.
+const DefaultSafeCacheTTL = time.Minute
+
+// safeRetry is how safe tools are retried when their source is unavailable.
+var safeRetry = Retry{MaxAttempts: 3, Backoff: 50 * time.Millisecond}
+
+// ErrTimeout is returned when an invocation runs past the tool's timeout.
+var ErrTimeout = errors.New("tool invocation timed out")
+
+// Example is a sample invocation of a tool.
+type Example struct {
+	Description string         `yaml:"description"`
+	Arguments   map[string]any `yaml:"arguments"`
+	Result      any            `yaml:"result"`
+}
+
+// IsZero returns true if no options were declared.
+func (o Options) IsZero() bool {
+	return reflect.ValueOf(o).IsZero()
+}
+
+// optionKeys returns the yaml keys of the fields in Options.
+func optionKeys() []string {
+	var keys []string
+	t := reflect.TypeOf(Options{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		keys = append(keys, name)
+	}
+	return keys
+}
+
+// DeclaredOptionKeys returns the Options keys that are also declared by the
+// fields of the tool config tc, such as the `timeout` of some kinds.
+func DeclaredOptionKeys(tc ToolConfig) []string {
+	t := reflect.Indirect(reflect.ValueOf(tc)).Type()
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if slices.Contains(optionKeys(), name) {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// SourceName returns the name of the source the tool config tc is configured
+// with, or "" if its kind doesn't use a source.
+func SourceName(tc ToolConfig) string {
+	if c, ok := tc.(ConfigWithOptions); ok {
+		tc = c.ToolConfig
+	}
+	v := reflect.Indirect(reflect.ValueOf(tc))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	f := v.FieldByName("Source")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
+
+// SplitOptions removes any Options keys from the raw tool configuration v,
+// except those in keep, and returns them decoded.
+func SplitOptions(ctx context.Context, v map[string]any, keep ...string) (Options, error) {
+	raw := make(map[string]any)
+	for _, k := range optionKeys() {
+		if slices.Contains(keep, k) {
+			continue
+		}
+		if val, ok := v[k]; ok {
+			raw[k] = val
+			delete(v, k)
+		}
+	}
+	var opts Options
+	if len(raw) == 0 {
+		return opts, nil
+	}
+	dec, err := util.NewStrictDecoder(raw)
+	if err != nil {
+		return opts, fmt.Errorf("error creating decoder: %w", err)
+	}
+	if err := dec.DecodeContext(ctx, &opts); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// validate interface
+var _ ToolConfig = ConfigWithOptions{}
+
+// ConfigWithOptions is a ToolConfig with Options declared on it.
+type ConfigWithOptions struct {
+	ToolConfig
+	Options Options
+}
+
+func (c ConfigWithOptions) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	var t Tool
+	var err error
+	if c.Options.TenantSources != nil {
+		t, err = initializeTenants(c.ToolConfig, srcs, *c.Options.TenantSources)
+	} else {
+		t, err = c.ToolConfig.Initialize(srcs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	opts := c.Options
+	if opts.Safe && opts.CacheTTL == 0 {
+		opts.CacheTTL = DefaultSafeCacheTTL
+	}
+	wt := ToolWithOptions{Tool: t, Options: opts}
+	for _, src := range opts.Validate {
+		c, err := compileConstraint(src, t.Manifest().Parameters)
+		if err != nil {
+			return nil, err
+		}
+		wt.constraints = append(wt.constraints, c)
+	}
+	if err := checkExamples(wt, c.Options.Examples); err != nil {
+		return nil, err
+	}
+	return wt, nil
+}
+
+// checkExamples returns an error for the first example whose arguments are not
+// valid for t. Arguments are decoded as they would be from a request body.
+// Tools with authenticated parameters are not checked, since those parameters
+// are read from the claims of a request rather than from its arguments.
+func checkExamples(t Tool, examples []Example) error {
+	for _, p := range t.Manifest().Parameters {
+		if len(p.AuthServices) > 0 || p.FromAuthClaim != "" {
+			return nil
+		}
+	}
+	for i, example := range examples {
+		b, err := json.Marshal(example.Arguments)
+		if err != nil {
+			return fmt.Errorf("unable to marshal arguments of example %d: %w", i, err)
+		}
+		var data map[string]any
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if err := dec.Decode(&data); err != nil {
+			return fmt.Errorf("unable to decode arguments of example %d: %w", i, err)
+		}
+		if _, err := t.ParseParams(context.Background(), data, nil); err != nil {
+			return fmt.Errorf("invalid arguments for example %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validate interface
+var _ Tool = ToolWithOptions{}
+
+// ToolWithOptions is a Tool initialized from a ConfigWithOptions.
+type ToolWithOptions struct {
+	Tool
+	Options Options
+	// constraints are the compiled Validate expressions.
+	constraints []constraint
+}
+
+func (t ToolWithOptions) Unwrap() Tool {
+	return t.Tool
+}
+
+func (t ToolWithOptions) ParseParams(ctx context.Context, data map[string]any, claims map[string]map[string]any) (ParamValues, error) {
+	if t.Options.StrictArguments {
+		if err := checkArguments(t.Manifest().Parameters, data); err != nil {
+			return nil, err
+		}
+	}
+	params, err := t.Tool.ParseParams(ctx, data, claims)
+	if err != nil || len(t.constraints) == 0 {
+		return params, err
+	}
+	values := params.AsMap()
+	for _, c := range t.constraints {
+		if err := c.check(values); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+func (t ToolWithOptions) Invoke(ctx context.Context, params ParamValues) ([]any, error) {
+	res, err := t.invoke(ctx, params)
+	if err != nil || t.Options.ResultMode != ResultModeScalar {
+		return res, err
+	}
+	v, err := scalar(res)
+	if err != nil {
+		return nil, err
+	}
+	return []any{v}, nil
+}
+
+func (t ToolWithOptions) invoke(ctx context.Context, params ParamValues) ([]any, error) {
+	if t.Options.Timeout <= 0 {
+		return t.retry(ctx, params)
+	}
+	ctx, cancel := context.WithTimeout(ctx, t.Options.Timeout)
+	defer cancel()
+	res, err := t.retry(ctx, params)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w after %s: %w", ErrTimeout, t.Options.Timeout, err)
+	}
+	return res, err
+}
+
+// Stream streams the invocations of the wrapped tool within its timeout.
+// Scalar results can't be streamed.
+func (t ToolWithOptions) Stream() (Streamer, bool) {
+	s, ok := GetStreamer(t.Tool)
+	if !ok || t.Options.ResultMode == ResultModeScalar {
+		return nil, false
+	}
+	if t.Options.Timeout <= 0 {
+		return s, true
+	}
+	return StreamerFunc(func(ctx context.Context, params ParamValues, yield func(row any) error) error {
+		ctx, cancel := context.WithTimeout(ctx, t.Options.Timeout)
+		defer cancel()
+		err := s.InvokeStream(ctx, params, yield)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w after %s: %w", ErrTimeout, t.Options.Timeout, err)
+		}
+		return err
+	}), true
+}
+
+// retry invokes the tool, and invokes it again while its source is
+// unavailable, as configured by its Retry option. Safe tools are retried
+// with safeRetry by default.
+func (t ToolWithOptions) retry(ctx context.Context, params ParamValues) ([]any, error) {
+	res, err := t.Tool.Invoke(ctx, params)
+	policy, ok := t.retryPolicy()
+	for i := 1; ok && i < policy.MaxAttempts && sources.IsUnavailable(err); i++ {
+		select {
+		case <-ctx.Done():
+			return res, err
+		case <-time.After(policy.delay(i)):
+		}
+		res, err = t.Tool.Invoke(ctx, params)
+	}
+	return res, err
+}
+
+// retryPolicy returns how the tool is retried, with the defaults applied, or
+// false if it isn't.
+func (t ToolWithOptions) retryPolicy() (Retry, bool) {
+	if t.Options.Retry == nil {
+		return safeRetry, t.Options.Safe
+	}
+	policy := *t.Options.Retry
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if policy.Backoff == 0 {
+		policy.Backoff = DefaultRetryBackoff
+	}
+	return policy, true
+}
+
+func (t ToolWithOptions) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	m.Safe = t.Options.Safe
+	return m
+}
+
+// scalar returns the single value of a result with a single row and a single
+// column. Rows that aren't maps are taken to be the value themselves.
+func scalar(res []any) (any, error) {
+	if len(res) != 1 {
+		return nil, fmt.Errorf("resultMode %q requires a single row, got %d rows", ResultModeScalar, len(res))
+	}
+	row, ok := res[0].(map[string]any)
+	if !ok {
+		return res[0], nil
+	}
+	if len(row) != 1 {
+		return nil, fmt.Errorf("resultMode %q requires a single column, got %d columns", ResultModeScalar, len(row))
+	}
+	for _, v := range row {
+		return v, nil
+	}
+	return nil, nil
+}
+
+func (t ToolWithOptions) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	if t.Options.StrictArguments {
+		additionalProperties := false
+		m.InputSchema.AdditionalProperties = &additionalProperties
+	}
+	if t.Options.Safe {
+		m.Meta = map[string]any{"safe": true}
+	}
+	return m
+}
+
+// checkArguments returns an error naming the first argument in data, in
+// lexical order, that doesn't match any of params.
+func checkArguments(params []ParameterManifest, data map[string]any) error {
+	declared := make(map[string]bool, len(params))
+	for _, p := range params {
+		declared[p.Name] = true
+	}
+	for _, k := range slices.Sorted(maps.Keys(data)) {
+		if !declared[k] {
+			return fmt.Errorf("unexpected argument %q: tool has no parameter with that name", k)
+		}
+	}
+	return nil
+}
+
+// GetOptions returns the Options declared on the tool, if any. Tools that wrap
+// another Tool can implement `Unwrap() Tool` to expose its Options.
+func GetOptions(t Tool) Options {
+	for {
+		switch o := t.(type) {
+		case ToolWithOptions:
+			return o.Options
+		case interface{ Unwrap() Tool }:
+			t = o.Unwrap()
+		default:
+			return Options{}
+		}
+	}
+}
@@ -0,0 +1,743 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/inmemory"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/inmemorylookup"
+)
+
+func TestSplitOptions(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		name     string
+		in       map[string]any
+		want     tools.Options
+		wantRest map[string]any
+	}{
+		{
+			name:     "no options",
+			in:       map[string]any{"kind": "postgres-sql", "statement": "SELECT 1;"},
+			want:     tools.Options{},
+			wantRest: map[string]any{"kind": "postgres-sql", "statement": "SELECT 1;"},
+		},
+		{
+			name: "examples",
+			in: map[string]any{
+				"kind": "postgres-sql",
+				"examples": []any{
+					map[string]any{
+						"description": "find by id",
+						"arguments":   map[string]any{"id": "abc"},
+						"result":      "some result",
+					},
+				},
+			},
+			want: tools.Options{
+				Examples: []tools.Example{
+					{
+						Description: "find by id",
+						Arguments:   map[string]any{"id": "abc"},
+						Result:      "some result",
+					},
+				},
+			},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "caching",
+			in: map[string]any{
+				"kind":        "postgres-sql",
+				"cacheTTL":    "5m",
+				"invalidates": []any{"other-tool"},
+			},
+			want: tools.Options{
+				CacheTTL:    5 * time.Minute,
+				Invalidates: []string{"other-tool"},
+			},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "expected max rows",
+			in: map[string]any{
+				"kind":            "postgres-sql",
+				"expectedMaxRows": 100,
+			},
+			want: tools.Options{
+				ExpectedMaxRows: 100,
+			},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "strict arguments",
+			in: map[string]any{
+				"kind":            "postgres-sql",
+				"strictArguments": true,
+			},
+			want: tools.Options{
+				StrictArguments: true,
+			},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "timeout",
+			in: map[string]any{
+				"kind":    "postgres-sql",
+				"timeout": "30s",
+			},
+			want: tools.Options{
+				Timeout: 30 * time.Second,
+			},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "scalar result mode",
+			in: map[string]any{
+				"kind":       "postgres-sql",
+				"resultMode": "scalar",
+			},
+			want: tools.Options{
+				ResultMode: tools.ResultModeScalar,
+			},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "tenant sources",
+			in: map[string]any{
+				"kind": "postgres-sql",
+				"tenantSources": map[string]any{
+					"authService": "my-google-auth",
+					"claim":       "tenant",
+					"sources":     map[string]any{"acme": "acme-db", "globex": "globex-db"},
+				},
+			},
+			want: tools.Options{
+				TenantSources: &tools.TenantSources{
+					AuthService: "my-google-auth",
+					Claim:       "tenant",
+					Sources:     map[string]string{"acme": "acme-db", "globex": "globex-db"},
+				},
+			},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "safe",
+			in: map[string]any{
+				"kind": "postgres-sql",
+				"safe": true,
+			},
+			want: tools.Options{
+				Safe: true,
+			},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "allowed methods",
+			in: map[string]any{
+				"kind":           "postgres-sql",
+				"allowedMethods": []any{"GET", "POST"},
+			},
+			want:     tools.Options{AllowedMethods: []string{"GET", "POST"}},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "max rows",
+			in: map[string]any{
+				"kind":    "postgres-sql",
+				"maxRows": 100,
+			},
+			want:     tools.Options{MaxRows: 100},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "paginated",
+			in: map[string]any{
+				"kind":      "postgres-sql",
+				"paginated": true,
+			},
+			want:     tools.Options{Paginated: true},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "retry",
+			in: map[string]any{
+				"kind": "postgres-sql",
+				"retry": map[string]any{
+					"maxAttempts": 4,
+					"backoff":     "200ms",
+					"jitter":      0.2,
+				},
+			},
+			want: tools.Options{
+				Retry: &tools.Retry{MaxAttempts: 4, Backoff: 200 * time.Millisecond, Jitter: 0.2},
+			},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "validate",
+			in: map[string]any{
+				"kind":     "postgres-sql",
+				"validate": []any{"startDate <= endDate"},
+			},
+			want: tools.Options{
+				Validate: []string{"startDate <= endDate"},
+			},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "row filter",
+			in: map[string]any{
+				"kind":      "postgres-sql",
+				"rowFilter": "margin > 100",
+			},
+			want: tools.Options{
+				RowFilter: "margin > 100",
+			},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "access rules",
+			in: map[string]any{
+				"kind": "postgres-sql",
+				"listRequired": []any{
+					map[string]any{"authService": "my-google-auth"},
+				},
+				"invokeRequired": []any{
+					map[string]any{"authService": "my-google-auth", "claim": "groups", "values": []any{"admins"}},
+				},
+			},
+			want: tools.Options{
+				ListRequired:   []tools.AccessRule{{AuthService: "my-google-auth"}},
+				InvokeRequired: []tools.AccessRule{{AuthService: "my-google-auth", Claim: "groups", Values: []string{"admins"}}},
+			},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+		{
+			name: "empty null placeholder",
+			in: map[string]any{
+				"kind":            "postgres-sql",
+				"nullPlaceholder": "",
+			},
+			want:     tools.Options{NullPlaceholder: new(string)},
+			wantRest: map[string]any{"kind": "postgres-sql"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.SplitOptions(ctx, tc.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect options: diff %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantRest, tc.in); diff != "" {
+				t.Fatalf("incorrect remaining config: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailSplitOptions(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := map[string]any{
+		"kind":     "postgres-sql",
+		"examples": []any{map[string]any{"unknown": "field"}},
+	}
+	if _, err := tools.SplitOptions(ctx, in); err == nil {
+		t.Fatalf("expected an error, but got nil")
+	}
+
+	in = map[string]any{
+		"kind":       "postgres-sql",
+		"resultMode": "single",
+	}
+	if _, err := tools.SplitOptions(ctx, in); err == nil {
+		t.Fatalf("expected an error for an unknown result mode, but got nil")
+	}
+}
+
+func TestInitializeExamples(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"my-mem": &inmemory.Source{
+			Name:   "my-mem",
+			Kind:   inmemory.SourceKind,
+			Tables: map[string]inmemory.Table{"users": {{"id": 1, "name": "Alice"}}},
+		},
+	}
+	toolCfg := inmemorylookup.Config{
+		Name:        "get_user",
+		Kind:        "in-memory-lookup",
+		Source:      "my-mem",
+		Description: "Get a user by id.",
+		Table:       "users",
+		Parameters:  tools.Parameters{tools.NewIntParameter("id", "The id of the user.")},
+	}
+	tcs := []struct {
+		name     string
+		examples []tools.Example
+		wantErr  string
+	}{
+		{
+			name: "valid",
+			// integers are decoded from yaml as uint64
+			examples: []tools.Example{{Arguments: map[string]any{"id": uint64(1)}}},
+		},
+		{
+			name: "type mismatch",
+			examples: []tools.Example{
+				{Arguments: map[string]any{"id": uint64(1)}},
+				{Arguments: map[string]any{"id": "one"}},
+			},
+			wantErr: `invalid arguments for example 1: unable to parse value for "id": "one" not type "integer"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tools.ConfigWithOptions{ToolConfig: toolCfg, Options: tools.Options{Examples: tc.examples}}
+			_, err := cfg.Initialize(srcs)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected an error, but got nil")
+			}
+			if err.Error() != tc.wantErr {
+				t.Fatalf("unexpected error: got %q, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// notifyingTool is a Tool that is always notified of changes.
+type notifyingTool struct {
+	tools.Tool
+}
+
+func (notifyingTool) Notify(context.Context) (<-chan struct{}, bool) {
+	return nil, true
+}
+
+func TestGetNotifierWithOptions(t *testing.T) {
+	tool := tools.ToolWithOptions{Tool: notifyingTool{}, Options: tools.Options{Timeout: time.Second}}
+	if _, ok := tools.GetNotifier(tool); !ok {
+		t.Fatalf("expected the notifier of a tool with options to be found")
+	}
+}
+
+// slowStreamingTool is a Tool that streams rows until it is canceled.
+type slowStreamingTool struct {
+	tools.Tool
+}
+
+func (slowStreamingTool) InvokeStream(ctx context.Context, _ tools.ParamValues, yield func(row any) error) error {
+	if err := yield("first"); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestGetStreamerWithOptions(t *testing.T) {
+	scalar := tools.ToolWithOptions{Tool: slowStreamingTool{}, Options: tools.Options{ResultMode: tools.ResultModeScalar}}
+	if _, ok := tools.GetStreamer(scalar); ok {
+		t.Fatalf("expected scalar results not to be streamed")
+	}
+
+	tool := tools.ToolWithOptions{Tool: slowStreamingTool{}, Options: tools.Options{Timeout: 10 * time.Millisecond}}
+	s, ok := tools.GetStreamer(tool)
+	if !ok {
+		t.Fatalf("expected the streamer of a tool with options to be found")
+	}
+	var rows []any
+	err := s.InvokeStream(context.Background(), nil, func(row any) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if !errors.Is(err, tools.ErrTimeout) {
+		t.Fatalf("expected the stream to time out, got %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+}
+
+// flakyTool is a Tool whose source is unavailable for its first invocation.
+type flakyTool struct {
+	tools.Tool
+	calls *atomic.Int32
+}
+
+func (t flakyTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+	if t.calls.Add(1) == 1 {
+		return nil, fmt.Errorf("unable to execute query: %w", sources.ErrUnavailable)
+	}
+	return []any{"ok"}, nil
+}
+
+func TestSafeToolRetries(t *testing.T) {
+	tcs := []struct {
+		name      string
+		safe      bool
+		wantErr   bool
+		wantCalls int32
+	}{
+		{name: "safe", safe: true, wantCalls: 2},
+		{name: "unsafe", wantErr: true, wantCalls: 1},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := &atomic.Int32{}
+			tool := tools.ToolWithOptions{Tool: flakyTool{calls: calls}, Options: tools.Options{Safe: tc.safe}}
+			_, err := tool.Invoke(context.Background(), tools.ParamValues{})
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := calls.Load(); got != tc.wantCalls {
+				t.Fatalf("unexpected number of invocations: got %d, want %d", got, tc.wantCalls)
+			}
+		})
+	}
+}
+
+// unreliableTool is a Tool that fails with err for its first failures
+// invocations, like a source with a flaky connection.
+type unreliableTool struct {
+	tools.Tool
+	err      error
+	failures int32
+	calls    *atomic.Int32
+}
+
+func (t unreliableTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+	if t.calls.Add(1) <= t.failures {
+		return nil, fmt.Errorf("unable to execute query: %w", t.err)
+	}
+	return []any{"ok"}, nil
+}
+
+func TestRetry(t *testing.T) {
+	connReset := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	tcs := []struct {
+		name      string
+		retry     *tools.Retry
+		err       error
+		failures  int32
+		wantErr   bool
+		wantCalls int32
+	}{
+		{
+			name:      "no retry",
+			err:       connReset,
+			failures:  1,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "transient error retried",
+			retry:     &tools.Retry{MaxAttempts: 3, Backoff: time.Millisecond, Jitter: 0.5},
+			err:       connReset,
+			failures:  2,
+			wantCalls: 3,
+		},
+		{
+			name:      "attempts exhausted",
+			retry:     &tools.Retry{MaxAttempts: 2, Backoff: time.Millisecond},
+			err:       connReset,
+			failures:  5,
+			wantErr:   true,
+			wantCalls: 2,
+		},
+		{
+			name:      "non-transient error not retried",
+			retry:     &tools.Retry{MaxAttempts: 3, Backoff: time.Millisecond},
+			err:       errors.New(`ERROR: syntax error at or near "SELCT" (SQLSTATE 42601)`),
+			failures:  1,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := &atomic.Int32{}
+			tool := tools.ToolWithOptions{
+				Tool:    unreliableTool{err: tc.err, failures: tc.failures, calls: calls},
+				Options: tools.Options{Retry: tc.retry},
+			}
+			_, err := tool.Invoke(context.Background(), tools.ParamValues{})
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := calls.Load(); got != tc.wantCalls {
+				t.Fatalf("unexpected number of invocations: got %d, want %d", got, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestSafeToolDefaults(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"my-mem": &inmemory.Source{
+			Name:   "my-mem",
+			Kind:   inmemory.SourceKind,
+			Tables: map[string]inmemory.Table{"users": {{"id": 1, "name": "Alice"}}},
+		},
+	}
+	cfg := tools.ConfigWithOptions{
+		ToolConfig: inmemorylookup.Config{
+			Name:        "get_user",
+			Kind:        "in-memory-lookup",
+			Source:      "my-mem",
+			Description: "Get a user by id.",
+			Table:       "users",
+			Parameters:  tools.Parameters{tools.NewIntParameter("id", "The id of the user.")},
+		},
+		Options: tools.Options{Safe: true},
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := tools.GetOptions(tool).CacheTTL; got != tools.DefaultSafeCacheTTL {
+		t.Fatalf("unexpected cache ttl: got %s, want %s", got, tools.DefaultSafeCacheTTL)
+	}
+	if !tool.Manifest().Safe {
+		t.Fatalf("expected the manifest of a safe tool to be marked safe")
+	}
+	if got := tool.McpManifest().Meta["safe"]; got != true {
+		t.Fatalf("expected the mcp manifest of a safe tool to be marked safe, got %v", got)
+	}
+}
+
+func TestValidateConstraints(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"my-mem": &inmemory.Source{
+			Name:   "my-mem",
+			Kind:   inmemory.SourceKind,
+			Tables: map[string]inmemory.Table{"bookings": {{"id": 1}}},
+		},
+	}
+	limit := tools.NewIntParameter("limit", "The maximum number of bookings.")
+	limit.Default = 0
+	toolCfg := inmemorylookup.Config{
+		Name:        "list_bookings",
+		Kind:        "in-memory-lookup",
+		Source:      "my-mem",
+		Description: "List the bookings in a date range.",
+		Table:       "bookings",
+		Parameters: tools.Parameters{
+			tools.NewStringParameter("startDate", "The first day."),
+			tools.NewStringParameter("endDate", "The last day."),
+			limit,
+		},
+	}
+	validate := []string{
+		"startDate <= endDate",
+		"limit == 0 || (limit > 0 && limit <= 100)",
+	}
+	tool, err := tools.ConfigWithOptions{ToolConfig: toolCfg, Options: tools.Options{Validate: validate}}.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	tcs := []struct {
+		name    string
+		in      map[string]any
+		wantErr string
+	}{
+		{
+			name: "satisfied",
+			in:   map[string]any{"startDate": "2025-01-01", "endDate": "2025-01-31", "limit": 10},
+		},
+		{
+			name: "satisfied with default",
+			in:   map[string]any{"startDate": "2025-01-01", "endDate": "2025-01-01"},
+		},
+		{
+			name:    "violated",
+			in:      map[string]any{"startDate": "2025-02-01", "endDate": "2025-01-31"},
+			wantErr: `parameters must satisfy "startDate <= endDate"`,
+		},
+		{
+			name:    "violated second constraint",
+			in:      map[string]any{"startDate": "2025-01-01", "endDate": "2025-01-31", "limit": 500},
+			wantErr: `parameters must satisfy "limit == 0 || (limit > 0 && limit <= 100)"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tool.ParseParams(context.Background(), tc.in, nil)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.wantErr {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestFailCompileConstraints(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"my-mem": &inmemory.Source{Name: "my-mem", Kind: inmemory.SourceKind, Tables: map[string]inmemory.Table{"bookings": {}}},
+	}
+	toolCfg := inmemorylookup.Config{
+		Name:        "list_bookings",
+		Kind:        "in-memory-lookup",
+		Source:      "my-mem",
+		Description: "List the bookings in a date range.",
+		Table:       "bookings",
+		Parameters: tools.Parameters{
+			tools.NewStringParameter("startDate", "The first day."),
+			tools.NewStringParameter("endDate", "The last day."),
+		},
+	}
+	tcs := []struct {
+		name     string
+		validate string
+		wantErr  string
+	}{
+		{
+			name:     "unknown parameter",
+			validate: "startDate <= finishDate",
+			wantErr:  `invalid validate expression "startDate <= finishDate": tool has no parameter "finishDate"`,
+		},
+		{
+			name:     "missing operand",
+			validate: "startDate <=",
+			wantErr:  `invalid validate expression "startDate <=": unexpected end of expression`,
+		},
+		{
+			name:     "unbalanced parentheses",
+			validate: "(startDate <= endDate",
+			wantErr:  `invalid validate expression "(startDate <= endDate": missing )`,
+		},
+		{
+			name:     "unsupported operator",
+			validate: "startDate + 1 <= endDate",
+			wantErr:  `invalid validate expression "startDate + 1 <= endDate": unexpected character '+'`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tools.ConfigWithOptions{ToolConfig: toolCfg, Options: tools.Options{Validate: []string{tc.validate}}}
+			_, err := cfg.Initialize(srcs)
+			if err == nil || err.Error() != tc.wantErr {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestRowFilter(t *testing.T) {
+	tcs := []struct {
+		name   string
+		filter string
+		row    any
+		want   bool
+	}{
+		{
+			name:   "matching row",
+			filter: `margin > 100 && region == "emea"`,
+			row:    map[string]any{"margin": int64(150), "region": "emea"},
+			want:   true,
+		},
+		{
+			name:   "row below threshold",
+			filter: "margin > 100",
+			row:    map[string]any{"margin": 99.5},
+		},
+		{
+			name:   "null field",
+			filter: "margin > 100",
+			row:    map[string]any{"margin": nil},
+		},
+		{
+			name:   "missing field tested for null",
+			filter: "discount == null",
+			row:    map[string]any{"margin": 1},
+			want:   true,
+		},
+		{
+			name:   "row that isn't an object",
+			filter: "margin > 100",
+			row:    150,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := tools.CompileRowFilter(tc.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := f.Match(tc.row); got != tc.want {
+				t.Fatalf("unexpected match: got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFailCompileRowFilter(t *testing.T) {
+	for src, wantErr := range map[string]string{
+		"margin >":     `invalid row filter "margin >": unexpected end of expression`,
+		"margin > 1 )": `invalid row filter "margin > 1 )": unexpected ")"`,
+		"margin ~ 100": `invalid row filter "margin ~ 100": unexpected character '~'`,
+		"":             `invalid row filter "": unexpected end of expression`,
+	} {
+		if _, err := tools.CompileRowFilter(src); err == nil || err.Error() != wantErr {
+			t.Fatalf("unexpected error for %q: got %v, want %q", src, err, wantErr)
+		}
+	}
+
+	// deeply nested expressions are rejected rather than overflowing the stack
+	for _, src := range []string{
+		strings.Repeat("(", 100000) + "margin > 1" + strings.Repeat(")", 100000),
+		strings.Repeat("!", 100000) + "paid",
+	} {
+		_, err := tools.CompileRowFilter(src)
+		if err == nil || !strings.Contains(err.Error(), "expression is nested more than 64 levels deep") {
+			t.Fatalf("unexpected error for a deeply nested filter: got %.100v", err)
+		}
+	}
+	if _, err := tools.CompileRowFilter(strings.Repeat("(", 64) + "margin > 1" + strings.Repeat(")", 64)); err != nil {
+		t.Fatalf("unexpected error for a filter nested 64 levels deep: %s", err)
+	}
+}
@@ -18,11 +18,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"slices"
+	"strconv"
 	"strings"
 	"text/template"
 
+	"github.com/google/uuid"
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
@@ -32,6 +36,9 @@ const (
 	typeFloat  = "float"
 	typeBool   = "boolean"
 	typeArray  = "array"
+	typeUUID   = "uuid"
+	typeFile   = "file"
+	typeObject = "object"
 )
 
 // ParamValues is an ordered list of ParamValue
@@ -100,6 +107,10 @@ func (p ParamValues) AsMapWithDollarPrefix() map[string]interface{} {
 	return params
 }
 
+// ErrUnauthenticated is returned when parsing a parameter whose value comes
+// from an auth service that the caller didn't authenticate with.
+var ErrUnauthenticated = errors.New("missing or invalid authentication header")
+
 func parseFromAuthService(paramAuthServices []ParamAuthService, claimsMap map[string]map[string]any) (any, error) {
 	// parse a parameter from claims using its specified auth services
 	for _, a := range paramAuthServices {
@@ -115,22 +126,87 @@ func parseFromAuthService(paramAuthServices []ParamAuthService, claimsMap map[st
 		}
 		return v, nil
 	}
-	return nil, fmt.Errorf("missing or invalid authentication header")
+	return nil, ErrUnauthenticated
+}
+
+// parseFromAuthClaim returns the claim named claim of the first auth service,
+// by name, that validated the request.
+func parseFromAuthClaim(claim string, claimsMap map[string]map[string]any) (any, error) {
+	if len(claimsMap) == 0 {
+		return nil, ErrUnauthenticated
+	}
+	for _, name := range slices.Sorted(maps.Keys(claimsMap)) {
+		if v, ok := claimsMap[name][claim]; ok {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("no field named %s in claims", claim)
+}
+
+// CheckFromAuthClaims returns an error if a parameter of the tool described by
+// m is set from an auth claim, but the tool doesn't require authorization or
+// the parameter also lists authServices.
+func CheckFromAuthClaims(m Manifest) error {
+	for _, p := range m.Parameters {
+		if p.FromAuthClaim == "" {
+			continue
+		}
+		if len(p.AuthServices) > 0 {
+			return fmt.Errorf("parameter %q can't set both fromAuthClaim and authServices", p.Name)
+		}
+		if len(m.AuthRequired) == 0 {
+			return fmt.Errorf("parameter %q sets fromAuthClaim, which requires the tool to set authRequired", p.Name)
+		}
+	}
+	return nil
 }
 
 // ParseParams is a helper function for parsing Parameters from an arbitraryJSON object.
-func ParseParams(ps Parameters, data map[string]any, claimsMap map[string]map[string]any) (ParamValues, error) {
+func ParseParams(ctx context.Context, ps Parameters, data map[string]any, claimsMap map[string]map[string]any) (ParamValues, error) {
 	params := make([]ParamValue, 0, len(ps))
 	for _, p := range ps {
 		var v any
 		paramAuthServices := p.GetAuthServices()
 		name := p.GetName()
-		if len(paramAuthServices) == 0 {
+		if claim := p.GetFromAuthClaim(); claim != "" {
+			// parse parameter set from a claim, which clients can't override
+			if _, ok := data[name]; ok {
+				return nil, fmt.Errorf("parameter %q is set from the %q auth claim and can't be provided", name, claim)
+			}
+			var err error
+			v, err = parseFromAuthClaim(claim, claimsMap)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing authenticated parameter %q: %w", name, err)
+			}
+		} else if len(paramAuthServices) == 0 {
 			// parse non auth-required parameter
 			var ok bool
 			v, ok = data[name]
+			if ok && v == nil {
+				if !p.GetNullable() {
+					return nil, &InvalidParameterError{Name: name, Err: errNullValue}
+				}
+				// an explicit null is passed as is, unlike an omitted parameter
+				params = append(params, ParamValue{Name: name, Value: nil})
+				continue
+			}
+			if !ok && p.GetDefault() != nil {
+				// optional parameters are set to their default when omitted
+				v, ok = p.GetDefault(), true
+			}
 			if !ok {
-				return nil, fmt.Errorf("parameter %q is required", name)
+				dependsOn := p.GetDependsOn()
+				if len(dependsOn) == 0 {
+					return nil, fmt.Errorf("parameter %q is required", name)
+				}
+				for _, trigger := range dependsOn {
+					if _, set := data[trigger]; set {
+						return nil, fmt.Errorf("parameter %q is required when %q is set", name, trigger)
+					}
+				}
+				// dependent parameters are omitted when none of their triggers are set
+				params = append(params, ParamValue{Name: name, Value: nil})
+				continue
 			}
 		} else {
 			// parse authenticated parameter
@@ -140,15 +216,34 @@ func ParseParams(ps Parameters, data map[string]any, claimsMap map[string]map[st
 				return nil, fmt.Errorf("error parsing authenticated parameter %q: %w", name, err)
 			}
 		}
-		newV, err := p.Parse(v)
+		newV, err := p.Parse(ctx, v)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse value for %q: %w", name, err)
+			return nil, &InvalidParameterError{Name: name, Err: err}
 		}
 		params = append(params, ParamValue{Name: name, Value: newV})
 	}
 	return params, nil
 }
 
+// errNullValue is the error of a null value for a parameter that isn't
+// nullable.
+var errNullValue = errors.New("value can't be null, unless the parameter is nullable")
+
+// InvalidParameterError is returned by ParseParams when the value of a
+// parameter can't be parsed.
+type InvalidParameterError struct {
+	Name string
+	Err  error
+}
+
+func (e *InvalidParameterError) Error() string {
+	return fmt.Sprintf("unable to parse value for %q: %s", e.Name, e.Err)
+}
+
+func (e *InvalidParameterError) Unwrap() error {
+	return e.Err
+}
+
 // helper function to convert a string array parameter to a comma separated string
 func ConvertArrayParamToString(param any) (string, error) {
 	switch v := param.(type) {
@@ -182,16 +277,60 @@ func GetParams(params Parameters, paramValuesMap map[string]any) (ParamValues, e
 }
 
 func ResolveTemplateParams(templateParams Parameters, originalStatement string, paramsMap map[string]any) (string, error) {
+	return resolveTemplate(templateParams, originalStatement, paramsMap, nil)
+}
+
+// ResolveTemplateParamsWithBinds resolves the template parameters of a
+// statement, binding the values passed to the "join" and "values" helpers as
+// parameters instead of inlining them. placeholder returns the placeholder for the i-th
+// (1-based) parameter of the statement, and offset is the number of
+// parameters already bound. It returns the resolved statement and the values
+// to bind after the existing parameters.
+func ResolveTemplateParamsWithBinds(templateParams Parameters, originalStatement string, paramsMap map[string]any, placeholder func(i int) string, offset int) (string, []any, error) {
+	var binds []any
+	bind := func(v any) string {
+		binds = append(binds, v)
+		return placeholder(offset + len(binds))
+	}
+	statement, err := resolveTemplate(templateParams, originalStatement, paramsMap, bind)
+	if err != nil {
+		return "", nil, err
+	}
+	return statement, binds, nil
+}
+
+// ResolveJSONTemplate resolves a template of a JSON document, such as a query
+// filter. Parameters are inserted as JSON values, so that `{"name": {{.name}}}`
+// becomes `{"name": "Alice"}`, and values can't change the structure of the
+// document.
+func ResolveJSONTemplate(name, payload string, paramsMap map[string]any) (string, error) {
+	values := make(map[string]string, len(paramsMap))
+	for k, v := range paramsMap {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("unable to marshal parameter %q: %w", k, err)
+		}
+		values[k] = string(b)
+	}
+	t, err := template.New(name).Option("missingkey=error").Parse(payload)
+	if err != nil {
+		return "", fmt.Errorf("error creating go template %s", err)
+	}
+	var result bytes.Buffer
+	if err := t.Execute(&result, values); err != nil {
+		return "", fmt.Errorf("error executing go template %s", err)
+	}
+	return result.String(), nil
+}
+
+func resolveTemplate(templateParams Parameters, originalStatement string, paramsMap map[string]any, bind func(any) string) (string, error) {
 	templateParamsValues, err := GetParams(templateParams, paramsMap)
 	templateParamsMap := templateParamsValues.AsMap()
 	if err != nil {
 		return "", fmt.Errorf("error getting template params %s", err)
 	}
 
-	funcMap := template.FuncMap{
-		"array": ConvertArrayParamToString,
-	}
-	t, err := template.New("statement").Funcs(funcMap).Parse(originalStatement)
+	t, err := template.New("statement").Funcs(templateFuncs(bind)).Parse(originalStatement)
 	if err != nil {
 		return "", fmt.Errorf("error creating go template %s", err)
 	}
@@ -205,6 +344,110 @@ func ResolveTemplateParams(templateParams Parameters, originalStatement string,
 	return modifiedStatement, nil
 }
 
+// templateFuncs returns the helper functions that can be used in statement
+// templates. Values passed to "join" and "values" are bound with bind, which
+// is nil if the tool does not support binding them.
+func templateFuncs(bind func(any) string) template.FuncMap {
+	return template.FuncMap{
+		"array":   ConvertArrayParamToString,
+		"default": templateDefault,
+		"lower":   strings.ToLower,
+		"repeat":  templateRepeat,
+		"join": func(v any) (string, error) {
+			if bind == nil {
+				return "", fmt.Errorf("join is not supported by this tool")
+			}
+			items, ok := v.([]any)
+			if !ok || len(items) == 0 {
+				return "", fmt.Errorf("join expects a non-empty array")
+			}
+			placeholders := make([]string, len(items))
+			for i, item := range items {
+				placeholders[i] = bind(item)
+			}
+			return strings.Join(placeholders, ", "), nil
+		},
+		"values": func(v any, fields ...string) (string, error) {
+			if bind == nil {
+				return "", fmt.Errorf("values is not supported by this tool")
+			}
+			return templateValues(bind, v, fields)
+		},
+	}
+}
+
+// templateRepeat expands snippet once for each item of an array, such as
+// "{item} = ?" into "id = ?, name = ?", and joins the expansions with sep,
+// which defaults to ", ". In snippet, "{item}" is replaced by the item and
+// "{n}" by its 1-based position. An empty array expands to "".
+func templateRepeat(v any, snippet string, sep ...string) (string, error) {
+	if len(sep) > 1 {
+		return "", fmt.Errorf("repeat expects at most one separator")
+	}
+	separator := ", "
+	if len(sep) == 1 {
+		separator = sep[0]
+	}
+	var items []any
+	switch v := v.(type) {
+	case nil:
+	case []any:
+		items = v
+	default:
+		return "", fmt.Errorf("repeat expects an array, got %T", v)
+	}
+	expanded := make([]string, len(items))
+	for i, item := range items {
+		r := strings.NewReplacer("{item}", fmt.Sprint(item), "{n}", strconv.Itoa(i+1))
+		expanded[i] = r.Replace(snippet)
+	}
+	return strings.Join(expanded, separator), nil
+}
+
+// templateValues expands an array of objects into the rows of a VALUES
+// clause, such as "($1, $2), ($3, $4)". Each row has the given fields of an
+// object, in order, and each field is bound separately. Fields missing from
+// an object are bound as nil.
+func templateValues(bind func(any) string, v any, fields []string) (string, error) {
+	if len(fields) == 0 {
+		return "", fmt.Errorf("values expects the fields of the rows")
+	}
+	items, ok := v.([]any)
+	if !ok || len(items) == 0 {
+		return "", fmt.Errorf("values expects a non-empty array")
+	}
+	rows := make([]string, len(items))
+	for i, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("values expects an array of objects, item %d is %T", i, item)
+		}
+		placeholders := make([]string, len(fields))
+		for j, f := range fields {
+			placeholders[j] = bind(obj[f])
+		}
+		rows[i] = "(" + strings.Join(placeholders, ", ") + ")"
+	}
+	return strings.Join(rows, ", "), nil
+}
+
+// templateDefault returns d if v is empty, or v otherwise.
+func templateDefault(d, v any) any {
+	switch v := v.(type) {
+	case nil:
+		return d
+	case string:
+		if v == "" {
+			return d
+		}
+	case []any:
+		if len(v) == 0 {
+			return d
+		}
+	}
+	return v
+}
+
 // ProcessParameters concatenate templateParameters and parameters from a tool.
 // It returns a list of concatenated parameters, concatenated Toolbox manifest, and concatenated MCP Manifest.
 func ProcessParameters(templateParams Parameters, params Parameters) (Parameters, []ParameterManifest, McpToolsSchema) {
@@ -241,9 +484,10 @@ func ProcessParameters(templateParams Parameters, params Parameters) (Parameters
 
 	// Create a new McpToolsSchema with all parameters
 	paramMcpManifest := McpToolsSchema{
-		Type:       "object",
-		Properties: concatPropertiesManifest,
-		Required:   concatRequiredManifest,
+		Type:              "object",
+		Properties:        concatPropertiesManifest,
+		Required:          concatRequiredManifest,
+		DependentRequired: allParameters.McpManifest().DependentRequired,
 	}
 	return allParameters, paramManifest, paramMcpManifest
 }
@@ -254,7 +498,11 @@ type Parameter interface {
 	GetName() string
 	GetType() string
 	GetAuthServices() []ParamAuthService
-	Parse(any) (any, error)
+	GetDependsOn() []string
+	GetDefault() any
+	GetFromAuthClaim() string
+	GetNullable() bool
+	Parse(context.Context, any) (any, error)
 	Manifest() ParameterManifest
 	McpManifest() ParameterMcpManifest
 }
@@ -264,6 +512,12 @@ type McpToolsSchema struct {
 	Type       string                          `json:"type"`
 	Properties map[string]ParameterMcpManifest `json:"properties"`
 	Required   []string                        `json:"required"`
+	// DependentRequired maps parameters to the parameters that are required
+	// when they are set.
+	DependentRequired map[string][]string `json:"dependentRequired,omitempty"`
+	// AdditionalProperties is false if arguments other than Properties are
+	// rejected.
+	AdditionalProperties *bool `json:"additionalProperties,omitempty"`
 }
 
 // Parameters is a type used to allow unmarshal a list of parameters
@@ -286,8 +540,23 @@ func (c *Parameters) UnmarshalYAML(ctx context.Context, unmarshal func(interface
 }
 
 // parseParamFromDelayedUnmarshaler is a helper function that is required to parse
-// parameters because there are multiple different types
+// parameters because there are multiple different types. The default of the
+// parameter, if any, is checked against its type.
 func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmarshaler) (Parameter, error) {
+	p, err := decodeParam(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	if d := p.GetDefault(); d != nil {
+		if _, err := p.Parse(ctx, d); err != nil {
+			return nil, fmt.Errorf("invalid default for parameter %q: %w", p.GetName(), err)
+		}
+	}
+	return p, nil
+}
+
+// decodeParam decodes a parameter as the Parameter implementation of its type.
+func decodeParam(ctx context.Context, u *util.DelayedUnmarshaler) (Parameter, error) {
 	var p map[string]any
 	err := u.Unmarshal(&p)
 	if err != nil {
@@ -313,6 +582,9 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 		if err := dec.DecodeContext(ctx, a); err != nil {
 			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
 		}
+		if a.ValuesQuery != nil && len(a.AllowedValues) > 0 {
+			return nil, fmt.Errorf("parameter %q can't set both allowedValues and valuesQuery", a.Name)
+		}
 		if a.AuthSources != nil {
 			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
@@ -324,6 +596,9 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 		if err := dec.DecodeContext(ctx, a); err != nil {
 			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
 		}
+		if a.Min != nil && a.Max != nil && *a.Min > *a.Max {
+			return nil, fmt.Errorf("invalid bounds for parameter %q: min %d is greater than max %d", a.Name, *a.Min, *a.Max)
+		}
 		if a.AuthSources != nil {
 			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
@@ -341,11 +616,35 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 			a.AuthSources = nil
 		}
 		return a, nil
-	case typeBool:
+	case typeBool, "bool":
 		a := &BooleanParameter{}
 		if err := dec.DecodeContext(ctx, a); err != nil {
 			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
 		}
+		// "bool" is accepted as an alias
+		a.Type = typeBool
+		if a.AuthSources != nil {
+			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
+			a.AuthServices = append(a.AuthServices, a.AuthSources...)
+			a.AuthSources = nil
+		}
+		return a, nil
+	case typeUUID:
+		a := &UUIDParameter{}
+		if err := dec.DecodeContext(ctx, a); err != nil {
+			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
+		}
+		if a.AuthSources != nil {
+			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
+			a.AuthServices = append(a.AuthServices, a.AuthSources...)
+			a.AuthSources = nil
+		}
+		return a, nil
+	case typeFile:
+		a := &FileParameter{}
+		if err := dec.DecodeContext(ctx, a); err != nil {
+			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
+		}
 		if a.AuthSources != nil {
 			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
@@ -363,6 +662,17 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 			a.AuthSources = nil
 		}
 		return a, nil
+	case typeObject:
+		a := &ObjectParameter{}
+		if err := dec.DecodeContext(ctx, a); err != nil {
+			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
+		}
+		if a.AuthSources != nil {
+			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
+			a.AuthServices = append(a.AuthServices, a.AuthSources...)
+			a.AuthSources = nil
+		}
+		return a, nil
 	}
 	return nil, fmt.Errorf("%q is not valid type for a parameter", t)
 }
@@ -378,35 +688,98 @@ func (ps Parameters) Manifest() []ParameterManifest {
 func (ps Parameters) McpManifest() McpToolsSchema {
 	properties := make(map[string]ParameterMcpManifest)
 	required := make([]string, 0)
+	var dependentRequired map[string][]string
 
 	for _, p := range ps {
+		if p.GetFromAuthClaim() != "" {
+			// clients can't provide parameters set from auth claims
+			continue
+		}
 		name := p.GetName()
 		properties[name] = p.McpManifest()
-		// all parameters are added to the required field
-		required = append(required, name)
+		if p.GetDefault() != nil {
+			// parameters with a default are optional
+			continue
+		}
+		dependsOn := p.GetDependsOn()
+		if len(dependsOn) == 0 {
+			// all other parameters are added to the required field
+			required = append(required, name)
+			continue
+		}
+		// dependent parameters are only required when their triggers are set
+		if dependentRequired == nil {
+			dependentRequired = make(map[string][]string)
+		}
+		for _, trigger := range dependsOn {
+			dependentRequired[trigger] = append(dependentRequired[trigger], name)
+		}
 	}
 
 	return McpToolsSchema{
-		Type:       "object",
-		Properties: properties,
-		Required:   required,
+		Type:              "object",
+		Properties:        properties,
+		Required:          required,
+		DependentRequired: dependentRequired,
 	}
 }
 
 // ParameterManifest represents parameters when served as part of a ToolManifest.
 type ParameterManifest struct {
-	Name         string             `json:"name"`
-	Type         string             `json:"type"`
-	Description  string             `json:"description"`
-	AuthServices []string           `json:"authSources"`
-	Items        *ParameterManifest `json:"items,omitempty"`
+	Name          string              `json:"name"`
+	Type          string              `json:"type"`
+	Description   string              `json:"description"`
+	AuthServices  []string            `json:"authSources"`
+	DependsOn     []string            `json:"dependsOn,omitempty"`
+	Default       any                 `json:"default,omitempty"`
+	Sensitive     bool                `json:"sensitive,omitempty"`
+	FromAuthClaim string              `json:"fromAuthClaim,omitempty"`
+	Nullable      bool                `json:"nullable,omitempty"`
+	AllowedValues []string            `json:"allowedValues,omitempty"`
+	Min           *int                `json:"min,omitempty"`
+	Max           *int                `json:"max,omitempty"`
+	Items         *ParameterManifest  `json:"items,omitempty"`
+	Properties    []ParameterManifest `json:"properties,omitempty"`
 }
 
 // ParameterMcpManifest represents properties when served as part of a ToolMcpManifest.
 type ParameterMcpManifest struct {
 	Type        string                `json:"type"`
+	Format      string                `json:"format,omitempty"`
 	Description string                `json:"description"`
+	Default     any                   `json:"default,omitempty"`
+	Enum        []string              `json:"enum,omitempty"`
+	Minimum     *int                  `json:"minimum,omitempty"`
+	Maximum     *int                  `json:"maximum,omitempty"`
 	Items       *ParameterMcpManifest `json:"items,omitempty"`
+	// Properties and Required describe the fields of "object" parameters.
+	Properties map[string]ParameterMcpManifest `json:"properties,omitempty"`
+	Required   []string                        `json:"required,omitempty"`
+	// Nullable parameters are marshaled with "null" among their types, as
+	// JSON Schema has no nullable keyword.
+	Nullable bool `json:"-"`
+}
+
+// MarshalJSON marshals the manifest as a JSON Schema. The type of nullable
+// parameters is an array that includes "null", such as ["string", "null"],
+// and null is added to their allowed values, if any.
+func (m ParameterMcpManifest) MarshalJSON() ([]byte, error) {
+	type manifest ParameterMcpManifest
+	if !m.Nullable {
+		return json.Marshal(manifest(m))
+	}
+	var enum []any
+	for _, v := range m.Enum {
+		enum = append(enum, v)
+	}
+	if enum != nil {
+		enum = append(enum, nil)
+	}
+	return json.Marshal(struct {
+		Type []string `json:"type"`
+		Enum []any    `json:"enum,omitempty"`
+		manifest
+	}{Type: []string{m.Type, "null"}, Enum: enum, manifest: manifest(m)})
 }
 
 // CommonParameter are default fields that are emebdding in most Parameter implementations. Embedding this stuct will give the object Name() and Type() functions.
@@ -416,6 +789,22 @@ type CommonParameter struct {
 	Desc         string             `yaml:"description" validate:"required"`
 	AuthServices []ParamAuthService `yaml:"authServices"`
 	AuthSources  []ParamAuthService `yaml:"authSources"` // Deprecated: Kept for compatibility.
+	// DependsOn lists the parameters that this parameter applies to. It is
+	// only required when one of them is set.
+	DependsOn []string `yaml:"dependsOn"`
+	// Default is the value used when the parameter is omitted, which makes
+	// it optional.
+	Default any `yaml:"default"`
+	// Sensitive marks values that must not be stored in plain text, such as
+	// in the keys of cached results.
+	Sensitive bool `yaml:"sensitive"`
+	// FromAuthClaim is the name of a claim of the validated auth token that
+	// the parameter is set to. Clients can't provide the parameter.
+	FromAuthClaim string `yaml:"fromAuthClaim"`
+	// Nullable accepts an explicit null, which is passed to the tool as nil,
+	// such as to bind SQL NULL. An omitted parameter is still required
+	// unless it has a default.
+	Nullable bool `yaml:"nullable"`
 }
 
 // GetName returns the name specified for the Parameter.
@@ -428,6 +817,27 @@ func (p *CommonParameter) GetType() string {
 	return p.Type
 }
 
+// GetDependsOn returns the parameters that the Parameter depends on.
+func (p *CommonParameter) GetDependsOn() []string {
+	return p.DependsOn
+}
+
+// GetDefault returns the default value of the Parameter, or nil if it has none.
+func (p *CommonParameter) GetDefault() any {
+	return p.Default
+}
+
+// GetFromAuthClaim returns the auth claim the Parameter is set from, or "" if
+// clients provide it.
+func (p *CommonParameter) GetFromAuthClaim() string {
+	return p.FromAuthClaim
+}
+
+// GetNullable returns whether the Parameter accepts an explicit null.
+func (p *CommonParameter) GetNullable() bool {
+	return p.Nullable
+}
+
 // Manifest returns the manifest for the Parameter.
 func (p *CommonParameter) Manifest() ParameterManifest {
 	// only list ParamAuthService names (without fields) in manifest
@@ -436,10 +846,15 @@ func (p *CommonParameter) Manifest() ParameterManifest {
 		authNames[i] = a.Name
 	}
 	return ParameterManifest{
-		Name:         p.Name,
-		Type:         p.Type,
-		Description:  p.Desc,
-		AuthServices: authNames,
+		Name:          p.Name,
+		Type:          p.Type,
+		Description:   p.Desc,
+		AuthServices:  authNames,
+		DependsOn:     p.DependsOn,
+		Default:       p.Default,
+		Sensitive:     p.Sensitive,
+		FromAuthClaim: p.FromAuthClaim,
+		Nullable:      p.Nullable,
 	}
 }
 
@@ -448,6 +863,8 @@ func (p *CommonParameter) McpManifest() ParameterMcpManifest {
 	return ParameterMcpManifest{
 		Type:        p.Type,
 		Description: p.Desc,
+		Default:     p.Default,
+		Nullable:    p.Nullable,
 	}
 }
 
@@ -496,16 +913,61 @@ var _ Parameter = &StringParameter{}
 // StringParameter is a parameter representing the "string" type.
 type StringParameter struct {
 	CommonParameter `yaml:",inline"`
+	// Trim removes leading and trailing whitespace from values, such as
+	// identifiers used in templateParameters.
+	Trim bool `yaml:"trim"`
+	// Case folds values to "lower" or "upper" case, for example to match the
+	// case of identifiers in the database catalog.
+	Case string `yaml:"case" validate:"omitempty,oneof=lower upper"`
+	// AllowedValues, if set, restricts values to one of the listed strings.
+	AllowedValues []string `yaml:"allowedValues"`
+	// ValuesQuery, if set, restricts values to those listed by a query.
+	ValuesQuery *ValuesQuery `yaml:"valuesQuery"`
 }
 
-// Parse casts the value "v" as a "string".
-func (p *StringParameter) Parse(v any) (any, error) {
+// Parse casts the value "v" as a "string", and normalizes it.
+func (p *StringParameter) Parse(ctx context.Context, v any) (any, error) {
 	newV, ok := v.(string)
 	if !ok {
 		return nil, &ParseTypeError{p.Name, p.Type, v}
 	}
+	if p.Trim {
+		newV = strings.TrimSpace(newV)
+	}
+	switch p.Case {
+	case "lower":
+		newV = strings.ToLower(newV)
+	case "upper":
+		newV = strings.ToUpper(newV)
+	}
+	if len(p.AllowedValues) > 0 && !slices.Contains(p.AllowedValues, newV) {
+		return nil, fmt.Errorf("parameter %q must be one of %q", p.Name, p.AllowedValues)
+	}
+	if p.ValuesQuery != nil && p.ValuesQuery.Values != nil {
+		ok, err := p.ValuesQuery.Values.Contains(ctx, newV)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list allowed values of parameter %q: %w", p.Name, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("parameter %q must be one of the values listed by its valuesQuery", p.Name)
+		}
+	}
 	return newV, nil
 }
+
+// Manifest returns the manifest for the StringParameter.
+func (p *StringParameter) Manifest() ParameterManifest {
+	m := p.CommonParameter.Manifest()
+	m.AllowedValues = p.AllowedValues
+	return m
+}
+
+// McpManifest returns the MCP manifest for the StringParameter.
+func (p *StringParameter) McpManifest() ParameterMcpManifest {
+	m := p.CommonParameter.McpManifest()
+	m.Enum = p.AllowedValues
+	return m
+}
 func (p *StringParameter) GetAuthServices() []ParamAuthService {
 	return p.AuthServices
 }
@@ -539,9 +1001,12 @@ var _ Parameter = &IntParameter{}
 // IntParameter is a parameter representing the "int" type.
 type IntParameter struct {
 	CommonParameter `yaml:",inline"`
+	// Min and Max, if set, are the inclusive bounds of values.
+	Min *int `yaml:"min"`
+	Max *int `yaml:"max"`
 }
 
-func (p *IntParameter) Parse(v any) (any, error) {
+func (p *IntParameter) Parse(ctx context.Context, v any) (any, error) {
 	var out int
 	switch newV := v.(type) {
 	default:
@@ -552,6 +1017,9 @@ func (p *IntParameter) Parse(v any) (any, error) {
 		out = int(newV)
 	case int64:
 		out = int(newV)
+	case uint64:
+		// YAML decodes non-negative integers, such as defaults, as uint64
+		out = int(newV)
 	case json.Number:
 		newI, err := newV.Int64()
 		if err != nil {
@@ -559,9 +1027,29 @@ func (p *IntParameter) Parse(v any) (any, error) {
 		}
 		out = int(newI)
 	}
+	if p.Min != nil && out < *p.Min {
+		return nil, fmt.Errorf("parameter %q must be >= %d", p.Name, *p.Min)
+	}
+	if p.Max != nil && out > *p.Max {
+		return nil, fmt.Errorf("parameter %q must be <= %d", p.Name, *p.Max)
+	}
 	return out, nil
 }
 
+// Manifest returns the manifest for the IntParameter.
+func (p *IntParameter) Manifest() ParameterManifest {
+	m := p.CommonParameter.Manifest()
+	m.Min, m.Max = p.Min, p.Max
+	return m
+}
+
+// McpManifest returns the MCP manifest for the IntParameter.
+func (p *IntParameter) McpManifest() ParameterMcpManifest {
+	m := p.CommonParameter.McpManifest()
+	m.Minimum, m.Maximum = p.Min, p.Max
+	return m
+}
+
 func (p *IntParameter) GetAuthServices() []ParamAuthService {
 	return p.AuthServices
 }
@@ -597,21 +1085,31 @@ type FloatParameter struct {
 	CommonParameter `yaml:",inline"`
 }
 
-func (p *FloatParameter) Parse(v any) (any, error) {
+// Parse casts the value "v" as a "float64". Integers are accepted and
+// converted.
+func (p *FloatParameter) Parse(ctx context.Context, v any) (any, error) {
 	var out float64
 	switch newV := v.(type) {
 	default:
-		return nil, &ParseTypeError{p.Name, p.Type, v}
+		return nil, fmt.Errorf("parameter %q must be a number", p.Name)
+	case int:
+		out = float64(newV)
+	case int32:
+		out = float64(newV)
+	case int64:
+		out = float64(newV)
+	case uint64:
+		out = float64(newV)
 	case float32:
 		out = float64(newV)
 	case float64:
 		out = newV
 	case json.Number:
-		newI, err := newV.Float64()
+		newF, err := newV.Float64()
 		if err != nil {
-			return nil, &ParseTypeError{p.Name, p.Type, v}
+			return nil, fmt.Errorf("parameter %q must be a number", p.Name)
 		}
-		out = float64(newI)
+		out = newF
 	}
 	return out, nil
 }
@@ -651,10 +1149,12 @@ type BooleanParameter struct {
 	CommonParameter `yaml:",inline"`
 }
 
-func (p *BooleanParameter) Parse(v any) (any, error) {
+// Parse validates that "v" is a JSON boolean. Strings such as "true" and
+// numbers such as 1 are rejected rather than converted.
+func (p *BooleanParameter) Parse(ctx context.Context, v any) (any, error) {
 	newV, ok := v.(bool)
 	if !ok {
-		return nil, &ParseTypeError{p.Name, p.Type, v}
+		return nil, fmt.Errorf("parameter %q must be a boolean (true or false)", p.Name)
 	}
 	return newV, nil
 }
@@ -663,6 +1163,139 @@ func (p *BooleanParameter) GetAuthServices() []ParamAuthService {
 	return p.AuthServices
 }
 
+// NewUUIDParameter is a convenience function for initializing a UUIDParameter.
+func NewUUIDParameter(name, desc string) *UUIDParameter {
+	return &UUIDParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeUUID,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+	}
+}
+
+// NewUUIDParameterWithAuth is a convenience function for initializing a UUIDParameter with a list of ParamAuthService.
+func NewUUIDParameterWithAuth(name, desc string, authServices []ParamAuthService) *UUIDParameter {
+	return &UUIDParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeUUID,
+			Desc:         desc,
+			AuthServices: authServices,
+		},
+	}
+}
+
+var _ Parameter = &UUIDParameter{}
+
+// UUIDParameter is a parameter representing the "uuid" type.
+type UUIDParameter struct {
+	CommonParameter `yaml:",inline"`
+}
+
+// Parse validates that "v" is a well-formed UUID, and returns it in its
+// canonical string form.
+func (p *UUIDParameter) Parse(ctx context.Context, v any) (any, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	}
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	}
+	return u.String(), nil
+}
+
+func (p *UUIDParameter) GetAuthServices() []ParamAuthService {
+	return p.AuthServices
+}
+
+// McpManifest returns the MCP manifest for the UUIDParameter.
+func (p *UUIDParameter) McpManifest() ParameterMcpManifest {
+	// JSON Schema has no uuid type, it is represented as a formatted string
+	return ParameterMcpManifest{
+		Type:        typeString,
+		Format:      typeUUID,
+		Description: p.Desc,
+		Default:     p.Default,
+		Nullable:    p.Nullable,
+	}
+}
+
+// NativeUUIDValues returns the values of paramValues in order, with the values
+// of uuid parameters converted to uuid.UUID. This should be used when binding
+// to sources that natively support the uuid type, such as Postgres.
+func NativeUUIDValues(params Parameters, paramValues ParamValues) ([]any, error) {
+	isUUID := make(map[string]bool)
+	for _, p := range params {
+		if p.GetType() == typeUUID {
+			isUUID[p.GetName()] = true
+		}
+	}
+	values := make([]any, 0, len(paramValues))
+	for _, pv := range paramValues {
+		if !isUUID[pv.Name] {
+			values = append(values, pv.Value)
+			continue
+		}
+		s, ok := pv.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid value for uuid parameter %q", pv.Name)
+		}
+		u, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for uuid parameter %q: %w", pv.Name, err)
+		}
+		values = append(values, u)
+	}
+	return values, nil
+}
+
+// NewFileParameter is a convenience function for initializing a FileParameter.
+func NewFileParameter(name, desc string) *FileParameter {
+	return &FileParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeFile,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+	}
+}
+
+var _ Parameter = &FileParameter{}
+
+// FileParameter is a parameter representing the "file" type. Its value is the
+// content of a file uploaded with a multipart/form-data request.
+type FileParameter struct {
+	CommonParameter `yaml:",inline"`
+}
+
+func (p *FileParameter) Parse(ctx context.Context, v any) (any, error) {
+	content, ok := v.(string)
+	if !ok {
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	}
+	return content, nil
+}
+
+func (p *FileParameter) GetAuthServices() []ParamAuthService {
+	return p.AuthServices
+}
+
+// McpManifest returns the MCP manifest for the FileParameter.
+func (p *FileParameter) McpManifest() ParameterMcpManifest {
+	// MCP clients can't upload files, so the content is passed as a string
+	return ParameterMcpManifest{
+		Type:        typeString,
+		Description: p.Desc,
+		Default:     p.Default,
+		Nullable:    p.Nullable,
+	}
+}
+
 // NewArrayParameter is a convenience function for initializing a ArrayParameter.
 func NewArrayParameter(name, desc string, items Parameter) *ArrayParameter {
 	return &ArrayParameter{
@@ -718,14 +1351,14 @@ func (p *ArrayParameter) UnmarshalYAML(ctx context.Context, unmarshal func(inter
 	return nil
 }
 
-func (p *ArrayParameter) Parse(v any) (any, error) {
+func (p *ArrayParameter) Parse(ctx context.Context, v any) (any, error) {
 	arrVal, ok := v.([]any)
 	if !ok {
 		return nil, &ParseTypeError{p.Name, p.Type, arrVal}
 	}
 	rtn := make([]any, 0, len(arrVal))
 	for idx, val := range arrVal {
-		val, err := p.Items.Parse(val)
+		val, err := p.Items.Parse(ctx, val)
 		if err != nil {
 			return nil, fmt.Errorf("unable to parse element #%d: %w", idx, err)
 		}
@@ -747,11 +1380,16 @@ func (p *ArrayParameter) Manifest() ParameterManifest {
 	}
 	items := p.Items.Manifest()
 	return ParameterManifest{
-		Name:         p.Name,
-		Type:         p.Type,
-		Description:  p.Desc,
-		AuthServices: authNames,
-		Items:        &items,
+		Name:          p.Name,
+		Type:          p.Type,
+		Description:   p.Desc,
+		AuthServices:  authNames,
+		DependsOn:     p.DependsOn,
+		Default:       p.Default,
+		Sensitive:     p.Sensitive,
+		FromAuthClaim: p.FromAuthClaim,
+		Nullable:      p.Nullable,
+		Items:         &items,
 	}
 }
 
@@ -766,6 +1404,155 @@ func (p *ArrayParameter) McpManifest() ParameterMcpManifest {
 	return ParameterMcpManifest{
 		Type:        p.Type,
 		Description: p.Desc,
+		Default:     p.Default,
+		Nullable:    p.Nullable,
 		Items:       &items,
 	}
 }
+
+// NewObjectParameter is a convenience function for initializing an ObjectParameter.
+func NewObjectParameter(name, desc string, properties ...Parameter) *ObjectParameter {
+	return &ObjectParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeObject,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+		Properties: properties,
+	}
+}
+
+var _ Parameter = &ObjectParameter{}
+
+// ObjectParameter is a parameter representing the "object" type, whose
+// fields are declared as nested parameters.
+type ObjectParameter struct {
+	CommonParameter `yaml:",inline"`
+	Properties      Parameters `yaml:"properties"`
+}
+
+func (p *ObjectParameter) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	var rawItem struct {
+		CommonParameter `yaml:",inline"`
+		Properties      Parameters `yaml:"properties"`
+	}
+	if err := unmarshal(&rawItem); err != nil {
+		return err
+	}
+	if len(rawItem.Properties) == 0 {
+		return fmt.Errorf("object parameter %q must declare its 'properties'", rawItem.Name)
+	}
+	for _, f := range rawItem.Properties {
+		if len(f.GetAuthServices()) != 0 {
+			return fmt.Errorf("nested properties should not have auth services")
+		}
+	}
+	p.CommonParameter = rawItem.CommonParameter
+	p.Properties = rawItem.Properties
+	return nil
+}
+
+// ObjectFieldError is returned when a field of an object parameter is
+// invalid. Path is the dotted path of the field, e.g. "filter.age".
+type ObjectFieldError struct {
+	Path string
+	Msg  string
+}
+
+func (e *ObjectFieldError) Error() string {
+	return fmt.Sprintf("parameter %s %s", e.Path, e.Msg)
+}
+
+// typeNoun returns how the values of a parameter type are named in errors.
+func typeNoun(t string) string {
+	switch t {
+	case typeInt:
+		return "an integer"
+	case typeFloat:
+		return "a number"
+	case typeBool:
+		return "a boolean"
+	case typeArray:
+		return "an array"
+	case typeObject:
+		return "an object"
+	default:
+		return "a " + t
+	}
+}
+
+// Parse checks that "v" is an object with the declared fields, and parses
+// each of them. Omitted fields take their default, if any.
+func (p *ObjectParameter) Parse(ctx context.Context, v any) (any, error) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	}
+	rtn := make(map[string]any, len(p.Properties))
+	for _, f := range p.Properties {
+		name := f.GetName()
+		path := p.Name + "." + name
+		val, ok := obj[name]
+		if ok && val == nil {
+			// an explicit null is handled like for top-level parameters
+			if !f.GetNullable() {
+				return nil, &ObjectFieldError{Path: path, Msg: "can't be null, unless the property is nullable"}
+			}
+			rtn[name] = nil
+			continue
+		}
+		if !ok {
+			if f.GetDefault() == nil {
+				return nil, &ObjectFieldError{Path: path, Msg: "is required"}
+			}
+			val = f.GetDefault()
+		}
+		newV, err := f.Parse(ctx, val)
+		if err != nil {
+			var fieldErr *ObjectFieldError
+			var typeErr *ParseTypeError
+			switch {
+			case errors.As(err, &fieldErr):
+				// the field is itself an object, prefix the path of its field
+				return nil, &ObjectFieldError{Path: p.Name + "." + fieldErr.Path, Msg: fieldErr.Msg}
+			case errors.As(err, &typeErr):
+				return nil, &ObjectFieldError{Path: path, Msg: "must be " + typeNoun(f.GetType())}
+			default:
+				// errors of the form `parameter "age" must be ...` are
+				// reported against the path of the field
+				if msg, ok := strings.CutPrefix(err.Error(), fmt.Sprintf("parameter %q ", name)); ok {
+					return nil, &ObjectFieldError{Path: path, Msg: msg}
+				}
+				return nil, &ObjectFieldError{Path: path, Msg: "is invalid: " + err.Error()}
+			}
+		}
+		rtn[name] = newV
+	}
+	for name := range obj {
+		if !slices.ContainsFunc(p.Properties, func(f Parameter) bool { return f.GetName() == name }) {
+			return nil, &ObjectFieldError{Path: p.Name + "." + name, Msg: "is not a declared property"}
+		}
+	}
+	return rtn, nil
+}
+
+func (p *ObjectParameter) GetAuthServices() []ParamAuthService {
+	return p.AuthServices
+}
+
+// Manifest returns the manifest for the ObjectParameter.
+func (p *ObjectParameter) Manifest() ParameterManifest {
+	m := p.CommonParameter.Manifest()
+	m.Properties = p.Properties.Manifest()
+	return m
+}
+
+// McpManifest returns the MCP manifest for the ObjectParameter.
+func (p *ObjectParameter) McpManifest() ParameterMcpManifest {
+	m := p.CommonParameter.McpManifest()
+	schema := p.Properties.McpManifest()
+	m.Properties = schema.Properties
+	m.Required = schema.Required
+	return m
+}
@@ -16,10 +16,16 @@ package tools_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
@@ -50,6 +56,165 @@ func TestParametersMarshal(t *testing.T) {
 				tools.NewStringParameter("my_string", "this param is a string"),
 			},
 		},
+		{
+			name: "string with dependency",
+			in: []map[string]any{
+				{
+					"name":        "my_string",
+					"type":        "string",
+					"description": "this param is a string",
+					"dependsOn":   []string{"my_trigger"},
+				},
+			},
+			want: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{
+						Name:      "my_string",
+						Type:      "string",
+						Desc:      "this param is a string",
+						DependsOn: []string{"my_trigger"},
+					},
+				},
+			},
+		},
+		{
+			name: "string with allowed values",
+			in: []map[string]any{
+				{
+					"name":          "region",
+					"type":          "string",
+					"description":   "region of the instance",
+					"allowedValues": []string{"us-east1", "us-west1"},
+				},
+			},
+			want: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{
+						Name: "region",
+						Type: "string",
+						Desc: "region of the instance",
+					},
+					AllowedValues: []string{"us-east1", "us-west1"},
+				},
+			},
+		},
+		{
+			name: "string with values query",
+			in: []map[string]any{
+				{
+					"name":        "region",
+					"type":        "string",
+					"description": "region of the instance",
+					"valuesQuery": map[string]any{
+						"source":    "my-pg-instance",
+						"statement": "SELECT name FROM regions",
+						"ttl":       "10m",
+					},
+				},
+			},
+			want: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{
+						Name: "region",
+						Type: "string",
+						Desc: "region of the instance",
+					},
+					ValuesQuery: &tools.ValuesQuery{
+						Source:    "my-pg-instance",
+						Statement: "SELECT name FROM regions",
+						TTL:       10 * time.Minute,
+					},
+				},
+			},
+		},
+		{
+			name: "int with default",
+			in: []map[string]any{
+				{
+					"name":        "limit",
+					"type":        "integer",
+					"description": "maximum number of rows",
+					"default":     100,
+				},
+			},
+			want: tools.Parameters{
+				&tools.IntParameter{
+					CommonParameter: tools.CommonParameter{
+						Name:    "limit",
+						Type:    "integer",
+						Desc:    "maximum number of rows",
+						Default: uint64(100),
+					},
+				},
+			},
+		},
+		{
+			name: "nullable string",
+			in: []map[string]any{
+				{
+					"name":        "middle_name",
+					"type":        "string",
+					"description": "middle name of the user",
+					"nullable":    true,
+				},
+			},
+			want: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{
+						Name:     "middle_name",
+						Type:     "string",
+						Desc:     "middle name of the user",
+						Nullable: true,
+					},
+				},
+			},
+		},
+		{
+			name: "int with bounds",
+			in: []map[string]any{
+				{
+					"name":        "id",
+					"type":        "integer",
+					"description": "id of the user",
+					"min":         1,
+					"max":         1000,
+				},
+			},
+			want: tools.Parameters{
+				&tools.IntParameter{
+					CommonParameter: tools.CommonParameter{
+						Name: "id",
+						Type: "integer",
+						Desc: "id of the user",
+					},
+					Min: intPtr(1),
+					Max: intPtr(1000),
+				},
+			},
+		},
+		{
+			name: "string with normalization",
+			in: []map[string]any{
+				{
+					"name":        "my_string",
+					"type":        "string",
+					"description": "this param is a string",
+					"trim":        true,
+					"case":        "lower",
+				},
+			},
+			want: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{
+						Name: "my_string",
+						Type: "string",
+						Desc: "this param is a string",
+					},
+					Trim: true,
+					Case: "lower",
+				},
+			},
+		},
 		{
 			name: "int",
 			in: []map[string]any{
@@ -63,6 +228,32 @@ func TestParametersMarshal(t *testing.T) {
 				tools.NewIntParameter("my_integer", "this param is an int"),
 			},
 		},
+		{
+			name: "uuid",
+			in: []map[string]any{
+				{
+					"name":        "my_uuid",
+					"type":        "uuid",
+					"description": "this param is a uuid",
+				},
+			},
+			want: tools.Parameters{
+				tools.NewUUIDParameter("my_uuid", "this param is a uuid"),
+			},
+		},
+		{
+			name: "file",
+			in: []map[string]any{
+				{
+					"name":        "my_file",
+					"type":        "file",
+					"description": "this param is a file",
+				},
+			},
+			want: tools.Parameters{
+				tools.NewFileParameter("my_file", "this param is a file"),
+			},
+		},
 		{
 			name: "float",
 			in: []map[string]any{
@@ -89,6 +280,19 @@ func TestParametersMarshal(t *testing.T) {
 				tools.NewBooleanParameter("my_bool", "this param is a boolean"),
 			},
 		},
+		{
+			name: "bool alias",
+			in: []map[string]any{
+				{
+					"name":        "my_bool",
+					"type":        "bool",
+					"description": "this param is a boolean",
+				},
+			},
+			want: tools.Parameters{
+				tools.NewBooleanParameter("my_bool", "this param is a boolean"),
+			},
+		},
 		{
 			name: "string array",
 			in: []map[string]any{
@@ -125,6 +329,82 @@ func TestParametersMarshal(t *testing.T) {
 				tools.NewArrayParameter("my_array", "this param is an array of floats", tools.NewFloatParameter("my_float", "float item")),
 			},
 		},
+		{
+			name: "object",
+			in: []map[string]any{
+				{
+					"name":        "filter",
+					"type":        "object",
+					"description": "filter on the users",
+					"properties": []map[string]any{
+						{
+							"name":        "name",
+							"type":        "string",
+							"description": "name of the user",
+						},
+						{
+							"name":        "age",
+							"type":        "integer",
+							"description": "age of the user",
+						},
+					},
+				},
+			},
+			want: tools.Parameters{
+				tools.NewObjectParameter("filter", "filter on the users",
+					tools.NewStringParameter("name", "name of the user"),
+					tools.NewIntParameter("age", "age of the user"),
+				),
+			},
+		},
+		{
+			name: "array with default",
+			in: []map[string]any{
+				{
+					"name":        "tags",
+					"type":        "array",
+					"description": "tags of the users",
+					"default":     []string{"admin", "staff"},
+					"items": map[string]string{
+						"name":        "tag",
+						"type":        "string",
+						"description": "tag of a user",
+					},
+				},
+			},
+			want: tools.Parameters{
+				func() tools.Parameter {
+					p := tools.NewArrayParameter("tags", "tags of the users", tools.NewStringParameter("tag", "tag of a user"))
+					p.Default = []any{"admin", "staff"}
+					return p
+				}(),
+			},
+		},
+		{
+			name: "object with default",
+			in: []map[string]any{
+				{
+					"name":        "filter",
+					"type":        "object",
+					"description": "filter on the users",
+					"default":     map[string]any{"name": "alice"},
+					"properties": []map[string]any{
+						{
+							"name":        "name",
+							"type":        "string",
+							"description": "name of the user",
+						},
+					},
+				},
+			},
+			want: tools.Parameters{
+				func() tools.Parameter {
+					p := tools.NewObjectParameter("filter", "filter on the users", tools.NewStringParameter("name", "name of the user"))
+					p.Default = map[string]any{"name": "alice"}
+					return p
+				}(),
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -463,6 +743,53 @@ func TestParametersParse(t *testing.T) {
 			},
 			want: tools.ParamValues{tools.ParamValue{Name: "my_string", Value: "hello world"}},
 		},
+		{
+			name: "file",
+			params: tools.Parameters{
+				tools.NewFileParameter("my_file", "this param is a file"),
+			},
+			in: map[string]any{
+				"my_file": "1,alice\n2,bob\n",
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_file", Value: "1,alice\n2,bob\n"}},
+		},
+		{
+			name: "not file",
+			params: tools.Parameters{
+				tools.NewFileParameter("my_file", "this param is a file"),
+			},
+			in: map[string]any{
+				"my_file": 4,
+			},
+		},
+		{
+			name: "uuid",
+			params: tools.Parameters{
+				tools.NewUUIDParameter("my_uuid", "this param is a uuid"),
+			},
+			in: map[string]any{
+				"my_uuid": "F47AC10B-58CC-4372-A567-0E02B2C3D479",
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_uuid", Value: "f47ac10b-58cc-4372-a567-0e02b2c3d479"}},
+		},
+		{
+			name: "malformed uuid",
+			params: tools.Parameters{
+				tools.NewUUIDParameter("my_uuid", "this param is a uuid"),
+			},
+			in: map[string]any{
+				"my_uuid": "f47ac10b-58cc-4372-a567",
+			},
+		},
+		{
+			name: "not uuid",
+			params: tools.Parameters{
+				tools.NewUUIDParameter("my_uuid", "this param is a uuid"),
+			},
+			in: map[string]any{
+				"my_uuid": 4,
+			},
+		},
 		{
 			name: "not string",
 			params: tools.Parameters{
@@ -511,6 +838,16 @@ func TestParametersParse(t *testing.T) {
 			},
 			want: tools.ParamValues{tools.ParamValue{Name: "my_float", Value: 1.5}},
 		},
+		{
+			name: "integer as float",
+			params: tools.Parameters{
+				tools.NewFloatParameter("my_float", "this param is a float"),
+			},
+			in: map[string]any{
+				"my_float": 19,
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_float", Value: float64(19)}},
+		},
 		{
 			name: "not float",
 			params: tools.Parameters{
@@ -520,6 +857,15 @@ func TestParametersParse(t *testing.T) {
 				"my_float": true,
 			},
 		},
+		{
+			name: "string as float",
+			params: tools.Parameters{
+				tools.NewFloatParameter("my_float", "this param is a float"),
+			},
+			in: map[string]any{
+				"my_float": "abc",
+			},
+		},
 		{
 			name: "bool",
 			params: tools.Parameters{
@@ -539,6 +885,31 @@ func TestParametersParse(t *testing.T) {
 				"my_bool": 1.5,
 			},
 		},
+		{
+			name: "bool as string",
+			params: tools.Parameters{
+				tools.NewBooleanParameter("my_bool", "this param is a bool"),
+			},
+			in: map[string]any{
+				"my_bool": "true",
+			},
+		},
+		{
+			name: "bool as number",
+			params: tools.Parameters{
+				tools.NewBooleanParameter("my_bool", "this param is a bool"),
+			},
+			in: map[string]any{
+				"my_bool": 1,
+			},
+		},
+		{
+			name: "missing bool",
+			params: tools.Parameters{
+				tools.NewBooleanParameter("my_bool", "this param is a bool"),
+			},
+			in: map[string]any{},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -558,7 +929,7 @@ func TestParametersParse(t *testing.T) {
 			}
 
 			wantErr := len(tc.want) == 0 // error is expected if no items in want
-			gotAll, err := tools.ParseParams(tc.params, m, make(map[string]map[string]any))
+			gotAll, err := tools.ParseParams(context.Background(), tc.params, m, make(map[string]map[string]any))
 			if err != nil {
 				if wantErr {
 					return
@@ -582,57 +953,325 @@ func TestParametersParse(t *testing.T) {
 	}
 }
 
-func TestAuthParametersParse(t *testing.T) {
-	authServices := []tools.ParamAuthService{
-		{
-			Name:  "my-google-auth-service",
-			Field: "auth_field",
-		},
-		{
-			Name:  "other-auth-service",
-			Field: "other_auth_field",
-		}}
+func TestFloatParameterParseError(t *testing.T) {
+	p := tools.NewFloatParameter("price", "price of the item")
+	_, err := p.Parse(context.Background(), "abc")
+	if err == nil {
+		t.Fatalf("expected parsing to fail")
+	}
+	if want := `parameter "price" must be a number`; err.Error() != want {
+		t.Fatalf("unexpected error: got %q, want %q", err, want)
+	}
+}
+
+func TestBooleanParameterParseError(t *testing.T) {
+	params := tools.Parameters{tools.NewBooleanParameter("active", "whether the user is active")}
 	tcs := []struct {
-		name      string
-		params    tools.Parameters
-		in        map[string]any
-		claimsMap map[string]map[string]any
-		want      tools.ParamValues
+		name string
+		in   map[string]any
+		want string
 	}{
 		{
 			name: "string",
-			params: tools.Parameters{
-				tools.NewStringParameterWithAuth("my_string", "this param is a string", authServices),
-			},
-			in: map[string]any{
-				"my_string": "hello world",
-			},
-			claimsMap: map[string]map[string]any{"my-google-auth-service": {"auth_field": "hello"}},
-			want:      tools.ParamValues{tools.ParamValue{Name: "my_string", Value: "hello"}},
+			in:   map[string]any{"active": "true"},
+			want: `unable to parse value for "active": parameter "active" must be a boolean (true or false)`,
 		},
 		{
-			name: "not string",
-			params: tools.Parameters{
-				tools.NewStringParameterWithAuth("my_string", "this param is a string", authServices),
-			},
-			in: map[string]any{
-				"my_string": 4,
-			},
-			claimsMap: map[string]map[string]any{},
+			name: "number",
+			in:   map[string]any{"active": json.Number("0")},
+			want: `unable to parse value for "active": parameter "active" must be a boolean (true or false)`,
 		},
 		{
-			name: "int",
-			params: tools.Parameters{
-				tools.NewIntParameterWithAuth("my_int", "this param is an int", authServices),
-			},
-			in: map[string]any{
-				"my_int": 100,
-			},
-			claimsMap: map[string]map[string]any{"other-auth-service": {"other_auth_field": 120}},
-			want:      tools.ParamValues{tools.ParamValue{Name: "my_int", Value: 120}},
+			name: "missing",
+			in:   map[string]any{},
+			want: `parameter "active" is required`,
 		},
-		{
-			name: "not int",
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tools.ParseParams(context.Background(), params, tc.in, nil)
+			if err == nil {
+				t.Fatalf("expected parsing to fail")
+			}
+			if err.Error() != tc.want {
+				t.Fatalf("unexpected error: got %q, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestAllowedValuesParameter(t *testing.T) {
+	region := tools.NewStringParameter("region", "region of the instance")
+	region.AllowedValues = []string{"us-east1", "us-west1"}
+	params := tools.Parameters{region}
+
+	got, err := tools.ParseParams(context.Background(), params, map[string]any{"region": "us-east1"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.ParamValues{{Name: "region", Value: "us-east1"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected params: diff %v", diff)
+	}
+
+	_, err = tools.ParseParams(context.Background(), params, map[string]any{"region": "eu-west1"}, nil)
+	if err == nil {
+		t.Fatalf("expected a value outside of the allowed values to be rejected")
+	}
+	wantErr := `unable to parse value for "region": parameter "region" must be one of ["us-east1" "us-west1"]`
+	if err.Error() != wantErr {
+		t.Fatalf("unexpected error: got %q, want %q", err, wantErr)
+	}
+
+	wantMcp := tools.ParameterMcpManifest{Type: "string", Description: "region of the instance", Enum: []string{"us-east1", "us-west1"}}
+	if diff := cmp.Diff(wantMcp, region.McpManifest()); diff != "" {
+		t.Fatalf("unexpected mcp manifest: diff %v", diff)
+	}
+}
+
+func TestValuesQueryParameter(t *testing.T) {
+	// the reference table gains a region after the values were first listed
+	var calls atomic.Int32
+	query := func(context.Context) ([]string, error) {
+		if calls.Add(1) == 1 {
+			return []string{"us-east1", "us-west1"}, nil
+		}
+		return []string{"us-east1", "us-west1", "eu-west1"}, nil
+	}
+	region := tools.NewStringParameter("region", "region of the instance")
+	region.ValuesQuery = &tools.ValuesQuery{Values: tools.NewValueSet(query, 100*time.Millisecond)}
+	params := tools.Parameters{region}
+
+	if _, err := tools.ParseParams(context.Background(), params, map[string]any{"region": "us-east1"}, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err := tools.ParseParams(context.Background(), params, map[string]any{"region": "eu-west1"}, nil)
+	wantErr := `unable to parse value for "region": parameter "region" must be one of the values listed by its valuesQuery`
+	if err == nil || err.Error() != wantErr {
+		t.Fatalf("unexpected error: got %v, want %q", err, wantErr)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected the values to be cached, got %d queries", got)
+	}
+
+	// the values are queried again once the ttl has expired
+	time.Sleep(200 * time.Millisecond)
+	if _, err := tools.ParseParams(context.Background(), params, map[string]any{"region": "eu-west1"}, nil); err != nil {
+		t.Fatalf("unexpected error after the values were refreshed: %s", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected the values to be refreshed once, got %d queries", got)
+	}
+}
+
+func TestValuesQueryParameterContext(t *testing.T) {
+	// the values are listed with the context of the invocation
+	query := func(ctx context.Context) ([]string, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return []string{"us-east1"}, nil
+	}
+	region := tools.NewStringParameter("region", "region of the instance")
+	region.ValuesQuery = &tools.ValuesQuery{Values: tools.NewValueSet(query, time.Minute)}
+	params := tools.Parameters{region}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := tools.ParseParams(ctx, params, map[string]any{"region": "us-east1"}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestObjectParameter(t *testing.T) {
+	filter := tools.NewObjectParameter("filter", "filter on the users",
+		tools.NewStringParameter("name", "name of the user"),
+		tools.NewFloatParameter("age", "age of the user"),
+		tools.NewObjectParameter("address", "address of the user",
+			tools.NewStringParameter("city", "city of the user"),
+		),
+	)
+	params := tools.Parameters{filter}
+
+	got, err := tools.ParseParams(context.Background(), params, map[string]any{"filter": map[string]any{
+		"name":    "Alice",
+		"age":     21.0,
+		"address": map[string]any{"city": "Paris"},
+	}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.ParamValues{{Name: "filter", Value: map[string]any{
+		"name":    "Alice",
+		"age":     21.0,
+		"address": map[string]any{"city": "Paris"},
+	}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected params: diff %v", diff)
+	}
+
+	tcs := []struct {
+		name string
+		in   any
+		err  string
+	}{
+		{
+			name: "invalid field",
+			in:   map[string]any{"name": "Alice", "age": "21", "address": map[string]any{"city": "Paris"}},
+			err:  `unable to parse value for "filter": parameter filter.age must be a number`,
+		},
+		{
+			name: "invalid nested field",
+			in:   map[string]any{"name": "Alice", "age": 21.0, "address": map[string]any{"city": 75}},
+			err:  `unable to parse value for "filter": parameter filter.address.city must be a string`,
+		},
+		{
+			name: "missing field",
+			in:   map[string]any{"name": "Alice", "address": map[string]any{"city": "Paris"}},
+			err:  `unable to parse value for "filter": parameter filter.age is required`,
+		},
+		{
+			name: "null field",
+			in:   map[string]any{"name": "Alice", "age": nil, "address": map[string]any{"city": "Paris"}},
+			err:  `unable to parse value for "filter": parameter filter.age can't be null, unless the property is nullable`,
+		},
+		{
+			name: "undeclared field",
+			in:   map[string]any{"name": "Alice", "age": 21.0, "address": map[string]any{"city": "Paris"}, "email": "alice@example.com"},
+			err:  `unable to parse value for "filter": parameter filter.email is not a declared property`,
+		},
+		{
+			name: "not an object",
+			in:   "Alice",
+			err:  `unable to parse value for "filter": "Alice" not type "object"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tools.ParseParams(context.Background(), params, map[string]any{"filter": tc.in}, nil)
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+
+	wantMcp := tools.ParameterMcpManifest{
+		Type:        "object",
+		Description: "filter on the users",
+		Properties: map[string]tools.ParameterMcpManifest{
+			"name": {Type: "string", Description: "name of the user"},
+			"age":  {Type: "float", Description: "age of the user"},
+			"address": {
+				Type:        "object",
+				Description: "address of the user",
+				Properties:  map[string]tools.ParameterMcpManifest{"city": {Type: "string", Description: "city of the user"}},
+				Required:    []string{"city"},
+			},
+		},
+		Required: []string{"name", "age", "address"},
+	}
+	if diff := cmp.Diff(wantMcp, filter.McpManifest()); diff != "" {
+		t.Fatalf("unexpected mcp manifest: diff %v", diff)
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestIntRangeParameter(t *testing.T) {
+	id := tools.NewIntParameter("id", "id of the user")
+	id.Min, id.Max = intPtr(1), intPtr(1000)
+	params := tools.Parameters{id}
+
+	tcs := []struct {
+		name string
+		in   any
+		err  string
+	}{
+		{name: "in range", in: 42},
+		{name: "min", in: 1},
+		{name: "max", in: 1000},
+		{name: "below min", in: 0, err: `unable to parse value for "id": parameter "id" must be >= 1`},
+		{name: "above max", in: 1001, err: `unable to parse value for "id": parameter "id" must be <= 1000`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ParseParams(context.Background(), params, map[string]any{"id": tc.in}, nil)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			want := tools.ParamValues{{Name: "id", Value: tc.in}}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected params: diff %v", diff)
+			}
+		})
+	}
+
+	wantMcp := tools.ParameterMcpManifest{Type: "integer", Description: "id of the user", Minimum: intPtr(1), Maximum: intPtr(1000)}
+	if diff := cmp.Diff(wantMcp, id.McpManifest()); diff != "" {
+		t.Fatalf("unexpected mcp manifest: diff %v", diff)
+	}
+}
+
+func TestAuthParametersParse(t *testing.T) {
+	authServices := []tools.ParamAuthService{
+		{
+			Name:  "my-google-auth-service",
+			Field: "auth_field",
+		},
+		{
+			Name:  "other-auth-service",
+			Field: "other_auth_field",
+		}}
+	tcs := []struct {
+		name      string
+		params    tools.Parameters
+		in        map[string]any
+		claimsMap map[string]map[string]any
+		want      tools.ParamValues
+	}{
+		{
+			name: "string",
+			params: tools.Parameters{
+				tools.NewStringParameterWithAuth("my_string", "this param is a string", authServices),
+			},
+			in: map[string]any{
+				"my_string": "hello world",
+			},
+			claimsMap: map[string]map[string]any{"my-google-auth-service": {"auth_field": "hello"}},
+			want:      tools.ParamValues{tools.ParamValue{Name: "my_string", Value: "hello"}},
+		},
+		{
+			name: "not string",
+			params: tools.Parameters{
+				tools.NewStringParameterWithAuth("my_string", "this param is a string", authServices),
+			},
+			in: map[string]any{
+				"my_string": 4,
+			},
+			claimsMap: map[string]map[string]any{},
+		},
+		{
+			name: "int",
+			params: tools.Parameters{
+				tools.NewIntParameterWithAuth("my_int", "this param is an int", authServices),
+			},
+			in: map[string]any{
+				"my_int": 100,
+			},
+			claimsMap: map[string]map[string]any{"other-auth-service": {"other_auth_field": 120}},
+			want:      tools.ParamValues{tools.ParamValue{Name: "my_int", Value: 120}},
+		},
+		{
+			name: "not int",
 			params: tools.Parameters{
 				tools.NewIntParameterWithAuth("my_int", "this param is an int", authServices),
 			},
@@ -721,7 +1360,7 @@ func TestAuthParametersParse(t *testing.T) {
 				t.Fatalf("unable to unmarshal: %s", err)
 			}
 
-			gotAll, err := tools.ParseParams(tc.params, m, tc.claimsMap)
+			gotAll, err := tools.ParseParams(context.Background(), tc.params, m, tc.claimsMap)
 			if err != nil {
 				if len(tc.want) == 0 {
 					// error is expected if no items in want
@@ -825,6 +1464,11 @@ func TestParamManifest(t *testing.T) {
 			in:   tools.NewBooleanParameter("foo-bool", "bar"),
 			want: tools.ParameterManifest{Name: "foo-bool", Type: "boolean", Description: "bar", AuthServices: []string{}},
 		},
+		{
+			name: "uuid",
+			in:   tools.NewUUIDParameter("foo-uuid", "bar"),
+			want: tools.ParameterManifest{Name: "foo-uuid", Type: "uuid", Description: "bar", AuthServices: []string{}},
+		},
 		{
 			name: "array",
 			in:   tools.NewArrayParameter("foo-array", "bar", tools.NewStringParameter("foo-string", "bar")),
@@ -873,6 +1517,16 @@ func TestParamMcpManifest(t *testing.T) {
 			in:   tools.NewBooleanParameter("foo-bool", "bar"),
 			want: tools.ParameterMcpManifest{Type: "boolean", Description: "bar"},
 		},
+		{
+			name: "uuid",
+			in:   tools.NewUUIDParameter("foo-uuid", "bar"),
+			want: tools.ParameterMcpManifest{Type: "string", Format: "uuid", Description: "bar"},
+		},
+		{
+			name: "file",
+			in:   tools.NewFileParameter("foo-file", "bar"),
+			want: tools.ParameterMcpManifest{Type: "string", Description: "bar"},
+		},
 		{
 			name: "array",
 			in:   tools.NewArrayParameter("foo-array", "bar", tools.NewStringParameter("foo-string", "bar")),
@@ -893,6 +1547,411 @@ func TestParamMcpManifest(t *testing.T) {
 	}
 }
 
+func TestDependentParameters(t *testing.T) {
+	sortBy := tools.NewStringParameter("sort_by", "column to sort by")
+	sortBy.DependsOn = []string{"limit"}
+	params := tools.Parameters{
+		tools.NewIntParameter("limit", "maximum number of rows"),
+		sortBy,
+	}
+
+	wantSchema := tools.McpToolsSchema{
+		Type: "object",
+		Properties: map[string]tools.ParameterMcpManifest{
+			"limit":   {Type: "integer", Description: "maximum number of rows"},
+			"sort_by": {Type: "string", Description: "column to sort by"},
+		},
+		Required:          []string{"limit"},
+		DependentRequired: map[string][]string{"limit": {"sort_by"}},
+	}
+	if diff := cmp.Diff(wantSchema, params.McpManifest()); diff != "" {
+		t.Fatalf("unexpected schema: diff %v", diff)
+	}
+
+	// the dependent parameter is required because its trigger was provided
+	_, err := tools.ParseParams(context.Background(), params, map[string]any{"limit": 10}, nil)
+	if err == nil {
+		t.Fatalf("expected a missing dependent parameter to be rejected")
+	}
+	if want := `parameter "sort_by" is required when "limit" is set`; err.Error() != want {
+		t.Fatalf("unexpected error: got %q, want %q", err, want)
+	}
+
+	got, err := tools.ParseParams(context.Background(), params, map[string]any{"limit": 10, "sort_by": "name"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.ParamValues{{Name: "limit", Value: 10}, {Name: "sort_by", Value: "name"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected params: diff %v", diff)
+	}
+
+	// without its trigger, the dependent parameter may be omitted
+	params = tools.Parameters{sortBy}
+	got, err = tools.ParseParams(context.Background(), params, map[string]any{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want = tools.ParamValues{{Name: "sort_by", Value: nil}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected params: diff %v", diff)
+	}
+}
+
+func TestDefaultParameters(t *testing.T) {
+	limit := tools.NewIntParameter("limit", "maximum number of rows")
+	limit.Default = uint64(100)
+	params := tools.Parameters{
+		tools.NewStringParameter("name", "name of the user"),
+		limit,
+	}
+
+	wantSchema := tools.McpToolsSchema{
+		Type: "object",
+		Properties: map[string]tools.ParameterMcpManifest{
+			"name":  {Type: "string", Description: "name of the user"},
+			"limit": {Type: "integer", Description: "maximum number of rows", Default: uint64(100)},
+		},
+		Required: []string{"name"},
+	}
+	if diff := cmp.Diff(wantSchema, params.McpManifest()); diff != "" {
+		t.Fatalf("unexpected schema: diff %v", diff)
+	}
+
+	// the default is used when the parameter is omitted
+	got, err := tools.ParseParams(context.Background(), params, map[string]any{"name": "Alice"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.ParamValues{{Name: "name", Value: "Alice"}, {Name: "limit", Value: 100}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected params: diff %v", diff)
+	}
+
+	got, err = tools.ParseParams(context.Background(), params, map[string]any{"name": "Alice", "limit": json.Number("5")}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want = tools.ParamValues{{Name: "name", Value: "Alice"}, {Name: "limit", Value: 5}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected params: diff %v", diff)
+	}
+}
+
+func TestNullableParameters(t *testing.T) {
+	middleName := tools.NewStringParameter("middle_name", "middle name of the user")
+	middleName.Nullable = true
+	manager := tools.NewIntParameter("manager_id", "id of the manager")
+	manager.Nullable = true
+	manager.Default = uint64(1)
+	params := tools.Parameters{
+		tools.NewStringParameter("name", "name of the user"),
+		middleName,
+		manager,
+	}
+
+	wantSchema := tools.McpToolsSchema{
+		Type: "object",
+		Properties: map[string]tools.ParameterMcpManifest{
+			"name":        {Type: "string", Description: "name of the user"},
+			"middle_name": {Type: "string", Description: "middle name of the user", Nullable: true},
+			"manager_id":  {Type: "integer", Description: "id of the manager", Default: uint64(1), Nullable: true},
+		},
+		Required: []string{"name", "middle_name"},
+	}
+	if diff := cmp.Diff(wantSchema, params.McpManifest()); diff != "" {
+		t.Fatalf("unexpected schema: diff %v", diff)
+	}
+	// JSON Schema has no nullable keyword, null is one of the types instead
+	b, err := json.Marshal(params.McpManifest())
+	if err != nil {
+		t.Fatalf("unable to marshal schema: %s", err)
+	}
+	wantJSON := `{"type":"object","properties":{` +
+		`"manager_id":{"type":["integer","null"],"description":"id of the manager","default":1},` +
+		`"middle_name":{"type":["string","null"],"description":"middle name of the user"},` +
+		`"name":{"type":"string","description":"name of the user"}},` +
+		`"required":["name","middle_name"]}`
+	if string(b) != wantJSON {
+		t.Fatalf("unexpected JSON schema: got %s, want %s", b, wantJSON)
+	}
+
+	tcs := []struct {
+		name string
+		data map[string]any
+		want tools.ParamValues
+		err  string
+	}{
+		{
+			name: "values",
+			data: map[string]any{"name": "Alice", "middle_name": "Jane", "manager_id": json.Number("7")},
+			want: tools.ParamValues{{Name: "name", Value: "Alice"}, {Name: "middle_name", Value: "Jane"}, {Name: "manager_id", Value: 7}},
+		},
+		{
+			name: "nulls",
+			data: map[string]any{"name": "Alice", "middle_name": nil, "manager_id": nil},
+			want: tools.ParamValues{{Name: "name", Value: "Alice"}, {Name: "middle_name", Value: nil}, {Name: "manager_id", Value: nil}},
+		},
+		{
+			name: "omitted parameter with a default",
+			data: map[string]any{"name": "Alice", "middle_name": nil},
+			want: tools.ParamValues{{Name: "name", Value: "Alice"}, {Name: "middle_name", Value: nil}, {Name: "manager_id", Value: 1}},
+		},
+		{
+			name: "omitted required parameter",
+			data: map[string]any{"name": "Alice"},
+			err:  `parameter "middle_name" is required`,
+		},
+		{
+			name: "null for a parameter that isn't nullable",
+			data: map[string]any{"name": nil, "middle_name": nil},
+			err:  `unable to parse value for "name": value can't be null, unless the parameter is nullable`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ParseParams(context.Background(), params, tc.data, nil)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected params: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestNullableObjectProperties(t *testing.T) {
+	middleName := tools.NewStringParameter("middle_name", "middle name of the user")
+	middleName.Nullable = true
+	manager := tools.NewIntParameter("manager_id", "id of the manager")
+	manager.Nullable = true
+	manager.Default = uint64(1)
+	params := tools.Parameters{tools.NewObjectParameter("user", "the user",
+		tools.NewStringParameter("name", "name of the user"),
+		middleName,
+		manager,
+	)}
+
+	// nullable properties have null among their types too, and null is
+	// added to their allowed values
+	middleName.AllowedValues = []string{"Jane"}
+	b, err := json.Marshal(params[0].McpManifest().Properties["middle_name"])
+	if err != nil {
+		t.Fatalf("unable to marshal schema: %s", err)
+	}
+	wantJSON := `{"type":["string","null"],"enum":["Jane",null],"description":"middle name of the user"}`
+	if string(b) != wantJSON {
+		t.Fatalf("unexpected JSON schema: got %s, want %s", b, wantJSON)
+	}
+	middleName.AllowedValues = nil
+
+	tcs := []struct {
+		name string
+		in   map[string]any
+		want map[string]any
+		err  string
+	}{
+		{
+			name: "nulls",
+			in:   map[string]any{"name": "Alice", "middle_name": nil, "manager_id": nil},
+			want: map[string]any{"name": "Alice", "middle_name": nil, "manager_id": nil},
+		},
+		{
+			name: "omitted property with a default",
+			in:   map[string]any{"name": "Alice", "middle_name": nil},
+			want: map[string]any{"name": "Alice", "middle_name": nil, "manager_id": 1},
+		},
+		{
+			name: "omitted required property",
+			in:   map[string]any{"name": "Alice"},
+			err:  `unable to parse value for "user": parameter user.middle_name is required`,
+		},
+		{
+			name: "null for a property that isn't nullable",
+			in:   map[string]any{"name": nil, "middle_name": nil},
+			err:  `unable to parse value for "user": parameter user.name can't be null, unless the property is nullable`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ParseParams(context.Background(), params, map[string]any{"user": tc.in}, nil)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			want := tools.ParamValues{{Name: "user", Value: tc.want}}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected params: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFromAuthClaimParameters(t *testing.T) {
+	email := tools.NewStringParameter("email", "email of the user")
+	email.FromAuthClaim = "email"
+	params := tools.Parameters{
+		tools.NewIntParameter("limit", "maximum number of rows"),
+		email,
+	}
+
+	// clients can't provide the parameter, so it's left out of the schema
+	wantSchema := tools.McpToolsSchema{
+		Type: "object",
+		Properties: map[string]tools.ParameterMcpManifest{
+			"limit": {Type: "integer", Description: "maximum number of rows"},
+		},
+		Required: []string{"limit"},
+	}
+	if diff := cmp.Diff(wantSchema, params.McpManifest()); diff != "" {
+		t.Fatalf("unexpected schema: diff %v", diff)
+	}
+
+	claims := map[string]map[string]any{"my-google-auth-service": {"email": "alice@example.com"}}
+	got, err := tools.ParseParams(context.Background(), params, map[string]any{"limit": 10}, claims)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.ParamValues{{Name: "limit", Value: 10}, {Name: "email", Value: "alice@example.com"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected params: diff %v", diff)
+	}
+
+	tcs := []struct {
+		name    string
+		data    map[string]any
+		claims  map[string]map[string]any
+		wantErr error
+	}{
+		{
+			name:   "overridden by the client",
+			data:   map[string]any{"limit": 10, "email": "bob@example.com"},
+			claims: claims,
+		},
+		{
+			name:    "unauthenticated",
+			data:    map[string]any{"limit": 10},
+			wantErr: tools.ErrUnauthenticated,
+		},
+		{
+			name:   "missing claim",
+			data:   map[string]any{"limit": 10},
+			claims: map[string]map[string]any{"my-google-auth-service": {"sub": "alice"}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tools.ParseParams(context.Background(), params, tc.data, tc.claims)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+				t.Fatalf("unexpected error: got %q, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckFromAuthClaims(t *testing.T) {
+	email := tools.NewStringParameter("email", "email of the user")
+	email.FromAuthClaim = "email"
+	both := tools.NewStringParameterWithAuth("email", "email of the user", []tools.ParamAuthService{{Name: "my-google-auth-service", Field: "email"}})
+	both.FromAuthClaim = "email"
+
+	tcs := []struct {
+		name     string
+		manifest tools.Manifest
+		wantErr  bool
+	}{
+		{
+			name: "auth required",
+			manifest: tools.Manifest{
+				Parameters:   []tools.ParameterManifest{email.Manifest()},
+				AuthRequired: []string{"my-google-auth-service"},
+			},
+		},
+		{
+			name:     "no auth required",
+			manifest: tools.Manifest{Parameters: []tools.ParameterManifest{email.Manifest()}},
+			wantErr:  true,
+		},
+		{
+			name: "with auth services",
+			manifest: tools.Manifest{
+				Parameters:   []tools.ParameterManifest{both.Manifest()},
+				AuthRequired: []string{"my-google-auth-service"},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "no parameters from claims",
+			manifest: tools.Manifest{Parameters: []tools.ParameterManifest{tools.NewIntParameter("limit", "maximum number of rows").Manifest()}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tools.CheckFromAuthClaims(tc.manifest)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: got %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultArrayAndObjectParameters(t *testing.T) {
+	tags := tools.NewArrayParameter("tags", "tags of the users", tools.NewStringParameter("tag", "tag of a user"))
+	tags.Default = []any{"admin", "staff"}
+	filter := tools.NewObjectParameter("filter", "filter on the users",
+		tools.NewStringParameter("name", "name of the user"),
+		tools.NewIntParameter("age", "age of the user"),
+	)
+	filter.Default = map[string]any{"name": "alice", "age": uint64(30)}
+	params := tools.Parameters{tags, filter}
+
+	// the defaults are parsed against the item and field types when omitted
+	got, err := tools.ParseParams(context.Background(), params, map[string]any{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.ParamValues{
+		{Name: "tags", Value: []any{"admin", "staff"}},
+		{Name: "filter", Value: map[string]any{"name": "alice", "age": 30}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected params: diff %v", diff)
+	}
+
+	// the bound default is a copy, so that invocations can't change it
+	got[0].Value.([]any)[0] = "changed"
+	if diff := cmp.Diff([]any{"admin", "staff"}, tags.Default); diff != "" {
+		t.Fatalf("default was modified: diff %v", diff)
+	}
+
+	got, err = tools.ParseParams(context.Background(), params, map[string]any{"tags": []any{"guest"}, "filter": map[string]any{"name": "bob", "age": json.Number("40")}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want = tools.ParamValues{
+		{Name: "tags", Value: []any{"guest"}},
+		{Name: "filter", Value: map[string]any{"name": "bob", "age": 40}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected params: diff %v", diff)
+	}
+}
+
 func TestFailParametersUnmarshal(t *testing.T) {
 	ctx, err := testutils.ContextWithNewLogger()
 	if err != nil {
@@ -959,6 +2018,106 @@ func TestFailParametersUnmarshal(t *testing.T) {
 			},
 			err: "unable to parse as \"array\": unable to parse 'items' field: unable to parse as \"string\": Key: 'CommonParameter.Name' Error:Field validation for 'Name' failed on the 'required' tag",
 		},
+		{
+			name: "string parameter with invalid case",
+			in: []map[string]any{
+				{
+					"name":        "my_string",
+					"type":        "string",
+					"description": "this param is a string",
+					"case":        "title",
+				},
+			},
+			err: "unable to parse as \"string\": [1:7] Key: 'StringParameter.Case' Error:Field validation for 'Case' failed on the 'oneof' tag\n>  1 | case: title\n             ^\n   2 | description: this param is a string\n   3 | name: my_string\n   4 | type: string",
+		},
+		{
+			name: "int parameter with string default",
+			in: []map[string]any{
+				{
+					"name":        "limit",
+					"type":        "integer",
+					"description": "maximum number of rows",
+					"default":     "x",
+				},
+			},
+			err: `invalid default for parameter "limit": "x" not type "integer"`,
+		},
+		{
+			name: "int parameter with min greater than max",
+			in: []map[string]any{
+				{
+					"name":        "id",
+					"type":        "integer",
+					"description": "id of the user",
+					"min":         10,
+					"max":         1,
+				},
+			},
+			err: `invalid bounds for parameter "id": min 10 is greater than max 1`,
+		},
+		{
+			name: "string parameter with allowed values and values query",
+			in: []map[string]any{
+				{
+					"name":          "region",
+					"type":          "string",
+					"description":   "region of the instance",
+					"allowedValues": []string{"us-east1"},
+					"valuesQuery": map[string]any{
+						"source":    "my-pg-instance",
+						"statement": "SELECT name FROM regions",
+					},
+				},
+			},
+			err: `parameter "region" can't set both allowedValues and valuesQuery`,
+		},
+		{
+			name: "object parameter without properties",
+			in: []map[string]any{
+				{
+					"name":        "filter",
+					"type":        "object",
+					"description": "filter on the users",
+				},
+			},
+			err: `unable to parse as "object": object parameter "filter" must declare its 'properties'`,
+		},
+		{
+			name: "array parameter with invalid default item",
+			in: []map[string]any{
+				{
+					"name":        "my_array",
+					"type":        "array",
+					"description": "this param is an array of integers",
+					"default":     []any{1, "two"},
+					"items": map[string]string{
+						"name":        "my_int",
+						"type":        "integer",
+						"description": "int item",
+					},
+				},
+			},
+			err: `invalid default for parameter "my_array": unable to parse element #1: "two" not type "integer"`,
+		},
+		{
+			name: "object parameter with invalid default field",
+			in: []map[string]any{
+				{
+					"name":        "filter",
+					"type":        "object",
+					"description": "filter on the users",
+					"default":     map[string]any{"age": "old"},
+					"properties": []map[string]any{
+						{
+							"name":        "age",
+							"type":        "integer",
+							"description": "age of the user",
+						},
+					},
+				},
+			},
+			err: `invalid default for parameter "filter": parameter filter.age must be an integer`,
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1215,6 +2374,19 @@ func TestResolveTemplateParameters(t *testing.T) {
 			},
 			want: "SELECT * FROM hotels WHERE name = $1",
 		},
+		{
+			name: "lower and default helpers",
+			templateParams: tools.Parameters{
+				tools.NewStringParameter("tableName", "this is a string template parameter"),
+				tools.NewStringParameter("orderBy", "this is a string template parameter"),
+			},
+			statement: "SELECT * FROM {{lower .tableName}} ORDER BY {{.orderBy | default \"id\"}}",
+			in: map[string]any{
+				"tableName": "HOTELS",
+				"orderBy":   "",
+			},
+			want: "SELECT * FROM hotels ORDER BY id",
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1226,6 +2398,189 @@ func TestResolveTemplateParameters(t *testing.T) {
 	}
 }
 
+func TestResolveNormalizedTemplateParameters(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := []map[string]any{
+		{
+			"name":        "tableName",
+			"type":        "string",
+			"description": "table to select from",
+			"trim":        true,
+			"case":        "lower",
+		},
+	}
+	data, err := yaml.Marshal(in)
+	if err != nil {
+		t.Fatalf("unable to marshal input to yaml: %s", err)
+	}
+	var templateParams tools.Parameters
+	if err := yaml.UnmarshalContext(ctx, data, &templateParams); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+
+	// a padded, mixed-case table name is normalized before the statement is resolved
+	params, err := tools.ParseParams(context.Background(), templateParams, map[string]any{"tableName": "  Hotels\n"}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	got, err := tools.ResolveTemplateParams(templateParams, "SELECT * FROM {{.tableName}}", params.AsMap())
+	if err != nil {
+		t.Fatalf("unable to resolve template params: %s", err)
+	}
+	if want := "SELECT * FROM hotels"; got != want {
+		t.Fatalf("incorrect resolved statement: got %q, want %q", got, want)
+	}
+}
+
+func TestResolveTemplateParamsWithBinds(t *testing.T) {
+	templateParams := tools.Parameters{
+		tools.NewArrayParameter("names", "this is an array template parameter", tools.NewStringParameter("name", "a name")),
+	}
+	statement := "SELECT * FROM hotels WHERE id = $1 AND name IN ({{join .names}})"
+	in := map[string]any{
+		"names": []any{"Hilton", "'; DROP TABLE hotels; --"},
+	}
+	placeholder := func(i int) string { return fmt.Sprintf("$%d", i) }
+
+	got, binds, err := tools.ResolveTemplateParamsWithBinds(templateParams, statement, in, placeholder, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "SELECT * FROM hotels WHERE id = $1 AND name IN ($2, $3)"; got != want {
+		t.Fatalf("unexpected statement: got %q, want %q", got, want)
+	}
+	if diff := cmp.Diff(in["names"], binds); diff != "" {
+		t.Fatalf("incorrect binds: diff %v", diff)
+	}
+}
+
+func TestResolveTemplateParamsWithValues(t *testing.T) {
+	templateParams := tools.Parameters{
+		tools.NewArrayParameter("users", "the users to insert", tools.NewObjectParameter("user", "a user",
+			tools.NewIntParameter("id", "the id of the user"),
+			tools.NewStringParameter("name", "the name of the user"),
+		)),
+	}
+	statement := `INSERT INTO users (id, name) VALUES {{values .users "id" "name"}}`
+	in := map[string]any{
+		"users": []any{
+			map[string]any{"id": 1, "name": "Alice"},
+			map[string]any{"id": 2},
+		},
+	}
+	placeholder := func(i int) string { return fmt.Sprintf("$%d", i) }
+
+	got, binds, err := tools.ResolveTemplateParamsWithBinds(templateParams, statement, in, placeholder, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "INSERT INTO users (id, name) VALUES ($1, $2), ($3, $4)"; got != want {
+		t.Fatalf("unexpected statement: got %q, want %q", got, want)
+	}
+	// missing fields are bound as nil
+	if diff := cmp.Diff([]any{1, "Alice", 2, nil}, binds); diff != "" {
+		t.Fatalf("incorrect binds: diff %v", diff)
+	}
+
+	tcs := []struct {
+		name      string
+		statement string
+		in        map[string]any
+		err       string
+	}{
+		{
+			name:      "no fields",
+			statement: `VALUES {{values .users}}`,
+			in:        in,
+			err:       "values expects the fields of the rows",
+		},
+		{
+			name:      "empty array",
+			statement: `VALUES {{values .users "id"}}`,
+			in:        map[string]any{"users": []any{}},
+			err:       "values expects a non-empty array",
+		},
+		{
+			name:      "not objects",
+			statement: `VALUES {{values .users "id"}}`,
+			in:        map[string]any{"users": []any{"Alice"}},
+			err:       "values expects an array of objects, item 0 is string",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := tools.ResolveTemplateParamsWithBinds(templateParams, tc.statement, tc.in, placeholder, 0)
+			if err == nil || !strings.HasSuffix(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestResolveTemplateParamsWithRepeat(t *testing.T) {
+	templateParams := tools.Parameters{
+		tools.NewStringParameter("tableName", "this is a string template parameter"),
+		tools.NewArrayParameter("columns", "this is an array template parameter", tools.NewStringParameter("column", "a column")),
+	}
+	tcs := []struct {
+		name      string
+		statement string
+		columns   []any
+		want      string
+	}{
+		{
+			name:      "no items",
+			statement: `INSERT INTO {{.tableName}} ({{array .columns}}) VALUES ({{repeat .columns "?"}})`,
+			columns:   []any{},
+			want:      "INSERT INTO hotels () VALUES ()",
+		},
+		{
+			name:      "one item",
+			statement: `INSERT INTO {{.tableName}} ({{array .columns}}) VALUES ({{repeat .columns "?"}})`,
+			columns:   []any{"name"},
+			want:      "INSERT INTO hotels (name) VALUES (?)",
+		},
+		{
+			name:      "three items",
+			statement: `INSERT INTO {{.tableName}} ({{array .columns}}) VALUES ({{repeat .columns "?"}})`,
+			columns:   []any{"id", "name", "location"},
+			want:      "INSERT INTO hotels (id, name, location) VALUES (?, ?, ?)",
+		},
+		{
+			name:      "three items with positions",
+			statement: `UPDATE {{.tableName}} SET {{repeat .columns "{item} = ${n}"}}`,
+			columns:   []any{"id", "name", "location"},
+			want:      "UPDATE hotels SET id = $1, name = $2, location = $3",
+		},
+		{
+			name:      "separator",
+			statement: `SELECT * FROM {{.tableName}} WHERE {{repeat .columns "{item} IS NOT NULL" " AND "}}`,
+			columns:   []any{"id", "name", "location"},
+			want:      "SELECT * FROM hotels WHERE id IS NOT NULL AND name IS NOT NULL AND location IS NOT NULL",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			in := map[string]any{"tableName": "hotels", "columns": tc.columns}
+			got, err := tools.ResolveTemplateParams(templateParams, tc.statement, in)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected statement: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	_, err := tools.ResolveTemplateParams(templateParams, `SELECT {{repeat .tableName "?"}}`, map[string]any{"tableName": "hotels", "columns": []any{}})
+	if err == nil || !strings.HasSuffix(err.Error(), "repeat expects an array, got string") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestFailResolveTemplateParameters(t *testing.T) {
 	tcs := []struct {
 		name           string
@@ -1276,6 +2631,28 @@ func TestFailResolveTemplateParameters(t *testing.T) {
 			},
 			err: "error executing go template template: statement:1:16: executing \"statement\" at <.tableName>: tableName is not a method but has arguments",
 		},
+		{
+			name: "values without binding",
+			templateParams: tools.Parameters{
+				tools.NewArrayParameter("users", "this is an array template parameter", tools.NewObjectParameter("user", "a user", tools.NewIntParameter("id", "an id"))),
+			},
+			statement: `INSERT INTO users (id) VALUES {{values .users "id"}}`,
+			in: map[string]any{
+				"users": []any{map[string]any{"id": 1}},
+			},
+			err: "error executing go template template: statement:1:32: executing \"statement\" at <values .users \"id\">: error calling values: values is not supported by this tool",
+		},
+		{
+			name: "join without binding",
+			templateParams: tools.Parameters{
+				tools.NewArrayParameter("names", "this is an array template parameter", tools.NewStringParameter("name", "a name")),
+			},
+			statement: "SELECT * FROM hotels WHERE name IN ({{join .names}})",
+			in: map[string]any{
+				"names": []any{"Hilton"},
+			},
+			err: "error executing go template template: statement:1:38: executing \"statement\" at <join .names>: error calling join: join is not supported by this tool",
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1287,3 +2664,21 @@ func TestFailResolveTemplateParameters(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveJSONTemplate(t *testing.T) {
+	payload := `{"name": {{.name}}, "age": {"$gte": {{.age}}}, "tags": {"$in": {{.tags}}}}`
+	params := map[string]any{"name": `Alice", "admin": "true`, "age": 18, "tags": []any{"a", "b"}}
+	got, err := tools.ResolveJSONTemplate("filter", payload, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// values are escaped, so they can't add fields to the document
+	want := `{"name": "Alice\", \"admin\": \"true", "age": {"$gte": 18}, "tags": {"$in": ["a","b"]}}`
+	if got != want {
+		t.Fatalf("unexpected result: got %s, want %s", got, want)
+	}
+
+	if _, err := tools.ResolveJSONTemplate("filter", `{"name": {{.missing}}}`, params); err == nil {
+		t.Fatalf("expected a missing parameter to be rejected")
+	}
+}
@@ -17,6 +17,9 @@ package postgressql
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -24,6 +27,8 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
 	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -59,10 +64,23 @@ type Config struct {
 	Kind               string           `yaml:"kind" validate:"required"`
 	Source             string           `yaml:"source" validate:"required"`
 	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
+	Statement          string           `yaml:"statement" validate:"required_without=Statements"`
 	AuthRequired       []string         `yaml:"authRequired"`
 	Parameters         tools.Parameters `yaml:"parameters"`
 	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	ExplainAnalyze     bool             `yaml:"explainAnalyze"`
+	// Statements are run in order in a single transaction instead of
+	// Statement, which is committed if all of them succeed and rolled back
+	// otherwise. The parameters are shared by all statements.
+	Statements []string `yaml:"statements" validate:"excluded_with=Statement,dive,required"`
+	// ResultStatement is the position, starting at 1, of the statement of
+	// Statements whose rows are returned. The rows of the last statement are
+	// returned if it is 0.
+	ResultStatement int `yaml:"resultStatement" validate:"gte=0"`
+	// NotifyChannel is a channel that the database sends NOTIFY events on when
+	// the result of the tool changes. MCP clients subscribed to the tool's
+	// resource are then updated without polling.
+	NotifyChannel string `yaml:"notifyChannel"`
 }
 
 // validate interface
@@ -85,6 +103,21 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
+	if err := tools.BindValuesQueries(srcs, cfg.Parameters, cfg.TemplateParameters); err != nil {
+		return nil, err
+	}
+
+	if cfg.ResultStatement > len(cfg.Statements) {
+		return nil, fmt.Errorf("resultStatement %d is out of range, the tool has %d statements", cfg.ResultStatement, len(cfg.Statements))
+	}
+	if cfg.ExplainAnalyze && len(cfg.Statements) > 0 {
+		return nil, fmt.Errorf("explainAnalyze requires a single statement")
+	}
+
+	if cfg.NotifyChannel != "" && (len(cfg.Parameters) > 0 || len(cfg.TemplateParameters) > 0) {
+		return nil, fmt.Errorf("notifyChannel requires a tool without parameters, as only those are served as resources")
+	}
+
 	allParameters, paramManifest, paramMcpManifest := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
 
 	mcpManifest := tools.McpManifest{
@@ -101,6 +134,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		TemplateParameters: cfg.TemplateParameters,
 		AllParams:          allParameters,
 		Statement:          cfg.Statement,
+		Statements:         cfg.Statements,
+		ResultStatement:    cfg.ResultStatement,
+		ExplainAnalyze:     cfg.ExplainAnalyze,
+		NotifyChannel:      cfg.NotifyChannel,
 		AuthRequired:       cfg.AuthRequired,
 		Pool:               s.PostgresPool(),
 		manifest:           tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
@@ -111,6 +148,9 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 // validate interface
 var _ tools.Tool = Tool{}
+var _ tools.Notifier = Tool{}
+var _ tools.DryRunner = Tool{}
+var _ tools.Streamer = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -120,49 +160,380 @@ type Tool struct {
 	TemplateParameters tools.Parameters `yaml:"templateParameters"`
 	AllParams          tools.Parameters `yaml:"allParams"`
 
-	Pool        *pgxpool.Pool
-	Statement   string
-	manifest    tools.Manifest
-	mcpManifest tools.McpManifest
+	Pool            *pgxpool.Pool
+	Statement       string
+	Statements      []string
+	ResultStatement int
+	ExplainAnalyze  bool
+	NotifyChannel   string
+	manifest        tools.Manifest
+	mcpManifest     tools.McpManifest
 }
 
-func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+// bindPlaceholders renumbers the placeholders of statement from $1, in the
+// order of their numbers, and returns it with the values of params they bind.
+// This lets a statement bind any subset of params, as postgres requires every
+// value passed to a statement to be bound to a placeholder of it.
+func bindPlaceholders(statement string, params []any) (string, []any, error) {
+	placeholders := findPlaceholders(statement)
+	var used []int
+	for _, p := range placeholders {
+		n, err := strconv.Atoi(statement[p[0]+1 : p[1]])
+		if err != nil || n < 1 || n > len(params) {
+			return "", nil, fmt.Errorf("placeholder %s is out of range, the tool has %d parameters", statement[p[0]:p[1]], len(params))
+		}
+		if !slices.Contains(used, n) {
+			used = append(used, n)
+		}
+	}
+	slices.Sort(used)
+	binds := make([]any, len(used))
+	for i, n := range used {
+		binds[i] = params[n-1]
+	}
+	var b strings.Builder
+	last := 0
+	for _, p := range placeholders {
+		n, _ := strconv.Atoi(statement[p[0]+1 : p[1]])
+		fmt.Fprintf(&b, "%s$%d", statement[last:p[0]], slices.Index(used, n)+1)
+		last = p[1]
+	}
+	b.WriteString(statement[last:])
+	return b.String(), binds, nil
+}
+
+// findPlaceholders returns the start and end offsets of the placeholders of
+// statement, such as $1. Like postgres, it skips string literals, quoted
+// identifiers, comments and dollar-quoted strings, and identifiers, which may
+// contain '$'.
+func findPlaceholders(statement string) [][2]int {
+	var found [][2]int
+	for i := 0; i < len(statement); {
+		c := statement[i]
+		switch {
+		case c == '\'' || c == '"':
+			i = skipQuoted(statement, i, false)
+		case strings.HasPrefix(statement[i:], "--"):
+			if j := strings.IndexByte(statement[i:], '\n'); j >= 0 {
+				i += j + 1
+			} else {
+				i = len(statement)
+			}
+		case strings.HasPrefix(statement[i:], "/*"):
+			i = skipBlockComment(statement, i)
+		case c == '$':
+			j := i + 1
+			for j < len(statement) && isDigit(statement[j]) {
+				j++
+			}
+			if j > i+1 {
+				found = append(found, [2]int{i, j})
+				i = j
+			} else if tag, ok := dollarQuoteTag(statement[i:]); ok {
+				if k := strings.Index(statement[i+len(tag):], tag); k >= 0 {
+					i += k + 2*len(tag)
+				} else {
+					i = len(statement)
+				}
+			} else {
+				i++
+			}
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(statement) && (isIdentStart(statement[j]) || isDigit(statement[j]) || statement[j] == '$') {
+				j++
+			}
+			// E'...' strings may escape quotes with backslashes
+			if j == i+1 && (c == 'E' || c == 'e') && j < len(statement) && statement[j] == '\'' {
+				j = skipQuoted(statement, j, true)
+			}
+			i = j
+		default:
+			i++
+		}
+	}
+	return found
+}
+
+// skipQuoted returns the offset past the quoted string or identifier starting
+// at statement[i], whose quote is escaped by doubling it, or with a backslash
+// if backslashes is true.
+func skipQuoted(statement string, i int, backslashes bool) int {
+	quote := statement[i]
+	for j := i + 1; j < len(statement); j++ {
+		switch {
+		case backslashes && statement[j] == '\\':
+			j++
+		case statement[j] == quote:
+			if j+1 < len(statement) && statement[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(statement)
+}
+
+// skipBlockComment returns the offset past the block comment starting at
+// statement[i]. Like in postgres, block comments nest.
+func skipBlockComment(statement string, i int) int {
+	depth := 0
+	for j := i; j+1 < len(statement); j++ {
+		switch statement[j : j+2] {
+		case "/*":
+			depth++
+			j++
+		case "*/":
+			depth--
+			j++
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(statement)
+}
+
+// dollarQuoteTag returns the opening tag of the dollar-quoted string that s
+// starts with, such as $$ or $body$.
+func dollarQuoteTag(s string) (string, bool) {
+	for j := 1; j < len(s); j++ {
+		switch c := s[j]; {
+		case c == '$':
+			return s[:j+1], true
+		case isIdentStart(c), j > 1 && isDigit(c):
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// isIdentStart reports whether c may start an identifier. Bytes of non-ASCII
+// characters may, like in postgres.
+func isIdentStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c >= 0x80
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// resolve returns statement with its template parameters resolved, and the
+// values to bind to its placeholders.
+func (t Tool) resolve(statement string, params tools.ParamValues) (string, []any, error) {
 	paramsMap := params.AsMap()
-	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
+	newParams, err := tools.GetParams(t.Parameters, paramsMap)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to extract standard params %w", err)
+	}
+	// bind uuid parameters as the native postgres uuid type
+	sliceParams, err := tools.NativeUUIDValues(t.Parameters, newParams)
 	if err != nil {
-		return nil, fmt.Errorf("unable to extract template params %w", err)
+		return "", nil, err
+	}
+	if len(t.Statements) > 0 {
+		// the statements of a transaction share the parameters, and each
+		// binds those it references
+		if statement, sliceParams, err = bindPlaceholders(statement, sliceParams); err != nil {
+			return "", nil, err
+		}
 	}
 
-	newParams, err := tools.GetParams(t.Parameters, paramsMap)
+	placeholder := func(i int) string { return fmt.Sprintf("$%d", i) }
+	newStatement, binds, err := tools.ResolveTemplateParamsWithBinds(t.TemplateParameters, statement, paramsMap, placeholder, len(sliceParams))
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to extract template params %w", err)
+	}
+	return newStatement, append(sliceParams, binds...), nil
+}
+
+// DryRun returns the statement that would be run for params, without running
+// it.
+func (t Tool) DryRun(_ context.Context, params tools.ParamValues) (tools.DryRunResult, error) {
+	if len(t.Statements) > 0 {
+		return tools.DryRunResult{}, fmt.Errorf("dry runs of tools with several statements are not supported")
+	}
+	newStatement, sliceParams, err := t.resolve(t.Statement, params)
+	if err != nil {
+		return tools.DryRunResult{}, err
+	}
+	return tools.DryRunResult{Statement: newStatement, Parameters: params.AsMap(), Binds: sliceParams}, nil
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	if len(t.Statements) > 0 {
+		return t.invokeTransaction(ctx, params)
+	}
+	newStatement, sliceParams, err := t.resolve(t.Statement, params)
+	if err != nil {
+		return nil, err
+	}
+	if t.ExplainAnalyze {
+		return t.explainAnalyze(ctx, newStatement, sliceParams)
+	}
+	results, err := t.Pool.Query(ctx, newStatement, sliceParams...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	return collectRows(results)
+}
+
+// InvokeStream runs the statement and passes each row to yield as it is read.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues, yield func(row any) error) error {
+	if len(t.Statements) > 0 || t.ExplainAnalyze {
+		// the rows are only known once the transaction has completed
+		res, err := t.Invoke(ctx, params)
+		if err != nil {
+			return err
+		}
+		for _, row := range res {
+			if err := yield(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	newStatement, sliceParams, err := t.resolve(t.Statement, params)
 	if err != nil {
-		return nil, fmt.Errorf("unable to extract standard params %w", err)
+		return err
 	}
-	sliceParams := newParams.AsSlice()
 	results, err := t.Pool.Query(ctx, newStatement, sliceParams...)
 	if err != nil {
+		return fmt.Errorf("unable to execute query: %w", err)
+	}
+	return streamRows(results, yield)
+}
+
+// invokeTransaction runs the statements of the tool in a single transaction,
+// and returns the rows of its result statement. The transaction is rolled
+// back if any statement fails.
+func (t Tool) invokeTransaction(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	tx, err := t.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	// rolling back a committed transaction has no effect
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result := len(t.Statements)
+	if t.ResultStatement > 0 {
+		result = t.ResultStatement
+	}
+	var out []any
+	for i, statement := range t.Statements {
+		newStatement, sliceParams, err := t.resolve(statement, params)
+		if err != nil {
+			return nil, err
+		}
+		if i+1 != result {
+			if _, err := tx.Exec(ctx, newStatement, sliceParams...); err != nil {
+				return nil, fmt.Errorf("unable to execute statement %d: %w", i+1, err)
+			}
+			continue
+		}
+		results, err := tx.Query(ctx, newStatement, sliceParams...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to execute statement %d: %w", i+1, err)
+		}
+		if out, err = collectRows(results); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+	return out, nil
+}
+
+// explainTable is the temporary table the results of an explained statement
+// are written to.
+const explainTable = "toolbox_explain_analyze"
+
+// explainAnalyze runs the statement with EXPLAIN ANALYZE and records the plan
+// in the result metadata. The statement is executed only once: its results are
+// written to a temporary table, which is read back within the same
+// transaction.
+func (t Tool) explainAnalyze(ctx context.Context, statement string, params []any) ([]any, error) {
+	tx, err := t.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	// nothing is persisted, the temporary table is dropped on rollback
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	statement = strings.TrimRight(strings.TrimSpace(statement), ";")
+	explain := fmt.Sprintf("EXPLAIN (ANALYZE, FORMAT JSON) CREATE TEMPORARY TABLE %s AS %s", explainTable, statement)
+	var plan []any
+	if err := tx.QueryRow(ctx, explain, params...).Scan(&plan); err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
+	if len(plan) == 1 {
+		tools.SetMeta(ctx, "plan", plan[0])
+	} else {
+		tools.SetMeta(ctx, "plan", plan)
+	}
 
-	fields := results.FieldDescriptions()
+	results, err := tx.Query(ctx, "SELECT * FROM "+explainTable)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read query results: %w", err)
+	}
+	return collectRows(results)
+}
 
+// collectRows reads all rows into a slice of maps keyed by column name.
+func collectRows(results pgx.Rows) ([]any, error) {
 	var out []any
+	err := streamRows(results, func(row any) error {
+		out = append(out, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// streamRows passes each row to yield as a map keyed by column name.
+func streamRows(results pgx.Rows, yield func(row any) error) error {
+	defer results.Close()
+	fields := results.FieldDescriptions()
+
 	for results.Next() {
 		v, err := results.Values()
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse row: %w", err)
+			return fmt.Errorf("unable to parse row: %w", err)
 		}
 		vMap := make(map[string]any)
 		for i, f := range fields {
 			vMap[f.Name] = v[i]
 		}
-		out = append(out, vMap)
+		if err := yield(vMap); err != nil {
+			return err
+		}
 	}
+	if err := results.Err(); err != nil {
+		return fmt.Errorf("unable to read rows: %w", err)
+	}
+	return nil
+}
 
-	return out, nil
+// Notify listens on the tool's notifyChannel, if set.
+func (t Tool) Notify(ctx context.Context) (<-chan struct{}, bool) {
+	if t.NotifyChannel == "" {
+		return nil, false
+	}
+	onError := func(err error) {
+		if logger, lErr := util.LoggerFromContext(ctx); lErr == nil {
+			logger.WarnContext(ctx, fmt.Sprintf("lost connection listening on %q, listening again: %s", t.NotifyChannel, err))
+		}
+	}
+	return postgres.Listen(ctx, t.Pool, t.NotifyChannel, onError), true
 }
 
-func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
-	return tools.ParseParams(t.AllParams, data, claims)
+func (t Tool) ParseParams(ctx context.Context, data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(ctx, t.AllParams, data, claims)
 }
 
 func (t Tool) Manifest() tools.Manifest {
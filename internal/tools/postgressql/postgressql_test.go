@@ -15,6 +15,14 @@
 package postgressql_test
 
 import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
@@ -23,6 +31,10 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/postgressql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 func TestParseFromYamlPostgres(t *testing.T) {
@@ -74,6 +86,82 @@ func TestParseFromYamlPostgres(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "explain analyze",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					explainAnalyze: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgressql.Config{
+					Name:           "example_tool",
+					Kind:           "postgres-sql",
+					Source:         "my-pg-instance",
+					Description:    "some description",
+					Statement:      "SELECT * FROM SQL_STATEMENT;\n",
+					AuthRequired:   []string{},
+					ExplainAnalyze: true,
+				},
+			},
+		},
+		{
+			desc: "notify channel",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM orders;
+					notifyChannel: orders_changed
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgressql.Config{
+					Name:          "example_tool",
+					Kind:          "postgres-sql",
+					Source:        "my-pg-instance",
+					Description:   "some description",
+					Statement:     "SELECT * FROM orders;\n",
+					AuthRequired:  []string{},
+					NotifyChannel: "orders_changed",
+				},
+			},
+		},
+		{
+			desc: "statements",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statements:
+						- INSERT INTO orders (id, quantity) VALUES ($1, $2);
+						- UPDATE stock SET count = count - $2 WHERE id = $1;
+					resultStatement: 1
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgressql.Config{
+					Name:        "example_tool",
+					Kind:        "postgres-sql",
+					Source:      "my-pg-instance",
+					Description: "some description",
+					Statements: []string{
+						"INSERT INTO orders (id, quantity) VALUES ($1, $2);",
+						"UPDATE stock SET count = count - $2 WHERE id = $1;",
+					},
+					ResultStatement: 1,
+					AuthRequired:    []string{},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -147,6 +235,39 @@ func TestParseFromYamlWithTemplateParamsPostgres(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "join helper",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM hotels WHERE name IN ({{join .names}});
+					templateParameters:
+						- name: names
+						  type: array
+						  description: The names of the hotels.
+						  items:
+								name: name
+								type: string
+								description: A hotel name.
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgressql.Config{
+					Name:         "example_tool",
+					Kind:         "postgres-sql",
+					Source:       "my-pg-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM hotels WHERE name IN ({{join .names}});\n",
+					AuthRequired: []string{},
+					TemplateParameters: []tools.Parameter{
+						tools.NewArrayParameter("names", "The names of the hotels.", tools.NewStringParameter("name", "A hotel name.")),
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -165,3 +286,310 @@ func TestParseFromYamlWithTemplateParamsPostgres(t *testing.T) {
 	}
 
 }
+
+func TestDryRun(t *testing.T) {
+	tool := postgressql.Tool{
+		Name:               "example_tool",
+		Kind:               "postgres-sql",
+		Parameters:         tools.Parameters{tools.NewStringParameter("country", "some description")},
+		TemplateParameters: tools.Parameters{tools.NewStringParameter("tableName", "The table to select hotels from.")},
+		Statement:          "SELECT * FROM {{.tableName}} WHERE country = $1",
+	}
+	params := tools.ParamValues{
+		{Name: "country", Value: "US"},
+		{Name: "tableName", Value: "hotels"},
+	}
+	got, err := tool.DryRun(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.DryRunResult{
+		Statement:  "SELECT * FROM hotels WHERE country = $1",
+		Parameters: map[string]any{"country": "US", "tableName": "hotels"},
+		Binds:      []any{"US"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect dry run: diff %v", diff)
+	}
+}
+
+// fakePostgres is a postgres server that supports transactions. Statements
+// starting with INSERT add a row, a statement starting with FAIL fails, and
+// other statements return the number of rows as a column named count. Rows
+// added in a transaction are only kept once it is committed. Like postgres,
+// it rejects statements that aren't bound a value for each placeholder.
+type fakePostgres struct {
+	mu        sync.Mutex
+	committed int
+	pending   int
+	args      [][]int64
+}
+
+// serve accepts connections on ln until it is closed.
+func (p *fakePostgres) serve(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go p.serveConn(conn)
+	}
+}
+
+func (p *fakePostgres) serveConn(conn net.Conn) {
+	defer conn.Close()
+	backend := pgproto3.NewBackend(conn, conn)
+	if _, err := backend.ReceiveStartupMessage(); err != nil {
+		return
+	}
+	backend.Send(&pgproto3.AuthenticationOk{})
+	backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+	if err := backend.Flush(); err != nil {
+		return
+	}
+
+	var query string
+	var bind *pgproto3.Bind
+	// failed is set once a message of the current extended query failed,
+	// until the following sync
+	failed := false
+	txStatus := byte('I')
+	fail := func(msg string) {
+		backend.Send(&pgproto3.ErrorResponse{Severity: "ERROR", Code: "XX000", Message: msg})
+		failed = true
+		if txStatus == 'T' {
+			txStatus = 'E'
+		}
+	}
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			return
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.Query:
+			switch strings.ToLower(msg.String) {
+			case "begin":
+				txStatus = 'T'
+			case "commit":
+				p.mu.Lock()
+				p.committed += p.pending
+				p.pending = 0
+				p.mu.Unlock()
+				txStatus = 'I'
+			case "rollback":
+				p.mu.Lock()
+				p.pending = 0
+				p.mu.Unlock()
+				txStatus = 'I'
+			default:
+				fail("unexpected simple query " + msg.String)
+			}
+			if !failed {
+				backend.Send(&pgproto3.CommandComplete{CommandTag: []byte(strings.ToUpper(msg.String))})
+			}
+			failed = false
+			backend.Send(&pgproto3.ReadyForQuery{TxStatus: txStatus})
+		case *pgproto3.Parse:
+			if failed {
+				continue
+			}
+			query = msg.Query
+			backend.Send(&pgproto3.ParseComplete{})
+		case *pgproto3.Bind:
+			if failed {
+				continue
+			}
+			if want := lastPlaceholder(query); len(msg.Parameters) != want {
+				fail(fmt.Sprintf("bind message supplies %d parameters, but prepared statement %q requires %d", len(msg.Parameters), "", want))
+				continue
+			}
+			bind = msg
+			backend.Send(&pgproto3.BindComplete{})
+		case *pgproto3.Describe:
+			if failed {
+				continue
+			}
+			if strings.HasPrefix(query, "INSERT") || strings.HasPrefix(query, "FAIL") {
+				backend.Send(&pgproto3.NoData{})
+				continue
+			}
+			backend.Send(&pgproto3.RowDescription{Fields: []pgproto3.FieldDescription{
+				{Name: []byte("count"), DataTypeOID: pgtype.Int8OID, DataTypeSize: 8, TypeModifier: -1, Format: resultFormat(bind)},
+			}})
+		case *pgproto3.Execute:
+			if failed {
+				continue
+			}
+			p.mu.Lock()
+			p.args = append(p.args, bindArgs(bind))
+			count := p.committed + p.pending
+			if strings.HasPrefix(query, "INSERT") {
+				p.pending++
+			}
+			p.mu.Unlock()
+			switch {
+			case strings.HasPrefix(query, "FAIL"):
+				fail("statement failed")
+			case strings.HasPrefix(query, "INSERT"):
+				backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("INSERT 0 1")})
+			default:
+				value := []byte(strconv.Itoa(count))
+				if resultFormat(bind) == pgtype.BinaryFormatCode {
+					value = binary.BigEndian.AppendUint64(nil, uint64(count))
+				}
+				backend.Send(&pgproto3.DataRow{Values: [][]byte{value}})
+				backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("SELECT 1")})
+			}
+		case *pgproto3.Sync:
+			failed = false
+			backend.Send(&pgproto3.ReadyForQuery{TxStatus: txStatus})
+		case *pgproto3.Terminate:
+			return
+		}
+		if err := backend.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// lastPlaceholder returns the highest placeholder number in query, outside of
+// its string literals, comments and dollar-quoted strings.
+func lastPlaceholder(query string) int {
+	last := 0
+	query = regexp.MustCompile(`(?s)'[^']*'|--[^\n]*|/\*.*?\*/|\$\$.*?\$\$`).ReplaceAllString(query, "")
+	for _, m := range regexp.MustCompile(`\$(\d+)`).FindAllStringSubmatch(query, -1) {
+		if n, _ := strconv.Atoi(m[1]); n > last {
+			last = n
+		}
+	}
+	return last
+}
+
+// resultFormat returns the format the results of bind are requested in.
+func resultFormat(bind *pgproto3.Bind) int16 {
+	if len(bind.ResultFormatCodes) > 0 {
+		return bind.ResultFormatCodes[0]
+	}
+	return pgtype.TextFormatCode
+}
+
+// bindArgs decodes the integer parameters of bind.
+func bindArgs(bind *pgproto3.Bind) []int64 {
+	var args []int64
+	for i, v := range bind.Parameters {
+		format := int16(pgtype.TextFormatCode)
+		switch len(bind.ParameterFormatCodes) {
+		case 1:
+			format = bind.ParameterFormatCodes[0]
+		case len(bind.Parameters):
+			format = bind.ParameterFormatCodes[i]
+		}
+		if format == pgtype.BinaryFormatCode {
+			args = append(args, int64(binary.BigEndian.Uint64(v)))
+			continue
+		}
+		n, _ := strconv.ParseInt(string(v), 10, 64)
+		args = append(args, n)
+	}
+	return args
+}
+
+func TestInvokeTransaction(t *testing.T) {
+	ctx := context.Background()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	defer ln.Close()
+	p := &fakePostgres{}
+	go p.serve(ln)
+
+	config, err := pgxpool.ParseConfig(fmt.Sprintf("postgres://toolbox@%s/toolbox?sslmode=disable", ln.Addr()))
+	if err != nil {
+		t.Fatalf("unable to parse pool config: %s", err)
+	}
+	// bind the parameters with the extended protocol, as they are with a
+	// statement cache, without preparing the statements
+	config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeExec
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("unable to create pool: %s", err)
+	}
+	defer pool.Close()
+
+	newTool := func(statements []string, resultStatement int) postgressql.Tool {
+		return postgressql.Tool{
+			Name: "place_order",
+			Kind: "postgres-sql",
+			Parameters: tools.Parameters{
+				tools.NewIntParameter("id", "The id of the order."),
+				tools.NewIntParameter("quantity", "The quantity ordered."),
+			},
+			Pool:            pool,
+			Statements:      statements,
+			ResultStatement: resultStatement,
+		}
+	}
+	params := tools.ParamValues{{Name: "id", Value: 1}, {Name: "quantity", Value: 2}}
+
+	// a failing statement rolls back the statements before it
+	tool := newTool([]string{
+		"INSERT INTO orders (id, quantity) VALUES ($1, $2)",
+		"INSERT INTO order_events (order_id) VALUES ($1)",
+		"FAIL",
+		"SELECT count(*) AS count FROM orders",
+	}, 0)
+	if _, err := tool.Invoke(ctx, params); err == nil || !strings.Contains(err.Error(), "unable to execute statement 3") {
+		t.Fatalf("unexpected error: got %v, want the third statement to fail", err)
+	}
+	if p.committed != 0 {
+		t.Fatalf("expected the failed transaction to be rolled back, got %d committed rows", p.committed)
+	}
+
+	// parameters are shared, and each statement is bound those whose
+	// placeholders it references
+	p.args = nil
+	tool = newTool([]string{
+		"INSERT INTO orders (id, quantity) VALUES ($1, $2)",
+		"SELECT count(*) AS count FROM orders",
+		"INSERT INTO order_events (quantity) VALUES ($2)",
+	}, 2)
+	res, err := tool.Invoke(ctx, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if p.committed != 2 {
+		t.Fatalf("expected the transaction to be committed, got %d committed rows", p.committed)
+	}
+	if diff := cmp.Diff([]any{map[string]any{"count": int64(1)}}, res); diff != "" {
+		t.Fatalf("unexpected result of the result statement: diff %v", diff)
+	}
+	wantArgs := [][]int64{{1, 2}, nil, {2}}
+	if diff := cmp.Diff(wantArgs, p.args); diff != "" {
+		t.Fatalf("incorrect args: diff %v", diff)
+	}
+
+	// dollar signs in string literals, comments and dollar-quoted strings
+	// aren't placeholders
+	p.args = nil
+	tool = newTool([]string{
+		"INSERT INTO order_events (quantity, note) VALUES ($2, 'costs $5') -- was $4",
+		"INSERT INTO order_events (note, quantity) VALUES (/* $3 */ 'it''s $3', $2)",
+		"INSERT INTO order_events (note, quantity) VALUES ($$ $3 $$, $2)",
+		"INSERT INTO order_events (note, quantity) VALUES ($body$ '$3' $body$, $2)",
+	}, 0)
+	if _, err := tool.Invoke(ctx, params); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantArgs = [][]int64{{2}, {2}, {2}, {2}}
+	if diff := cmp.Diff(wantArgs, p.args); diff != "" {
+		t.Fatalf("incorrect args: diff %v", diff)
+	}
+
+	// placeholders beyond the parameters are rejected
+	tool = newTool([]string{"INSERT INTO orders (id) VALUES ($3)"}, 0)
+	if _, err := tool.Invoke(ctx, params); err == nil || !strings.Contains(err.Error(), "placeholder $3 is out of range") {
+		t.Fatalf("unexpected error: got %v, want an out of range placeholder", err)
+	}
+}
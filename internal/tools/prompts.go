@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"text/template"
+)
+
+// PromptConfig is the configuration of a reusable prompt template, served to
+// MCP clients with prompts/list and prompts/get.
+type PromptConfig struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Arguments are the arguments of the prompt. MCP prompt arguments are
+	// strings, so they must be string parameters.
+	Arguments Parameters `yaml:"arguments"`
+	// Message is the text of the prompt, a Go template of the arguments in
+	// the same syntax as template parameters, such as `{{.city}}`.
+	Message string `yaml:"message" validate:"required"`
+}
+
+// Prompt is an initialized prompt.
+type Prompt struct {
+	Name        string
+	Description string
+	Arguments   Parameters
+	Message     string
+}
+
+// PromptArgumentMcpManifest describes an argument of a prompt to MCP clients.
+type PromptArgumentMcpManifest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PromptMcpManifest describes a prompt to MCP clients.
+type PromptMcpManifest struct {
+	Name        string                      `json:"name"`
+	Description string                      `json:"description,omitempty"`
+	Arguments   []PromptArgumentMcpManifest `json:"arguments,omitempty"`
+}
+
+func (c PromptConfig) Initialize() (Prompt, error) {
+	if !IsValidName(c.Name) {
+		return Prompt{}, fmt.Errorf("invalid prompt name: %s", c.Name)
+	}
+	for _, a := range c.Arguments {
+		if a.GetType() != typeString {
+			return Prompt{}, fmt.Errorf("argument %q of prompt %q must be a string, since MCP prompt arguments are strings", a.GetName(), c.Name)
+		}
+	}
+	if _, err := template.New("message").Funcs(templateFuncs(nil)).Parse(c.Message); err != nil {
+		return Prompt{}, fmt.Errorf("invalid message for prompt %q: %w", c.Name, err)
+	}
+	return Prompt{Name: c.Name, Description: c.Description, Arguments: c.Arguments, Message: c.Message}, nil
+}
+
+// McpManifest returns the manifest of the prompt. Arguments without a default
+// are required.
+func (p Prompt) McpManifest() PromptMcpManifest {
+	m := PromptMcpManifest{Name: p.Name, Description: p.Description}
+	for _, a := range p.Arguments {
+		m.Arguments = append(m.Arguments, PromptArgumentMcpManifest{
+			Name:        a.GetName(),
+			Description: a.Manifest().Description,
+			Required:    a.GetDefault() == nil,
+		})
+	}
+	return m
+}
+
+// Render validates the arguments the same way tool parameters are, and
+// returns the message of the prompt with the arguments filled in.
+func (p Prompt) Render(ctx context.Context, args map[string]any) (string, error) {
+	params, err := ParseParams(ctx, p.Arguments, args, nil)
+	if err != nil {
+		return "", err
+	}
+	return resolveTemplate(p.Arguments, p.Message, params.AsMap(), nil)
+}
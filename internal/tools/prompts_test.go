@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestPromptRender(t *testing.T) {
+	region := tools.NewStringParameter("region", "region of the instance")
+	region.AllowedValues = []string{"us-east1", "europe-west1"}
+	prompt, err := tools.PromptConfig{
+		Name:      "list_instances",
+		Arguments: tools.Parameters{region},
+		Message:   `List the instances in {{.region | lower}}.`,
+	}.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize prompt: %s", err)
+	}
+
+	got, err := prompt.Render(context.Background(), map[string]any{"region": "europe-west1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "List the instances in europe-west1."; got != want {
+		t.Fatalf("unexpected message: got %q, want %q", got, want)
+	}
+
+	// arguments are validated like tool parameters
+	for _, args := range []map[string]any{{}, {"region": "mars-north1"}} {
+		if _, err := prompt.Render(context.Background(), args); err == nil {
+			t.Fatalf("expected an error rendering with %v", args)
+		}
+	}
+}
+
+func TestFailPromptInitialize(t *testing.T) {
+	tcs := []struct {
+		name string
+		cfg  tools.PromptConfig
+		err  string
+	}{
+		{
+			name: "invalid name",
+			cfg:  tools.PromptConfig{Name: "plan trip", Message: "Plan a trip."},
+			err:  "invalid prompt name: plan trip",
+		},
+		{
+			name: "argument that is not a string",
+			cfg: tools.PromptConfig{
+				Name:      "plan_trip",
+				Arguments: tools.Parameters{tools.NewIntParameter("days", "length of the trip")},
+				Message:   "Plan a {{.days}} day trip.",
+			},
+			err: `argument "days" of prompt "plan_trip" must be a string, since MCP prompt arguments are strings`,
+		},
+		{
+			name: "invalid message",
+			cfg:  tools.PromptConfig{Name: "plan_trip", Message: "Plan a trip to {{.city"},
+			err:  `invalid message for prompt "plan_trip": template: message:1: unclosed action`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.cfg.Initialize()
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %q, want %q", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	redissrc "github.com/googleapis/genai-toolbox/internal/sources/redis"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/redis/go-redis/v9"
+)
+
+const kind string = "redis"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	RedisClient() *redis.Client
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &redissrc.Source{}
+
+var compatibleSources = [...]string{redissrc.SourceKind}
+
+// readCommands are the commands that only read data, and that tools may run
+// without allowWrites.
+var readCommands = map[string]bool{
+	"BITCOUNT": true, "DBSIZE": true, "ECHO": true, "EXISTS": true,
+	"GEODIST": true, "GEOPOS": true, "GEOSEARCH": true, "GET": true,
+	"GETBIT": true, "GETRANGE": true, "HEXISTS": true, "HGET": true,
+	"HGETALL": true, "HKEYS": true, "HLEN": true, "HMGET": true,
+	"HSCAN": true, "HSTRLEN": true, "HVALS": true, "KEYS": true,
+	"LINDEX": true, "LLEN": true, "LPOS": true, "LRANGE": true,
+	"MGET": true, "PFCOUNT": true, "PING": true, "PTTL": true,
+	"SCAN": true, "SCARD": true, "SISMEMBER": true, "SMEMBERS": true,
+	"SMISMEMBER": true, "SRANDMEMBER": true, "SSCAN": true, "STRLEN": true,
+	"TTL": true, "TYPE": true, "XLEN": true, "XRANGE": true,
+	"XREVRANGE": true, "ZCARD": true, "ZCOUNT": true, "ZRANGE": true,
+	"ZRANGEBYSCORE": true, "ZRANK": true, "ZREVRANGE": true, "ZREVRANGEBYSCORE": true,
+	"ZREVRANK": true, "ZSCAN": true, "ZSCORE": true,
+}
+
+type Config struct {
+	Name        string `yaml:"name" validate:"required"`
+	Kind        string `yaml:"kind" validate:"required"`
+	Source      string `yaml:"source" validate:"required"`
+	Description string `yaml:"description" validate:"required"`
+	// Command is the command and its arguments, such as
+	// ["HGETALL", "user:{{.id}}"]. Arguments are templates resolved with the
+	// parameters, and each one is passed as a single argument whatever its
+	// value.
+	Command []string `yaml:"command" validate:"required,min=1"`
+	// AllowWrites allows commands that write data or administer the server,
+	// which are rejected by default.
+	AllowWrites  bool             `yaml:"allowWrites"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	// verify the command is allowed, which can only be known if its name
+	// isn't a template
+	name := cfg.Command[0]
+	if strings.Contains(name, "{{") {
+		return nil, fmt.Errorf("invalid command: the command name %q can't be a template", name)
+	}
+	if !cfg.AllowWrites && !readCommands[strings.ToUpper(name)] {
+		return nil, fmt.Errorf("command %q may write data or administer the server, set allowWrites to run it", name)
+	}
+
+	// verify the argument templates are valid
+	args := make([]*template.Template, len(cfg.Command)-1)
+	for i, arg := range cfg.Command[1:] {
+		tmpl, err := template.New("command").Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid command argument %q: %w", arg, err)
+		}
+		args[i] = tmpl
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: cfg.Parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   cfg.Parameters,
+		AuthRequired: cfg.AuthRequired,
+		Client:       s.RedisClient(),
+		command:      name,
+		args:         args,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: cfg.Parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	AuthRequired []string         `yaml:"authRequired"`
+
+	Client      *redis.Client
+	command     string
+	args        []*template.Template
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	paramsMap := params.AsMap()
+	cmd := make([]any, 0, len(t.args)+1)
+	cmd = append(cmd, t.command)
+	for _, tmpl := range t.args {
+		var arg bytes.Buffer
+		if err := tmpl.Execute(&arg, paramsMap); err != nil {
+			return nil, fmt.Errorf("unable to resolve command: %w", err)
+		}
+		cmd = append(cmd, arg.String())
+	}
+
+	reply, err := t.Client.Do(ctx, cmd...).Result()
+	if errors.Is(err, redis.Nil) {
+		return []any{nil}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to run command: %w", err)
+	}
+	return []any{ConvertReply(reply)}, nil
+}
+
+// ConvertReply converts a reply of Redis into a value that can be encoded as
+// JSON. Maps, such as the reply of HGETALL, are keyed by strings.
+func ConvertReply(reply any) any {
+	switch v := reply.(type) {
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = ConvertReply(e)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[fmt.Sprint(k)] = ConvertReply(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func (t Tool) ParseParams(ctx context.Context, data map[string]any, claimsMap map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(ctx, t.Parameters, data, claimsMap)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
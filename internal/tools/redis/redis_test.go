@@ -0,0 +1,211 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis_test
+
+import (
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	redissrc "github.com/googleapis/genai-toolbox/internal/sources/redis"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/redis"
+)
+
+func TestParseFromYamlRedis(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: redis
+					source: my-redis
+					description: some tool description
+					authRequired:
+						- my-google-auth-service
+					command: ["HGETALL", "user:{{.id}}"]
+					parameters:
+						- name: id
+						  type: string
+						  description: id parameter description
+			`,
+			want: server.ToolConfigs{
+				"example_tool": redis.Config{
+					Name:         "example_tool",
+					Kind:         "redis",
+					Source:       "my-redis",
+					Description:  "some tool description",
+					AuthRequired: []string{"my-google-auth-service"},
+					Command:      []string{"HGETALL", "user:{{.id}}"},
+					Parameters: []tools.Parameter{
+						tools.NewStringParameter("id", "id parameter description"),
+					},
+				},
+			},
+		},
+		{
+			desc: "with writes allowed",
+			in: `
+			tools:
+				example_tool:
+					kind: redis
+					source: my-redis
+					description: some tool description
+					command: ["INCR", "visits"]
+					allowWrites: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": redis.Config{
+					Name:         "example_tool",
+					Kind:         "redis",
+					Source:       "my-redis",
+					Description:  "some tool description",
+					AuthRequired: []string{},
+					Command:      []string{"INCR", "visits"},
+					AllowWrites:  true,
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlRedis(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: redis
+			source: my-redis
+			description: some tool description
+	`
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err == nil {
+		t.Fatalf("expected a tool without a command to be rejected")
+	}
+}
+
+func TestInitializeRedisCommands(t *testing.T) {
+	srcs := map[string]sources.Source{"my-redis": &redissrc.Source{}}
+	tcs := []struct {
+		desc        string
+		command     []string
+		allowWrites bool
+		err         string
+	}{
+		{
+			desc:    "read command",
+			command: []string{"get", "counter:{{.name}}"},
+		},
+		{
+			desc:    "write command",
+			command: []string{"SET", "counter:{{.name}}", "0"},
+			err:     `command "SET" may write data or administer the server`,
+		},
+		{
+			desc:    "admin command",
+			command: []string{"FLUSHALL"},
+			err:     `command "FLUSHALL" may write data or administer the server`,
+		},
+		{
+			desc:        "write command with writes allowed",
+			command:     []string{"INCR", "counter:{{.name}}"},
+			allowWrites: true,
+		},
+		{
+			desc:        "templated command name",
+			command:     []string{"{{.cmd}}", "counter"},
+			allowWrites: true,
+			err:         "can't be a template",
+		},
+		{
+			desc:    "invalid argument",
+			command: []string{"GET", "counter:{{.name"},
+			err:     "invalid command argument",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := redis.Config{
+				Name:        "example_tool",
+				Kind:        "redis",
+				Source:      "my-redis",
+				Description: "some tool description",
+				Command:     tc.command,
+				AllowWrites: tc.allowWrites,
+			}
+			_, err := cfg.Initialize(srcs)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %v, want it to contain %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestConvertReply(t *testing.T) {
+	reply := []any{
+		"a",
+		int64(1),
+		nil,
+		map[any]any{"name": "Alice", int64(2): []any{"x"}},
+	}
+	want := []any{
+		"a",
+		int64(1),
+		nil,
+		map[string]any{"name": "Alice", "2": []any{"x"}},
+	}
+	if diff := cmp.Diff(want, redis.ConvertReply(reply)); diff != "" {
+		t.Fatalf("unexpected reply: diff %v", diff)
+	}
+}
@@ -15,14 +15,20 @@
 package sqlitesql_test
 
 import (
+	"context"
+	"encoding/json"
+	"path/filepath"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/sqlite"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/sqlitesql"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestParseFromYamlSQLite(t *testing.T) {
@@ -92,3 +98,141 @@ func TestParseFromYamlSQLite(t *testing.T) {
 	}
 
 }
+
+func TestInvokeInMemory(t *testing.T) {
+	ctx := context.Background()
+	src, err := sqlite.Config{
+		Name:     "my-sqlite-db",
+		Kind:     sqlite.SourceKind,
+		Database: ":memory:",
+	}.Initialize(ctx, noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	tool, err := sqlitesql.Config{
+		Name:        "select_1",
+		Kind:        "sqlite-sql",
+		Source:      "my-sqlite-db",
+		Description: "some description",
+		Statement:   "SELECT 1",
+	}.Initialize(map[string]sources.Source{"my-sqlite-db": src})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	res, err := tool.Invoke(ctx, tools.ParamValues{})
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+	got, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("unable to marshal result: %s", err)
+	}
+	// the same shape as the select_1_want of the sqlite integration test
+	if want := `[{"1":1}]`; string(got) != want {
+		t.Fatalf("unexpected result: got %s, want %s", got, want)
+	}
+}
+
+func TestInvokeScalarResultMode(t *testing.T) {
+	ctx := context.Background()
+	src, err := sqlite.Config{
+		Name:     "my-sqlite-db",
+		Kind:     sqlite.SourceKind,
+		Database: ":memory:",
+	}.Initialize(ctx, noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	srcs := map[string]sources.Source{"my-sqlite-db": src}
+
+	tcs := []struct {
+		desc      string
+		statement string
+		want      any
+		wantErr   string
+	}{
+		{
+			desc:      "single value",
+			statement: "SELECT count(*) AS count FROM (SELECT 1 UNION SELECT 2 UNION SELECT 3 UNION SELECT 4 UNION SELECT 5)",
+			want:      int64(5),
+		},
+		{
+			desc:      "multiple columns",
+			statement: "SELECT 1 AS a, 2 AS b",
+			wantErr:   `resultMode "scalar" requires a single column, got 2 columns`,
+		},
+		{
+			desc:      "multiple rows",
+			statement: "SELECT 1 UNION SELECT 2",
+			wantErr:   `resultMode "scalar" requires a single row, got 2 rows`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tool, err := tools.ConfigWithOptions{
+				ToolConfig: sqlitesql.Config{
+					Name:        "count",
+					Kind:        "sqlite-sql",
+					Source:      "my-sqlite-db",
+					Description: "some description",
+					Statement:   tc.statement,
+				},
+				Options: tools.Options{ResultMode: tools.ResultModeScalar},
+			}.Initialize(srcs)
+			if err != nil {
+				t.Fatalf("unable to initialize tool: %s", err)
+			}
+			res, err := tool.Invoke(ctx, tools.ParamValues{})
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unable to invoke tool: %s", err)
+			}
+			if diff := cmp.Diff([]any{tc.want}, res); diff != "" {
+				t.Fatalf("unexpected result: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestValuesQuery(t *testing.T) {
+	ctx := context.Background()
+	src, err := sqlite.Config{
+		Name:     "my-sqlite-db",
+		Kind:     sqlite.SourceKind,
+		Database: filepath.Join(t.TempDir(), "values.db"),
+	}.Initialize(ctx, noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	db := src.(*sqlite.Source).SQLiteDB()
+	if _, err := db.ExecContext(ctx, "CREATE TABLE regions (name TEXT); INSERT INTO regions VALUES ('us-east1'), ('us-west1')"); err != nil {
+		t.Fatalf("unable to set up reference table: %s", err)
+	}
+
+	region := tools.NewStringParameter("region", "region of the instance")
+	region.ValuesQuery = &tools.ValuesQuery{Source: "my-sqlite-db", Statement: "SELECT name FROM regions"}
+	tool, err := sqlitesql.Config{
+		Name:        "get_region",
+		Kind:        "sqlite-sql",
+		Source:      "my-sqlite-db",
+		Description: "some description",
+		Statement:   "SELECT ?",
+		Parameters:  tools.Parameters{region},
+	}.Initialize(map[string]sources.Source{"my-sqlite-db": src})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	if _, err := tool.ParseParams(context.Background(), map[string]any{"region": "us-west1"}, nil); err != nil {
+		t.Fatalf("expected a value of the reference table to be accepted: %s", err)
+	}
+	if _, err := tool.ParseParams(context.Background(), map[string]any{"region": "eu-west1"}, nil); err == nil {
+		t.Fatalf("expected a value absent from the reference table to be rejected")
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import "context"
+
+// Streamer is implemented by tools that can pass the rows of their results
+// to yield as they are read from the source, instead of collecting them.
+// Streaming stops at the first error returned by yield.
+type Streamer interface {
+	InvokeStream(ctx context.Context, params ParamValues, yield func(row any) error) error
+}
+
+// StreamerFunc is a function that implements Streamer.
+type StreamerFunc func(ctx context.Context, params ParamValues, yield func(row any) error) error
+
+func (f StreamerFunc) InvokeStream(ctx context.Context, params ParamValues, yield func(row any) error) error {
+	return f(ctx, params, yield)
+}
+
+// GetStreamer returns the Streamer of the tool, if any. Tools that wrap
+// another Tool can implement `Unwrap() Tool` to expose its Streamer, or
+// `Stream() (Streamer, bool)` to return a Streamer of their own, for example
+// to transform the rows.
+func GetStreamer(t Tool) (Streamer, bool) {
+	for {
+		switch d := t.(type) {
+		case interface{ Stream() (Streamer, bool) }:
+			return d.Stream()
+		case Streamer:
+			return d, true
+		case interface{ Unwrap() Tool }:
+			t = d.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
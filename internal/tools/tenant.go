@@ -0,0 +1,125 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// TenantSources maps the values of a claim of the caller, such as a tenant
+// id, to the source that serves them.
+type TenantSources struct {
+	AuthService string            `yaml:"authService" validate:"required"`
+	Claim       string            `yaml:"claim" validate:"required"`
+	Sources     map[string]string `yaml:"sources" validate:"required"`
+}
+
+// initializeTenants initializes tc once for each tenant of ts, in place of
+// its own source.
+func initializeTenants(tc ToolConfig, srcs map[string]sources.Source, ts TenantSources) (Tool, error) {
+	name := SourceName(tc)
+	if name == "" {
+		return nil, fmt.Errorf("tenantSources requires a tool kind with a source")
+	}
+	if len(ts.Sources) == 0 {
+		return nil, fmt.Errorf("tenantSources must map at least one tenant to a source")
+	}
+	t := tenantTool{routing: ts, tenants: make(map[string]Tool, len(ts.Sources))}
+	for _, tenant := range slices.Sorted(maps.Keys(ts.Sources)) {
+		src, ok := srcs[ts.Sources[tenant]]
+		if !ok {
+			return nil, fmt.Errorf("no source named %q configured for tenant %q", ts.Sources[tenant], tenant)
+		}
+		tenantSrcs := maps.Clone(srcs)
+		tenantSrcs[name] = src
+		tt, err := tc.Initialize(tenantSrcs)
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize tool for tenant %q: %w", tenant, err)
+		}
+		if t.Tool == nil {
+			t.Tool = tt
+		}
+		t.tenants[tenant] = tt
+	}
+	return t, nil
+}
+
+// validate interface
+var _ Tool = tenantTool{}
+
+// tenantTool is a Tool that invokes the tool of the caller's tenant. It
+// embeds the tool of one of the tenants for its manifest and parameters,
+// which are the same for all of them.
+type tenantTool struct {
+	Tool
+	routing TenantSources
+	tenants map[string]Tool
+}
+
+func (t tenantTool) Unwrap() Tool {
+	return t.Tool
+}
+
+// Authorized requires the auth service that the tenant is read from, in
+// addition to the auth services required by the tool.
+func (t tenantTool) Authorized(verifiedAuthServices []string) bool {
+	return slices.Contains(verifiedAuthServices, t.routing.AuthService) && t.Tool.Authorized(verifiedAuthServices)
+}
+
+func (t tenantTool) Invoke(ctx context.Context, params ParamValues) ([]any, error) {
+	tool, err := t.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tool.Invoke(ctx, params)
+}
+
+// Stream streams the invocations of the tool of the caller's tenant.
+func (t tenantTool) Stream() (Streamer, bool) {
+	if _, ok := GetStreamer(t.Tool); !ok {
+		return nil, false
+	}
+	return StreamerFunc(func(ctx context.Context, params ParamValues, yield func(row any) error) error {
+		tool, err := t.resolve(ctx)
+		if err != nil {
+			return err
+		}
+		s, ok := GetStreamer(tool)
+		if !ok {
+			return fmt.Errorf("tool does not support streaming")
+		}
+		return s.InvokeStream(ctx, params, yield)
+	}), true
+}
+
+// resolve returns the tool of the caller's tenant.
+func (t tenantTool) resolve(ctx context.Context) (Tool, error) {
+	claims, _ := ClaimsFromContext(ctx, t.routing.AuthService)
+	v, ok := claims[t.routing.Claim]
+	if !ok {
+		return nil, fmt.Errorf("claim %q of auth service %q is required to select a source", t.routing.Claim, t.routing.AuthService)
+	}
+	tenant := fmt.Sprint(v)
+	tool, ok := t.tenants[tenant]
+	if !ok {
+		return nil, fmt.Errorf("unknown tenant %q", tenant)
+	}
+	return tool, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/inmemory"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/inmemorylookup"
+)
+
+func TestTenantSources(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"acme-db": &inmemory.Source{
+			Name:   "acme-db",
+			Kind:   inmemory.SourceKind,
+			Tables: map[string]inmemory.Table{"users": {{"id": 1, "name": "Alice"}}},
+		},
+		"globex-db": &inmemory.Source{
+			Name:   "globex-db",
+			Kind:   inmemory.SourceKind,
+			Tables: map[string]inmemory.Table{"users": {{"id": 1, "name": "Bob"}}},
+		},
+	}
+	cfg := tools.ConfigWithOptions{
+		ToolConfig: inmemorylookup.Config{
+			Name:        "get_user",
+			Kind:        "in-memory-lookup",
+			Source:      "tenant-db",
+			Description: "Get a user by id.",
+			Table:       "users",
+			Parameters:  tools.Parameters{tools.NewIntParameter("id", "The id of the user.")},
+		},
+		Options: tools.Options{TenantSources: &tools.TenantSources{
+			AuthService: "my-google-auth",
+			Claim:       "tenant",
+			Sources:     map[string]string{"acme": "acme-db", "globex": "globex-db"},
+		}},
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	if tool.Authorized(nil) {
+		t.Fatalf("expected the tool to require the auth service of the tenant claim")
+	}
+	if !tool.Authorized([]string{"my-google-auth"}) {
+		t.Fatalf("expected the tool to be authorized with the auth service of the tenant claim")
+	}
+
+	params := tools.ParamValues{{Name: "id", Value: 1}}
+	tcs := []struct {
+		tenant  string
+		want    []any
+		wantErr string
+	}{
+		{tenant: "acme", want: []any{map[string]any{"id": 1, "name": "Alice"}}},
+		{tenant: "globex", want: []any{map[string]any{"id": 1, "name": "Bob"}}},
+		{tenant: "initech", wantErr: `unknown tenant "initech"`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.tenant, func(t *testing.T) {
+			ctx := tools.WithClaims(context.Background(), map[string]map[string]any{
+				"my-google-auth": {"tenant": tc.tenant},
+			})
+			got, err := tool.Invoke(ctx, params)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected result: diff %v", diff)
+			}
+		})
+	}
+
+	if _, err := tool.Invoke(context.Background(), params); err == nil {
+		t.Fatalf("expected an invocation without the tenant claim to be rejected")
+	}
+}
+
+func TestFailTenantSources(t *testing.T) {
+	cfg := tools.ConfigWithOptions{
+		ToolConfig: inmemorylookup.Config{
+			Name:        "get_user",
+			Kind:        "in-memory-lookup",
+			Source:      "tenant-db",
+			Description: "Get a user by id.",
+			Table:       "users",
+		},
+		Options: tools.Options{TenantSources: &tools.TenantSources{
+			AuthService: "my-google-auth",
+			Claim:       "tenant",
+			Sources:     map[string]string{"acme": "acme-db"},
+		}},
+	}
+	_, err := cfg.Initialize(map[string]sources.Source{})
+	wantErr := `no source named "acme-db" configured for tenant "acme"`
+	if err == nil || err.Error() != wantErr {
+		t.Fatalf("unexpected error: got %v, want %q", err, wantErr)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import "context"
+
+type toolNameKey struct{}
+
+// WithToolName returns a context carrying the name of the invoked tool, so
+// that sources can attribute the work they do to it.
+func WithToolName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, toolNameKey{}, name)
+}
+
+// ToolNameFromContext returns the name of the invoked tool, if any.
+func ToolNameFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(toolNameKey{}).(string)
+	return name, ok && name != ""
+}
@@ -17,6 +17,7 @@ package tools
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"slices"
 
 	yaml "github.com/goccy/go-yaml"
@@ -65,7 +66,7 @@ type ToolConfig interface {
 
 type Tool interface {
 	Invoke(context.Context, ParamValues) ([]any, error)
-	ParseParams(map[string]any, map[string]map[string]any) (ParamValues, error)
+	ParseParams(context.Context, map[string]any, map[string]map[string]any) (ParamValues, error)
 	Manifest() Manifest
 	McpManifest() McpManifest
 	Authorized([]string) bool
@@ -76,6 +77,13 @@ type Manifest struct {
 	Description  string              `json:"description"`
 	Parameters   []ParameterManifest `json:"parameters"`
 	AuthRequired []string            `json:"authRequired"`
+	// Version is the version of the tool, if several versions are served.
+	Version string `json:"version,omitempty"`
+	// Safe is true if the tool has no side effects.
+	Safe bool `json:"safe,omitempty"`
+	// ETag identifies the definition of the tool. It is only set in the
+	// response for a single tool.
+	ETag string `json:"etag,omitempty"`
 }
 
 // Definition for a tool the MCP client can call.
@@ -86,6 +94,8 @@ type McpManifest struct {
 	Description string `json:"description,omitempty"`
 	// A JSON Schema object defining the expected parameters for the tool.
 	InputSchema McpToolsSchema `json:"inputSchema,omitempty"`
+	// Metadata about the tool, such as whether it is safe.
+	Meta map[string]any `json:"_meta,omitempty"`
 }
 
 // Helper function that returns if a tool invocation request is authorized
@@ -101,3 +111,24 @@ func IsAuthorized(authRequiredSources []string, verifiedAuthServices []string) b
 	}
 	return false
 }
+
+// Kind returns the kind of t, read from the Kind field of the tool that t
+// wraps, or "" if it has none.
+func Kind(t Tool) string {
+	for {
+		w, ok := t.(interface{ Unwrap() Tool })
+		if !ok {
+			break
+		}
+		t = w.Unwrap()
+	}
+	v := reflect.Indirect(reflect.ValueOf(t))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	f := v.FieldByName("Kind")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
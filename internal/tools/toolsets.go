@@ -16,10 +16,16 @@ package tools
 
 import (
 	"fmt"
+	"path"
+	"slices"
+	"strings"
 )
 
 type ToolsetConfig struct {
-	Name      string   `yaml:"name"`
+	Name string `yaml:"name"`
+	// ToolNames are the names of the tools of the toolset, or glob patterns
+	// such as "analytics_*" that are expanded to the names of the tools they
+	// match.
 	ToolNames []string `yaml:",inline"`
 }
 
@@ -43,12 +49,16 @@ func (t ToolsetConfig) Initialize(serverVersion string, toolsMap map[string]Tool
 	if !IsValidName(toolset.Name) {
 		return toolset, fmt.Errorf("invalid toolset name: %s", t)
 	}
-	toolset.Tools = make([]*Tool, len(t.ToolNames))
+	toolNames, err := t.expandToolNames(toolsMap)
+	if err != nil {
+		return toolset, err
+	}
+	toolset.Tools = make([]*Tool, 0, len(toolNames))
 	toolset.Manifest = ToolsetManifest{
 		ServerVersion: serverVersion,
 		ToolsManifest: make(map[string]Manifest),
 	}
-	for _, toolName := range t.ToolNames {
+	for _, toolName := range toolNames {
 		tool, ok := toolsMap[toolName]
 		if !ok {
 			return toolset, fmt.Errorf("tool does not exist: %s", t)
@@ -60,3 +70,46 @@ func (t ToolsetConfig) Initialize(serverVersion string, toolsMap map[string]Tool
 
 	return toolset, nil
 }
+
+// isToolNamePattern reports whether name is a glob pattern rather than the
+// name of a tool.
+func isToolNamePattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// expandToolNames returns the tool names of t, with each glob pattern replaced
+// by the sorted names of the tools of toolsMap it matches. A pattern that
+// matches no tools is an error, so that typos are caught.
+func (t ToolsetConfig) expandToolNames(toolsMap map[string]Tool) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, name := range t.ToolNames {
+		if !isToolNamePattern(name) {
+			add(name)
+			continue
+		}
+		if _, err := path.Match(name, ""); err != nil {
+			return nil, fmt.Errorf("invalid tool name pattern %q: %w", name, err)
+		}
+		var matches []string
+		for toolName := range toolsMap {
+			if ok, _ := path.Match(name, toolName); ok {
+				matches = append(matches, toolName)
+			}
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("tool name pattern %q matches no tools", name)
+		}
+		slices.Sort(matches)
+		for _, m := range matches {
+			add(m)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// namedTool is a Tool whose manifests only hold its name.
+type namedTool struct {
+	tools.Tool
+	name string
+}
+
+func (t namedTool) Manifest() tools.Manifest {
+	return tools.Manifest{Description: t.name}
+}
+
+func (t namedTool) McpManifest() tools.McpManifest {
+	return tools.McpManifest{Name: t.name}
+}
+
+func TestToolsetInitialize(t *testing.T) {
+	toolsMap := make(map[string]tools.Tool)
+	for _, name := range []string{"analytics_daily", "analytics_weekly", "billing_invoices", "search"} {
+		toolsMap[name] = namedTool{name: name}
+	}
+	tcs := []struct {
+		desc      string
+		toolNames []string
+		want      []string
+	}{
+		{
+			desc:      "tool names",
+			toolNames: []string{"search", "billing_invoices"},
+			want:      []string{"search", "billing_invoices"},
+		},
+		{
+			desc:      "prefix pattern",
+			toolNames: []string{"analytics_*"},
+			want:      []string{"analytics_daily", "analytics_weekly"},
+		},
+		{
+			desc:      "pattern and tool names",
+			toolNames: []string{"search", "analytics_*", "analytics_daily"},
+			want:      []string{"search", "analytics_daily", "analytics_weekly"},
+		},
+		{
+			desc:      "character class",
+			toolNames: []string{"[bs]*"},
+			want:      []string{"billing_invoices", "search"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			toolset, err := tools.ToolsetConfig{Name: "my_toolset", ToolNames: tc.toolNames}.Initialize("0.0.0", toolsMap)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var got []string
+			for _, m := range toolset.McpManifest {
+				got = append(got, m.Name)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected tools (-want +got):\n%s", diff)
+			}
+			if len(toolset.Manifest.ToolsManifest) != len(tc.want) || len(toolset.Tools) != len(tc.want) {
+				t.Fatalf("unexpected number of tools: got %d manifests and %d tools, want %d", len(toolset.Manifest.ToolsManifest), len(toolset.Tools), len(tc.want))
+			}
+		})
+	}
+}
+
+func TestFailToolsetInitialize(t *testing.T) {
+	toolsMap := map[string]tools.Tool{"analytics_daily": namedTool{name: "analytics_daily"}}
+	tcs := []struct {
+		desc      string
+		toolNames []string
+		err       string
+	}{
+		{
+			desc:      "pattern without matches",
+			toolNames: []string{"analytcs_*"},
+			err:       `tool name pattern "analytcs_*" matches no tools`,
+		},
+		{
+			desc:      "invalid pattern",
+			toolNames: []string{"analytics_[*"},
+			err:       `invalid tool name pattern "analytics_[*": syntax error in pattern`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tools.ToolsetConfig{Name: "my_toolset", ToolNames: tc.toolNames}.Initialize("0.0.0", toolsMap)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %q, want %q", err, tc.err)
+			}
+		})
+	}
+}
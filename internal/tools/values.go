@@ -0,0 +1,98 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// defaultValuesTTL is how long the values of a ValuesQuery are cached when
+// its ttl is not set.
+const defaultValuesTTL = 5 * time.Minute
+
+// ValuesQuery lists the allowed values of a parameter with a query against a
+// source, such as a reference table.
+type ValuesQuery struct {
+	Source    string `yaml:"source" validate:"required"`
+	Statement string `yaml:"statement" validate:"required"`
+	// TTL is how long the values are cached before they are queried again.
+	TTL time.Duration `yaml:"ttl"`
+	// Values is set once the query is bound to its source, see
+	// BindValuesQueries.
+	Values *ValueSet `yaml:"-"`
+}
+
+// ValueSet is a set of values that is refreshed once its TTL has expired.
+type ValueSet struct {
+	query func(context.Context) ([]string, error)
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	values  []string
+	expires time.Time
+}
+
+// NewValueSet returns a ValueSet listed by query. If ttl is not positive, the
+// values are cached for 5 minutes.
+func NewValueSet(query func(context.Context) ([]string, error), ttl time.Duration) *ValueSet {
+	if ttl <= 0 {
+		ttl = defaultValuesTTL
+	}
+	return &ValueSet{query: query, ttl: ttl}
+}
+
+// Contains reports whether v is one of the current values of the set.
+func (s *ValueSet) Contains(ctx context.Context, v string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Now().After(s.expires) {
+		values, err := s.query(ctx)
+		if err != nil {
+			return false, err
+		}
+		s.values = values
+		s.expires = time.Now().Add(s.ttl)
+	}
+	return slices.Contains(s.values, v), nil
+}
+
+// BindValuesQueries binds the valuesQuery of the string parameters of each
+// of ps to its source, so that their values are checked when they are parsed.
+func BindValuesQueries(srcs map[string]sources.Source, ps ...Parameters) error {
+	for _, params := range ps {
+		for _, p := range params {
+			sp, ok := p.(*StringParameter)
+			if !ok || sp.ValuesQuery == nil {
+				continue
+			}
+			src, ok := srcs[sp.ValuesQuery.Source]
+			if !ok {
+				return fmt.Errorf("no source named %q configured for the valuesQuery of parameter %q", sp.ValuesQuery.Source, sp.Name)
+			}
+			query, err := sources.NewValuesQuery(src, sp.ValuesQuery.Statement)
+			if err != nil {
+				return fmt.Errorf("invalid valuesQuery for parameter %q: %w", sp.Name, err)
+			}
+			sp.ValuesQuery.Values = NewValueSet(query, sp.ValuesQuery.TTL)
+		}
+	}
+	return nil
+}
@@ -164,6 +164,7 @@ func TestAlloyDBPgToolEndpoints(t *testing.T) {
 	}
 
 	tests.RunToolGetTest(t)
+	tests.RunToolsListTest(t)
 
 	select1Want, failInvocationWant, createTableStatement := tests.GetPostgresWants()
 	invokeParamWant, mcpInvokeParamWant := tests.GetNonSpannerInvokeParamWant()
@@ -123,6 +123,7 @@ func TestBigQueryToolEndpoints(t *testing.T) {
 	}
 
 	tests.RunToolGetTest(t)
+	tests.RunToolsListTest(t)
 
 	select1Want := "[{\"f0_\":1}]"
 	// Partial message; the full error message is too long.
@@ -102,6 +102,7 @@ func TestBigtableToolEndpoints(t *testing.T) {
 	}
 
 	tests.RunToolGetTest(t)
+	tests.RunToolsListTest(t)
 
 	// Actual test parameters are set in https://github.com/googleapis/genai-toolbox/blob/52b09a67cb40ac0c5f461598b4673136699a3089/tests/tool_test.go#L250
 	select1Want := "[{\"$col1\":1}]"
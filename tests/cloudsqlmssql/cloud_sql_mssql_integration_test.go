@@ -148,6 +148,7 @@ func TestCloudSQLMssqlToolEndpoints(t *testing.T) {
 	}
 
 	tests.RunToolGetTest(t)
+	tests.RunToolsListTest(t)
 
 	select1Want, failInvocationWant, createTableStatement := tests.GetMssqlWants()
 	invokeParamWant, mcpInvokeParamWant := tests.GetNonSpannerInvokeParamWant()
@@ -127,6 +127,7 @@ func TestCloudSQLMysqlToolEndpoints(t *testing.T) {
 	// Write config into a file and pass it to command
 	toolsFile := tests.GetToolsConfig(sourceConfig, CLOUD_SQL_MYSQL_TOOL_KIND, tool_statement1, tool_statement2)
 	toolsFile = tests.AddMySqlExecuteSqlConfig(t, toolsFile)
+	toolsFile = tests.AddMySqlWarningConfig(t, toolsFile)
 	tmplSelectCombined, tmplSelectFilterCombined := tests.GetMysqlSQLTmplToolStatement()
 	toolsFile = tests.AddTemplateParamConfig(t, toolsFile, CLOUD_SQL_MYSQL_TOOL_KIND, tmplSelectCombined, tmplSelectFilterCombined)
 
@@ -145,6 +146,7 @@ func TestCloudSQLMysqlToolEndpoints(t *testing.T) {
 	}
 
 	tests.RunToolGetTest(t)
+	tests.RunToolsListTest(t)
 
 	select1Want, failInvocationWant, createTableStatement := tests.GetMysqlWants()
 	invokeParamWant, mcpInvokeParamWant := tests.GetNonSpannerInvokeParamWant()
@@ -152,6 +154,7 @@ func TestCloudSQLMysqlToolEndpoints(t *testing.T) {
 	tests.RunExecuteSqlToolInvokeTest(t, createTableStatement, select1Want)
 	tests.RunMCPToolCallMethod(t, mcpInvokeParamWant, failInvocationWant)
 	tests.RunToolInvokeWithTemplateParameters(t, tableNameTemplateParam)
+	tests.RunMySqlWarningsInvokeTest(t)
 }
 
 // Test connection with different IP type
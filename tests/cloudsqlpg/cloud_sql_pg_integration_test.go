@@ -149,6 +149,7 @@ func TestCloudSQLPgSimpleToolEndpoints(t *testing.T) {
 	}
 
 	tests.RunToolGetTest(t)
+	tests.RunToolsListTest(t)
 
 	select1Want, failInvocationWant, createTableStatement := tests.GetPostgresWants()
 	invokeParamWant, mcpInvokeParamWant := tests.GetNonSpannerInvokeParamWant()
@@ -129,6 +129,43 @@ func AddPgExecuteSqlConfig(t *testing.T, config map[string]any) map[string]any {
 	return config
 }
 
+// AddPgExplainAnalyzeConfig adds a postgres-sql tool with explainAnalyze enabled
+func AddPgExplainAnalyzeConfig(t *testing.T, config map[string]any) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-explain-analyze-tool"] = map[string]any{
+		"kind":           "postgres-sql",
+		"source":         "my-instance",
+		"description":    "Tool to run a statement with explain analyze",
+		"statement":      "SELECT 1 AS one;",
+		"explainAnalyze": true,
+	}
+	config["tools"] = tools
+	return config
+}
+
+// AddPgJoinTemplateParamConfig adds a postgres-sql tool that binds an array
+// template parameter with the join helper
+func AddPgJoinTemplateParamConfig(t *testing.T, config map[string]any) map[string]any {
+	toolsMap, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	toolsMap["select-join-templateParams-tool"] = map[string]any{
+		"kind":        "postgres-sql",
+		"source":      "my-instance",
+		"description": "Select tool with the join template helper",
+		"statement":   "SELECT name FROM (VALUES ('Alice'), ('Jane'), ('Sid')) AS t(name) WHERE name IN ({{join .names}}) ORDER BY name",
+		"templateParameters": []tools.Parameter{
+			tools.NewArrayParameter("names", "The names to select", tools.NewStringParameter("name", "A name to select")),
+		},
+	}
+	config["tools"] = toolsMap
+	return config
+}
+
 func AddTemplateParamConfig(t *testing.T, config map[string]any, toolKind, tmplSelectCombined, tmplSelectFilterCombined string) map[string]any {
 	toolsMap, ok := config["tools"].(map[string]any)
 	if !ok {
@@ -231,6 +268,22 @@ func AddMySqlExecuteSqlConfig(t *testing.T, config map[string]any) map[string]an
 	return config
 }
 
+// AddMySqlWarningConfig adds a mysql-sql tool whose statement raises a warning
+func AddMySqlWarningConfig(t *testing.T, config map[string]any) map[string]any {
+	tools, ok := config["tools"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to get tools from config")
+	}
+	tools["my-warning-tool"] = map[string]any{
+		"kind":        "mysql-sql",
+		"source":      "my-instance",
+		"description": "Tool to run a statement that raises a warning",
+		"statement":   "SELECT CAST('abc' AS SIGNED) AS value;",
+	}
+	config["tools"] = tools
+	return config
+}
+
 // AddMssqlExecuteSqlConfig gets the tools config for `mssql-execute-sql`
 func AddMssqlExecuteSqlConfig(t *testing.T, config map[string]any) map[string]any {
 	tools, ok := config["tools"].(map[string]any)
@@ -21,6 +21,7 @@ import (
 	"io"
 	"net/http"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
@@ -75,6 +76,56 @@ func RunToolGetTest(t *testing.T) {
 	}
 }
 
+// RunToolsListTest runs the tools list endpoint
+func RunToolsListTest(t *testing.T) {
+	resp, err := http.Get("http://127.0.0.1:5000/api/tools")
+	if err != nil {
+		t.Fatalf("error when sending a request: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		t.Fatalf("response status code is not 200")
+	}
+
+	var body struct {
+		Tools []struct {
+			Name        string `json:"name"`
+			Kind        string `json:"kind"`
+			Description string `json:"description"`
+		} `json:"tools"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("error parsing response body")
+	}
+	found := false
+	for _, tool := range body.Tools {
+		if tool.Name != "my-simple-tool" {
+			continue
+		}
+		found = true
+		want := "Simple tool to test end to end functionality."
+		if tool.Description != want {
+			t.Fatalf("unexpected description of my-simple-tool: got %q, want %q", tool.Description, want)
+		}
+		if tool.Kind == "" {
+			t.Fatalf("expected my-simple-tool to have a kind")
+		}
+	}
+	if !found {
+		t.Fatalf("unable to find my-simple-tool in %v", body.Tools)
+	}
+
+	// unknown toolsets are not found
+	resp, err = http.Get("http://127.0.0.1:5000/api/tools?toolset=unknown-toolset")
+	if err != nil {
+		t.Fatalf("error when sending a request: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected status code for an unknown toolset: got %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
 // RunToolInvoke runs the tool invoke endpoint
 func RunToolInvokeTest(t *testing.T, select_1_want, invoke_param_want string) {
 	// Get ID token
@@ -91,6 +142,8 @@ func RunToolInvokeTest(t *testing.T, select_1_want, invoke_param_want string) {
 		requestBody   io.Reader
 		want          string
 		isErr         bool
+		// wantCode is the error code of the response when isErr is true.
+		wantCode string
 	}{
 		{
 			name:          "invoke my-simple-tool",
@@ -114,6 +167,7 @@ func RunToolInvokeTest(t *testing.T, select_1_want, invoke_param_want string) {
 			requestHeader: map[string]string{},
 			requestBody:   bytes.NewBuffer([]byte(`{}`)),
 			isErr:         true,
+			wantCode:      "INVALID_PARAMETERS",
 		},
 		{
 			name:          "Invoke my-tool with insufficient parameters",
@@ -121,6 +175,7 @@ func RunToolInvokeTest(t *testing.T, select_1_want, invoke_param_want string) {
 			requestHeader: map[string]string{},
 			requestBody:   bytes.NewBuffer([]byte(`{"id": 1}`)),
 			isErr:         true,
+			wantCode:      "INVALID_PARAMETERS",
 		},
 		{
 			name:          "Invoke my-auth-tool with auth token",
@@ -136,6 +191,7 @@ func RunToolInvokeTest(t *testing.T, select_1_want, invoke_param_want string) {
 			requestHeader: map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
 			requestBody:   bytes.NewBuffer([]byte(`{}`)),
 			isErr:         true,
+			wantCode:      "UNAUTHORIZED",
 		},
 		{
 			name:          "Invoke my-auth-tool without auth token",
@@ -143,6 +199,7 @@ func RunToolInvokeTest(t *testing.T, select_1_want, invoke_param_want string) {
 			requestHeader: map[string]string{},
 			requestBody:   bytes.NewBuffer([]byte(`{}`)),
 			isErr:         true,
+			wantCode:      "UNAUTHORIZED",
 		},
 		{
 			name:          "Invoke my-auth-required-tool with auth token",
@@ -158,6 +215,7 @@ func RunToolInvokeTest(t *testing.T, select_1_want, invoke_param_want string) {
 			requestHeader: map[string]string{"my-google-auth_token": "INVALID_TOKEN"},
 			requestBody:   bytes.NewBuffer([]byte(`{}`)),
 			isErr:         true,
+			wantCode:      "UNAUTHORIZED",
 		},
 		{
 			name:          "Invoke my-auth-required-tool without auth token",
@@ -165,6 +223,7 @@ func RunToolInvokeTest(t *testing.T, select_1_want, invoke_param_want string) {
 			requestHeader: map[string]string{},
 			requestBody:   bytes.NewBuffer([]byte(`{}`)),
 			isErr:         true,
+			wantCode:      "UNAUTHORIZED",
 		},
 	}
 	for _, tc := range invokeTcs {
@@ -186,6 +245,15 @@ func RunToolInvokeTest(t *testing.T, select_1_want, invoke_param_want string) {
 
 			if resp.StatusCode != http.StatusOK {
 				if tc.isErr {
+					var errBody struct {
+						Code string `json:"code"`
+					}
+					if err := json.NewDecoder(resp.Body).Decode(&errBody); err != nil {
+						t.Fatalf("error parsing error response body: %s", err)
+					}
+					if errBody.Code != tc.wantCode {
+						t.Fatalf("unexpected error code: got %q, want %q", errBody.Code, tc.wantCode)
+					}
 					return
 				}
 				bodyBytes, _ := io.ReadAll(resp.Body)
@@ -456,6 +524,130 @@ func RunExecuteSqlToolInvokeTest(t *testing.T, createTableStatement string, sele
 	}
 }
 
+// RunPgJoinTemplateParamInvokeTest asserts that values passed to the join
+// template helper are bound as parameters
+func RunPgJoinTemplateParamInvokeTest(t *testing.T) {
+	api := "http://127.0.0.1:5000/api/tool/select-join-templateParams-tool/invoke"
+	tcs := []struct {
+		name        string
+		requestBody string
+		want        string
+	}{
+		{
+			name:        "join names",
+			requestBody: `{"names": ["Alice", "Sid"]}`,
+			want:        `[{"name":"Alice"},{"name":"Sid"}]`,
+		},
+		{
+			name:        "join binds values instead of inlining them",
+			requestBody: `{"names": ["Alice') OR ('1'='1"]}`,
+			want:        "null",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := http.Post(api, "application/json", bytes.NewBuffer([]byte(tc.requestBody)))
+			if err != nil {
+				t.Fatalf("unable to send request: %s", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+			}
+
+			var body map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("error parsing response body: %s", err)
+			}
+			got, ok := body["result"].(string)
+			if !ok {
+				t.Fatalf("unable to find result in response body")
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected value: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// RunPgExplainAnalyzeInvokeTest asserts that a tool with explainAnalyze enabled
+// returns both its rows and the execution plan
+func RunPgExplainAnalyzeInvokeTest(t *testing.T) {
+	api := "http://127.0.0.1:5000/api/tool/my-explain-analyze-tool/invoke"
+	resp, err := http.Post(api, "application/json", bytes.NewBuffer([]byte(`{}`)))
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var body struct {
+		Result string         `json:"result"`
+		Meta   map[string]any `json:"_meta"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("error parsing response body: %s", err)
+	}
+
+	if want := `[{"one":1}]`; body.Result != want {
+		t.Fatalf("unexpected result: got %q, want %q", body.Result, want)
+	}
+	plan, ok := body.Meta["plan"].(map[string]any)
+	if !ok {
+		t.Fatalf("unable to find plan in response metadata: %v", body.Meta)
+	}
+	for _, k := range []string{"Plan", "Execution Time"} {
+		if _, ok := plan[k]; !ok {
+			t.Fatalf("plan is missing %q: %v", k, plan)
+		}
+	}
+}
+
+// RunMySqlWarningsInvokeTest asserts that the warnings raised by a statement
+// are returned in the result metadata
+func RunMySqlWarningsInvokeTest(t *testing.T) {
+	api := "http://127.0.0.1:5000/api/tool/my-warning-tool/invoke"
+	resp, err := http.Post(api, "application/json", bytes.NewBuffer([]byte(`{}`)))
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		t.Fatalf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var body struct {
+		Result string         `json:"result"`
+		Meta   map[string]any `json:"_meta"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("error parsing response body: %s", err)
+	}
+
+	if want := `[{"value":0}]`; body.Result != want {
+		t.Fatalf("unexpected result: got %q, want %q", body.Result, want)
+	}
+	warnings, ok := body.Meta["warnings"].([]any)
+	if !ok || len(warnings) == 0 {
+		t.Fatalf("unable to find warnings in response metadata: %v", body.Meta)
+	}
+	warning, ok := warnings[0].(map[string]any)
+	if !ok {
+		t.Fatalf("unexpected warning: %v", warnings[0])
+	}
+	if code := warning["code"]; code != float64(1292) {
+		t.Fatalf("unexpected warning code: got %v, want 1292", code)
+	}
+	if msg, _ := warning["message"].(string); !strings.Contains(msg, "Truncated incorrect INTEGER value") {
+		t.Fatalf("unexpected warning message: %q", msg)
+	}
+}
+
 // RunMCPToolCallMethod runs the tool/call for mcp endpoint
 func RunMCPToolCallMethod(t *testing.T, invoke_param_want, fail_invocation_want string) {
 	// Test tool invoke endpoint
@@ -604,3 +796,132 @@ func RunMCPToolCallMethod(t *testing.T, invoke_param_want, fail_invocation_want
 		})
 	}
 }
+
+// ExampleMismatch describes a tool example whose invocation did not return
+// the declared result.
+type ExampleMismatch struct {
+	Tool  string
+	Index int
+	Got   any
+	Want  any
+	Err   error
+}
+
+func (m ExampleMismatch) String() string {
+	if m.Err != nil {
+		return fmt.Sprintf("tool %q example #%d: %s", m.Tool, m.Index, m.Err)
+	}
+	return fmt.Sprintf("tool %q example #%d: got %v, want %v", m.Tool, m.Index, m.Got, m.Want)
+}
+
+// CheckToolExamples invokes every example declared on the tools of a toolset
+// in toolsFile against a running server at api (e.g.
+// "http://127.0.0.1:5000/api"), and returns the examples whose result did not
+// match. An empty toolsetName selects all tools.
+func CheckToolExamples(api string, toolsFile map[string]any, toolsetName string) ([]ExampleMismatch, error) {
+	// round-trip through JSON to get a consistent structure regardless of how
+	// the tools file was built
+	b, err := json.Marshal(toolsFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal tools file: %w", err)
+	}
+	var parsed struct {
+		Tools map[string]struct {
+			Examples []struct {
+				Arguments map[string]any `json:"arguments"`
+				Result    any            `json:"result"`
+			} `json:"examples"`
+		} `json:"tools"`
+		Toolsets map[string][]string `json:"toolsets"`
+	}
+	if err := json.Unmarshal(b, &parsed); err != nil {
+		return nil, fmt.Errorf("unable to parse tools file: %w", err)
+	}
+
+	var toolNames []string
+	if toolsetName == "" {
+		for name := range parsed.Tools {
+			toolNames = append(toolNames, name)
+		}
+	} else {
+		var ok bool
+		toolNames, ok = parsed.Toolsets[toolsetName]
+		if !ok {
+			return nil, fmt.Errorf("toolset %q does not exist", toolsetName)
+		}
+	}
+	slices.Sort(toolNames)
+
+	var mismatches []ExampleMismatch
+	for _, name := range toolNames {
+		for i, example := range parsed.Tools[name].Examples {
+			m := ExampleMismatch{Tool: name, Index: i}
+			reqBody, err := json.Marshal(example.Arguments)
+			if err != nil {
+				return nil, fmt.Errorf("unable to marshal arguments for tool %q example #%d: %w", name, i, err)
+			}
+			m.Got, m.Err = invokeToolForExample(fmt.Sprintf("%s/tool/%s/invoke", api, name), reqBody)
+			if m.Err != nil {
+				mismatches = append(mismatches, m)
+				continue
+			}
+			// normalize the declared result the same way as the response
+			wantBytes, err := json.Marshal(example.Result)
+			if err != nil {
+				return nil, fmt.Errorf("unable to marshal result for tool %q example #%d: %w", name, i, err)
+			}
+			if err := json.Unmarshal(wantBytes, &m.Want); err != nil {
+				return nil, fmt.Errorf("unable to parse result for tool %q example #%d: %w", name, i, err)
+			}
+			if !reflect.DeepEqual(m.Got, m.Want) {
+				mismatches = append(mismatches, m)
+			}
+		}
+	}
+	return mismatches, nil
+}
+
+// invokeToolForExample sends an invoke request and returns the decoded result.
+func invokeToolForExample(api string, reqBody []byte) (any, error) {
+	req, err := http.NewRequest(http.MethodPost, api, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+	req.Header.Add("Content-type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("response status code is not 200, got %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("error parsing response body: %w", err)
+	}
+	result, ok := body["result"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to find result in response body")
+	}
+	var got any
+	if err := json.Unmarshal([]byte(result), &got); err != nil {
+		return nil, fmt.Errorf("unable to parse result %q: %w", result, err)
+	}
+	return got, nil
+}
+
+// RunToolExamplesTest runs the examples declared on the tools of a toolset
+// against the running server and reports every mismatch.
+func RunToolExamplesTest(t *testing.T, toolsFile map[string]any, toolsetName string) {
+	mismatches, err := CheckToolExamples("http://127.0.0.1:5000/api", toolsFile, toolsetName)
+	if err != nil {
+		t.Fatalf("unable to check tool examples: %s", err)
+	}
+	for _, m := range mismatches {
+		t.Errorf("example mismatch: %s", m)
+	}
+}
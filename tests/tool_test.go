@@ -0,0 +1,96 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestCheckToolExamples(t *testing.T) {
+	// sqlite is used so that the harness can be exercised without external infra
+	f, err := os.CreateTemp("", "examples-*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %s", err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	toolsFile := map[string]any{
+		"sources": map[string]any{
+			"my-instance": map[string]any{
+				"kind":     "sqlite",
+				"database": f.Name(),
+			},
+		},
+		"tools": map[string]any{
+			"my-passing-tool": map[string]any{
+				"kind":        "sqlite-sql",
+				"source":      "my-instance",
+				"description": "Tool with an example that matches.",
+				"statement":   "SELECT 1 AS one;",
+				"examples": []any{
+					map[string]any{
+						"arguments": map[string]any{},
+						"result":    []any{map[string]any{"one": 1}},
+					},
+				},
+			},
+			"my-failing-tool": map[string]any{
+				"kind":        "sqlite-sql",
+				"source":      "my-instance",
+				"description": "Tool with an example that does not match.",
+				"statement":   "SELECT 2 AS two;",
+				"examples": []any{
+					map[string]any{
+						"arguments": map[string]any{},
+						"result":    []any{map[string]any{"two": 3}},
+					},
+				},
+			},
+		},
+	}
+
+	cmd, cleanup, err := StartCmd(ctx, toolsFile, "--port", "5010")
+	if err != nil {
+		t.Fatalf("command initialization returned an error: %s", err)
+	}
+	defer cleanup()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	out, err := cmd.WaitForString(waitCtx, regexp.MustCompile(`Server ready to serve`))
+	if err != nil {
+		t.Logf("toolbox command logs: \n%s", out)
+		t.Fatalf("toolbox didn't start successfully: %s", err)
+	}
+
+	mismatches, err := CheckToolExamples("http://127.0.0.1:5010/api", toolsFile, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(mismatches) != 1 {
+		t.Fatalf("unexpected number of mismatches: got %d, want 1: %v", len(mismatches), mismatches)
+	}
+	if got := mismatches[0]; got.Tool != "my-failing-tool" || got.Index != 0 || got.Err != nil {
+		t.Fatalf("unexpected mismatch: %s", got)
+	}
+}